@@ -0,0 +1,570 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/guardian/content-filter/internal/integrity"
+	"github.com/guardian/content-filter/internal/logging"
+	"github.com/guardian/content-filter/internal/source"
+	"github.com/guardian/content-filter/internal/types"
+	"github.com/guardian/content-filter/internal/wordlist"
+	"github.com/guardian/content-filter/pkg/guardian"
+)
+
+// 管理接口分页参数
+const (
+	defaultPageSize = 50
+	maxPageSize     = 1000
+)
+
+// maxImportSize 批量导入请求体的最大字节数
+const maxImportSize = 64 << 20
+
+// maxDryRunTexts 预览请求的最大样本数
+const maxDryRunTexts = 10000
+
+var (
+	// errImportInvalid 导入后的词库有检查错误，未发布
+	errImportInvalid = errors.New("imported word list has lint errors")
+	// errValidateOnly 只校验导入内容，不发布
+	errValidateOnly = errors.New("validate only")
+)
+
+// wordAdmin 敏感词管理：通过词库配置源读取词库，修改经 Guardian.PublishWordDatabase 发布，各实例通过配置监听重载
+type wordAdmin struct {
+	g      *guardian.Guardian
+	src    source.ConfigSource
+	dataId string
+	group  string
+	format string             // 读取时的词库格式
+	lint   types.LintConfig   // 导入时的词库检查配置
+	key    ed25519.PrivateKey // 词库签名私钥，为nil时只写入校验和
+}
+
+// registerAdmin 注册 /admin 管理接口
+func registerAdmin(g *guardian.Guardian, config *types.Config) error {
+	src, err := source.New(config, logging.Default())
+	if err != nil {
+		return fmt.Errorf("failed to create config source: %w", err)
+	}
+
+	var key ed25519.PrivateKey
+	if config.Admin.SigningKey != "" {
+		if key, err = integrity.ParsePrivateKey(config.Admin.SigningKey); err != nil {
+			return fmt.Errorf("failed to parse signing key: %w", err)
+		}
+	}
+
+	admin := &wordAdmin{
+		g:      g,
+		src:    src,
+		dataId: config.FilterConfig.DataId,
+		group:  config.FilterConfig.Group,
+		format: config.FilterConfig.WordFormat,
+		lint:   config.FilterConfig.Lint,
+		key:    key,
+	}
+	auth := withAdminToken(config.Admin.Token)
+	http.HandleFunc("/admin/words", withTrace(auth(admin.wordsHandler)))
+	http.HandleFunc("/admin/words/", withTrace(auth(admin.wordHandler)))
+	http.HandleFunc("/admin/words/import", withTrace(auth(admin.importHandler)))
+	http.HandleFunc("/admin/words/export", withTrace(auth(admin.exportHandler)))
+	http.HandleFunc("/admin/diffs", withTrace(auth(diffsHandler(g))))
+	http.HandleFunc("/admin/canary", withTrace(auth(canaryHandler(g))))
+	http.HandleFunc("/admin/lint", withTrace(auth(lintHandler(g))))
+	http.HandleFunc("/admin/hits", withTrace(auth(hitsHandler(g))))
+	http.HandleFunc("/admin/dryrun", withTrace(auth(dryRunHandler(g))))
+	http.HandleFunc("/admin/reviews", withTrace(auth(reviewsHandler(g))))
+	http.HandleFunc("/admin/reviews/", withTrace(auth(reviewHandler(g))))
+	http.HandleFunc("/admin/offenders/", withTrace(auth(offenderHandler(g))))
+	return nil
+}
+
+// withAdminToken 校验 "Authorization: Bearer <token>"
+func withAdminToken(token string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			provided, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next(w, r)
+		}
+	}
+}
+
+// wordsHandler GET 分页查询敏感词，POST 添加敏感词
+//
+//	GET  /admin/words?page=1&page_size=50&q=关键字&category=abuse
+//	POST /admin/words  {"word": "...", "categories": ["abuse"], "level": 3}
+func (a *wordAdmin) wordsHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		a.listWords(w, r)
+
+	case http.MethodPost:
+		var word types.SensitiveWord
+		if err := json.NewDecoder(r.Body).Decode(&word); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+			return
+		}
+		if err := validateWord(&word); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		wordDB, err := a.g.PublishWordDatabase(a.g.InsertWord(word), a.key)
+		writeAdminResult(w, wordDB, err, http.StatusCreated)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// wordHandler PUT 更新敏感词，DELETE 删除敏感词
+//
+//	PUT    /admin/words/{word}  {"categories": ["abuse"], "level": 4}
+//	DELETE /admin/words/{word}
+func (a *wordAdmin) wordHandler(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/admin/words/")
+	if name == "" {
+		http.Error(w, "Missing word", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		var word types.SensitiveWord
+		if err := json.NewDecoder(r.Body).Decode(&word); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+			return
+		}
+		word.Word = name
+		if err := validateWord(&word); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		wordDB, err := a.g.PublishWordDatabase(a.g.ReplaceWord(word), a.key)
+		writeAdminResult(w, wordDB, err, http.StatusOK)
+
+	case http.MethodDelete:
+		wordDB, err := a.g.PublishWordDatabase(a.g.DeleteWord(name), a.key)
+		writeAdminResult(w, wordDB, err, http.StatusOK)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// importHandler 批量导入词表，请求体为词表文件内容，或 multipart/form-data 的 file 字段
+//
+//	POST /admin/words/import?format=csv&category=abuse&level=3&mode=merge&validate_only=false
+//
+// mode为merge（默认）时已存在的敏感词被覆盖、其余保留；为replace时用导入结果替换整个词库的黑名单和分类
+// 导入后的词库按 lint 配置检查，有错误时返回422且不发布；validate_only=true 时只返回检查报告
+func (a *wordAdmin) importHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	options := wordlist.DefaultImportOptions()
+	if category := query.Get("category"); category != "" {
+		options.DefaultCategory = category
+	}
+	if level := query.Get("level"); level != "" {
+		n, err := strconv.Atoi(level)
+		if err != nil || n <= 0 {
+			http.Error(w, "Invalid level", http.StatusBadRequest)
+			return
+		}
+		options.DefaultLevel = n
+	}
+	mode := query.Get("mode")
+	if mode != "" && mode != "merge" && mode != "replace" {
+		http.Error(w, "Invalid mode, expected merge or replace", http.StatusBadRequest)
+		return
+	}
+
+	validateOnly := query.Get("validate_only") == "true"
+
+	data, filename, err := readImport(w, r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to read request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	format := wordlist.DetectFormat(filename, data)
+	if name := query.Get("format"); name != "" && name != "auto" {
+		if format, err = wordlist.ParseFormat(name); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	imported, err := wordlist.Parse(bytes.NewReader(data), format, options)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to parse %s word list: %v", format, err), http.StatusBadRequest)
+		return
+	}
+
+	words := imported.Words()
+	var issues []types.LintIssue
+	merge := a.g.MergeWords(words)
+	wordDB, err := a.g.PublishWordDatabase(func(wordDB *types.WordDatabase) error {
+		if mode == "replace" {
+			wordDB.Blacklist = nil
+			wordDB.Categories = map[string][]types.SensitiveWord{}
+		}
+		if err := merge(wordDB); err != nil {
+			return err
+		}
+
+		issues = wordlist.LintWithConfig(wordDB, &a.lint)
+		for _, issue := range issues {
+			if issue.Severity == types.LintError {
+				return errImportInvalid
+			}
+		}
+		if validateOnly {
+			return errValidateOnly
+		}
+		return nil
+	}, a.key)
+
+	report := map[string]interface{}{
+		"imported": len(words),
+		"format":   format,
+		"issues":   issues,
+	}
+	status := http.StatusOK
+	switch {
+	case errors.Is(err, errImportInvalid):
+		status = http.StatusUnprocessableEntity
+		report["error"] = err.Error()
+	case errors.Is(err, errValidateOnly):
+	case err != nil:
+		writeAdminResult(w, nil, err, 0)
+		return
+	default:
+		report["version"] = wordDB.Version
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
+}
+
+// readImport 读取导入的词表内容，multipart请求读取 file 字段并返回文件名，用于识别格式
+func readImport(w http.ResponseWriter, r *http.Request) ([]byte, string, error) {
+	body := http.MaxBytesReader(w, r.Body, maxImportSize)
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		data, err := io.ReadAll(body)
+		return data, "", err
+	}
+
+	r.Body = body
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		return nil, "", err
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	return data, header.Filename, err
+}
+
+// exportHandler 下载当前词库，format为csv时只包含词语、分类和级别
+//
+//	GET /admin/words/export?format=json|csv
+func (a *wordAdmin) exportHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = types.WordFormatJSON
+	}
+	if format != types.WordFormatJSON && format != types.WordFormatCSV {
+		http.Error(w, "Invalid format, expected json or csv", http.StatusBadRequest)
+		return
+	}
+
+	wordDB, err := source.GetWordDatabase(a.src, a.dataId, a.group, a.format)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to read word database: %v", err), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", a.dataId+"."+format))
+	if format == types.WordFormatCSV {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		if err := wordlist.WriteCSV(w, wordDB); err != nil {
+			log.Printf("Failed to export word database: %v", err)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(wordDB)
+}
+
+// diffsHandler 返回最近的词库重载差异，最新的在前
+//
+//	GET /admin/diffs
+func diffsHandler(g *guardian.Guardian) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(g.ReloadDiffs())
+	}
+}
+
+// lintHandler 返回最近一次词库检查的结果，未检查过时返回404
+//
+//	GET /admin/lint
+func lintHandler(g *guardian.Guardian) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		report := g.LintReport()
+		if report == nil {
+			http.Error(w, "No lint report", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(report)
+	}
+}
+
+// hitsHandler 返回当前词库生效以来命中最多和从未命中的敏感词
+//
+//	GET /admin/hits?limit=100
+func hitsHandler(g *guardian.Guardian) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		limit, err := positiveInt(r.URL.Query().Get("limit"), defaultPageSize)
+		if err != nil {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(g.WordHits(limit))
+	}
+}
+
+// dryRunHandler 使用候选词库检查样本文本，返回与当前词库结果不同的样本，用于发布前预览影响
+//
+//	POST /admin/dryrun {"word_database": {...}, "texts": ["..."], "options": {...}}
+func dryRunHandler(g *guardian.Guardian) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req struct {
+			WordDatabase *types.WordDatabase  `json:"word_database"`
+			Texts        []string             `json:"texts"`
+			Options      *types.FilterOptions `json:"options,omitempty"`
+		}
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxImportSize)).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+			return
+		}
+		if req.WordDatabase == nil {
+			http.Error(w, "word_database is required", http.StatusBadRequest)
+			return
+		}
+		if len(req.Texts) > maxDryRunTexts {
+			http.Error(w, fmt.Sprintf("Too many texts, at most %d", maxDryRunTexts), http.StatusBadRequest)
+			return
+		}
+
+		result, err := g.DryRun(r.Context(), req.WordDatabase, req.Texts, req.Options)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Dry run failed: %v", err), http.StatusUnprocessableEntity)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+// canaryHandler GET 查询新词库的灰度状态，POST 立即全量生效或放弃灰度中的词库
+//
+//	GET  /admin/canary
+//	POST /admin/canary?action=promote|abort
+func canaryHandler(g *guardian.Guardian) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			status := g.CanaryStatus()
+			if status == nil {
+				http.Error(w, guardian.ErrNoCanary.Error(), http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(status)
+
+		case http.MethodPost:
+			var err error
+			switch action := r.URL.Query().Get("action"); action {
+			case "promote":
+				err = g.PromoteCanary()
+			case "abort":
+				err = g.AbortCanary()
+			default:
+				http.Error(w, "Invalid action, expected promote or abort", http.StatusBadRequest)
+				return
+			}
+			if errors.Is(err, guardian.ErrNoCanary) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			} else if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+// listWords 分页查询敏感词，按词语排序
+func (a *wordAdmin) listWords(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	page, err := positiveInt(query.Get("page"), 1)
+	if err != nil {
+		http.Error(w, "Invalid page", http.StatusBadRequest)
+		return
+	}
+	pageSize, err := positiveInt(query.Get("page_size"), defaultPageSize)
+	if err != nil {
+		http.Error(w, "Invalid page_size", http.StatusBadRequest)
+		return
+	}
+	pageSize = min(pageSize, maxPageSize)
+
+	wordDB, err := source.GetWordDatabase(a.src, a.dataId, a.group, a.format)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to read word database: %v", err), http.StatusBadGateway)
+		return
+	}
+
+	search := strings.ToLower(query.Get("q"))
+	category := query.Get("category")
+	words := make([]types.SensitiveWord, 0)
+	for _, word := range wordDB.Words() {
+		if search != "" && !strings.Contains(strings.ToLower(word.Word), search) {
+			continue
+		}
+		if category != "" && !hasCategory(word, category) {
+			continue
+		}
+		words = append(words, word)
+	}
+	sort.Slice(words, func(i, j int) bool { return words[i].Word < words[j].Word })
+
+	start := min((page-1)*pageSize, len(words))
+	end := min(start+pageSize, len(words))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"version":   wordDB.Version,
+		"total":     len(words),
+		"page":      page,
+		"page_size": pageSize,
+		"words":     words[start:end],
+	})
+}
+
+// writeAdminResult 输出修改结果，按错误类型返回状态码
+func writeAdminResult(w http.ResponseWriter, wordDB *types.WordDatabase, err error, status int) {
+	switch {
+	case errors.Is(err, guardian.ErrWordExists), errors.Is(err, guardian.ErrConcurrentUpdate), errors.Is(err, guardian.ErrPersistFormat):
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	case errors.Is(err, guardian.ErrWordNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case errors.Is(err, guardian.ErrInvalidWordDB):
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	case errors.Is(err, guardian.ErrWordSourceUnavailable):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"version": wordDB.Version})
+}
+
+// validateWord 校验敏感词
+func validateWord(word *types.SensitiveWord) error {
+	word.Word = strings.TrimSpace(word.Word)
+	if word.Word == "" {
+		return errors.New("word must not be empty")
+	}
+	if word.Level <= 0 {
+		return errors.New("level must be positive")
+	}
+	return nil
+}
+
+// hasCategory 敏感词是否属于分类
+func hasCategory(word types.SensitiveWord, category string) bool {
+	for _, c := range word.Categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// positiveInt 解析正整数参数，为空时返回默认值
+func positiveInt(value string, fallback int) (int, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid positive integer %q", value)
+	}
+	return n, nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/guardian/content-filter/internal/artifact"
+	"github.com/guardian/content-filter/internal/integrity"
+	"github.com/guardian/content-filter/internal/normalize"
+	"github.com/guardian/content-filter/internal/wordlist"
+)
+
+// runCompile 将词表编译为带校验和的二进制产物
+//
+//	guardian compile -input words.json -output words.gda -config configs/config.yaml
+//
+// 敏感词按配置文件中的 filter_config.normalize 标准化，加载产物的实例须使用相同的标准化配置
+// 配置了 admin.signing_key 时产物带签名，配置了 filter_config.public_key 的实例只加载签名有效的产物
+func runCompile(args []string) error {
+	fs := flag.NewFlagSet("compile", flag.ExitOnError)
+	input := fs.String("input", "", "输入词表文件路径（- 表示标准输入）")
+	format := fs.String("format", "auto", "词表格式: auto|json|yaml|text|hanlp|tieba|csv")
+	output := fs.String("output", "words.gda", "输出产物路径")
+	version := fs.String("version", "", "词库版本号，默认使用词表中的版本")
+	configFile := fs.String("config", "configs/config.yaml", "配置文件路径（读取 filter_config.normalize 和 admin.signing_key）")
+	wordStats := fs.Bool("word-stats", false, "在产物中保存完整的敏感词，用于命中统计、词库对比和加载时检查，产物会变大")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *input == "" {
+		fs.Usage()
+		return fmt.Errorf("missing -input")
+	}
+
+	data, err := readInput(*input)
+	if err != nil {
+		return err
+	}
+
+	wordFormat := wordlist.DetectFormat(*input, data)
+	if *format != "auto" {
+		if wordFormat, err = wordlist.ParseFormat(*format); err != nil {
+			return err
+		}
+	}
+
+	wordDB, err := wordlist.Parse(bytes.NewReader(data), wordFormat, &wordlist.ImportOptions{
+		DefaultCategory: "default",
+		DefaultLevel:    3,
+		Version:         *version,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to parse %s word list: %w", wordFormat, err)
+	}
+	if *version != "" {
+		wordDB.Version = *version
+	}
+
+	config, err := loadConfig(*configFile)
+	if err != nil {
+		return err
+	}
+
+	var key ed25519.PrivateKey
+	if config.Admin.SigningKey != "" {
+		if key, err = integrity.ParsePrivateKey(config.Admin.SigningKey); err != nil {
+			return err
+		}
+	}
+
+	start := time.Now()
+	a, err := artifact.Compile(wordDB, normalize.New(&config.FilterConfig.Normalize), &artifact.CompileOptions{WordStats: *wordStats})
+	if err != nil {
+		return err
+	}
+	if err := a.Save(*output, key); err != nil {
+		return err
+	}
+
+	// 回读校验
+	loaded, err := artifact.Load(*output)
+	if err == nil && key != nil {
+		err = loaded.Verify(key.Public().(ed25519.PublicKey))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to verify artifact: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "compiled %d words (%d nodes, version %s) into %s in %v, sha256 %s\n",
+		loaded.Metadata.WordCount, loaded.Metadata.NodeCount, loaded.Metadata.Version,
+		*output, time.Since(start), loaded.Metadata.Checksum)
+	return nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// defaultConfig 默认配置，配置文件中未出现的字段保持默认值
+func defaultConfig() *types.Config {
+	return &types.Config{
+		NacosConfig: types.NacosConfig{
+			ServerConfigs: []types.ServerConfig{
+				{
+					IpAddr: "127.0.0.1",
+					Port:   8848,
+				},
+			},
+			ClientConfig: types.ClientConfig{
+				NamespaceId:         "public",
+				TimeoutMs:           5000,
+				NotLoadCacheAtStart: false,
+				LogDir:              "./logs",
+				CacheDir:            "./cache",
+				LogLevel:            "info",
+			},
+		},
+		FilterConfig: types.FilterConfig{
+			DataId:          "sensitive_words",
+			Group:           "DEFAULT_GROUP",
+			ReloadPeriod:    5 * time.Minute,
+			EnableCache:     true,
+			CacheSize:       10000,
+			EnableWhitelist: true,
+		},
+	}
+}
+
+// loadConfig 加载YAML配置文件（JSON作为YAML子集同样支持）
+// 文件不存在时使用默认配置；存在但无法解析、包含未知字段或校验失败时返回错误
+func loadConfig(filename string) (*types.Config, error) {
+	config := defaultConfig()
+
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		log.Printf("Config file %s not found, using default config", filename)
+		return config, config.Validate()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", filename, err)
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+
+	return config, nil
+}
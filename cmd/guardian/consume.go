@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/guardian/content-filter/internal/kafkarest"
+	"github.com/guardian/content-filter/internal/types"
+	"github.com/guardian/content-filter/pkg/guardian"
+)
+
+const (
+	// defaultConsumerGroup 默认消费组
+	defaultConsumerGroup = "guardian"
+	// defaultConsumerField 默认检查的字段
+	defaultConsumerField = "$.text"
+	// defaultConsumerBatchSize 每批默认处理的消息数
+	defaultConsumerBatchSize = 100
+	// maxConsumerBackoff 请求 REST Proxy 失败后重试的最长间隔
+	maxConsumerBackoff = 30 * time.Second
+)
+
+// consumeResult 写入输出主题的检查结果
+type consumeResult struct {
+	Topic     string              `json:"topic"`           // 来源主题
+	Partition int32               `json:"partition"`       // 来源分区
+	Offset    int64               `json:"offset"`          // 来源位移
+	Key       json.RawMessage     `json:"key,omitempty"`   // 原消息的键
+	Passed    bool                `json:"passed"`          // 所有字段都通过检查，消息无法检查时为false
+	Error     string              `json:"error,omitempty"` // 消息无法检查的原因：不是合法JSON或没有要检查的字段
+	Fields    []types.FieldResult `json:"fields"`          // 每个字段的检查结果
+	Value     json.RawMessage     `json:"value"`           // 原消息，不是合法JSON时为原始内容的JSON字符串
+}
+
+// consumer Kafka消费模式的运行状态
+type consumer struct {
+	g       *guardian.Guardian
+	kafka   *kafkarest.Consumer
+	output  *kafkarest.Producer
+	config  *types.ConsumerConfig
+	options *types.FilterOptions
+}
+
+// runConsume 消费Kafka主题中的JSON消息，检查配置的字段后将结果写入输出主题，收到 SIGINT/SIGTERM 时处理完当前批次后退出
+//
+//	guardian consume -config configs/config.yaml
+func runConsume(args []string) error {
+	fs := flag.NewFlagSet("consume", flag.ExitOnError)
+	configPath := fs.String("config", "configs/config.yaml", "配置文件路径")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	config, err := loadConfig(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	cc := consumerDefaults(config.Consumer)
+	if cc.Topic == "" {
+		return fmt.Errorf("consumer.topic is not set")
+	}
+
+	g, err := guardian.NewGuardian(config)
+	if err != nil {
+		return fmt.Errorf("failed to create Guardian: %w", err)
+	}
+	defer g.Close()
+
+	c := &consumer{g: g, config: &cc}
+	if cc.Scene != "" {
+		if c.options, err = g.SceneOptions(cc.Scene); err != nil {
+			return err
+		}
+	}
+	// 启动时检查一次选择器，避免每条消息都报同样的错误
+	if _, err := g.CheckJSON(context.Background(), []byte("{}"), []string{cc.Field}, c.options); err != nil {
+		return fmt.Errorf("invalid consumer.field: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	c.kafka, err = kafkarest.NewConsumer(ctx, cc.URL, cc.Group, cc.OffsetReset, cc.Headers, cc.Timeout)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeCtx, cancel := context.WithTimeout(context.Background(), cc.Timeout)
+		defer cancel()
+		if err := c.kafka.Close(closeCtx); err != nil {
+			log.Printf("%v", err)
+		}
+	}()
+	if err := c.kafka.Subscribe(ctx, cc.Topic); err != nil {
+		return err
+	}
+	c.output = kafkarest.NewProducer(cc.URL, cc.OutputTopic, cc.Headers, cc.Timeout)
+
+	log.Printf("Consuming %s (group %s, field %s), writing results to %s", cc.Topic, cc.Group, cc.Field, cc.OutputTopic)
+	c.run(ctx)
+	log.Printf("Consumer stopped")
+	return nil
+}
+
+// consumerDefaults 填充消费配置的默认值
+func consumerDefaults(cc types.ConsumerConfig) types.ConsumerConfig {
+	if cc.Group == "" {
+		cc.Group = defaultConsumerGroup
+	}
+	if cc.Field == "" {
+		cc.Field = defaultConsumerField
+	}
+	if cc.BatchSize <= 0 {
+		cc.BatchSize = defaultConsumerBatchSize
+	}
+	if cc.OffsetReset == "" {
+		cc.OffsetReset = types.OffsetResetLatest
+	}
+	if cc.PollTimeout <= 0 {
+		cc.PollTimeout = time.Second
+	}
+	if cc.Timeout <= 0 {
+		cc.Timeout = 10 * time.Second
+	}
+	return cc
+}
+
+// run 循环拉取消息并分批处理，直到ctx取消；拉取失败时退避重试
+func (c *consumer) run(ctx context.Context) {
+	backoff := time.Second
+	for ctx.Err() == nil {
+		records, err := c.kafka.Fetch(ctx, c.config.PollTimeout)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("%v, retrying in %s", err, backoff)
+			backoff = sleep(ctx, backoff)
+			continue
+		}
+		backoff = time.Second
+
+		for start := 0; start < len(records); start += c.config.BatchSize {
+			end := min(start+c.config.BatchSize, len(records))
+			if !c.process(ctx, records[start:end]) {
+				return
+			}
+		}
+	}
+}
+
+// process 检查一批消息并写入输出主题，写入成功后提交位移；写入失败时退避重试直到成功或ctx取消
+// 返回false表示ctx已取消，未提交的消息在重启后重新处理
+func (c *consumer) process(ctx context.Context, records []kafkarest.Record) bool {
+	var results []interface{}
+	for _, r := range records {
+		result := c.check(r)
+		if c.config.ViolationsOnly && result.Passed {
+			continue
+		}
+		results = append(results, result)
+	}
+
+	backoff := time.Second
+	for len(results) > 0 {
+		err := c.output.Produce(ctx, results...)
+		if err == nil {
+			break
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+		log.Printf("Failed to write %d results to %s: %v, retrying in %s", len(results), c.config.OutputTopic, err, backoff)
+		backoff = sleep(ctx, backoff)
+	}
+
+	// 输出已写入，收到退出信号也要提交；提交失败只会导致重启后重复处理，不阻塞消费
+	if err := c.kafka.Commit(context.WithoutCancel(ctx), records); err != nil {
+		log.Printf("%v", err)
+	}
+	return true
+}
+
+// check 检查一条消息，追踪ID为 主题-分区-位移
+// 消息不是合法JSON或没有要检查的字段时不视为通过，输出带 error 的未通过结果，由下游处理，不会被跳过
+// 收到退出信号时仍完成当前消息的检查，不传入可取消的ctx
+func (c *consumer) check(r kafkarest.Record) *consumeResult {
+	ctx := guardian.WithTraceID(context.Background(), fmt.Sprintf("%s-%d-%d", r.Topic, r.Partition, r.Offset))
+	ctx = guardian.WithCaller(ctx, "", "kafka:"+r.Topic)
+
+	result := &consumeResult{
+		Topic:     r.Topic,
+		Partition: r.Partition,
+		Offset:    r.Offset,
+		Key:       r.Key,
+		Value:     r.Value,
+	}
+	if !json.Valid(r.Value) {
+		// 原消息无法直接嵌入输出，作为字符串输出
+		result.Value, _ = json.Marshal(string(r.Value))
+	}
+
+	fields, err := c.g.CheckJSON(ctx, r.Value, []string{c.config.Field}, c.options)
+	switch {
+	case err != nil:
+		result.Error = err.Error()
+	case len(fields) == 0:
+		result.Error = fmt.Sprintf("field %s not found", c.config.Field)
+	}
+	if result.Error != "" {
+		log.Printf("Message %s-%d-%d cannot be checked: %s", r.Topic, r.Partition, r.Offset, result.Error)
+		result.Fields = []types.FieldResult{}
+		return result
+	}
+
+	result.Passed = true
+	result.Fields = fields
+	for _, field := range fields {
+		if !field.Result.Passed {
+			result.Passed = false
+		}
+	}
+	return result
+}
+
+// sleep 等待backoff或ctx取消，返回下一次的退避间隔
+func sleep(ctx context.Context, backoff time.Duration) time.Duration {
+	select {
+	case <-time.After(backoff):
+	case <-ctx.Done():
+	}
+	return min(backoff*2, maxConsumerBackoff)
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/guardian/content-filter/internal/integrity"
+	"github.com/guardian/content-filter/internal/logging"
+	"github.com/guardian/content-filter/internal/source"
+	"github.com/guardian/content-filter/internal/types"
+	"github.com/guardian/content-filter/internal/wordlist"
+)
+
+// runImport 导入第三方词表并转换为WordDatabase
+//
+//	guardian import -input words.txt -format text -category abuse -output words.json
+//	guardian import -input words.csv -publish -config configs/config.yaml
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	input := fs.String("input", "", "输入词表文件路径（- 表示标准输入）")
+	format := fs.String("format", "auto", "词表格式: auto|json|yaml|text|hanlp|tieba|csv")
+	output := fs.String("output", "-", "输出文件路径（- 表示标准输出）")
+	category := fs.String("category", "default", "默认分类")
+	level := fs.Int("level", 3, "默认敏感级别")
+	version := fs.String("version", "", "词库版本号，默认使用当前时间")
+	publish := fs.Bool("publish", false, "转换后发布到配置中心")
+	config := fs.String("config", "configs/config.yaml", "配置文件路径（发布时使用）")
+	signKey := fs.String("sign-key", "", "Ed25519私钥文件路径（PEM或base64），设置后为词库签名")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *input == "" {
+		fs.Usage()
+		return fmt.Errorf("missing -input")
+	}
+
+	data, err := readInput(*input)
+	if err != nil {
+		return err
+	}
+
+	wordFormat := wordlist.DetectFormat(*input, data)
+	if *format != "auto" {
+		if wordFormat, err = wordlist.ParseFormat(*format); err != nil {
+			return err
+		}
+	}
+
+	wordDB, err := wordlist.Parse(bytes.NewReader(data), wordFormat, &wordlist.ImportOptions{
+		DefaultCategory: *category,
+		DefaultLevel:    *level,
+		Version:         *version,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to parse %s word list: %w", wordFormat, err)
+	}
+
+	if err := sealWordDatabase(wordDB, *signKey); err != nil {
+		return err
+	}
+
+	if *publish {
+		cfg, err := loadConfig(*config)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		src, err := source.New(cfg, logging.Default())
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+
+		if err := source.PublishWordDatabaseShards(src, cfg.FilterConfig.DataId, cfg.FilterConfig.Group, wordDB, cfg.FilterConfig.ShardSize); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "published %d words (version %s, format %s)\n", len(wordDB.Blacklist), wordDB.Version, wordFormat)
+		return nil
+	}
+
+	content, err := json.MarshalIndent(wordDB, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal word database: %w", err)
+	}
+
+	if *output == "-" {
+		_, err = os.Stdout.Write(append(content, '\n'))
+		return err
+	}
+	if err := os.WriteFile(*output, content, 0644); err != nil {
+		return fmt.Errorf("failed to write word database: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "imported %d words (version %s, format %s) into %s\n", len(wordDB.Blacklist), wordDB.Version, wordFormat, *output)
+	return nil
+}
+
+// readInput 读取输入文件或标准输入
+func readInput(path string) ([]byte, error) {
+	if path == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read stdin: %w", err)
+		}
+		return data, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read word list: %w", err)
+	}
+	return data, nil
+}
+
+// sealWordDatabase 写入词库校验和，指定私钥文件时同时签名
+func sealWordDatabase(wordDB *types.WordDatabase, keyFile string) error {
+	if keyFile == "" {
+		return integrity.Seal(wordDB, nil)
+	}
+
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to read signing key: %w", err)
+	}
+	key, err := integrity.ParsePrivateKey(string(data))
+	if err != nil {
+		return err
+	}
+	return integrity.Seal(wordDB, key)
+}
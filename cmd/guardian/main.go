@@ -4,14 +4,14 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
-	"io/ioutil"
 	"log"
 	"net/http"
 	"os"
 	"time"
 
-	"github.com/guardian/content-filter/pkg/guardian"
+	"github.com/guardian/content-filter/internal/trace"
 	"github.com/guardian/content-filter/internal/types"
+	"github.com/guardian/content-filter/pkg/guardian"
 )
 
 var (
@@ -19,7 +19,24 @@ var (
 	port       = flag.String("port", "8080", "服务端口")
 )
 
+// commands 子命令
+var commands = map[string]func(args []string) error{
+	"import":  runImport,
+	"compile": runCompile,
+	"consume": runConsume,
+}
+
 func main() {
+	// 子命令
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			if err := cmd(os.Args[2:]); err != nil {
+				log.Fatalf("%s: %v", os.Args[1], err)
+			}
+			return
+		}
+	}
+
 	flag.Parse()
 
 	// 加载配置
@@ -37,61 +54,44 @@ func main() {
 
 	// 设置HTTP路由
 	http.HandleFunc("/health", healthHandler(g))
-	http.HandleFunc("/check", checkHandler(g))
-	http.HandleFunc("/check/batch", batchCheckHandler(g))
+	http.HandleFunc("/check", withTrace(checkHandler(g)))
+	http.HandleFunc("/check/batch", withTrace(batchCheckHandler(g)))
+	http.HandleFunc("/check/stream", withTrace(streamHandler(g, config.FilterConfig.BatchWorkers)))
+	http.HandleFunc("/ws", withTrace(wsHandler(g, newWSHub(g))))
 	http.HandleFunc("/stats", statsHandler(g))
-	http.HandleFunc("/whitelist", whitelistHandler(g))
+	http.HandleFunc("/metrics", metricsHandler(g))
+
+	// 管理接口和白名单修改，未配置令牌时不开放；开启 persist_whitelist 时白名单修改会写回整个集群共用的词库源
+	if config.Admin.Token != "" {
+		http.HandleFunc("/whitelist", withTrace(withAdminToken(config.Admin.Token)(whitelistHandler(g))))
+		if err := registerAdmin(g, config); err != nil {
+			log.Fatalf("Failed to register admin API: %v", err)
+		}
+	} else {
+		log.Printf("Admin API and /whitelist disabled: admin.token is not set")
+	}
 
 	// 启动HTTP服务器
 	log.Printf("Starting server on port %s", *port)
 	log.Fatal(http.ListenAndServe(":"+*port, nil))
 }
 
-// loadConfig 加载配置文件
-func loadConfig(filename string) (*types.Config, error) {
-	// 这里简化处理，实际项目中应该使用yaml解析器
-	// 为了演示，我们使用默认配置
-	config := &types.Config{
-		NacosConfig: types.NacosConfig{
-			ServerConfigs: []types.ServerConfig{
-				{
-					IpAddr: "127.0.0.1",
-					Port:   8848,
-				},
-			},
-			ClientConfig: types.ClientConfig{
-				NamespaceId:         "public",
-				TimeoutMs:           5000,
-				NotLoadCacheAtStart: false,
-				LogDir:              "./logs",
-				CacheDir:            "./cache",
-				LogLevel:            "info",
-			},
-		},
-		FilterConfig: types.FilterConfig{
-			DataId:         "sensitive_words",
-			Group:          "DEFAULT_GROUP",
-			ReloadPeriod:   5 * time.Minute,
-			EnableCache:    true,
-			CacheSize:      10000,
-			EnableWhitelist: true,
-		},
-	}
-
-	// 如果配置文件存在，尝试读取
-	if _, err := os.Stat(filename); err == nil {
-		data, err := ioutil.ReadFile(filename)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read config file: %w", err)
+// withTrace 从请求头读取追踪ID（缺失时生成），写入请求上下文并回写响应头；X-Tenant-ID、X-Caller-ID 写入审计记录
+func withTrace(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		traceID := r.Header.Get("X-Request-ID")
+		if traceID == "" {
+			traceID = r.Header.Get("X-Trace-ID")
 		}
-		
-		// 这里应该解析YAML，为了简化使用JSON
-		if err := json.Unmarshal(data, config); err != nil {
-			log.Printf("Warning: failed to parse config file, using default config: %v", err)
+		if traceID == "" {
+			traceID = trace.NewTraceID()
 		}
-	}
 
-	return config, nil
+		w.Header().Set("X-Request-ID", traceID)
+		ctx := guardian.WithTraceID(r.Context(), traceID)
+		ctx = guardian.WithCaller(ctx, r.Header.Get("X-Tenant-ID"), r.Header.Get("X-Caller-ID"))
+		next(w, r.WithContext(ctx))
+	}
 }
 
 // healthHandler 健康检查处理器
@@ -119,8 +119,9 @@ func checkHandler(g *guardian.Guardian) http.HandlerFunc {
 		}
 
 		var req struct {
-			Text    string                `json:"text"`
-			Options *types.FilterOptions `json:"options,omitempty"`
+			Text     string               `json:"text"`
+			Options  *types.FilterOptions `json:"options,omitempty"`
+			Metadata map[string]string    `json:"metadata,omitempty"` // 随复核条目保存，如内容ID
 		}
 
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -128,11 +129,11 @@ func checkHandler(g *guardian.Guardian) http.HandlerFunc {
 			return
 		}
 
-		var result *types.FilterResult
-		if req.Options != nil {
-			result = g.CheckWithOptions(req.Text, req.Options)
-		} else {
-			result = g.Check(req.Text)
+		ctx := guardian.WithMetadata(r.Context(), req.Metadata)
+		result, err := g.CheckContext(ctx, req.Text, req.Options)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Check aborted: %v", err), http.StatusRequestTimeout)
+			return
 		}
 
 		w.Header().Set("Content-Type", "application/json")
@@ -141,6 +142,7 @@ func checkHandler(g *guardian.Guardian) http.HandlerFunc {
 }
 
 // batchCheckHandler 批量检查处理器
+// 请求为 texts 时返回与之一一对应的结果数组；为带ID的 items 时返回附带ID的结果和汇总
 func batchCheckHandler(g *guardian.Guardian) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
@@ -149,7 +151,8 @@ func batchCheckHandler(g *guardian.Guardian) http.HandlerFunc {
 		}
 
 		var req struct {
-			Texts   []string              `json:"texts"`
+			Texts   []string             `json:"texts"`
+			Items   []types.BatchItem    `json:"items,omitempty"`
 			Options *types.FilterOptions `json:"options,omitempty"`
 		}
 
@@ -157,12 +160,26 @@ func batchCheckHandler(g *guardian.Guardian) http.HandlerFunc {
 			http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
 			return
 		}
+		if len(req.Texts) > 0 && len(req.Items) > 0 {
+			http.Error(w, "Only one of texts and items may be set", http.StatusBadRequest)
+			return
+		}
 
-		var results []*types.FilterResult
-		if req.Options != nil {
-			results = g.BatchCheckWithOptions(req.Texts, req.Options)
-		} else {
-			results = g.BatchCheck(req.Texts)
+		if len(req.Items) > 0 {
+			response, err := g.BatchCheckItems(r.Context(), req.Items, req.Options)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Check aborted: %v", err), http.StatusRequestTimeout)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(response)
+			return
+		}
+
+		results, err := g.BatchCheckContext(r.Context(), req.Texts, req.Options)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Check aborted: %v", err), http.StatusRequestTimeout)
+			return
 		}
 
 		w.Header().Set("Content-Type", "application/json")
@@ -192,7 +209,10 @@ func whitelistHandler(g *guardian.Guardian) http.HandlerFunc {
 				http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
 				return
 			}
-			g.AddToWhitelist(req.Word)
+			if err := g.AddToWhitelist(req.Word); err != nil {
+				http.Error(w, fmt.Sprintf("Failed to persist whitelist: %v", err), http.StatusInternalServerError)
+				return
+			}
 			w.WriteHeader(http.StatusOK)
 
 		case http.MethodDelete:
@@ -204,7 +224,10 @@ func whitelistHandler(g *guardian.Guardian) http.HandlerFunc {
 				http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
 				return
 			}
-			g.RemoveFromWhitelist(req.Word)
+			if err := g.RemoveFromWhitelist(req.Word); err != nil {
+				http.Error(w, fmt.Sprintf("Failed to persist whitelist: %v", err), http.StatusInternalServerError)
+				return
+			}
 			w.WriteHeader(http.StatusOK)
 
 		default:
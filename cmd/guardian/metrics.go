@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/guardian/content-filter/pkg/guardian"
+)
+
+// metricsHandler 以Prometheus文本格式输出运行指标，数据与 /stats 相同
+//
+//	GET /metrics
+//
+// 计数器为进程启动以来的累计值，命中率等比例应在查询时用 rate() 计算，guardian_cache_hit_ratio 仅为累计值
+func metricsHandler(g *guardian.Guardian) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		stats := g.GetStats()
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		m := metricsWriter{bufio.NewWriter(w)}
+		defer m.Flush()
+
+		m.metric("guardian_checks_total", "counter", "检查总次数", stats.Checks)
+		m.metric("guardian_blocked_total", "counter", "未通过的次数", stats.Blocked)
+		m.metric("guardian_degraded_total", "counter", "返回降级结果的次数", stats.Degraded)
+		m.metric("guardian_coalesced_total", "counter", "缓存未命中时合并到并发的相同检查的次数", stats.Coalesced)
+		m.metric("guardian_words", "gauge", "敏感词数量", stats.WordCount)
+		m.labeled("guardian_category_hits_total", "counter", "命中各分类的检查次数", "category", stats.Categories)
+
+		if c := stats.CacheSummary; c != nil {
+			m.metric("guardian_cache_hits_total", "counter", "结果缓存命中次数", c.Hits)
+			m.metric("guardian_cache_misses_total", "counter", "结果缓存未命中次数", c.Misses)
+			m.metric("guardian_cache_skipped_total", "counter", "文本过长、未读写缓存的检查次数", c.Skipped)
+			m.metric("guardian_cache_evictions_total", "counter", "结果缓存因容量淘汰的条目数", c.Evictions)
+			m.metric("guardian_cache_entries", "gauge", "结果缓存当前条目数", c.Entries)
+			m.metric("guardian_cache_capacity", "gauge", "结果缓存条目数上限，0表示不限制", c.Capacity)
+			m.metric("guardian_cache_memory_bytes", "gauge", "结果缓存条目占用内存的估算值", c.MemoryBytes)
+			m.metric("guardian_cache_hit_ratio", "gauge", "进程启动以来的累计缓存命中率", c.HitRate)
+		}
+	}
+}
+
+// metricsWriter 写入Prometheus文本格式的指标
+type metricsWriter struct {
+	*bufio.Writer
+}
+
+// metric 写入不带标签的指标
+func (m metricsWriter) metric(name, kind, help string, value interface{}) {
+	fmt.Fprintf(m, "# HELP %s %s\n# TYPE %s %s\n%s %v\n", name, help, name, kind, name, value)
+}
+
+// labeled 写入按一个标签区分的指标，按标签值排序输出
+func (m metricsWriter) labeled(name, kind, help, label string, values map[string]int64) {
+	fmt.Fprintf(m, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(m, "%s{%s=%q} %d\n", name, label, key, values[key])
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/guardian/content-filter/pkg/guardian"
+)
+
+// offenderHandler 查询（GET）或清空（DELETE）用户在统计窗口内的违规次数
+//
+//	GET /admin/offenders/{user_id}
+//	DELETE /admin/offenders/{user_id}
+func offenderHandler(g *guardian.Guardian) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := strings.TrimPrefix(r.URL.Path, "/admin/offenders/")
+		if userID == "" {
+			http.Error(w, "Missing user id", http.StatusBadRequest)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			status, err := g.OffenderStatus(r.Context(), userID)
+			if err != nil {
+				writeOffenderError(w, err)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(status)
+
+		case http.MethodDelete:
+			if err := g.ResetOffender(r.Context(), userID); err != nil {
+				writeOffenderError(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+// writeOffenderError 未启用违规统计时返回501，存储出错时返回502
+func writeOffenderError(w http.ResponseWriter, err error) {
+	if errors.Is(err, guardian.ErrOffenderDisabled) {
+		http.Error(w, err.Error(), http.StatusNotImplemented)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusBadGateway)
+}
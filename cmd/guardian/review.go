@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/guardian/content-filter/internal/types"
+	"github.com/guardian/content-filter/pkg/guardian"
+)
+
+// reviewsHandler 按入队顺序返回待复核条目
+//
+//	GET /admin/reviews?limit=50
+func reviewsHandler(g *guardian.Guardian) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		limit, err := positiveInt(r.URL.Query().Get("limit"), defaultPageSize)
+		if err != nil || limit > maxPageSize {
+			http.Error(w, "Invalid limit", http.StatusBadRequest)
+			return
+		}
+
+		items, err := g.PendingReviews(r.Context(), limit)
+		if err != nil {
+			writeReviewError(w, err)
+			return
+		}
+		if items == nil {
+			items = []*types.ReviewItem{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(items)
+	}
+}
+
+// reviewHandler GET 查询复核条目，POST 提交复核结论
+//
+//	GET  /admin/reviews/{id}
+//	POST /admin/reviews/{id}  {"status": "approved|rejected", "reviewer": "...", "note": "..."}
+func reviewHandler(g *guardian.Guardian) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/admin/reviews/")
+		if id == "" {
+			http.Error(w, "Missing review id", http.StatusBadRequest)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			item, err := g.ReviewItem(r.Context(), id)
+			if err != nil {
+				writeReviewError(w, err)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(item)
+
+		case http.MethodPost:
+			var decision types.ReviewDecision
+			if err := json.NewDecoder(r.Body).Decode(&decision); err != nil {
+				http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+				return
+			}
+			if decision.Reviewer == "" {
+				http.Error(w, "Missing reviewer", http.StatusBadRequest)
+				return
+			}
+			decision.ID = id
+			if err := g.ResolveReview(r.Context(), &decision); err != nil {
+				writeReviewError(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+	}
+}
+
+// writeReviewError 按错误类型返回状态码
+func writeReviewError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, guardian.ErrReviewNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, guardian.ErrReviewResolved):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, guardian.ErrReviewUnsupported):
+		http.Error(w, err.Error(), http.StatusNotImplemented)
+	case errors.Is(err, guardian.ErrInvalidReviewDecision):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusBadGateway)
+	}
+}
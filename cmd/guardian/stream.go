@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+
+	"github.com/guardian/content-filter/internal/types"
+	"github.com/guardian/content-filter/pkg/guardian"
+)
+
+// maxStreamLine 流式检查中单行请求的最大字节数
+const maxStreamLine = 4 << 20
+
+// streamRequest 流式检查的一行请求
+type streamRequest struct {
+	ID      string               `json:"id"`
+	Text    string               `json:"text"`
+	Options *types.FilterOptions `json:"options,omitempty"` // 为空时使用默认选项
+}
+
+// streamJob 一行请求及其结果，结果写入后关闭done
+type streamJob struct {
+	line   []byte
+	result types.BatchItemResult
+	done   chan struct{}
+}
+
+// streamHandler 流式检查处理器，请求和响应都是NDJSON
+//
+//	POST /check/stream
+//
+// 每行请求为 {"id":"...","text":"...","options":{...}}，每行响应为 {"id":"...","result":{...}}，与请求按顺序一一对应，读取一行即开始检查
+// workers个协程并发检查，已读取未写出的行不超过 2*workers，两端都无需缓存全部数据；无法解析的行返回 error，不影响后续行
+// 客户端断开时停止检查
+func streamHandler(g *guardian.Guardian, workers int) http.HandlerFunc {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		// HTTP/1.1 默认在写出响应后不再读取请求体，开启全双工以边读边写；HTTP/2 不需要
+		rc := http.NewResponseController(w)
+		rc.EnableFullDuplex()
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.WriteHeader(http.StatusOK)
+
+		ctx := r.Context()
+		jobs := make(chan *streamJob, workers)
+		order := make(chan *streamJob, 2*workers) // 按读取顺序排队等待写出
+		for i := 0; i < workers; i++ {
+			go func() {
+				for job := range jobs {
+					job.result = checkStreamLine(ctx, g, job.line)
+					close(job.done)
+				}
+			}()
+		}
+
+		// 读取请求行
+		readErr := make(chan error, 1)
+		go func() {
+			defer close(order)
+			defer close(jobs)
+			scanner := bufio.NewScanner(r.Body)
+			scanner.Buffer(make([]byte, 64<<10), maxStreamLine)
+			for scanner.Scan() {
+				line := bytes.TrimSpace(scanner.Bytes())
+				if len(line) == 0 {
+					continue
+				}
+				job := &streamJob{line: bytes.Clone(line), done: make(chan struct{})}
+				select {
+				case order <- job:
+				case <-ctx.Done():
+					readErr <- ctx.Err()
+					return
+				}
+				jobs <- job
+			}
+			readErr <- scanner.Err()
+		}()
+
+		// 按顺序写出结果，等待下一行或其结果前先发出已写出的行
+		encoder := json.NewEncoder(w)
+		for job := range order {
+			select {
+			case <-job.done:
+			default:
+				rc.Flush()
+				select {
+				case <-job.done:
+				case <-ctx.Done():
+					continue // 客户端已断开，排空队列等待读取结束
+				}
+			}
+			encoder.Encode(job.result)
+			if len(order) == 0 {
+				rc.Flush()
+			}
+		}
+		if err := <-readErr; err != nil && ctx.Err() == nil {
+			encoder.Encode(types.BatchItemResult{Error: fmt.Sprintf("failed to read request: %v", err)})
+		}
+	}
+}
+
+// checkStreamLine 解析并检查一行请求
+func checkStreamLine(ctx context.Context, g *guardian.Guardian, line []byte) types.BatchItemResult {
+	var req streamRequest
+	if err := json.Unmarshal(line, &req); err != nil {
+		return types.BatchItemResult{Error: fmt.Sprintf("invalid request line: %v", err)}
+	}
+	result, err := g.CheckContext(ctx, req.Text, req.Options)
+	if err != nil {
+		return types.BatchItemResult{ID: req.ID, Error: fmt.Sprintf("check aborted: %v", err)}
+	}
+	return types.BatchItemResult{ID: req.ID, Result: result}
+}
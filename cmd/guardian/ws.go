@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"github.com/guardian/content-filter/internal/types"
+	"github.com/guardian/content-filter/pkg/guardian"
+)
+
+const (
+	// wsMaxMessage 单条消息的最大字节数
+	wsMaxMessage = 4 << 20
+	// wsMaxInflight 每个连接同时检查的消息数，超过后暂停读取
+	wsMaxInflight = 32
+	// wsSendBuffer 每个连接等待发送的消息数，通知在缓冲区满时丢弃
+	wsSendBuffer = 64
+	// wsWriteTimeout 发送一条消息的超时时间，超时视为连接已失效
+	wsWriteTimeout = 10 * time.Second
+)
+
+// WebSocket 消息类型
+const (
+	wsTypeCheck             = "check"              // 检查文本，type为空时同样视为检查
+	wsTypePing              = "ping"               // 心跳
+	wsTypePong              = "pong"               // 心跳响应
+	wsTypeResult            = "result"             // 检查结果
+	wsTypeError             = "error"              // 消息无法处理
+	wsTypeDictionaryChanged = "dictionary_changed" // 词库变更通知
+)
+
+// wsRequest 客户端发送的消息
+type wsRequest struct {
+	Type     string               `json:"type,omitempty"`     // check（默认）|ping
+	ID       string               `json:"id"`                 // 调用方的标识，原样返回
+	Text     string               `json:"text"`               // 待检查文本
+	Options  *types.FilterOptions `json:"options,omitempty"`  // 为空时使用默认选项
+	Metadata map[string]string    `json:"metadata,omitempty"` // 随复核条目保存，如内容ID
+}
+
+// wsMessage 服务端发送的消息
+type wsMessage struct {
+	Type            string              `json:"type"`                       // 消息类型
+	ID              string              `json:"id,omitempty"`               // 请求中的ID
+	Result          *types.FilterResult `json:"result,omitempty"`           // 检查结果
+	Error           string              `json:"error,omitempty"`            // 消息无法处理的原因
+	Version         string              `json:"version,omitempty"`          // 变更后的词库版本
+	PreviousVersion string              `json:"previous_version,omitempty"` // 变更前的词库版本
+	Time            *time.Time          `json:"time,omitempty"`             // 词库生效时间
+}
+
+// wsHub 已建立的WebSocket连接，词库变更时向所有连接推送通知
+type wsHub struct {
+	mu    sync.Mutex
+	conns map[*wsConn]struct{}
+}
+
+// wsConn 一个WebSocket连接，所有消息经send由同一个协程写出
+type wsConn struct {
+	ws   *websocket.Conn
+	send chan *wsMessage
+}
+
+// newWSHub 创建连接集合并注册词库替换回调
+func newWSHub(g *guardian.Guardian) *wsHub {
+	hub := &wsHub{conns: make(map[*wsConn]struct{})}
+	g.OnReload(func(old, new *types.Stats) {
+		loadedAt := new.LoadedAt
+		hub.broadcast(&wsMessage{
+			Type:            wsTypeDictionaryChanged,
+			Version:         new.Version,
+			PreviousVersion: old.Version,
+			Time:            &loadedAt,
+		})
+	})
+	return hub
+}
+
+// broadcast 向所有连接推送消息，不等待发送；连接的发送缓冲区已满时丢弃
+func (h *wsHub) broadcast(msg *wsMessage) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for conn := range h.conns {
+		select {
+		case conn.send <- msg:
+		default:
+			log.Printf("Dropped %s notice for slow websocket client %s", msg.Type, conn.ws.Request().RemoteAddr)
+		}
+	}
+}
+
+func (h *wsHub) add(conn *wsConn) {
+	h.mu.Lock()
+	h.conns[conn] = struct{}{}
+	h.mu.Unlock()
+}
+
+func (h *wsHub) remove(conn *wsConn) {
+	h.mu.Lock()
+	delete(h.conns, conn)
+	h.mu.Unlock()
+}
+
+// wsHandler 实时检查的WebSocket处理器，适合聊天、直播弹幕等需要长连接的服务
+//
+//	GET /ws
+//
+// 客户端发送 {"id":"...","text":"...","options":{...}}，服务端返回 {"type":"result","id":"...","result":{...}}
+// 同一连接上的消息并发检查，结果按完成顺序返回，以id对应；{"type":"ping"} 返回 {"type":"pong"}
+// 词库变更时服务端推送 {"type":"dictionary_changed","version":"...","previous_version":"..."}
+func wsHandler(g *guardian.Guardian, hub *wsHub) http.HandlerFunc {
+	server := websocket.Server{
+		// 服务端调用方通常不带Origin，不做来源校验
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(ws *websocket.Conn) {
+			ws.MaxPayloadBytes = wsMaxMessage
+			conn := &wsConn{ws: ws, send: make(chan *wsMessage, wsSendBuffer)}
+			conn.serve(g, hub)
+		},
+	}
+	return server.ServeHTTP
+}
+
+// serve 读取并检查消息，直到连接关闭；期间加入hub接收通知
+func (c *wsConn) serve(g *guardian.Guardian, hub *wsHub) {
+	ctx, cancel := context.WithCancel(c.ws.Request().Context())
+	defer cancel()
+
+	writeDone := make(chan struct{})
+	go func() {
+		defer close(writeDone)
+		c.writeLoop(cancel)
+	}()
+	hub.add(c)
+
+	var inflight sync.WaitGroup
+	slots := make(chan struct{}, wsMaxInflight)
+	for {
+		var req wsRequest
+		if err := websocket.JSON.Receive(c.ws, &req); err != nil {
+			if !wsRecoverable(err) || ctx.Err() != nil {
+				break
+			}
+			c.reply(ctx, &wsMessage{Type: wsTypeError, Error: fmt.Sprintf("invalid message: %v", err)})
+			continue
+		}
+
+		switch req.Type {
+		case wsTypePing:
+			c.reply(ctx, &wsMessage{Type: wsTypePong, ID: req.ID})
+		case "", wsTypeCheck:
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				continue
+			}
+			inflight.Add(1)
+			go func() {
+				defer func() {
+					<-slots
+					inflight.Done()
+				}()
+				c.reply(ctx, c.check(ctx, g, &req))
+			}()
+		default:
+			c.reply(ctx, &wsMessage{Type: wsTypeError, ID: req.ID, Error: fmt.Sprintf("unknown message type %q", req.Type)})
+		}
+	}
+
+	cancel()
+	inflight.Wait()
+	// 先移出hub，之后不会再有通知写入send
+	hub.remove(c)
+	close(c.send)
+	<-writeDone
+}
+
+// wsRecoverable 读取消息的错误是否只影响这条消息，其余错误（连接关闭、读取失败）结束连接
+func wsRecoverable(err error) bool {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	return errors.Is(err, websocket.ErrFrameTooLarge) || errors.As(err, &syntaxErr) || errors.As(err, &typeErr)
+}
+
+// check 检查一条消息
+func (c *wsConn) check(ctx context.Context, g *guardian.Guardian, req *wsRequest) *wsMessage {
+	result, err := g.CheckContext(guardian.WithMetadata(ctx, req.Metadata), req.Text, req.Options)
+	if err != nil {
+		return &wsMessage{Type: wsTypeError, ID: req.ID, Error: fmt.Sprintf("check aborted: %v", err)}
+	}
+	return &wsMessage{Type: wsTypeResult, ID: req.ID, Result: result}
+}
+
+// reply 排队发送消息，连接关闭时放弃
+func (c *wsConn) reply(ctx context.Context, msg *wsMessage) {
+	select {
+	case c.send <- msg:
+	case <-ctx.Done():
+	}
+}
+
+// writeLoop 依次写出待发送的消息，写入失败时关闭连接
+func (c *wsConn) writeLoop(cancel context.CancelFunc) {
+	for msg := range c.send {
+		c.ws.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+		if err := websocket.JSON.Send(c.ws, msg); err != nil {
+			cancel()
+			c.ws.Close()
+			// 排空队列，避免发送方阻塞
+			for range c.send {
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// guardianctl Guardian运维命令行工具：检查运行中实例、查看统计、校验/比较/发布词库、压测词库
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// commands 子命令
+var commands = map[string]struct {
+	run   func(args []string) error
+	usage string
+}{
+	"check":    {runCheck, "检查文本，guardianctl check -text 文本（省略时读取标准输入）"},
+	"stats":    {runStats, "查看运行中实例的统计信息"},
+	"validate": {runValidate, "检查本地词库文件，有错误时退出码非0"},
+	"diff":     {runDiff, "比较两个词库，省略 -old 时与运行中实例的词库比较"},
+	"publish":  {runPublish, "通过管理接口发布词库文件"},
+	"bench":    {runBench, "压测词库的构建耗时和匹配吞吐"},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage(os.Stderr)
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		if os.Args[1] != "help" && os.Args[1] != "-h" && os.Args[1] != "--help" {
+			fmt.Fprintf(os.Stderr, "unknown command: %s\n\n", os.Args[1])
+		}
+		usage(os.Stderr)
+		os.Exit(2)
+	}
+
+	if err := cmd.run(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+// usage 输出帮助信息
+func usage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(w, "Usage: guardianctl <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-9s %s\n", name, commands[name].usage)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Environment:")
+	fmt.Fprintln(w, "  GUARDIAN_SERVER       运行中实例的地址，默认 http://localhost:8080")
+	fmt.Fprintln(w, "  GUARDIAN_ADMIN_TOKEN  管理接口令牌（admin.token）")
+}
+
+// readInput 读取文件内容，路径为 - 时读取标准输入
+func readInput(path string) ([]byte, error) {
+	if path == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read stdin: %w", err)
+		}
+		return data, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return data, nil
+}
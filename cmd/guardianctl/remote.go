@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// client 运行中Guardian实例的HTTP客户端
+type client struct {
+	server string
+	token  string
+	http   *http.Client
+}
+
+// serverFlags 注册连接运行中实例的公共参数
+func serverFlags(fs *flag.FlagSet) *client {
+	c := &client{http: &http.Client{Timeout: 30 * time.Second}}
+	fs.StringVar(&c.server, "server", envOr("GUARDIAN_SERVER", "http://localhost:8080"), "运行中实例的地址")
+	fs.StringVar(&c.token, "token", os.Getenv("GUARDIAN_ADMIN_TOKEN"), "管理接口令牌")
+	return c
+}
+
+// envOr 读取环境变量，未设置时返回默认值
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// do 发送请求并返回响应体，状态码不是2xx时返回错误
+func (c *client) do(method, path, contentType string, body io.Reader) ([]byte, error) {
+	req, err := http.NewRequest(method, strings.TrimRight(c.server, "/")+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response from %s: %w", path, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return data, fmt.Errorf("%s returned %s: %s", path, resp.Status, strings.TrimSpace(string(data)))
+	}
+	return data, nil
+}
+
+// wordDatabase 下载运行中实例的当前词库
+func (c *client) wordDatabase() (*types.WordDatabase, error) {
+	data, err := c.do(http.MethodGet, "/admin/words/export?format=json", "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var wordDB types.WordDatabase
+	if err := json.Unmarshal(data, &wordDB); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal word database: %w", err)
+	}
+	return &wordDB, nil
+}
+
+// printJSON 缩进输出JSON
+func printJSON(data []byte) error {
+	var out bytes.Buffer
+	if err := json.Indent(&out, data, "", "  "); err != nil {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	out.WriteByte('\n')
+	_, err := out.WriteTo(os.Stdout)
+	return err
+}
+
+// runCheck 检查文本，未通过时退出码为1
+//
+//	guardianctl check -text "文本" [-categories abuse,politics -min-level 3]
+//	echo "文本" | guardianctl check
+func runCheck(args []string) error {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	c := serverFlags(fs)
+	text := fs.String("text", "", "待检查文本，为空时读取标准输入")
+	categories := fs.String("categories", "", "只检查这些分类，逗号分隔")
+	minLevel := fs.Int("min-level", 0, "只检查不低于该级别的敏感词")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *text == "" {
+		data, err := readInput("-")
+		if err != nil {
+			return err
+		}
+		*text = strings.TrimRight(string(data), "\n")
+	}
+
+	request := map[string]interface{}{"text": *text}
+	if *categories != "" || *minLevel > 0 {
+		options := types.DefaultFilterOptions()
+		options.MinLevel = *minLevel
+		if *categories != "" {
+			options.Categories = strings.Split(*categories, ",")
+		}
+		request["options"] = options
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	data, err := c.do(http.MethodPost, "/check", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if err := printJSON(data); err != nil {
+		return err
+	}
+
+	var result types.FilterResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("failed to unmarshal result: %w", err)
+	}
+	if !result.Passed {
+		return fmt.Errorf("text blocked: %s", strings.Join(result.Words, ", "))
+	}
+	return nil
+}
+
+// runStats 查看统计信息
+//
+//	guardianctl stats
+func runStats(args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	c := serverFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	data, err := c.do(http.MethodGet, "/stats", "", nil)
+	if err != nil {
+		return err
+	}
+	return printJSON(data)
+}
+
+// runPublish 通过管理接口导入词库文件并发布，先校验再发布
+//
+//	guardianctl publish -input words.csv -mode replace [-validate-only]
+func runPublish(args []string) error {
+	fs := flag.NewFlagSet("publish", flag.ExitOnError)
+	c := serverFlags(fs)
+	input := fs.String("input", "", "词库文件路径，按扩展名和内容识别格式")
+	format := fs.String("format", "auto", "词表格式: auto|json|yaml|text|hanlp|tieba|csv")
+	mode := fs.String("mode", "merge", "merge（覆盖同名敏感词）| replace（替换全部敏感词）")
+	category := fs.String("category", "", "默认分类")
+	level := fs.Int("level", 0, "默认敏感级别")
+	validateOnly := fs.Bool("validate-only", false, "只返回检查报告，不发布")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *input == "" {
+		fs.Usage()
+		return fmt.Errorf("missing -input")
+	}
+
+	data, err := readInput(*input)
+	if err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filepath.Base(*input))
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(data); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	query := url.Values{"mode": {*mode}, "format": {*format}}
+	if *category != "" {
+		query.Set("category", *category)
+	}
+	if *level > 0 {
+		query.Set("level", fmt.Sprint(*level))
+	}
+	if *validateOnly {
+		query.Set("validate_only", "true")
+	}
+
+	data, err = c.do(http.MethodPost, "/admin/words/import?"+query.Encode(), writer.FormDataContentType(), &body)
+	if len(data) > 0 {
+		printJSON(data)
+	}
+	return err
+}
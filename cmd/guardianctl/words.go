@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/guardian/content-filter/internal/algorithm"
+	"github.com/guardian/content-filter/internal/normalize"
+	"github.com/guardian/content-filter/internal/types"
+	"github.com/guardian/content-filter/internal/wordlist"
+)
+
+// loadWordDatabase 读取并解析本地词库文件
+func loadWordDatabase(path, format string) (*types.WordDatabase, error) {
+	data, err := readInput(path)
+	if err != nil {
+		return nil, err
+	}
+
+	wordFormat := wordlist.DetectFormat(path, data)
+	if format != "" && format != "auto" {
+		if wordFormat, err = wordlist.ParseFormat(format); err != nil {
+			return nil, err
+		}
+	}
+
+	wordDB, err := wordlist.Parse(bytes.NewReader(data), wordFormat, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s word list %s: %w", wordFormat, path, err)
+	}
+	return wordDB, nil
+}
+
+// runValidate 检查本地词库文件，有错误（-strict 时有任何问题）时返回错误
+//
+//	guardianctl validate -input words.json [-strict] [-ignore short_word]
+func runValidate(args []string) error {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	input := fs.String("input", "", "词库文件路径（- 表示标准输入）")
+	format := fs.String("format", "auto", "词表格式: auto|json|yaml|text|hanlp|tieba|csv")
+	minLength := fs.Int("min-word-length", 0, "短于该字符数的敏感词报告short_word，默认2")
+	ignore := fs.String("ignore", "", "忽略的规则，逗号分隔")
+	strict := fs.Bool("strict", false, "有警告时也视为失败")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *input == "" {
+		fs.Usage()
+		return fmt.Errorf("missing -input")
+	}
+
+	wordDB, err := loadWordDatabase(*input, *format)
+	if err != nil {
+		return err
+	}
+
+	config := &types.LintConfig{MinWordLength: *minLength}
+	if *ignore != "" {
+		config.Ignore = strings.Split(*ignore, ",")
+	}
+	issues := wordlist.LintWithConfig(wordDB, config)
+
+	errors := 0
+	for _, issue := range issues {
+		if issue.Severity == types.LintError {
+			errors++
+		}
+		location := "blacklist"
+		if issue.Category != "" {
+			location = "categories." + issue.Category
+		}
+		fmt.Printf("%-7s %-13s %-20s %q: %s\n", issue.Severity, issue.Rule, location, issue.Word, issue.Message)
+	}
+	fmt.Printf("%s: version %s, %d words, %d error(s), %d warning(s)\n",
+		*input, wordDB.Version, len(wordDB.Words()), errors, len(issues)-errors)
+
+	if errors > 0 || *strict && len(issues) > 0 {
+		return fmt.Errorf("%s failed validation", *input)
+	}
+	return nil
+}
+
+// runDiff 比较两个词库
+//
+//	guardianctl diff -old v1.json -new v2.json
+//	guardianctl diff -new v2.json        # 与运行中实例的词库比较
+func runDiff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	c := serverFlags(fs)
+	oldPath := fs.String("old", "", "旧词库文件路径，为空时下载运行中实例的词库")
+	newPath := fs.String("new", "", "新词库文件路径")
+	format := fs.String("format", "auto", "词表格式: auto|json|yaml|text|hanlp|tieba|csv")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *newPath == "" {
+		fs.Usage()
+		return fmt.Errorf("missing -new")
+	}
+
+	var (
+		old *types.WordDatabase
+		err error
+	)
+	if *oldPath != "" {
+		old, err = loadWordDatabase(*oldPath, *format)
+	} else {
+		old, err = c.wordDatabase()
+	}
+	if err != nil {
+		return err
+	}
+	new, err := loadWordDatabase(*newPath, *format)
+	if err != nil {
+		return err
+	}
+
+	diff := wordlist.Diff(old, new)
+	fmt.Printf("version %s -> %s\n", diff.FromVersion, diff.ToVersion)
+	for _, word := range diff.Added {
+		fmt.Printf("+ %s\n", word)
+	}
+	for _, word := range diff.Removed {
+		fmt.Printf("- %s\n", word)
+	}
+	for _, change := range diff.LevelChanges {
+		fmt.Printf("~ %s level %d -> %d\n", change.Word, change.From, change.To)
+	}
+	for _, entry := range diff.WhitelistAdded {
+		fmt.Printf("+ whitelist %s\n", entry)
+	}
+	for _, entry := range diff.WhitelistRemoved {
+		fmt.Printf("- whitelist %s\n", entry)
+	}
+	fmt.Printf("%d added, %d removed, %d level changes, whitelist %d added, %d removed\n",
+		len(diff.Added), len(diff.Removed), len(diff.LevelChanges), len(diff.WhitelistAdded), len(diff.WhitelistRemoved))
+	return nil
+}
+
+// runBench 压测词库：构建自动机并对样本文本反复匹配，输出构建耗时、内存估算和匹配吞吐
+//
+//	guardianctl bench -input words.json -texts samples.txt [-duration 10s] [-layout flat]
+func runBench(args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	input := fs.String("input", "", "词库文件路径")
+	format := fs.String("format", "auto", "词表格式: auto|json|yaml|text|hanlp|tieba|csv")
+	textsPath := fs.String("texts", "", "样本文本文件，每行一条")
+	duration := fs.Duration("duration", 5*time.Second, "匹配压测时长")
+	layout := fs.String("layout", types.LayoutMap, "自动机布局: map|flat")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *input == "" || *textsPath == "" {
+		fs.Usage()
+		return fmt.Errorf("missing -input or -texts")
+	}
+
+	wordDB, err := loadWordDatabase(*input, *format)
+	if err != nil {
+		return err
+	}
+	texts, err := readLines(*textsPath)
+	if err != nil {
+		return err
+	}
+	if len(texts) == 0 {
+		return fmt.Errorf("%s contains no texts", *textsPath)
+	}
+
+	normalizer := normalize.New(&types.NormalizeConfig{})
+	sensitiveWords := wordDB.Words()
+	entries := make([]algorithm.WordEntry, 0, len(sensitiveWords))
+	for _, word := range sensitiveWords {
+		entries = append(entries, algorithm.WordEntry{Word: normalizer.Normalize(word.Word), Categories: word.Categories, Level: word.Level, Pinyin: word.Pinyin, Fuzzy: word.Fuzzy})
+	}
+
+	start := time.Now()
+	var matcher algorithm.Automaton
+	if *layout == types.LayoutFlat {
+		matcher, err = algorithm.BuildFlat(entries, nil)
+	} else {
+		matcher, err = algorithm.Build(entries, nil)
+	}
+	if err != nil {
+		return err
+	}
+	buildTime := time.Since(start)
+	fmt.Printf("build: %d words, %d nodes, ~%.1f MB, %v (layout %s)\n",
+		len(entries), matcher.GetNodeCount(), float64(matcher.EstimateMemory())/(1<<20), buildTime, *layout)
+
+	normalized := make([]string, len(texts))
+	for i, text := range texts {
+		normalized[i] = normalizer.Normalize(text)
+	}
+
+	options := &algorithm.SearchOptions{MinLevel: 1}
+	var checks, scanned, matched int
+	deadline := time.Now().Add(*duration)
+	start = time.Now()
+	for time.Now().Before(deadline) {
+		for _, text := range normalized {
+			if len(matcher.FindAll(text, options)) > 0 {
+				matched++
+			}
+			scanned += len(text)
+		}
+		checks += len(normalized)
+	}
+	elapsed := time.Since(start)
+
+	fmt.Printf("match: %d checks in %v, %.0f checks/s, %.1f MB/s, %.2f µs/check, %.1f%% matched\n",
+		checks, elapsed.Round(time.Millisecond), float64(checks)/elapsed.Seconds(),
+		float64(scanned)/(1<<20)/elapsed.Seconds(), float64(elapsed.Microseconds())/float64(checks),
+		float64(matched)*100/float64(checks))
+	return nil
+}
+
+// readLines 读取文件的非空行
+func readLines(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	lines := make([]string, 0)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return lines, nil
+}
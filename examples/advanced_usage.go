@@ -64,7 +64,10 @@ func main() {
 	if !result.Passed {
 		fmt.Printf("匹配词: %v\n", result.Words)
 		fmt.Printf("分类: %v\n", result.Categories)
-		fmt.Printf("详情: %v\n", result.Details)
+		for _, match := range result.Matches {
+			fmt.Printf("详情: %s 级别:%d 分类:%v 次数:%d 位置:%v\n",
+				match.Word, match.Level, match.Categories, match.Count, match.Positions)
+		}
 	}
 
 	// 动态添加白名单
@@ -154,10 +157,11 @@ func main() {
 	// 获取最终统计信息
 	fmt.Println("\n=== 最终统计信息 ===")
 	stats := g.GetStats()
-	fmt.Printf("版本: %v\n", stats["version"])
-	fmt.Printf("节点数: %v\n", stats["node_count"])
-	fmt.Printf("白名单大小: %v\n", stats["whitelist_size"])
-	if cacheStats, ok := stats["cache_stats"]; ok {
-		fmt.Printf("缓存统计: %v\n", cacheStats)
+	fmt.Printf("版本: %v\n", stats.Version)
+	fmt.Printf("节点数: %v\n", stats.NodeCount)
+	fmt.Printf("白名单大小: %v\n", stats.WhitelistSize)
+	fmt.Printf("拦截率: %.2f%%, 平均耗时: %.3fms\n", stats.BlockRate*100, stats.Latency.AvgMs)
+	if stats.Cache != nil {
+		fmt.Printf("缓存统计: %v\n", stats.Cache)
 	}
 }
@@ -0,0 +1,103 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// defaultTimeout 告警回调的默认超时
+const defaultTimeout = 5 * time.Second
+
+// Notifier 告警的发送方式
+type Notifier interface {
+	// Notify 发送一条告警
+	Notify(ctx context.Context, alert *types.Alert) error
+}
+
+// WebhookNotifier 将告警以JSON POST到指定地址，非2xx响应视为失败
+type WebhookNotifier struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+// NewWebhookNotifier 创建告警回调
+func NewWebhookNotifier(endpoint string, headers map[string]string) *WebhookNotifier {
+	return &WebhookNotifier{url: endpoint, headers: headers, client: &http.Client{Timeout: defaultTimeout}}
+}
+
+// Notify 发送告警
+func (n *WebhookNotifier) Notify(ctx context.Context, alert *types.Alert) error {
+	_, err := post(ctx, n.client, n.url, n.headers, alert)
+	return err
+}
+
+// Join 将多个告警通道合并为一个，依次发送并合并错误；忽略nil，全部为nil时返回nil
+func Join(notifiers ...Notifier) Notifier {
+	var joined multiNotifier
+	for _, n := range notifiers {
+		if n != nil {
+			joined = append(joined, n)
+		}
+	}
+	switch len(joined) {
+	case 0:
+		return nil
+	case 1:
+		return joined[0]
+	default:
+		return joined
+	}
+}
+
+// multiNotifier 依次发送到多个告警通道
+type multiNotifier []Notifier
+
+// Notify 发送告警，单个通道失败不影响其他通道
+func (m multiNotifier) Notify(ctx context.Context, alert *types.Alert) error {
+	var errs []error
+	for _, n := range m {
+		if err := n.Notify(ctx, alert); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// post 将body以JSON POST到指定地址，返回响应内容，非2xx响应视为失败
+func post(ctx context.Context, client *http.Client, endpoint string, headers map[string]string, body interface{}) ([]byte, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal alert: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send alert: %w", err)
+	}
+	defer resp.Body.Close()
+
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("alert webhook returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return msg, nil
+}
@@ -0,0 +1,170 @@
+package alert
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// levelNames 告警级别的显示名称
+var levelNames = map[string]string{
+	types.AlertWarning:  "警告",
+	types.AlertCritical: "严重",
+	types.AlertResolved: "恢复",
+}
+
+// typeNames 告警类型的显示名称
+var typeNames = map[string]string{
+	types.AlertBlockRateAnomaly: "拦截率突变",
+	types.AlertReloadFailed:     "词库重载失败",
+	types.AlertDictionaryStale:  "词库过期",
+}
+
+// New 按配置创建告警通道
+func New(config *types.AlertNotifierConfig) (Notifier, error) {
+	client := &http.Client{Timeout: defaultTimeout}
+	switch config.Type {
+	case "", types.AlertNotifierWebhook:
+		return NewWebhookNotifier(config.URL, config.Headers), nil
+	case types.AlertNotifierDingTalk:
+		return &robotNotifier{url: config.URL, secret: config.Secret, client: client, sign: dingTalkSign, message: dingTalkMessage}, nil
+	case types.AlertNotifierFeishu:
+		return &robotNotifier{url: config.URL, secret: config.Secret, client: client, sign: feishuSign, message: feishuMessage}, nil
+	case types.AlertNotifierWeCom:
+		return &robotNotifier{url: config.URL, client: client, message: weComMessage}, nil
+	default:
+		return nil, fmt.Errorf("unsupported alert notifier type: %s", config.Type)
+	}
+}
+
+// robotNotifier 将告警以文本消息推送到钉钉、飞书或企业微信群机器人
+type robotNotifier struct {
+	url     string
+	secret  string // 加签密钥，为空时不签名
+	client  *http.Client
+	sign    func(endpoint, secret string, now time.Time) (string, map[string]interface{}, error) // 加签，返回请求地址和需要加入消息体的字段
+	message func(text string) map[string]interface{}                                             // 文本消息体
+}
+
+// robotResponse 机器人接口的响应，钉钉和企业微信使用 errcode，飞书使用 code
+type robotResponse struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+	Code    int    `json:"code"`
+	Msg     string `json:"msg"`
+}
+
+// Notify 发送告警，机器人返回非0错误码视为失败
+func (n *robotNotifier) Notify(ctx context.Context, alert *types.Alert) error {
+	endpoint := n.url
+	body := n.message(FormatText(alert))
+	if n.secret != "" && n.sign != nil {
+		signed, fields, err := n.sign(endpoint, n.secret, time.Now())
+		if err != nil {
+			return err
+		}
+		endpoint = signed
+		for key, value := range fields {
+			body[key] = value
+		}
+	}
+
+	data, err := post(ctx, n.client, endpoint, nil, body)
+	if err != nil {
+		return err
+	}
+
+	var resp robotResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return fmt.Errorf("failed to decode robot response: %w", err)
+	}
+	if resp.ErrCode != 0 {
+		return fmt.Errorf("robot returned error %d: %s", resp.ErrCode, resp.ErrMsg)
+	}
+	if resp.Code != 0 {
+		return fmt.Errorf("robot returned error %d: %s", resp.Code, resp.Msg)
+	}
+	return nil
+}
+
+// FormatText 将告警格式化为面向人的多行文本
+func FormatText(alert *types.Alert) string {
+	level := levelNames[alert.Level]
+	if level == "" {
+		level = alert.Level
+	}
+	title := typeNames[alert.Type]
+	if title == "" {
+		title = alert.Type
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "【%s】%s\n%s", level, title, alert.Message)
+	if alert.Version != "" {
+		fmt.Fprintf(&b, "\n词库版本: %s", alert.Version)
+	}
+	fmt.Fprintf(&b, "\n时间: %s", alert.Time.Format("2006-01-02 15:04:05"))
+	return b.String()
+}
+
+// dingTalkMessage 钉钉文本消息
+func dingTalkMessage(text string) map[string]interface{} {
+	return map[string]interface{}{
+		"msgtype": "text",
+		"text":    map[string]string{"content": text},
+	}
+}
+
+// dingTalkSign 钉钉加签，签名和时间戳（毫秒）作为查询参数
+func dingTalkSign(endpoint, secret string, now time.Time) (string, map[string]interface{}, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to parse robot url: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(now.UnixMilli(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + secret))
+
+	query := u.Query()
+	query.Set("timestamp", timestamp)
+	query.Set("sign", base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	u.RawQuery = query.Encode()
+	return u.String(), nil, nil
+}
+
+// feishuMessage 飞书文本消息
+func feishuMessage(text string) map[string]interface{} {
+	return map[string]interface{}{
+		"msg_type": "text",
+		"content":  map[string]string{"text": text},
+	}
+}
+
+// feishuSign 飞书加签，签名和时间戳（秒）放在消息体中
+func feishuSign(endpoint, secret string, now time.Time) (string, map[string]interface{}, error) {
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(timestamp+"\n"+secret))
+	return endpoint, map[string]interface{}{
+		"timestamp": timestamp,
+		"sign":      base64.StdEncoding.EncodeToString(mac.Sum(nil)),
+	}, nil
+}
+
+// weComMessage 企业微信文本消息，企业微信机器人不支持加签
+func weComMessage(text string) map[string]interface{} {
+	return map[string]interface{}{
+		"msgtype": "text",
+		"text":    map[string]string{"content": text},
+	}
+}
@@ -0,0 +1,81 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+func testAlert() *types.Alert {
+	return &types.Alert{
+		Type:    types.AlertReloadFailed,
+		Level:   types.AlertCritical,
+		Time:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local),
+		Message: "词库连续重载失败 3 次",
+		Version: "1.0.3",
+	}
+}
+
+func TestFormatText(t *testing.T) {
+	got := FormatText(testAlert())
+	want := "【严重】词库重载失败\n词库连续重载失败 3 次\n词库版本: 1.0.3\n时间: 2024-01-02 03:04:05"
+	if got != want {
+		t.Errorf("FormatText() = %q, want %q", got, want)
+	}
+}
+
+func TestDingTalkNotifier(t *testing.T) {
+	var query map[string][]string
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	defer server.Close()
+
+	n, err := New(&types.AlertNotifierConfig{Type: types.AlertNotifierDingTalk, URL: server.URL + "?access_token=abc", Secret: "SEC"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := n.Notify(context.Background(), testAlert()); err != nil {
+		t.Fatalf("Notify() error = %v", err)
+	}
+
+	for _, key := range []string{"access_token", "timestamp", "sign"} {
+		if len(query[key]) != 1 {
+			t.Errorf("query %s = %v, want one value", key, query[key])
+		}
+	}
+	text, _ := body["text"].(map[string]interface{})
+	if content, _ := text["content"].(string); !strings.HasPrefix(content, "【严重】") {
+		t.Errorf("content = %q, want formatted alert", content)
+	}
+}
+
+func TestRobotNotifierError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":19021,"msg":"sign match fail"}`))
+	}))
+	defer server.Close()
+
+	n, err := New(&types.AlertNotifierConfig{Type: types.AlertNotifierFeishu, URL: server.URL, Secret: "SEC"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if err := n.Notify(context.Background(), testAlert()); err == nil || !strings.Contains(err.Error(), "19021") {
+		t.Errorf("Notify() error = %v, want robot error code", err)
+	}
+}
+
+func TestNewUnsupported(t *testing.T) {
+	if _, err := New(&types.AlertNotifierConfig{Type: "slack", URL: "http://example.com"}); err == nil {
+		t.Error("New() error = nil, want unsupported type")
+	}
+}
@@ -1,8 +1,11 @@
 package algorithm
 
 import (
+	"context"
 	"sync"
 	"unicode/utf8"
+
+	"github.com/guardian/content-filter/internal/normalize"
 )
 
 // ACNode AC自动机节点
@@ -22,9 +25,13 @@ type Output struct {
 
 // ACAutomaton AC自动机
 type ACAutomaton struct {
-	root    *ACNode
-	mu      sync.RWMutex
-	version string
+	root        *ACNode
+	mu          sync.RWMutex
+	version     string
+	pinyinWords []WordEntry    // 启用拼音匹配的敏感词
+	pinyin      *PinyinMatcher // 拼音匹配器，BuildFailPointers时构建
+	fuzzyWords  []WordEntry    // 启用编辑距离匹配的敏感词
+	fuzzy       *FuzzyMatcher  // 编辑距离匹配器，BuildFailPointers时构建
 }
 
 // NewACAutomaton 创建新的AC自动机
@@ -42,10 +49,48 @@ func (ac *ACAutomaton) AddWord(word string, categories []string, level int) {
 	ac.mu.Lock()
 	defer ac.mu.Unlock()
 
+	ac.addWord(word, categories, level)
+}
+
+// AddPinyinWord 添加敏感词并启用拼音匹配，例如 "minganci"、"min gan ci" 均可命中 "敏感词"
+// 拼音匹配开销较大且容易误判，只应对必要的词语启用
+func (ac *ACAutomaton) AddPinyinWord(word string, categories []string, level int) {
+	ac.AddWordEntry(WordEntry{Word: word, Categories: categories, Level: level, Pinyin: true})
+}
+
+// AddFuzzyWord 添加敏感词并启用编辑距离匹配，替换、缺失或多出一个字符的变体也会命中，如 "fuk"、"fxck" 命中 "fuck"
+// 只对不少于 MinFuzzyWordLength 个字符的词语生效，应只对高敏感级别的词语启用
+func (ac *ACAutomaton) AddFuzzyWord(word string, categories []string, level int) {
+	ac.AddWordEntry(WordEntry{Word: word, Categories: categories, Level: level, Fuzzy: true})
+}
+
+// AddWordEntry 添加敏感词，按词语设置启用拼音匹配和编辑距离匹配
+func (ac *ACAutomaton) AddWordEntry(entry WordEntry) {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
+	ac.addEntry(entry)
+}
+
+// addEntry 添加敏感词并记录其匹配方式，返回新建的节点数，调用方需持有写锁
+func (ac *ACAutomaton) addEntry(entry WordEntry) int {
+	created := ac.addWord(entry.Word, entry.Categories, entry.Level)
+	if entry.Pinyin {
+		ac.pinyinWords = append(ac.pinyinWords, entry)
+	}
+	if entry.Fuzzy {
+		ac.fuzzyWords = append(ac.fuzzyWords, entry)
+	}
+	return created
+}
+
+// addWord 添加敏感词，返回新建的节点数，调用方需持有写锁
+func (ac *ACAutomaton) addWord(word string, categories []string, level int) int {
 	if word == "" {
-		return
+		return 0
 	}
 
+	created := 0
 	node := ac.root
 	for _, char := range word {
 		if node.children[char] == nil {
@@ -53,6 +98,7 @@ func (ac *ACAutomaton) AddWord(word string, categories []string, level int) {
 				children: make(map[rune]*ACNode),
 				output:   make([]*Output, 0),
 			}
+			created++
 		}
 		node = node.children[char]
 	}
@@ -64,6 +110,8 @@ func (ac *ACAutomaton) AddWord(word string, categories []string, level int) {
 		Level:      level,
 	}
 	node.output = append(node.output, output)
+
+	return created
 }
 
 // BuildFailPointers 构建失败指针
@@ -104,6 +152,9 @@ func (ac *ACAutomaton) BuildFailPointers() {
 			}
 		}
 	}
+
+	ac.pinyin = NewPinyinMatcher(ac.pinyinWords)
+	ac.fuzzy = NewFuzzyMatcher(ac.fuzzyWords)
 }
 
 // Search 搜索敏感词
@@ -156,7 +207,7 @@ func (ac *ACAutomaton) SearchWithOptions(text string, options *SearchOptions) []
 		// 收集输出
 		if len(node.output) > 0 {
 			for _, output := range node.output {
-				if ac.matchesOptions(output, options) {
+				if matchesOptions(output, options) {
 					results = append(results, output)
 				}
 			}
@@ -166,8 +217,85 @@ func (ac *ACAutomaton) SearchWithOptions(text string, options *SearchOptions) []
 	return results
 }
 
+// Match 带位置的匹配结果
+type Match struct {
+	*Output
+	Start int // 起始字节偏移
+	End   int // 结束字节偏移（不含）
+}
+
+// FindAll 带选项搜索，返回每一次命中及其在文本中的字节位置
+func (ac *ACAutomaton) FindAll(text string, options *SearchOptions) []Match {
+	matches, _ := ac.FindAllContext(context.Background(), text, options)
+	return matches
+}
+
+// ctxCheckInterval 检查上下文是否取消的间隔（字符数）
+const ctxCheckInterval = 4096
+
+// FindAllContext 与FindAll相同，但会定期检查上下文，取消或超时时返回上下文错误
+func (ac *ACAutomaton) FindAllContext(ctx context.Context, text string, options *SearchOptions) ([]Match, error) {
+	ac.mu.RLock()
+	defer ac.mu.RUnlock()
+
+	results := make([]Match, 0)
+	node := ac.root
+	scanned := 0
+
+	for i, char := range text {
+		scanned++
+		if scanned%ctxCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+
+		for node.children[char] == nil && node != ac.root {
+			node = node.fail
+		}
+
+		if node.children[char] != nil {
+			node = node.children[char]
+		}
+
+		if len(node.output) == 0 {
+			continue
+		}
+
+		_, size := utf8.DecodeRuneInString(text[i:])
+		end := i + size
+		for _, output := range node.output {
+			if matchesOptions(output, options) {
+				results = append(results, Match{
+					Output: output,
+					Start:  end - len(output.Word),
+					End:    end,
+				})
+			}
+		}
+	}
+
+	if ac.pinyin != nil {
+		pinyinMatches, err := ac.pinyin.FindAllContext(ctx, text, options)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, pinyinMatches...)
+	}
+
+	if ac.fuzzy != nil {
+		fuzzyMatches, err := ac.fuzzy.FindAllContext(ctx, text, options)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, fuzzyMatches...)
+	}
+
+	return results, nil
+}
+
 // matchesOptions 检查输出是否匹配选项
-func (ac *ACAutomaton) matchesOptions(output *Output, options *SearchOptions) bool {
+func matchesOptions(output *Output, options *SearchOptions) bool {
 	// 检查敏感级别
 	if output.Level < options.MinLevel {
 		return false
@@ -205,6 +333,10 @@ func (ac *ACAutomaton) Clear() {
 		output:   make([]*Output, 0),
 	}
 	ac.version = ""
+	ac.pinyinWords = nil
+	ac.pinyin = nil
+	ac.fuzzyWords = nil
+	ac.fuzzy = nil
 }
 
 // GetVersion 获取版本
@@ -228,6 +360,44 @@ func (ac *ACAutomaton) GetNodeCount() int {
 	return ac.countNodes(ac.root)
 }
 
+// FirstChars 返回所有敏感词首字符的集合；启用了拼音匹配时任何字母和汉字都可能命中，返回nil
+func (ac *ACAutomaton) FirstChars() *CharSet {
+	ac.mu.RLock()
+	defer ac.mu.RUnlock()
+
+	if ac.pinyin != nil {
+		return nil
+	}
+
+	set := NewCharSet()
+	for char := range ac.root.children {
+		set.Add(char)
+	}
+	ac.fuzzy.addFirstChars(set)
+	return set
+}
+
+// MaxMatchLen 返回一次命中在文本中可能跨越的最大字节数，用于分块扫描时确定块间重叠
+func (ac *ACAutomaton) MaxMatchLen() int {
+	ac.mu.RLock()
+	defer ac.mu.RUnlock()
+
+	maxLen := 0
+	queue := []*ACNode{ac.root}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, output := range node.output {
+			maxLen = max(maxLen, len(output.Word))
+		}
+		for _, child := range node.children {
+			queue = append(queue, child)
+		}
+	}
+
+	return max(maxLen, ac.pinyin.maxMatchLen(), ac.fuzzy.maxMatchLen())
+}
+
 // countNodes 递归计算节点数量
 func (ac *ACAutomaton) countNodes(node *ACNode) int {
 	count := 0
@@ -243,11 +413,32 @@ type SearchOptions struct {
 	MinLevel   int      // 最小敏感级别
 }
 
-// FuzzySearch 模糊搜索（支持拼音、简繁转换等）
+// Allows 输出是否满足搜索选项，选项为nil时全部满足
+func (o *SearchOptions) Allows(output *Output) bool {
+	return o == nil || matchesOptions(output, o)
+}
+
+// FuzzySearch 模糊搜索，在精确匹配之外对启用了拼音匹配的敏感词进行拼音匹配，对启用了编辑距离匹配的敏感词进行编辑距离匹配
 func (ac *ACAutomaton) FuzzySearch(text string, options *SearchOptions) []*Output {
-	// 这里可以实现更复杂的模糊匹配逻辑
-	// 比如拼音匹配、简繁转换、同音字替换等
-	return ac.SearchWithOptions(text, options)
+	results := ac.SearchWithOptions(text, options)
+
+	ac.mu.RLock()
+	pm, fm := ac.pinyin, ac.fuzzy
+	ac.mu.RUnlock()
+
+	if pm != nil {
+		matches, _ := pm.FindAllContext(context.Background(), text, options)
+		for _, match := range matches {
+			results = append(results, match.Output)
+		}
+	}
+	if fm != nil {
+		matches, _ := fm.FindAllContext(context.Background(), text, options)
+		for _, match := range matches {
+			results = append(results, match.Output)
+		}
+	}
+	return results
 }
 
 // GetWordLength 获取字符长度（支持中文）
@@ -255,9 +446,49 @@ func GetWordLength(word string) int {
 	return utf8.RuneCountInString(word)
 }
 
-// NormalizeText 标准化文本
+// NormalizeText 标准化文本：NFKC标准化、全角转半角、Unicode大小写折叠
+// 需要将匹配位置还原到原文时使用 normalize.Fold
 func NormalizeText(text string) string {
-	// 这里可以实现文本标准化逻辑
-	// 比如去除特殊字符、统一大小写等
-	return text
+	return normalize.Fold(text).String()
+}
+
+// Position 命中位置（字节偏移）
+type Position struct {
+	Start int // 起始字节偏移
+	End   int // 结束字节偏移（不含）
+}
+
+// WordHit 去重后的命中词，同一敏感词的多次命中合并为一条
+type WordHit struct {
+	*Output
+	Count     int        // 命中次数
+	Positions []Position // 每次命中的位置，按出现顺序
+}
+
+// GroupMatches 按敏感词合并匹配结果，保持首次出现的顺序
+func GroupMatches(matches []Match) []WordHit {
+	index := make(map[*Output]int, len(matches))
+	hits := make([]WordHit, 0, len(matches))
+
+	for _, match := range matches {
+		position := Position{Start: match.Start, End: match.End}
+		if i, ok := index[match.Output]; ok {
+			hits[i].Count++
+			hits[i].Positions = append(hits[i].Positions, position)
+			continue
+		}
+		index[match.Output] = len(hits)
+		hits = append(hits, WordHit{
+			Output:    match.Output,
+			Count:     1,
+			Positions: []Position{position},
+		})
+	}
+
+	return hits
+}
+
+// SearchUnique 带选项搜索，返回去重后的命中词及其出现次数和位置
+func (ac *ACAutomaton) SearchUnique(text string, options *SearchOptions) []WordHit {
+	return GroupMatches(ac.FindAll(text, options))
 }
@@ -1,6 +1,11 @@
 package algorithm
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
 	"testing"
 )
 
@@ -87,6 +92,359 @@ func TestACAutomatonClear(t *testing.T) {
 	}
 }
 
+func TestACAutomatonMarshalBinary(t *testing.T) {
+	ac := NewACAutomaton()
+	ac.AddWord("敏感词1", []string{"abuse"}, 3)
+	ac.AddWord("敏感词2", []string{"politics"}, 4)
+	ac.AddWord("感词", []string{"test"}, 1)
+	ac.BuildFailPointers()
+	ac.SetVersion("1.0.0")
+
+	data, err := ac.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+
+	loaded := NewACAutomaton()
+	if err := loaded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+
+	if loaded.GetVersion() != "1.0.0" {
+		t.Errorf("Expected version 1.0.0, got %s", loaded.GetVersion())
+	}
+
+	text := "敏感词1和敏感词2"
+	if got, expected := len(loaded.Search(text)), len(ac.Search(text)); got != expected {
+		t.Errorf("Loaded automaton found %d matches, expected %d", got, expected)
+	}
+
+	if err := loaded.UnmarshalBinary(data[:len(data)/2]); err == nil {
+		t.Errorf("UnmarshalBinary should fail on truncated data")
+	}
+}
+
+func TestACAutomatonFindAllContext(t *testing.T) {
+	ac := NewACAutomaton()
+	ac.AddWord("敏感词", []string{"abuse"}, 3)
+	ac.BuildFailPointers()
+
+	text := strings.Repeat("正常文本", ctxCheckInterval) + "敏感词"
+	options := &SearchOptions{MinLevel: 1}
+
+	matches, err := ac.FindAllContext(context.Background(), text, options)
+	if err != nil || len(matches) != 1 {
+		t.Fatalf("FindAllContext = %d matches, %v; expected 1 match", len(matches), err)
+	}
+	if got := text[matches[0].Start:matches[0].End]; got != "敏感词" {
+		t.Errorf("Match position points to %q, expected 敏感词", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ac.FindAllContext(ctx, text, options); err != context.Canceled {
+		t.Errorf("FindAllContext with cancelled context returned %v, expected context.Canceled", err)
+	}
+}
+
+func TestACAutomatonSearchUnique(t *testing.T) {
+	ac := NewACAutomaton()
+	ac.AddWord("敏感词", []string{"abuse"}, 3)
+	ac.AddWord("辱骂", []string{"abuse"}, 4)
+	ac.BuildFailPointers()
+
+	text := "敏感词，辱骂，敏感词，敏感词"
+	hits := ac.SearchUnique(text, &SearchOptions{MinLevel: 1})
+	if len(hits) != 2 {
+		t.Fatalf("SearchUnique returned %d hits, expected 2", len(hits))
+	}
+
+	if hits[0].Word != "敏感词" || hits[0].Count != 3 || len(hits[0].Positions) != 3 {
+		t.Errorf("hits[0] = %s x%d, expected 敏感词 x3", hits[0].Word, hits[0].Count)
+	}
+	if hits[1].Word != "辱骂" || hits[1].Count != 1 {
+		t.Errorf("hits[1] = %s x%d, expected 辱骂 x1", hits[1].Word, hits[1].Count)
+	}
+	for _, position := range hits[0].Positions {
+		if got := text[position.Start:position.End]; got != "敏感词" {
+			t.Errorf("Position points to %q, expected 敏感词", got)
+		}
+	}
+}
+
+func TestFlatAutomaton(t *testing.T) {
+	ac := NewACAutomaton()
+	ac.AddWord("he", []string{"test"}, 1)
+	ac.AddWord("she", []string{"test"}, 2)
+	ac.AddWord("his", []string{"test"}, 3)
+	ac.AddWord("hers", []string{"test"}, 4)
+	ac.AddWord("敏感词", []string{"abuse"}, 3)
+	ac.AddWord("感词", []string{"abuse"}, 5)
+	ac.AddWord("😀笑", []string{"emoji"}, 2)
+	ac.BuildFailPointers()
+	ac.SetVersion("v1")
+
+	fa := NewFlatAutomaton(ac)
+	if fa.GetNodeCount() != ac.GetNodeCount() || fa.GetVersion() != "v1" {
+		t.Fatalf("FlatAutomaton nodes=%d version=%s, expected nodes=%d version=v1",
+			fa.GetNodeCount(), fa.GetVersion(), ac.GetNodeCount())
+	}
+
+	texts := []string{"ushers", "ahishers", "这是敏感词和感词", "😀笑😀", "正常文本"}
+	options := []*SearchOptions{{MinLevel: 1}, {MinLevel: 3}, {Categories: []string{"abuse"}}}
+	for _, text := range texts {
+		for _, option := range options {
+			expected := ac.FindAll(text, option)
+			got := fa.FindAll(text, option)
+			if len(got) != len(expected) {
+				t.Fatalf("FindAll(%q) = %d matches, expected %d", text, len(got), len(expected))
+			}
+			for i := range got {
+				if got[i].Word != expected[i].Word || got[i].Start != expected[i].Start || got[i].End != expected[i].End {
+					t.Errorf("FindAll(%q)[%d] = %+v, expected %+v", text, i, got[i], expected[i])
+				}
+			}
+		}
+	}
+}
+
+func TestBuildFlat(t *testing.T) {
+	entries := []WordEntry{
+		{Word: "he", Categories: []string{"test"}, Level: 1},
+		{Word: "she", Categories: []string{"test"}, Level: 2},
+		{Word: "his", Categories: []string{"test"}, Level: 3},
+		{Word: "hers", Categories: []string{"test"}, Level: 4},
+		{Word: "he", Categories: []string{"dup"}, Level: 5},
+		{Word: ""},
+		{Word: "敏感词", Categories: []string{"abuse"}, Level: 3, Pinyin: true},
+		{Word: "感词", Categories: []string{"abuse"}, Level: 5},
+		{Word: "😀笑", Categories: []string{"emoji"}, Level: 2},
+		{Word: "fuck", Categories: []string{"abuse"}, Level: 5, Fuzzy: true},
+	}
+	ac, err := Build(entries, nil)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	expected := NewFlatAutomaton(ac)
+	built, err := BuildFlat(entries, nil)
+	if err != nil {
+		t.Fatalf("BuildFlat failed: %v", err)
+	}
+
+	// 直接构建、从两种布局序列化结果恢复的紧凑布局与转换结果一致
+	flatData, err := built.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+	acData, err := ac.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+	fromFlat, err := UnmarshalFlat(flatData)
+	if err != nil {
+		t.Fatalf("UnmarshalFlat failed: %v", err)
+	}
+	fromAC, err := UnmarshalFlat(acData)
+	if err != nil {
+		t.Fatalf("UnmarshalFlat failed: %v", err)
+	}
+	restored := NewACAutomaton()
+	if err := restored.UnmarshalBinary(flatData); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+
+	texts := []string{"ushers", "ahishers", "这是敏感词和感词", "😀笑😀", "minganci", "what the fxck", "正常文本"}
+	options := []*SearchOptions{{MinLevel: 1}, {MinLevel: 3}, {Categories: []string{"abuse"}}}
+	for name, matcher := range map[string]Matcher{"BuildFlat": built, "UnmarshalFlat(flat)": fromFlat, "UnmarshalFlat(map)": fromAC, "UnmarshalBinary(flat)": restored} {
+		if matcher.GetNodeCount() != expected.GetNodeCount() {
+			t.Errorf("%s nodes = %d, expected %d", name, matcher.GetNodeCount(), expected.GetNodeCount())
+		}
+		for _, text := range texts {
+			for _, option := range options {
+				want := expected.FindAll(text, option)
+				got := matcher.FindAll(text, option)
+				if len(got) != len(want) {
+					t.Fatalf("%s FindAll(%q) = %d matches, expected %d", name, text, len(got), len(want))
+				}
+				for i := range got {
+					if got[i].Word != want[i].Word || got[i].Level != want[i].Level || got[i].Start != want[i].Start || got[i].End != want[i].End {
+						t.Errorf("%s FindAll(%q)[%d] = %+v, expected %+v", name, text, i, got[i], want[i])
+					}
+				}
+			}
+		}
+	}
+
+	if _, err := BuildFlat(entries, &BuildOptions{MemoryBudget: 1024}); !errors.Is(err, ErrMemoryBudgetExceeded) {
+		t.Errorf("BuildFlat over budget error = %v, expected ErrMemoryBudgetExceeded", err)
+	}
+	if estimated := built.EstimateMemory(); estimated >= ac.EstimateMemory()+rootTableBytes {
+		t.Errorf("flat EstimateMemory = %d, expected less than map layout %d plus root table", estimated, ac.EstimateMemory())
+	}
+}
+
+func TestACAutomatonPinyin(t *testing.T) {
+	ac := NewACAutomaton()
+	ac.AddPinyinWord("敏感词", []string{"abuse"}, 3)
+	ac.AddWord("辱骂", []string{"abuse"}, 3)
+	ac.BuildFailPointers()
+
+	if key := PinyinKey("敏感词"); key != "minganci" {
+		t.Fatalf("PinyinKey(敏感词) = %q, expected minganci", key)
+	}
+
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"这里有minganci", "minganci"},
+		{"这里有 Min Gan Ci 哦", "Min Gan Ci"},
+		{"这里有min-gan-词", "min-gan-词"},
+		{"luma不应通过拼音命中", ""},
+	}
+
+	options := &SearchOptions{MinLevel: 1}
+	for _, test := range tests {
+		matches := ac.FindAll(test.text, options)
+		if test.expected == "" {
+			if len(matches) != 0 {
+				t.Errorf("FindAll(%q) = %d matches, expected none", test.text, len(matches))
+			}
+			continue
+		}
+		if len(matches) != 1 || matches[0].Word != "敏感词" {
+			t.Fatalf("FindAll(%q) = %+v, expected 敏感词", test.text, matches)
+		}
+		if got := test.text[matches[0].Start:matches[0].End]; got != test.expected {
+			t.Errorf("FindAll(%q) position points to %q, expected %q", test.text, got, test.expected)
+		}
+	}
+
+	if results := ac.FuzzySearch("minganci", options); len(results) != 1 || results[0].Word != "敏感词" {
+		t.Errorf("FuzzySearch(minganci) = %v, expected 敏感词", results)
+	}
+
+	data, err := ac.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+	restored := NewACAutomaton()
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+	if matches := restored.FindAll("minganci", options); len(matches) != 1 {
+		t.Errorf("Restored automaton lost pinyin words, got %d matches", len(matches))
+	}
+}
+
+func TestACAutomatonFuzzy(t *testing.T) {
+	ac := NewACAutomaton()
+	ac.AddFuzzyWord("fuck", []string{"abuse"}, 5)
+	ac.AddFuzzyWord("法轮功", []string{"politics"}, 5)
+	ac.AddFuzzyWord("ab", []string{"abuse"}, 5)
+	ac.BuildFailPointers()
+
+	tests := []struct {
+		text     string
+		word     string
+		expected string
+	}{
+		{"what the fxck", "fuck", "fxck"},
+		{"what the fuk!", "fuck", "fuk"},
+		{"what the fucck", "fuck", "fucck"},
+		{"练法论功的人", "法轮功", "法论功"},
+		{"what the fuck", "fuck", "fuck"},
+		{"a frock", "", ""},
+		{"xb", "", ""},
+	}
+
+	options := &SearchOptions{MinLevel: 1}
+	for _, test := range tests {
+		matches := ac.FindAll(test.text, options)
+		if test.word == "" {
+			if len(matches) != 0 {
+				t.Errorf("FindAll(%q) = %+v, expected none", test.text, matches)
+			}
+			continue
+		}
+		if len(matches) != 1 || matches[0].Word != test.word {
+			t.Fatalf("FindAll(%q) = %+v, expected %s", test.text, matches, test.word)
+		}
+		if got := test.text[matches[0].Start:matches[0].End]; got != test.expected {
+			t.Errorf("FindAll(%q) position points to %q, expected %q", test.text, got, test.expected)
+		}
+	}
+
+	data, err := ac.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+	restored := NewACAutomaton()
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+	if matches := NewFlatAutomaton(restored).FindAll("fxck", options); len(matches) != 1 {
+		t.Errorf("Restored automaton lost fuzzy words, got %d matches", len(matches))
+	}
+}
+
+func TestFirstChars(t *testing.T) {
+	ac := NewACAutomaton()
+	ac.AddWord("敏感词", []string{"test"}, 1)
+	ac.AddWord("bad", []string{"test"}, 1)
+	ac.AddFuzzyWord("fuck", []string{"test"}, 5)
+	ac.BuildFailPointers()
+
+	tests := []struct {
+		text     string
+		expected bool
+	}{
+		{"这是一段正常的文本", false},
+		{"这是敏感的内容", true},
+		{"a bad word", true},
+		{"fxck", true},
+		{"xxck", true},
+		{"", false},
+	}
+
+	for _, set := range []*CharSet{ac.FirstChars(), NewFlatAutomaton(ac).FirstChars()} {
+		for _, test := range tests {
+			if got := set.MayMatch(test.text); got != test.expected {
+				t.Errorf("MayMatch(%q) = %v, expected %v", test.text, got, test.expected)
+			}
+		}
+	}
+
+	ac.AddPinyinWord("敏感词", []string{"test"}, 1)
+	ac.BuildFailPointers()
+	if set := ac.FirstChars(); set != nil {
+		t.Errorf("FirstChars with pinyin words = %v, expected nil", set)
+	}
+}
+
+func TestIsWholeWord(t *testing.T) {
+	tests := []struct {
+		text     string
+		word     string
+		expected bool
+	}{
+		{"you ass", "ass", true},
+		{"ass!", "ass", true},
+		{"classic", "ass", false},
+		{"assume", "ass", false},
+		{"这是敏感词吧", "敏感词", true},
+		{"abc敏感词", "敏感词", true},
+		{"ass敏感", "ass", true},
+	}
+
+	for _, test := range tests {
+		start := strings.Index(test.text, test.word)
+		if got := IsWholeWord(test.text, start, start+len(test.word)); got != test.expected {
+			t.Errorf("IsWholeWord(%q, %q) = %v, expected %v", test.text, test.word, got, test.expected)
+		}
+	}
+}
+
 func BenchmarkACAutomatonSearch(b *testing.B) {
 	ac := NewACAutomaton()
 
@@ -99,7 +457,7 @@ func BenchmarkACAutomatonSearch(b *testing.B) {
 	ac.BuildFailPointers()
 
 	text := "这是一段包含敏感词1和辱骂词1的测试文本"
-	
+
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		ac.Search(text)
@@ -122,9 +480,82 @@ func BenchmarkACAutomatonSearchWithOptions(b *testing.B) {
 		Categories: []string{"test"},
 		MinLevel:   2,
 	}
-	
+
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		ac.SearchWithOptions(text, options)
 	}
 }
+
+func BenchmarkFlatAutomatonFindAll(b *testing.B) {
+	ac := NewACAutomaton()
+
+	words := []string{"敏感词1", "敏感词2", "敏感词3", "辱骂词1", "辱骂词2", "政治词1", "政治词2"}
+	for i, word := range words {
+		ac.AddWord(word, []string{"test"}, i%3+1)
+	}
+
+	ac.BuildFailPointers()
+	fa := NewFlatAutomaton(ac)
+
+	text := "这是一段包含敏感词1和辱骂词1的测试文本"
+	options := &SearchOptions{MinLevel: 1}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		fa.FindAll(text, options)
+	}
+}
+
+// benchmarkWords 生成n个由常用汉字组成的敏感词，前缀大量共享，与真实词库的节点分布相近
+func benchmarkWords(n int) []WordEntry {
+	chars := []rune("的一是在不了有和人这中大为上个国我以要他时来用们生到作地于出就分对成会可主发年动同工也能下过子说产种面而方后多定行学法所民得经十三之进着等部度家电力里如水化高自二理起小物现实加量都两体制机当使点从业本去把性好应开它合还因由其些然前外天政四日那社义事平形相全表间样与关各重新线内数正心反你明看原又么利比或但质气第向道命此变条只没结解问意建月公无系军很情者最立代想已通并提直题党程展五果料象员革位入常文总次品式活设及管特件长求老头基资边流路级少图山统接知较将组见计别她手角期根论运农指几九区强放决西被干做必战先回则任取据处队南给色光门即保治北造百规热领七海口东导器压志世金增争济阶油思术极交受联什认六共权收证改清己美再采转更单风切打白教速花带安场身车例真务具万每目至达走积示议声报斗完类八离华名确才科张信马节话米整空元况今集温传土许步群广石记需段研界拉林律叫且究观越织装影算低持音众书布复容儿须际商非验连断深难近矿千周委素技备半办青省列习响约支般史感劳便团往酸历市克何除消构府称太准精值号率族维划选标写存候毛亲快效斯院查江型眼王按格养易置派层片始却专状育厂京识适属圆包火住调满县局照参红细引听该铁价严")
+	words := make([]WordEntry, n)
+	for i := range words {
+		var sb strings.Builder
+		x := uint64(i)*2654435761 + 1
+		for j := 0; j < 2+int(x%4); j++ {
+			sb.WriteRune(chars[x%uint64(len(chars))])
+			x = x*6364136223846793005 + 1442695040888963407
+			x ^= x >> 29
+		}
+		fmt.Fprintf(&sb, "%d", i%7)
+		words[i] = WordEntry{Word: sb.String(), Categories: []string{"test"}, Level: i%5 + 1}
+	}
+	return words
+}
+
+// BenchmarkBuildFlat 比较先构建map布局再转换与直接构建紧凑布局的内存
+// heap-bytes 为构建前后的堆内存增长，构建期间的临时对象尚未回收，接近构建的峰值内存
+func BenchmarkBuildFlat(b *testing.B) {
+	words := benchmarkWords(100000)
+	builds := []struct {
+		name  string
+		build func() Matcher
+	}{
+		{"convert", func() Matcher {
+			ac, _ := Build(words, nil)
+			return NewFlatAutomaton(ac)
+		}},
+		{"direct", func() Matcher {
+			fa, _ := BuildFlat(words, nil)
+			return fa
+		}},
+	}
+	for _, build := range builds {
+		b.Run(build.name, func(b *testing.B) {
+			b.ReportAllocs()
+			var before, after runtime.MemStats
+			var heap int64
+			for i := 0; i < b.N; i++ {
+				runtime.GC()
+				runtime.ReadMemStats(&before)
+				matcher := build.build()
+				runtime.ReadMemStats(&after)
+				heap += int64(after.HeapAlloc) - int64(before.HeapAlloc)
+				runtime.KeepAlive(matcher)
+			}
+			b.ReportMetric(float64(heap)/float64(b.N), "heap-bytes/op")
+		})
+	}
+}
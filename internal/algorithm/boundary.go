@@ -0,0 +1,41 @@
+package algorithm
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// IsWholeWord 检查文本中 [start, end) 的命中是否为完整单词
+// 只对字母文字（拉丁、西里尔等）生效：命中首尾字符为此类字母时，其前后相邻字符不能是同类字母；
+// 汉字、假名、谚文等不以空格分词的文字始终视为完整单词
+func IsWholeWord(text string, start, end int) bool {
+	if start >= end {
+		return true
+	}
+
+	first, _ := utf8.DecodeRuneInString(text[start:end])
+	if isAlphabetic(first) && start > 0 {
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		if isAlphabetic(before) {
+			return false
+		}
+	}
+
+	last, _ := utf8.DecodeLastRuneInString(text[start:end])
+	if isAlphabetic(last) && end < len(text) {
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if isAlphabetic(after) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isAlphabetic 是否为以空格分词的字母文字
+func isAlphabetic(char rune) bool {
+	if !unicode.IsLetter(char) {
+		return false
+	}
+	return !unicode.In(char, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul, unicode.Thai, unicode.Lao, unicode.Khmer, unicode.Myanmar)
+}
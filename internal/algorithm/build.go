@@ -0,0 +1,262 @@
+package algorithm
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+)
+
+// 内存估算参数（64位平台下的经验值）
+const (
+	nodeBytes       = 208             // 节点结构体 + 子节点map头部和首个桶
+	outputBytes     = 64              // Output结构体及分类切片头部
+	flatNodeBytes   = 5 * 4           // 紧凑布局每个节点：childStart、fail、outStart 及指向它的一条边（字符和目标）
+	flatOutputBytes = outputBytes + 8 // 紧凑布局每个输出：Output结构体及输出表中的指针
+	flatIndexBytes  = 4               // 紧凑布局节点输出列表中的每一项
+	rootTableBytes  = rootTableSize * 4
+)
+
+// ErrMemoryBudgetExceeded 构建超出内存预算
+var ErrMemoryBudgetExceeded = errors.New("automaton memory budget exceeded")
+
+// 构建阶段
+const (
+	PhaseInsert = "insert" // 插入敏感词
+	PhaseLink   = "link"   // 构建失败指针
+	PhaseDone   = "done"   // 构建完成
+)
+
+// WordEntry 待构建的敏感词
+type WordEntry struct {
+	Word       string   // 敏感词
+	Categories []string // 分类
+	Level      int      // 敏感级别
+	Pinyin     bool     // 是否启用拼音匹配
+	Fuzzy      bool     // 是否启用编辑距离匹配
+}
+
+// BuildProgress 构建进度
+type BuildProgress struct {
+	Phase          string `json:"phase"`           // 当前阶段
+	Done           int    `json:"done"`            // 已处理敏感词数
+	Total          int    `json:"total"`           // 敏感词总数
+	Nodes          int    `json:"nodes"`           // 已创建节点数
+	EstimatedBytes int64  `json:"estimated_bytes"` // 估算内存占用
+}
+
+// BuildOptions 构建选项
+type BuildOptions struct {
+	MemoryBudget  int64               // 内存预算（字节），0表示不限制
+	ProgressEvery int                 // 每处理多少个敏感词报告一次进度，0表示只在阶段切换时报告
+	OnProgress    func(BuildProgress) // 进度回调
+}
+
+// Build 在新的自动机上构建词表，超出内存预算时中止并返回ErrMemoryBudgetExceeded
+func Build(words []WordEntry, options *BuildOptions) (*ACAutomaton, error) {
+	if options == nil {
+		options = &BuildOptions{}
+	}
+
+	report := func(progress BuildProgress) {
+		if options.OnProgress != nil {
+			options.OnProgress(progress)
+		}
+	}
+
+	ac := NewACAutomaton()
+	progress := BuildProgress{Phase: PhaseInsert, Total: len(words)}
+	report(progress)
+
+	for i, word := range words {
+		created := ac.addEntry(word)
+		progress.Done = i + 1
+		progress.Nodes += created
+		progress.EstimatedBytes += int64(created)*nodeBytes + outputBytes + int64(len(word.Word))
+
+		if options.MemoryBudget > 0 && progress.EstimatedBytes > options.MemoryBudget {
+			report(progress)
+			return nil, fmt.Errorf("%w: estimated %d bytes after %d/%d words, budget %d bytes",
+				ErrMemoryBudgetExceeded, progress.EstimatedBytes, progress.Done, progress.Total, options.MemoryBudget)
+		}
+
+		if options.ProgressEvery > 0 && progress.Done%options.ProgressEvery == 0 {
+			report(progress)
+		}
+	}
+
+	progress.Phase = PhaseLink
+	report(progress)
+	ac.BuildFailPointers()
+
+	progress.Phase = PhaseDone
+	report(progress)
+
+	return ac, nil
+}
+
+// flatSpan 构建紧凑布局时一个节点对应的敏感词区间：排序后的 order[lo:hi] 共享该节点的前缀，前缀长 depth 字节
+type flatSpan struct {
+	lo, hi, depth int32
+}
+
+// BuildFlat 直接构建紧凑布局的自动机，不经过每个节点一个map的 ACAutomaton，峰值内存接近最终的数组大小
+// 敏感词排序后共享前缀的词语相邻，按广度优先逐层划分区间即得到与 NewFlatAutomaton 相同的节点编号和边顺序；
+// 内存预算按紧凑布局的数组大小计算，超出时中止并返回ErrMemoryBudgetExceeded
+func BuildFlat(words []WordEntry, options *BuildOptions) (*FlatAutomaton, error) {
+	if options == nil {
+		options = &BuildOptions{}
+	}
+
+	report := func(progress BuildProgress) {
+		if options.OnProgress != nil {
+			options.OnProgress(progress)
+		}
+	}
+	overBudget := func(progress BuildProgress) error {
+		if options.MemoryBudget <= 0 || progress.EstimatedBytes <= options.MemoryBudget {
+			return nil
+		}
+		report(progress)
+		return fmt.Errorf("%w: estimated %d bytes after %d/%d words, budget %d bytes",
+			ErrMemoryBudgetExceeded, progress.EstimatedBytes, progress.Done, progress.Total, options.MemoryBudget)
+	}
+
+	progress := BuildProgress{Phase: PhaseInsert, Total: len(words), EstimatedBytes: rootTableBytes + flatNodeBytes}
+	report(progress)
+
+	// UTF-8的字节序与字符序一致，稳定排序保持同一词语的添加顺序，与 ACAutomaton 中的输出顺序相同
+	order := make([]int32, 0, len(words))
+	fa := &FlatAutomaton{outputs: make([]*Output, 0, len(words))}
+	for i, word := range words {
+		if word.Word != "" {
+			order = append(order, int32(i))
+		} else {
+			progress.Done++
+		}
+		if word.Pinyin {
+			fa.pinyinWords = append(fa.pinyinWords, word)
+		}
+		if word.Fuzzy {
+			fa.fuzzyWords = append(fa.fuzzyWords, word)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool { return words[order[a]].Word < words[order[b]].Word })
+
+	// 按广度优先处理节点：结束于该节点的词语在区间开头，其余按下一个字符分组为子节点
+	spans := []flatSpan{{lo: 0, hi: int32(len(order)), depth: 0}}
+	ownStart := make([]uint32, 0, 1)
+	ownIndex := make([]uint32, 0, len(order))
+	lastReported := 0
+	for node := 0; node < len(spans); node++ {
+		span := spans[node]
+		fa.childStart = append(fa.childStart, uint32(len(fa.edgeRunes)))
+		ownStart = append(ownStart, uint32(len(ownIndex)))
+
+		i := span.lo
+		for ; i < span.hi && len(words[order[i]].Word) == int(span.depth); i++ {
+			word := words[order[i]]
+			ownIndex = append(ownIndex, uint32(len(fa.outputs)))
+			fa.outputs = append(fa.outputs, &Output{Word: word.Word, Categories: word.Categories, Level: word.Level})
+			progress.Done++
+			progress.EstimatedBytes += flatOutputBytes + flatIndexBytes + int64(len(word.Word))
+		}
+
+		for i < span.hi {
+			char, size := utf8.DecodeRuneInString(words[order[i]].Word[span.depth:])
+			prefix := words[order[i]].Word[:int(span.depth)+size]
+			j := i + 1
+			for j < span.hi && len(words[order[j]].Word) >= len(prefix) && words[order[j]].Word[:len(prefix)] == prefix {
+				j++
+			}
+			fa.edgeRunes = append(fa.edgeRunes, char)
+			fa.edgeNext = append(fa.edgeNext, uint32(len(spans)))
+			spans = append(spans, flatSpan{lo: i, hi: j, depth: span.depth + int32(size)})
+			progress.Nodes++
+			progress.EstimatedBytes += flatNodeBytes
+			i = j
+		}
+
+		if err := overBudget(progress); err != nil {
+			return nil, err
+		}
+		if options.ProgressEvery > 0 && progress.Done/options.ProgressEvery > lastReported {
+			lastReported = progress.Done / options.ProgressEvery
+			report(progress)
+		}
+	}
+	nodes := len(spans)
+	spans = nil
+	fa.childStart = append(fa.childStart, uint32(len(fa.edgeRunes)))
+	ownStart = append(ownStart, uint32(len(ownIndex)))
+	fa.buildRootTable()
+
+	// 失败指针：按编号（即广度优先）处理，父节点的失败指针总是先于子节点确定
+	progress.Phase = PhaseLink
+	report(progress)
+	fa.fail = make([]uint32, nodes)
+	for node := 0; node < nodes; node++ {
+		for e := fa.childStart[node]; e < fa.childStart[node+1]; e++ {
+			char, child := fa.edgeRunes[e], fa.edgeNext[e]
+			if node == 0 {
+				continue
+			}
+			for fail := fa.fail[node]; ; fail = fa.fail[fail] {
+				if next, ok := fa.child(fail, char); ok {
+					fa.fail[child] = next
+					break
+				}
+				if fail == 0 {
+					break
+				}
+			}
+		}
+	}
+
+	// 合并输出：节点自身的输出在前，随后是失败指针所指节点（编号更小，已合并）的输出
+	fa.outStart = make([]uint32, nodes+1)
+	fa.outIndex = make([]uint32, 0, len(ownIndex))
+	for node := 0; node < nodes; node++ {
+		fa.outStart[node] = uint32(len(fa.outIndex))
+		fa.outIndex = append(fa.outIndex, ownIndex[ownStart[node]:ownStart[node+1]]...)
+		if node > 0 {
+			fail := fa.fail[node]
+			fa.outIndex = append(fa.outIndex, fa.outIndex[fa.outStart[fail]:fa.outStart[fail+1]]...)
+		}
+	}
+	fa.outStart[nodes] = uint32(len(fa.outIndex))
+	progress.EstimatedBytes += int64(len(fa.outIndex)-len(ownIndex)) * flatIndexBytes
+	if err := overBudget(progress); err != nil {
+		return nil, err
+	}
+
+	fa.pinyin = NewPinyinMatcher(fa.pinyinWords)
+	fa.fuzzy = NewFuzzyMatcher(fa.fuzzyWords)
+
+	progress.Phase = PhaseDone
+	report(progress)
+
+	return fa, nil
+}
+
+// EstimateMemory 估算自动机的内存占用（字节）
+func (ac *ACAutomaton) EstimateMemory() int64 {
+	ac.mu.RLock()
+	defer ac.mu.RUnlock()
+
+	var total int64
+	nodes := []*ACNode{ac.root}
+	for len(nodes) > 0 {
+		node := nodes[len(nodes)-1]
+		nodes = nodes[:len(nodes)-1]
+
+		total += nodeBytes + int64(len(node.output))*8
+		if node.isEnd {
+			total += outputBytes
+		}
+		for _, child := range node.children {
+			nodes = append(nodes, child)
+		}
+	}
+	return total
+}
@@ -0,0 +1,43 @@
+package algorithm
+
+// CharSet 字符集合，基本多文种平面内的字符使用位图，其余字符使用map
+type CharSet struct {
+	bmp   [rootTableSize / 64]uint64
+	other map[rune]bool
+}
+
+// NewCharSet 创建空的字符集合
+func NewCharSet() *CharSet {
+	return &CharSet{other: make(map[rune]bool)}
+}
+
+// Add 添加字符
+func (s *CharSet) Add(char rune) {
+	if char >= 0 && char < rootTableSize {
+		s.bmp[char>>6] |= 1 << (char & 63)
+		return
+	}
+	s.other[char] = true
+}
+
+// Contains 检查字符是否在集合中
+func (s *CharSet) Contains(char rune) bool {
+	if char >= 0 && char < rootTableSize {
+		return s.bmp[char>>6]&(1<<(char&63)) != 0
+	}
+	return s.other[char]
+}
+
+// MayMatch 检查文本是否包含集合中的任一字符，集合为nil时总是返回true
+// 用于敏感词首字符集合：不包含任何首字符的文本不可能命中，可跳过自动机遍历
+func (s *CharSet) MayMatch(text string) bool {
+	if s == nil {
+		return true
+	}
+	for _, char := range text {
+		if s.Contains(char) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,293 @@
+package algorithm
+
+import (
+	"context"
+	"sort"
+	"unicode/utf8"
+)
+
+// Matcher 多模式匹配器，AC自动机的不同内存布局均实现该接口
+type Matcher interface {
+	// FindAll 带选项搜索，返回每一次命中及其在文本中的字节位置
+	FindAll(text string, options *SearchOptions) []Match
+	// FindAllContext 与FindAll相同，但会定期检查上下文
+	FindAllContext(ctx context.Context, text string, options *SearchOptions) ([]Match, error)
+	// GetVersion 获取版本
+	GetVersion() string
+	// GetNodeCount 获取节点数量（不含根节点）
+	GetNodeCount() int
+	// FirstChars 返回所有敏感词首字符的集合，无法据此排除文本时返回nil
+	FirstChars() *CharSet
+	// MaxMatchLen 返回一次命中在文本中可能跨越的最大字节数
+	MaxMatchLen() int
+}
+
+// Automaton 可序列化的自动机，两种内存布局使用相同的二进制格式，可以互相加载
+type Automaton interface {
+	Matcher
+	// MarshalBinary 序列化为二进制，包括失败指针
+	MarshalBinary() ([]byte, error)
+	// EstimateMemory 估算内存占用（字节）
+	EstimateMemory() int64
+}
+
+var (
+	_ Automaton = (*ACAutomaton)(nil)
+	_ Automaton = (*FlatAutomaton)(nil)
+)
+
+// rootTableSize 根节点直接寻址表覆盖的字符范围（基本多文种平面）
+const rootTableSize = 0x10000
+
+// noNode 直接寻址表中表示不存在的子节点
+const noNode = ^uint32(0)
+
+// FlatAutomaton 紧凑布局的AC自动机
+// 节点按广度优先编号，子节点边按字符升序存放在连续切片中，避免每个节点一个map带来的内存和GC开销。
+// 构建完成后只读，可并发查询。
+type FlatAutomaton struct {
+	version string
+
+	// 节点i的子节点边为 edgeRunes/edgeNext[childStart[i]:childStart[i+1]]
+	childStart []uint32
+	edgeRunes  []rune
+	edgeNext   []uint32
+
+	// 节点i的失败指针
+	fail []uint32
+
+	// 节点i的输出为 outputs[outIndex[outStart[i]:outStart[i+1]]]
+	outStart []uint32
+	outIndex []uint32
+	outputs  []*Output
+
+	// 根节点子节点的直接寻址表，失败回退到根节点后的查找最频繁
+	rootTable []uint32
+
+	pinyinWords []WordEntry // 启用拼音匹配的敏感词，序列化时使用
+	pinyin      *PinyinMatcher
+	fuzzyWords  []WordEntry // 启用编辑距离匹配的敏感词，序列化时使用
+	fuzzy       *FuzzyMatcher
+}
+
+// NewFlatAutomaton 将已构建失败指针的AC自动机转换为紧凑布局
+// 转换期间两种布局同时存在，大词库应使用 BuildFlat 直接构建
+func NewFlatAutomaton(ac *ACAutomaton) *FlatAutomaton {
+	ac.mu.RLock()
+	defer ac.mu.RUnlock()
+
+	// 广度优先为节点编号，子节点按字符排序
+	nodes := []*ACNode{ac.root}
+	ids := map[*ACNode]uint32{ac.root: 0}
+	sortedChars := make([][]rune, 0, 1)
+	for i := 0; i < len(nodes); i++ {
+		chars := make([]rune, 0, len(nodes[i].children))
+		for char := range nodes[i].children {
+			chars = append(chars, char)
+		}
+		sort.Slice(chars, func(a, b int) bool { return chars[a] < chars[b] })
+		sortedChars = append(sortedChars, chars)
+
+		for _, char := range chars {
+			child := nodes[i].children[char]
+			ids[child] = uint32(len(nodes))
+			nodes = append(nodes, child)
+		}
+	}
+
+	fa := &FlatAutomaton{
+		version:     ac.version,
+		pinyinWords: ac.pinyinWords,
+		pinyin:      ac.pinyin,
+		fuzzyWords:  ac.fuzzyWords,
+		fuzzy:       ac.fuzzy,
+		childStart:  make([]uint32, len(nodes)+1),
+		edgeRunes:   make([]rune, 0, len(nodes)-1),
+		edgeNext:    make([]uint32, 0, len(nodes)-1),
+		fail:        make([]uint32, len(nodes)),
+		outStart:    make([]uint32, len(nodes)+1),
+		outIndex:    make([]uint32, 0),
+		outputs:     make([]*Output, 0),
+	}
+
+	outputIds := make(map[*Output]uint32)
+	for i, node := range nodes {
+		fa.childStart[i] = uint32(len(fa.edgeRunes))
+		for _, char := range sortedChars[i] {
+			fa.edgeRunes = append(fa.edgeRunes, char)
+			fa.edgeNext = append(fa.edgeNext, ids[node.children[char]])
+		}
+
+		if node.fail != nil {
+			fa.fail[i] = ids[node.fail]
+		}
+
+		fa.outStart[i] = uint32(len(fa.outIndex))
+		for _, output := range node.output {
+			id, ok := outputIds[output]
+			if !ok {
+				id = uint32(len(fa.outputs))
+				outputIds[output] = id
+				fa.outputs = append(fa.outputs, output)
+			}
+			fa.outIndex = append(fa.outIndex, id)
+		}
+	}
+	fa.childStart[len(nodes)] = uint32(len(fa.edgeRunes))
+	fa.outStart[len(nodes)] = uint32(len(fa.outIndex))
+	fa.buildRootTable()
+
+	return fa
+}
+
+// buildRootTable 根据根节点的子节点边填充直接寻址表
+func (fa *FlatAutomaton) buildRootTable() {
+	fa.rootTable = make([]uint32, rootTableSize)
+	for i := range fa.rootTable {
+		fa.rootTable[i] = noNode
+	}
+	for e := fa.childStart[0]; e < fa.childStart[1]; e++ {
+		if fa.edgeRunes[e] >= 0 && fa.edgeRunes[e] < rootTableSize {
+			fa.rootTable[fa.edgeRunes[e]] = fa.edgeNext[e]
+		}
+	}
+}
+
+// child 查找节点的子节点
+func (fa *FlatAutomaton) child(node uint32, char rune) (uint32, bool) {
+	if node == 0 && char >= 0 && char < rootTableSize {
+		next := fa.rootTable[char]
+		return next, next != noNode
+	}
+
+	lo, hi := int(fa.childStart[node]), int(fa.childStart[node+1])
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		switch c := fa.edgeRunes[mid]; {
+		case c == char:
+			return fa.edgeNext[mid], true
+		case c < char:
+			lo = mid + 1
+		default:
+			hi = mid
+		}
+	}
+	return 0, false
+}
+
+// FindAll 带选项搜索，返回每一次命中及其在文本中的字节位置
+func (fa *FlatAutomaton) FindAll(text string, options *SearchOptions) []Match {
+	matches, _ := fa.FindAllContext(context.Background(), text, options)
+	return matches
+}
+
+// FindAllContext 与FindAll相同，但会定期检查上下文，取消或超时时返回上下文错误
+func (fa *FlatAutomaton) FindAllContext(ctx context.Context, text string, options *SearchOptions) ([]Match, error) {
+	results := make([]Match, 0)
+	node := uint32(0)
+	scanned := 0
+
+	for i, char := range text {
+		scanned++
+		if scanned%ctxCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+
+		for {
+			if next, ok := fa.child(node, char); ok {
+				node = next
+				break
+			}
+			if node == 0 {
+				break
+			}
+			node = fa.fail[node]
+		}
+
+		start, end := fa.outStart[node], fa.outStart[node+1]
+		if start == end {
+			continue
+		}
+
+		_, size := utf8.DecodeRuneInString(text[i:])
+		matchEnd := i + size
+		for _, id := range fa.outIndex[start:end] {
+			output := fa.outputs[id]
+			if matchesOptions(output, options) {
+				results = append(results, Match{
+					Output: output,
+					Start:  matchEnd - len(output.Word),
+					End:    matchEnd,
+				})
+			}
+		}
+	}
+
+	if fa.pinyin != nil {
+		pinyinMatches, err := fa.pinyin.FindAllContext(ctx, text, options)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, pinyinMatches...)
+	}
+
+	if fa.fuzzy != nil {
+		fuzzyMatches, err := fa.fuzzy.FindAllContext(ctx, text, options)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, fuzzyMatches...)
+	}
+
+	return results, nil
+}
+
+// SetVersion 设置版本，须在开始查询前调用
+func (fa *FlatAutomaton) SetVersion(version string) {
+	fa.version = version
+}
+
+// GetVersion 获取版本
+func (fa *FlatAutomaton) GetVersion() string {
+	return fa.version
+}
+
+// GetNodeCount 获取节点数量（不含根节点）
+func (fa *FlatAutomaton) GetNodeCount() int {
+	return len(fa.fail) - 1
+}
+
+// EstimateMemory 估算自动机的内存占用（字节），包括各数组、根节点直接寻址表和输出表
+func (fa *FlatAutomaton) EstimateMemory() int64 {
+	total := int64(len(fa.childStart)+len(fa.edgeRunes)+len(fa.edgeNext)+len(fa.fail)+
+		len(fa.outStart)+len(fa.outIndex)+len(fa.rootTable)) * 4
+	for _, output := range fa.outputs {
+		total += flatOutputBytes + int64(len(output.Word))
+	}
+	return total
+}
+
+// FirstChars 返回所有敏感词首字符的集合；启用了拼音匹配时任何字母和汉字都可能命中，返回nil
+func (fa *FlatAutomaton) FirstChars() *CharSet {
+	if fa.pinyin != nil {
+		return nil
+	}
+
+	set := NewCharSet()
+	for _, char := range fa.edgeRunes[fa.childStart[0]:fa.childStart[1]] {
+		set.Add(char)
+	}
+	fa.fuzzy.addFirstChars(set)
+	return set
+}
+
+// MaxMatchLen 返回一次命中在文本中可能跨越的最大字节数，用于分块扫描时确定块间重叠
+func (fa *FlatAutomaton) MaxMatchLen() int {
+	maxLen := 0
+	for _, output := range fa.outputs {
+		maxLen = max(maxLen, len(output.Word))
+	}
+	return max(maxLen, fa.pinyin.maxMatchLen(), fa.fuzzy.maxMatchLen())
+}
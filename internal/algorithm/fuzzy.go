@@ -0,0 +1,192 @@
+package algorithm
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+)
+
+// MinFuzzyWordLength 启用编辑距离匹配的敏感词最少字符数，过短的词语距离为1的变体过多，误判严重
+const MinFuzzyWordLength = 3
+
+// fuzzyAnchor 敏感词的前半段或后半段
+// 编辑距离为1的变体只改动其中一段，另一段必然原样出现在文本中，命中后再校验整个词语
+type fuzzyAnchor struct {
+	word  *Output // 原敏感词
+	runes []rune  // 原敏感词的字符
+	left  bool    // 是否为前半段
+}
+
+// FuzzyMatcher 编辑距离匹配器，命中与敏感词编辑距离为1（替换、缺失或多出一个字符）的文本
+// 只包含启用了编辑距离匹配的敏感词，与原词完全相同的命中由精确匹配负责
+type FuzzyMatcher struct {
+	automaton *ACAutomaton
+	anchors   map[*Output]fuzzyAnchor // 半段Output -> 原敏感词
+	words     []WordEntry
+}
+
+// NewFuzzyMatcher 为启用编辑距离匹配的敏感词构建匹配器，没有可用词语时返回nil
+func NewFuzzyMatcher(words []WordEntry) *FuzzyMatcher {
+	fm := &FuzzyMatcher{
+		automaton: NewACAutomaton(),
+		anchors:   make(map[*Output]fuzzyAnchor),
+	}
+
+	for _, word := range words {
+		runes := []rune(word.Word)
+		if len(runes) < MinFuzzyWordLength {
+			continue
+		}
+
+		original := &Output{Word: word.Word, Categories: word.Categories, Level: word.Level}
+		half := len(runes) / 2
+		fm.addAnchor(string(runes[:half]), fuzzyAnchor{word: original, runes: runes, left: true})
+		fm.addAnchor(string(runes[half:]), fuzzyAnchor{word: original, runes: runes, left: false})
+		fm.words = append(fm.words, word)
+	}
+	if len(fm.words) == 0 {
+		return nil
+	}
+
+	fm.automaton.BuildFailPointers()
+	return fm
+}
+
+// addAnchor 将半段加入自动机，并记录其对应的原敏感词
+func (fm *FuzzyMatcher) addAnchor(part string, anchor fuzzyAnchor) {
+	fm.automaton.addWord(part, anchor.word.Categories, anchor.word.Level)
+
+	node := fm.automaton.root
+	for _, char := range part {
+		node = node.children[char]
+	}
+	fm.anchors[node.output[len(node.output)-1]] = anchor
+}
+
+// Words 返回启用了编辑距离匹配的敏感词
+func (fm *FuzzyMatcher) Words() []WordEntry {
+	return fm.words
+}
+
+// addFirstChars 将半段的首字符加入集合，编辑距离命中必然包含某个半段
+func (fm *FuzzyMatcher) addFirstChars(set *CharSet) {
+	if fm == nil {
+		return
+	}
+	for char := range fm.automaton.root.children {
+		set.Add(char)
+	}
+}
+
+// FindAllContext 搜索与敏感词编辑距离为1的文本，返回原敏感词及命中文本的字节位置
+func (fm *FuzzyMatcher) FindAllContext(ctx context.Context, text string, options *SearchOptions) ([]Match, error) {
+	hits, err := fm.automaton.FindAllContext(ctx, text, options)
+	if err != nil || len(hits) == 0 {
+		return nil, err
+	}
+
+	// 同一敏感词的前后半段可能在重叠的片段上各自命中，合并为一个覆盖两者的命中
+	last := make(map[*Output]int)
+	results := make([]Match, 0)
+
+	for _, hit := range hits {
+		anchor := fm.anchors[hit.Output]
+		start, end, ok := anchor.verify(text, hit.Start, hit.End)
+		if !ok {
+			continue
+		}
+
+		if i, ok := last[anchor.word]; ok && start < results[i].End && end > results[i].Start {
+			results[i].Start = min(results[i].Start, start)
+			results[i].End = max(results[i].End, end)
+			continue
+		}
+		last[anchor.word] = len(results)
+		results = append(results, Match{Output: anchor.word, Start: start, End: end})
+	}
+
+	return results, nil
+}
+
+// verify 以半段的命中位置为锚点，在文本中查找与原敏感词编辑距离恰好为1的片段
+// 前半段命中时片段从锚点起始位置开始，后半段命中时片段在锚点结束位置结束；依次尝试替换、缺失、多出一个字符
+func (a fuzzyAnchor) verify(text string, anchorStart, anchorEnd int) (int, int, bool) {
+	n := len(a.runes)
+	for _, length := range []int{n, n - 1, n + 1} {
+		start, end, ok := a.window(text, anchorStart, anchorEnd, length)
+		if !ok {
+			continue
+		}
+
+		candidate := text[start:end]
+		if strings.Contains(candidate, a.word.Word) {
+			// 原词完整出现，由精确匹配负责
+			return 0, 0, false
+		}
+		if editDistance(a.runes, []rune(candidate)) == 1 {
+			return start, end, true
+		}
+	}
+	return 0, 0, false
+}
+
+// window 取锚点处长度为length个字符的片段
+func (a fuzzyAnchor) window(text string, anchorStart, anchorEnd, length int) (int, int, bool) {
+	if a.left {
+		end := anchorStart
+		for i := 0; i < length; i++ {
+			if end >= len(text) {
+				return 0, 0, false
+			}
+			_, size := utf8.DecodeRuneInString(text[end:])
+			end += size
+		}
+		return anchorStart, end, true
+	}
+
+	start := anchorEnd
+	for i := 0; i < length; i++ {
+		if start <= 0 {
+			return 0, 0, false
+		}
+		_, size := utf8.DecodeLastRuneInString(text[:start])
+		start -= size
+	}
+	return start, anchorEnd, true
+}
+
+// editDistance 计算两个字符序列的Levenshtein编辑距离
+func editDistance(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
+
+// maxMatchLen 返回编辑距离命中在文本中可能跨越的最大字节数（原词多出一个字符）
+func (fm *FuzzyMatcher) maxMatchLen() int {
+	if fm == nil {
+		return 0
+	}
+
+	maxLen := 0
+	for _, word := range fm.words {
+		maxLen = max(maxLen, len(word.Word)+utf8.UTFMax)
+	}
+	return maxLen
+}
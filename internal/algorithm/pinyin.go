@@ -0,0 +1,179 @@
+package algorithm
+
+import (
+	"context"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mozillazg/go-pinyin"
+)
+
+// pinyinArgs 无声调拼音，多音字取常用读音
+var pinyinArgs = pinyin.Args{Style: pinyin.Normal}
+
+// pinyinBreak 拼音流中的断开符，不会出现在任何拼音键中
+const pinyinBreak = '#'
+
+// PinyinKey 返回词语的拼音匹配键（小写无声调字母），例如 "敏感词" -> "minganci"
+// 空白和标点被忽略，包含无法转换为拼音的字符时返回空字符串
+func PinyinKey(word string) string {
+	var key strings.Builder
+	for _, char := range word {
+		switch {
+		case isPinyinSkippable(char):
+			continue
+		case char < utf8.RuneSelf && unicode.IsLetter(char):
+			key.WriteRune(unicode.ToLower(char))
+		case unicode.Is(unicode.Han, char):
+			syllables := pinyin.SinglePinyin(char, pinyinArgs)
+			if len(syllables) == 0 {
+				return ""
+			}
+			key.WriteString(syllables[0])
+		default:
+			return ""
+		}
+	}
+	return key.String()
+}
+
+// isPinyinSkippable 拼音匹配时忽略的字符，使 "min gan ci"、"min-gan-ci" 与 "minganci" 等价
+func isPinyinSkippable(char rune) bool {
+	return unicode.IsSpace(char) || unicode.IsPunct(char) || unicode.IsSymbol(char)
+}
+
+// pinyinStream 文本转换后的拼音字母流，记录每个字节对应的原文字节区间
+type pinyinStream struct {
+	text   string
+	starts []int
+	ends   []int
+}
+
+// newPinyinStream 将文本中的汉字转换为拼音、英文字母转为小写，忽略空白和标点，其余字符转换为断开符
+func newPinyinStream(text string) *pinyinStream {
+	var buf strings.Builder
+	buf.Grow(len(text))
+	starts := make([]int, 0, len(text))
+	ends := make([]int, 0, len(text))
+
+	emit := func(s string, start, end int) {
+		buf.WriteString(s)
+		for i := 0; i < len(s); i++ {
+			starts = append(starts, start)
+			ends = append(ends, end)
+		}
+	}
+
+	for i, char := range text {
+		_, size := utf8.DecodeRuneInString(text[i:])
+		end := i + size
+
+		switch {
+		case isPinyinSkippable(char):
+		case char < utf8.RuneSelf && unicode.IsLetter(char):
+			emit(string(unicode.ToLower(char)), i, end)
+		case unicode.Is(unicode.Han, char):
+			if syllables := pinyin.SinglePinyin(char, pinyinArgs); len(syllables) > 0 {
+				emit(syllables[0], i, end)
+			} else {
+				emit(string(pinyinBreak), i, end)
+			}
+		default:
+			emit(string(pinyinBreak), i, end)
+		}
+	}
+
+	return &pinyinStream{text: buf.String(), starts: starts, ends: ends}
+}
+
+// PinyinMatcher 拼音匹配器，只包含启用了拼音匹配的敏感词
+// 命中时返回原敏感词的Output，位置映射回原文
+type PinyinMatcher struct {
+	automaton *ACAutomaton
+	outputs   map[*Output]*Output // 拼音键Output -> 原敏感词Output
+	words     []WordEntry
+}
+
+// NewPinyinMatcher 为启用拼音匹配的敏感词构建拼音匹配器，没有可用词语时返回nil
+func NewPinyinMatcher(words []WordEntry) *PinyinMatcher {
+	pm := &PinyinMatcher{
+		automaton: NewACAutomaton(),
+		outputs:   make(map[*Output]*Output),
+	}
+
+	for _, word := range words {
+		key := PinyinKey(word.Word)
+		if key == "" {
+			continue
+		}
+
+		pm.automaton.addWord(key, word.Categories, word.Level)
+		pm.words = append(pm.words, word)
+	}
+	if len(pm.words) == 0 {
+		return nil
+	}
+
+	// 拼音键节点上的Output与原词一一对应
+	original := make(map[string][]*Output)
+	for _, word := range pm.words {
+		key := PinyinKey(word.Word)
+		original[key] = append(original[key], &Output{Word: word.Word, Categories: word.Categories, Level: word.Level})
+	}
+	pm.mapOutputs(pm.automaton.root, original)
+	pm.automaton.BuildFailPointers()
+
+	return pm
+}
+
+// mapOutputs 建立拼音键Output到原敏感词Output的映射
+func (pm *PinyinMatcher) mapOutputs(node *ACNode, original map[string][]*Output) {
+	for _, output := range node.output {
+		candidates := original[output.Word]
+		pm.outputs[output] = candidates[0]
+		original[output.Word] = candidates[1:]
+	}
+	for _, child := range node.children {
+		pm.mapOutputs(child, original)
+	}
+}
+
+// Words 返回启用了拼音匹配的敏感词
+func (pm *PinyinMatcher) Words() []WordEntry {
+	return pm.words
+}
+
+// FindAllContext 在文本的拼音流中搜索，返回原敏感词及其在原文中的字节位置
+func (pm *PinyinMatcher) FindAllContext(ctx context.Context, text string, options *SearchOptions) ([]Match, error) {
+	stream := newPinyinStream(text)
+	matches, err := pm.automaton.FindAllContext(ctx, stream.text, options)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, match := range matches {
+		matches[i] = Match{
+			Output: pm.outputs[match.Output],
+			Start:  stream.starts[match.Start],
+			End:    stream.ends[match.End-1],
+		}
+	}
+	return matches, nil
+}
+
+// maxPinyinBytesPerLetter 拼音键中每个字母在原文中最多对应的字节数（单字母读音的汉字加一个分隔符）
+const maxPinyinBytesPerLetter = 4
+
+// maxMatchLen 返回拼音命中在原文中可能跨越的最大字节数
+func (pm *PinyinMatcher) maxMatchLen() int {
+	if pm == nil {
+		return 0
+	}
+
+	maxLen := 0
+	for _, word := range pm.words {
+		maxLen = max(maxLen, len(PinyinKey(word.Word))*maxPinyinBytesPerLetter)
+	}
+	return maxLen
+}
@@ -0,0 +1,433 @@
+package algorithm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// serializeVersion 序列化格式版本，版本2增加了启用拼音匹配的敏感词，版本3增加了启用编辑距离匹配的敏感词
+const serializeVersion = 3
+
+// MarshalBinary 将已构建的自动机（包括失败指针）序列化为二进制
+func (ac *ACAutomaton) MarshalBinary() ([]byte, error) {
+	ac.mu.RLock()
+	defer ac.mu.RUnlock()
+
+	// 广度优先为节点编号
+	nodes := []*ACNode{ac.root}
+	ids := map[*ACNode]uint64{ac.root: 0}
+	for i := 0; i < len(nodes); i++ {
+		for _, child := range nodes[i].children {
+			ids[child] = uint64(len(nodes))
+			nodes = append(nodes, child)
+		}
+	}
+
+	// 收集输出表
+	outputs := make([]*Output, 0)
+	outputIds := make(map[*Output]uint64)
+	for _, node := range nodes {
+		for _, output := range node.output {
+			if _, ok := outputIds[output]; !ok {
+				outputIds[output] = uint64(len(outputs))
+				outputs = append(outputs, output)
+			}
+		}
+	}
+
+	w := &binaryWriter{}
+	w.uvarint(serializeVersion)
+	w.string(ac.version)
+	w.outputs(outputs)
+
+	w.uvarint(uint64(len(nodes)))
+	for _, node := range nodes {
+		flags := uint64(0)
+		if node.isEnd {
+			flags |= 1
+		}
+		if node.fail != nil {
+			flags |= 2
+		}
+		w.uvarint(flags)
+		if node.fail != nil {
+			w.uvarint(ids[node.fail])
+		}
+
+		w.uvarint(uint64(len(node.output)))
+		for _, output := range node.output {
+			w.uvarint(outputIds[output])
+		}
+
+		w.uvarint(uint64(len(node.children)))
+		for char, child := range node.children {
+			w.varint(int64(char))
+			w.uvarint(ids[child])
+		}
+	}
+
+	w.entries(ac.pinyinWords)
+	w.entries(ac.fuzzyWords)
+
+	return w.buf.Bytes(), nil
+}
+
+// UnmarshalBinary 从二进制恢复自动机，恢复后无需再调用BuildFailPointers
+func (ac *ACAutomaton) UnmarshalBinary(data []byte) error {
+	r := &binaryReader{data: data}
+
+	format, err := r.format()
+	if err != nil {
+		return err
+	}
+	version := r.string()
+	outputs := r.outputs()
+
+	nodes := make([]*ACNode, r.count())
+	if r.err == nil && len(nodes) == 0 {
+		return errors.New("failed to read automaton: missing root node")
+	}
+	for i := range nodes {
+		nodes[i] = &ACNode{}
+	}
+
+	for _, node := range nodes {
+		if r.err != nil {
+			break
+		}
+
+		flags := r.uvarint()
+		node.isEnd = flags&1 != 0
+		if flags&2 != 0 {
+			node.fail = r.node(nodes)
+		}
+
+		node.output = make([]*Output, r.count())
+		for i := range node.output {
+			index := r.uvarint()
+			if index >= uint64(len(outputs)) {
+				r.fail(fmt.Errorf("output index %d out of range", index))
+				break
+			}
+			node.output[i] = outputs[index]
+		}
+
+		childCount := r.count()
+		node.children = make(map[rune]*ACNode, childCount)
+		for i := 0; i < childCount && r.err == nil; i++ {
+			char := rune(r.varint())
+			node.children[char] = r.node(nodes)
+		}
+	}
+
+	pinyinWords, fuzzyWords := r.matchEntries(format)
+	if r.err != nil {
+		return fmt.Errorf("failed to read automaton: %w", r.err)
+	}
+
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+	ac.root = nodes[0]
+	ac.version = version
+	ac.pinyinWords = pinyinWords
+	ac.pinyin = NewPinyinMatcher(pinyinWords)
+	ac.fuzzyWords = fuzzyWords
+	ac.fuzzy = NewFuzzyMatcher(fuzzyWords)
+
+	return nil
+}
+
+// MarshalBinary 将紧凑布局的自动机序列化为与 ACAutomaton 相同的二进制格式
+func (fa *FlatAutomaton) MarshalBinary() ([]byte, error) {
+	w := &binaryWriter{}
+	w.uvarint(serializeVersion)
+	w.string(fa.version)
+	w.outputs(fa.outputs)
+
+	nodes := len(fa.fail)
+	w.uvarint(uint64(nodes))
+	for node := 0; node < nodes; node++ {
+		// 节点的输出列表为自身的输出加上失败指针所指节点的输出，多出的部分即自身的输出
+		start, end := fa.outStart[node], fa.outStart[node+1]
+		flags := uint64(0)
+		if node > 0 {
+			fail := fa.fail[node]
+			if end-start > fa.outStart[fail+1]-fa.outStart[fail] {
+				flags |= 1
+			}
+			flags |= 2
+		}
+		w.uvarint(flags)
+		if node > 0 {
+			w.uvarint(uint64(fa.fail[node]))
+		}
+
+		w.uvarint(uint64(end - start))
+		for _, id := range fa.outIndex[start:end] {
+			w.uvarint(uint64(id))
+		}
+
+		w.uvarint(uint64(fa.childStart[node+1] - fa.childStart[node]))
+		for e := fa.childStart[node]; e < fa.childStart[node+1]; e++ {
+			w.varint(int64(fa.edgeRunes[e]))
+			w.uvarint(uint64(fa.edgeNext[e]))
+		}
+	}
+
+	w.entries(fa.pinyinWords)
+	w.entries(fa.fuzzyWords)
+
+	return w.buf.Bytes(), nil
+}
+
+// UnmarshalFlat 从二进制直接恢复紧凑布局的自动机，不经过每个节点一个map的 ACAutomaton
+func UnmarshalFlat(data []byte) (*FlatAutomaton, error) {
+	r := &binaryReader{data: data}
+
+	format, err := r.format()
+	if err != nil {
+		return nil, err
+	}
+	fa := &FlatAutomaton{version: r.string(), outputs: r.outputs()}
+
+	nodes := r.count()
+	if r.err == nil && nodes == 0 {
+		return nil, errors.New("failed to read automaton: missing root node")
+	}
+	fa.childStart = make([]uint32, nodes+1)
+	fa.fail = make([]uint32, nodes)
+	fa.outStart = make([]uint32, nodes+1)
+
+	type edge struct {
+		char rune
+		next uint32
+	}
+	var edges []edge
+	for node := 0; node < nodes && r.err == nil; node++ {
+		if flags := r.uvarint(); flags&2 != 0 {
+			fa.fail[node] = r.index(nodes, "node")
+		}
+
+		fa.outStart[node] = uint32(len(fa.outIndex))
+		outputCount := r.count()
+		for i := 0; i < outputCount && r.err == nil; i++ {
+			fa.outIndex = append(fa.outIndex, r.index(len(fa.outputs), "output"))
+		}
+
+		// 子节点边按字符排序后存放，查找时二分
+		fa.childStart[node] = uint32(len(fa.edgeRunes))
+		edges = edges[:0]
+		childCount := r.count()
+		for i := 0; i < childCount && r.err == nil; i++ {
+			edges = append(edges, edge{char: rune(r.varint()), next: r.index(nodes, "node")})
+		}
+		sort.Slice(edges, func(a, b int) bool { return edges[a].char < edges[b].char })
+		for _, e := range edges {
+			fa.edgeRunes = append(fa.edgeRunes, e.char)
+			fa.edgeNext = append(fa.edgeNext, e.next)
+		}
+	}
+
+	fa.pinyinWords, fa.fuzzyWords = r.matchEntries(format)
+	if r.err != nil {
+		return nil, fmt.Errorf("failed to read automaton: %w", r.err)
+	}
+
+	fa.childStart[nodes] = uint32(len(fa.edgeRunes))
+	fa.outStart[nodes] = uint32(len(fa.outIndex))
+	fa.buildRootTable()
+	fa.pinyin = NewPinyinMatcher(fa.pinyinWords)
+	fa.fuzzy = NewFuzzyMatcher(fa.fuzzyWords)
+
+	return fa, nil
+}
+
+// binaryWriter 变长编码写入器
+type binaryWriter struct {
+	buf     bytes.Buffer
+	scratch [binary.MaxVarintLen64]byte
+}
+
+func (w *binaryWriter) uvarint(v uint64) {
+	n := binary.PutUvarint(w.scratch[:], v)
+	w.buf.Write(w.scratch[:n])
+}
+
+func (w *binaryWriter) varint(v int64) {
+	n := binary.PutVarint(w.scratch[:], v)
+	w.buf.Write(w.scratch[:n])
+}
+
+func (w *binaryWriter) string(s string) {
+	w.uvarint(uint64(len(s)))
+	w.buf.WriteString(s)
+}
+
+// outputs 写入输出表
+func (w *binaryWriter) outputs(outputs []*Output) {
+	w.uvarint(uint64(len(outputs)))
+	for _, output := range outputs {
+		w.string(output.Word)
+		w.varint(int64(output.Level))
+		w.uvarint(uint64(len(output.Categories)))
+		for _, category := range output.Categories {
+			w.string(category)
+		}
+	}
+}
+
+// entries 写入敏感词列表
+func (w *binaryWriter) entries(words []WordEntry) {
+	w.uvarint(uint64(len(words)))
+	for _, word := range words {
+		w.string(word.Word)
+		w.varint(int64(word.Level))
+		w.uvarint(uint64(len(word.Categories)))
+		for _, category := range word.Categories {
+			w.string(category)
+		}
+	}
+}
+
+// binaryReader 变长编码读取器，首个错误之后的读取均返回零值
+type binaryReader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (r *binaryReader) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+func (r *binaryReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(r.data[r.pos:])
+	if n <= 0 {
+		r.fail(errors.New("corrupted varint"))
+		return 0
+	}
+	r.pos += n
+	return v
+}
+
+func (r *binaryReader) varint() int64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(r.data[r.pos:])
+	if n <= 0 {
+		r.fail(errors.New("corrupted varint"))
+		return 0
+	}
+	r.pos += n
+	return v
+}
+
+// count 读取长度字段，并防止恶意数据导致超大分配
+func (r *binaryReader) count() int {
+	v := r.uvarint()
+	if v > uint64(len(r.data)-r.pos) {
+		r.fail(fmt.Errorf("length %d exceeds remaining data", v))
+		return 0
+	}
+	return int(v)
+}
+
+func (r *binaryReader) string() string {
+	n := r.count()
+	if r.err != nil {
+		return ""
+	}
+	s := string(r.data[r.pos : r.pos+n])
+	r.pos += n
+	return s
+}
+
+// format 读取并检查格式版本
+func (r *binaryReader) format() (uint64, error) {
+	format := r.uvarint()
+	if r.err != nil {
+		return 0, fmt.Errorf("failed to read automaton: %w", r.err)
+	}
+	if format == 0 || format > serializeVersion {
+		return 0, fmt.Errorf("unsupported automaton format version: %d", format)
+	}
+	return format, nil
+}
+
+// outputs 读取输出表
+func (r *binaryReader) outputs() []*Output {
+	outputs := make([]*Output, r.count())
+	for i := range outputs {
+		output := &Output{
+			Word:  r.string(),
+			Level: int(r.varint()),
+		}
+		output.Categories = make([]string, r.count())
+		for j := range output.Categories {
+			output.Categories[j] = r.string()
+		}
+		outputs[i] = output
+	}
+	return outputs
+}
+
+// index 读取节点或输出的编号，超出范围时记录错误
+func (r *binaryReader) index(n int, kind string) uint32 {
+	id := r.uvarint()
+	if id >= uint64(n) {
+		r.fail(fmt.Errorf("%s index %d out of range", kind, id))
+		return 0
+	}
+	return uint32(id)
+}
+
+// matchEntries 读取启用拼音匹配和编辑距离匹配的敏感词，旧格式中没有的部分为nil
+func (r *binaryReader) matchEntries(format uint64) (pinyinWords, fuzzyWords []WordEntry) {
+	if format >= 2 {
+		pinyinWords = r.entries()
+		for i := range pinyinWords {
+			pinyinWords[i].Pinyin = true
+		}
+	}
+	if format >= 3 {
+		fuzzyWords = r.entries()
+		for i := range fuzzyWords {
+			fuzzyWords[i].Fuzzy = true
+		}
+	}
+	return pinyinWords, fuzzyWords
+}
+
+func (r *binaryReader) node(nodes []*ACNode) *ACNode {
+	id := r.uvarint()
+	if id >= uint64(len(nodes)) {
+		r.fail(fmt.Errorf("node index %d out of range", id))
+		return nil
+	}
+	return nodes[id]
+}
+
+// entries 读取敏感词列表
+func (r *binaryReader) entries() []WordEntry {
+	words := make([]WordEntry, r.count())
+	for i := range words {
+		words[i] = WordEntry{
+			Word:  r.string(),
+			Level: int(r.varint()),
+		}
+		words[i].Categories = make([]string, r.count())
+		for j := range words[i].Categories {
+			words[i].Categories[j] = r.string()
+		}
+	}
+	return words
+}
@@ -0,0 +1,173 @@
+package anomaly
+
+import (
+	"sync"
+	"time"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// 检测默认参数
+const (
+	defaultWindow    = 5 * time.Minute
+	defaultBaseline  = time.Hour
+	defaultMinChecks = 100
+	defaultRatio     = 3
+	defaultMinDelta  = 0.1
+	defaultCooldown  = 30 * time.Minute
+	bucketsPerWindow = 10 // 当前窗口划分的桶数，决定窗口滑动的粒度
+)
+
+// bucket 一个时间片内的计数
+type bucket struct {
+	slot    int64 // 时间片编号，用于识别环形缓冲中过期的桶
+	checks  int64
+	blocked int64
+}
+
+// Detector 按时间片统计检查和拦截次数，比较最近窗口与之前基线窗口的拦截率
+type Detector struct {
+	window    time.Duration
+	baseline  time.Duration
+	minChecks int64
+	ratio     float64
+	minDelta  float64
+	cooldown  time.Duration
+
+	mu         sync.Mutex
+	bucketSize time.Duration
+	buckets    []bucket // 环形缓冲，覆盖基线窗口和当前窗口
+	status     types.AnomalyStatus
+}
+
+// New 按配置创建检测器，未设置的参数使用默认值
+func New(config *types.AnomalyConfig) *Detector {
+	d := &Detector{
+		window:    config.Window,
+		baseline:  config.Baseline,
+		minChecks: config.MinChecks,
+		ratio:     config.Ratio,
+		minDelta:  config.MinDelta,
+		cooldown:  config.Cooldown,
+	}
+	if d.window <= 0 {
+		d.window = defaultWindow
+	}
+	if d.baseline <= 0 {
+		d.baseline = defaultBaseline
+	}
+	if d.minChecks <= 0 {
+		d.minChecks = defaultMinChecks
+	}
+	if d.ratio <= 1 {
+		d.ratio = defaultRatio
+	}
+	if d.minDelta <= 0 {
+		d.minDelta = defaultMinDelta
+	}
+	if d.cooldown <= 0 {
+		d.cooldown = defaultCooldown
+	}
+
+	d.bucketSize = d.window / bucketsPerWindow
+	if d.bucketSize < time.Second {
+		d.bucketSize = time.Second
+	}
+	count := int((d.window+d.baseline)/d.bucketSize) + 1
+	d.buckets = make([]bucket, count)
+	return d
+}
+
+// Interval 建议的判定间隔，即时间片长度
+func (d *Detector) Interval() time.Duration {
+	return d.bucketSize
+}
+
+// Record 记录一次检查
+func (d *Detector) Record(blocked bool, now time.Time) {
+	slot := now.UnixNano() / int64(d.bucketSize)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	b := &d.buckets[slot%int64(len(d.buckets))]
+	if b.slot != slot {
+		*b = bucket{slot: slot}
+	}
+	b.checks++
+	if blocked {
+		b.blocked++
+	}
+}
+
+// Evaluate 判定当前是否异常；alert为true表示应发出告警（刚进入异常，或持续异常超过冷却时间），
+// resolved为true表示刚从异常恢复
+func (d *Detector) Evaluate(now time.Time) (status types.AnomalyStatus, alert, resolved bool) {
+	current := now.UnixNano() / int64(d.bucketSize)
+	windowSlots := int64(d.window / d.bucketSize)
+	baselineSlots := int64(len(d.buckets)) - 1 - windowSlots
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var windowChecks, windowBlocked, baselineChecks, baselineBlocked int64
+	for _, b := range d.buckets {
+		age := current - b.slot
+		switch {
+		case age < 0 || b.checks == 0:
+		case age < windowSlots:
+			windowChecks += b.checks
+			windowBlocked += b.blocked
+		case age < windowSlots+baselineSlots:
+			baselineChecks += b.checks
+			baselineBlocked += b.blocked
+		}
+	}
+
+	wasAnomalous := d.status.Anomalous
+	d.status.WindowChecks = windowChecks
+	d.status.BaselineChecks = baselineChecks
+	d.status.WindowRate = rate(windowBlocked, windowChecks)
+	d.status.BaselineRate = rate(baselineBlocked, baselineChecks)
+	d.status.EvaluatedAt = now
+	d.status.Anomalous = windowChecks >= d.minChecks && baselineChecks >= d.minChecks &&
+		d.deviates(d.status.WindowRate, d.status.BaselineRate)
+
+	switch {
+	case d.status.Anomalous && (!wasAnomalous || d.status.LastAlertAt == nil || now.Sub(*d.status.LastAlertAt) >= d.cooldown):
+		alertAt := now
+		d.status.LastAlertAt = &alertAt
+		d.status.Alerts++
+		alert = true
+	case !d.status.Anomalous && wasAnomalous:
+		resolved = true
+	}
+	return d.status, alert, resolved
+}
+
+// Status 返回最近一次判定
+func (d *Detector) Status() types.AnomalyStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.status
+}
+
+// deviates 当前拦截率相对基线是否突增或突降
+func (d *Detector) deviates(current, baseline float64) bool {
+	delta := current - baseline
+	if delta < 0 {
+		delta = -delta
+	}
+	if delta < d.minDelta {
+		return false
+	}
+	return current >= baseline*d.ratio || current*d.ratio <= baseline
+}
+
+// rate 拦截率，没有检查时为0
+func rate(blocked, checks int64) float64 {
+	if checks == 0 {
+		return 0
+	}
+	return float64(blocked) / float64(checks)
+}
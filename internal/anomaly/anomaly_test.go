@@ -0,0 +1,68 @@
+package anomaly
+
+import (
+	"testing"
+	"time"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// record 在时间片内按拦截率记录检查
+func record(d *Detector, at time.Time, checks int, blockRate float64) {
+	blocked := int(float64(checks) * blockRate)
+	for i := 0; i < checks; i++ {
+		d.Record(i < blocked, at)
+	}
+}
+
+func TestDetectorBlockRateJump(t *testing.T) {
+	d := New(&types.AnomalyConfig{Window: time.Minute, Baseline: 10 * time.Minute, MinChecks: 50})
+	start := time.Unix(1700000000, 0)
+
+	// 基线：10分钟内拦截率2%
+	for i := 0; i < 10; i++ {
+		record(d, start.Add(time.Duration(i)*time.Minute), 100, 0.02)
+	}
+	now := start.Add(10*time.Minute + 30*time.Second)
+	record(d, now, 100, 0.03)
+	if status, alert, _ := d.Evaluate(now); status.Anomalous || alert {
+		t.Fatalf("unexpected anomaly: %+v", status)
+	}
+
+	// 词库发布后拦截率跳到40%
+	now = now.Add(20 * time.Second)
+	record(d, now, 300, 0.4)
+	status, alert, _ := d.Evaluate(now)
+	if !status.Anomalous || !alert {
+		t.Fatalf("expected anomaly, got %+v", status)
+	}
+	if status.BaselineRate > 0.05 || status.WindowRate < 0.2 {
+		t.Errorf("unexpected rates: %+v", status)
+	}
+
+	// 冷却时间内持续异常不重复告警
+	if _, alert, _ := d.Evaluate(now.Add(time.Second)); alert {
+		t.Error("expected no repeated alert within cooldown")
+	}
+
+	// 窗口滑过后恢复
+	later := now.Add(2 * time.Minute)
+	record(d, later, 100, 0.02)
+	status, _, resolved := d.Evaluate(later)
+	if status.Anomalous || !resolved {
+		t.Errorf("expected recovery, got %+v", status)
+	}
+}
+
+func TestDetectorMinChecks(t *testing.T) {
+	d := New(&types.AnomalyConfig{Window: time.Minute, Baseline: 10 * time.Minute, MinChecks: 100})
+	start := time.Unix(1700000000, 0)
+	record(d, start, 1000, 0.01)
+
+	// 当前窗口检查次数不足时不判定
+	now := start.Add(5 * time.Minute)
+	record(d, now, 10, 1)
+	if status, _, _ := d.Evaluate(now); status.Anomalous {
+		t.Errorf("expected no anomaly with few checks, got %+v", status)
+	}
+}
@@ -0,0 +1,254 @@
+package apollo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apolloconfig/agollo/v4"
+	"github.com/apolloconfig/agollo/v4/env/config"
+	"github.com/apolloconfig/agollo/v4/storage"
+
+	"github.com/guardian/content-filter/internal/logging"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+const (
+	defaultCluster   = "default"
+	defaultNamespace = "application"
+	defaultEnv       = "DEV"
+	defaultOperator  = "apollo"
+)
+
+// Client Apollo客户端，将 Group 映射为命名空间，DataId 映射为命名空间下的键
+type Client struct {
+	client     agollo.Client
+	config     *types.ApolloConfig
+	logger     logging.Logger
+	httpClient *http.Client
+	mu         sync.RWMutex
+	listeners  map[string][]func(string)
+}
+
+// NewClient 创建新的Apollo客户端
+func NewClient(cfg *types.ApolloConfig, logger logging.Logger) (*Client, error) {
+	timeout := time.Duration(cfg.TimeoutMs) * time.Millisecond
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	namespaces := cfg.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{defaultNamespace}
+	}
+
+	appConfig := &config.AppConfig{
+		AppID:            cfg.AppId,
+		Cluster:          orDefault(cfg.Cluster, defaultCluster),
+		IP:               cfg.MetaAddr,
+		NamespaceName:    strings.Join(namespaces, ","),
+		Secret:           cfg.Secret,
+		IsBackupConfig:   cfg.BackupPath != "",
+		BackupConfigPath: cfg.BackupPath,
+		MustStart:        true,
+	}
+
+	agollo.SetLogger(agolloLogger{logger})
+	client, err := agollo.StartWithConfig(func() (*config.AppConfig, error) {
+		return appConfig, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create apollo client: %w", err)
+	}
+
+	c := &Client{
+		client:     client,
+		config:     cfg,
+		logger:     logger,
+		httpClient: &http.Client{Timeout: timeout},
+		listeners:  make(map[string][]func(string)),
+	}
+	client.AddChangeListener(c)
+
+	return c, nil
+}
+
+// GetConfig 获取配置
+func (c *Client) GetConfig(dataId, group string) (string, error) {
+	namespace := c.namespace(group)
+	cfg := c.client.GetConfigAndInit(namespace)
+	if cfg == nil {
+		return "", fmt.Errorf("%w: apollo namespace not found: %s", types.ErrWordSourceUnavailable, namespace)
+	}
+
+	content := cfg.GetValue(dataId)
+	if content == "" {
+		return "", fmt.Errorf("%w: config not found: %s/%s", types.ErrEmptyDictionary, namespace, dataId)
+	}
+
+	return content, nil
+}
+
+// ListenConfig 监听配置变化
+func (c *Client) ListenConfig(dataId, group string, callback func(string)) error {
+	namespace := c.namespace(group)
+	if c.client.GetConfigAndInit(namespace) == nil {
+		return fmt.Errorf("%w: apollo namespace not found: %s", types.ErrWordSourceUnavailable, namespace)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := listenerKey(namespace, dataId)
+	c.listeners[key] = append(c.listeners[key], callback)
+
+	return nil
+}
+
+// OnChange 实现 storage.ChangeListener，将变更分发给对应键的监听者
+func (c *Client) OnChange(event *storage.ChangeEvent) {
+	for key, change := range event.Changes {
+		if change.ChangeType == storage.DELETED {
+			c.logger.Warnf("Apollo config deleted: namespace=%s, key=%s", event.Namespace, key)
+			continue
+		}
+
+		c.mu.RLock()
+		callbacks := c.listeners[listenerKey(event.Namespace, key)]
+		c.mu.RUnlock()
+		if len(callbacks) == 0 {
+			continue
+		}
+
+		content, ok := change.NewValue.(string)
+		if !ok {
+			content = fmt.Sprint(change.NewValue)
+		}
+
+		c.logger.Infof("Config changed: namespace=%s, key=%s", event.Namespace, key)
+		for _, callback := range callbacks {
+			callback(content)
+		}
+	}
+}
+
+// OnNewestChange 实现 storage.ChangeListener
+func (c *Client) OnNewestChange(event *storage.FullChangeEvent) {}
+
+// PublishConfig 通过Apollo开放平台接口修改并发布配置，需要配置 portal_addr 和 token
+func (c *Client) PublishConfig(dataId, group, content string) error {
+	if c.config.PortalAddr == "" || c.config.Token == "" {
+		return fmt.Errorf("failed to publish config: apollo portal_addr and token are required")
+	}
+
+	namespace := c.namespace(group)
+	operator := orDefault(c.config.Operator, defaultOperator)
+	base := fmt.Sprintf("%s/openapi/v1/envs/%s/apps/%s/clusters/%s/namespaces/%s",
+		strings.TrimRight(c.config.PortalAddr, "/"),
+		url.PathEscape(orDefault(c.config.Env, defaultEnv)),
+		url.PathEscape(c.config.AppId),
+		url.PathEscape(orDefault(c.config.Cluster, defaultCluster)),
+		url.PathEscape(namespace))
+
+	item := map[string]string{
+		"key":                      dataId,
+		"value":                    content,
+		"dataChangeCreatedBy":      operator,
+		"dataChangeLastModifiedBy": operator,
+	}
+	itemURL := base + "/items/" + url.PathEscape(dataId) + "?createIfNotExists=true"
+	if err := c.openAPI(http.MethodPut, itemURL, item); err != nil {
+		return fmt.Errorf("failed to publish config: %w", err)
+	}
+
+	release := map[string]string{
+		"releaseTitle": fmt.Sprintf("guardian-%s-%s", dataId, time.Now().Format("20060102150405")),
+		"releasedBy":   operator,
+	}
+	if err := c.openAPI(http.MethodPost, base+"/releases", release); err != nil {
+		return fmt.Errorf("failed to release config: %w", err)
+	}
+
+	c.logger.Infof("Config published successfully: namespace=%s, key=%s", namespace, dataId)
+	return nil
+}
+
+// HealthCheck 健康检查
+func (c *Client) HealthCheck() error {
+	namespace := c.namespace("")
+	if c.client.GetConfig(namespace) == nil {
+		return fmt.Errorf("apollo namespace not loaded: %s", namespace)
+	}
+	return nil
+}
+
+// Close 关闭客户端
+func (c *Client) Close() error {
+	c.client.RemoveChangeListener(c)
+	c.client.Close()
+	return nil
+}
+
+// namespace 将 Group 映射为命名空间，DEFAULT_GROUP 或空值使用首个配置的命名空间
+func (c *Client) namespace(group string) string {
+	if group != "" && group != "DEFAULT_GROUP" {
+		return group
+	}
+	if len(c.config.Namespaces) > 0 {
+		return c.config.Namespaces[0]
+	}
+	return defaultNamespace
+}
+
+// openAPI 调用Apollo开放平台接口
+func (c *Client) openAPI(method, endpoint string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(method, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", c.config.Token)
+	req.Header.Set("Content-Type", "application/json;charset=UTF-8")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("apollo open api returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+func listenerKey(namespace, key string) string {
+	return namespace + "\x00" + key
+}
+
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// agolloLogger 适配 agollo 的日志接口，非格式化方法按 fmt.Sprint 拼接后输出
+type agolloLogger struct {
+	logging.Logger
+}
+
+func (l agolloLogger) Debug(v ...interface{}) { l.Debugf("%s", fmt.Sprint(v...)) }
+func (l agolloLogger) Info(v ...interface{})  { l.Infof("%s", fmt.Sprint(v...)) }
+func (l agolloLogger) Warn(v ...interface{})  { l.Warnf("%s", fmt.Sprint(v...)) }
+func (l agolloLogger) Error(v ...interface{}) { l.Errorf("%s", fmt.Sprint(v...)) }
@@ -0,0 +1,335 @@
+package artifact
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/guardian/content-filter/internal/algorithm"
+	"github.com/guardian/content-filter/internal/normalize"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// 产物文件布局：
+//
+//	magic(4) | formatVersion(uint16) | metaLen(uint32) | meta(JSON) | payloadLen(uint64) | payload | sigLen(uint16) | signature | sha256(32)
+//
+// 签名为Ed25519私钥对payload及其之前内容的SHA-256摘要的签名，未签名时sigLen为0；
+// 校验和覆盖其之前的全部内容。格式版本1没有签名段，仍可加载。
+const (
+	magic         = "GDNA"
+	formatVersion = 2
+)
+
+var (
+	// ErrInvalidArtifact 产物文件格式错误
+	ErrInvalidArtifact = errors.New("invalid word list artifact")
+	// ErrChecksumMismatch 产物校验和不匹配
+	ErrChecksumMismatch = errors.New("word list artifact checksum mismatch")
+	// ErrScheduledWord 词库包含定时生效或失效的敏感词，产物中的自动机固定不变，无法按时段生效
+	ErrScheduledWord = errors.New("scheduled words are not supported in word list artifacts")
+	// ErrMissingSignature 要求签名但产物未签名
+	ErrMissingSignature = errors.New("word list artifact is not signed")
+	// ErrInvalidSignature 产物签名与公钥不匹配
+	ErrInvalidSignature = errors.New("word list artifact signature is invalid")
+)
+
+// Metadata 产物元数据
+type Metadata struct {
+	Version      string                `json:"version"`                // 词库版本
+	UpdateTime   time.Time             `json:"update_time"`            // 词库更新时间
+	BuildTime    time.Time             `json:"build_time"`             // 编译时间
+	WordCount    int                   `json:"word_count"`             // 敏感词数量
+	NodeCount    int                   `json:"node_count"`             // 自动机节点数量
+	Whitelist    []string              `json:"whitelist"`              // 白名单
+	Replacements map[string]string     `json:"replacements,omitempty"` // 敏感词 -> 替换词
+	Words        []types.SensitiveWord `json:"words,omitempty"`        // 编译的敏感词，只在 CompileOptions.WordStats 时保存，加载后用于命中统计、词库对比和检查
+	Normalize    string                `json:"normalize"`              // 编译时敏感词使用的标准化规则
+	WholeWords   []string              `json:"whole_words"`            // 只匹配完整单词的敏感词
+	Exclusions   map[string][]string   `json:"exclusions"`             // 敏感词 -> 排除语境
+	Weights      map[string]float64    `json:"weights"`                // 敏感词 -> 风险权重
+	Monitor      []string              `json:"monitor"`                // 只观察的敏感词
+	Checksum     string                `json:"-"`                      // 文件校验和（加载时填充）
+}
+
+// WordDatabase 返回产物对应的词库，用于运行时统计、对比和检查；只包含元数据中记录的内容
+func (m *Metadata) WordDatabase() *types.WordDatabase {
+	return &types.WordDatabase{
+		Version:      m.Version,
+		UpdateTime:   m.UpdateTime,
+		Whitelist:    m.Whitelist,
+		Blacklist:    m.Words,
+		Replacements: m.Replacements,
+	}
+}
+
+// Artifact 编译产物
+type Artifact struct {
+	Metadata  *Metadata
+	Automaton algorithm.Automaton // Load 恢复为map布局，LoadFlat 直接恢复为紧凑布局
+
+	digest    []byte // 签名覆盖内容的摘要（加载时填充）
+	signature []byte // 产物签名（加载时填充）
+}
+
+// CompileOptions 编译选项
+type CompileOptions struct {
+	WordStats bool // 是否在元数据中保存完整的敏感词；默认只保存匹配所需的内容，敏感词的分类、等级等已编译进自动机
+}
+
+// Compile 将词库编译为产物，敏感词按normalizer标准化后加入自动机，与运行时构建一致
+// normalizer须与加载产物的实例使用同一套标准化规则，规则记录在 Metadata.Normalize 中
+// 产物中的自动机固定不变，词库包含定时生效或失效的敏感词时返回 ErrScheduledWord
+func Compile(wordDB *types.WordDatabase, normalizer *normalize.Normalizer, options *CompileOptions) (*Artifact, error) {
+	if options == nil {
+		options = &CompileOptions{}
+	}
+
+	words := wordDB.Words()
+	for _, word := range words {
+		if word.Scheduled() {
+			return nil, fmt.Errorf("%w: %q", ErrScheduledWord, word.Word)
+		}
+	}
+
+	automaton := algorithm.NewACAutomaton()
+	wordCount := 0
+	wholeWords := make([]string, 0)
+	exclusions := make(map[string][]string)
+	weights := make(map[string]float64)
+	monitor := make([]string, 0)
+	for _, word := range words {
+		addWord(automaton, normalizer.Normalize(word.Word), word)
+		wordCount++
+		if word.WholeWord {
+			wholeWords = append(wholeWords, word.Word)
+		}
+		if len(word.Exclusions) > 0 {
+			exclusions[word.Word] = append(exclusions[word.Word], word.Exclusions...)
+		}
+		if word.Weight > 0 {
+			weights[word.Word] = word.Weight
+		}
+		if word.Monitor {
+			monitor = append(monitor, word.Word)
+		}
+	}
+	automaton.BuildFailPointers()
+	automaton.SetVersion(wordDB.Version)
+	if !options.WordStats {
+		words = nil
+	}
+
+	return &Artifact{
+		Metadata: &Metadata{
+			Version:      wordDB.Version,
+			UpdateTime:   wordDB.UpdateTime,
+			BuildTime:    time.Now(),
+			WordCount:    wordCount,
+			NodeCount:    automaton.GetNodeCount(),
+			Whitelist:    wordDB.Whitelist,
+			Replacements: wordDB.Replacements,
+			Words:        words,
+			Normalize:    normalizer.Signature(),
+			WholeWords:   wholeWords,
+			Exclusions:   exclusions,
+			Weights:      weights,
+			Monitor:      monitor,
+		},
+		Automaton: automaton,
+	}, nil
+}
+
+// addWord 以标准化后的形式添加敏感词，按词语设置启用拼音匹配和编辑距离匹配
+func addWord(automaton *algorithm.ACAutomaton, normalized string, word types.SensitiveWord) {
+	automaton.AddWordEntry(algorithm.WordEntry{
+		Word:       normalized,
+		Categories: word.Categories,
+		Level:      word.Level,
+		Pinyin:     word.Pinyin,
+		Fuzzy:      word.Fuzzy,
+	})
+}
+
+// Marshal 序列化产物，key不为nil时签名
+func (a *Artifact) Marshal(key ed25519.PrivateKey) ([]byte, error) {
+	meta, err := json.Marshal(a.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal artifact metadata: %w", err)
+	}
+
+	payload, err := a.Automaton.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal automaton: %w", err)
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(len(magic) + 2 + 4 + len(meta) + 8 + len(payload) + 2 + ed25519.SignatureSize + sha256.Size)
+	buf.WriteString(magic)
+	binary.Write(&buf, binary.BigEndian, uint16(formatVersion))
+	binary.Write(&buf, binary.BigEndian, uint32(len(meta)))
+	buf.Write(meta)
+	binary.Write(&buf, binary.BigEndian, uint64(len(payload)))
+	buf.Write(payload)
+
+	var signature []byte
+	if key != nil {
+		digest := sha256.Sum256(buf.Bytes())
+		signature = ed25519.Sign(key, digest[:])
+	}
+	binary.Write(&buf, binary.BigEndian, uint16(len(signature)))
+	buf.Write(signature)
+
+	sum := sha256.Sum256(buf.Bytes())
+	buf.Write(sum[:])
+
+	return buf.Bytes(), nil
+}
+
+// Unmarshal 反序列化产物并校验完整性，自动机恢复为map布局
+func Unmarshal(data []byte) (*Artifact, error) {
+	return unmarshal(data, false)
+}
+
+// UnmarshalFlat 与 Unmarshal 相同，但自动机直接恢复为紧凑布局，不经过map布局
+func UnmarshalFlat(data []byte) (*Artifact, error) {
+	return unmarshal(data, true)
+}
+
+// unmarshal 反序列化产物并校验完整性，flat为true时自动机恢复为紧凑布局
+func unmarshal(data []byte, flat bool) (*Artifact, error) {
+	headerLen := len(magic) + 2 + 4
+	if len(data) < headerLen+8+sha256.Size || string(data[:len(magic)]) != magic {
+		return nil, ErrInvalidArtifact
+	}
+
+	body := data[:len(data)-sha256.Size]
+	sum := sha256.Sum256(body)
+	if !bytes.Equal(sum[:], data[len(body):]) {
+		return nil, ErrChecksumMismatch
+	}
+
+	version := binary.BigEndian.Uint16(data[len(magic):])
+	if version != 1 && version != formatVersion {
+		return nil, fmt.Errorf("%w: unsupported format version %d", ErrInvalidArtifact, version)
+	}
+
+	pos := headerLen
+	metaLen := int(binary.BigEndian.Uint32(data[len(magic)+2:]))
+	if pos+metaLen+8 > len(body) {
+		return nil, fmt.Errorf("%w: truncated metadata", ErrInvalidArtifact)
+	}
+
+	var meta Metadata
+	err := json.Unmarshal(body[pos:pos+metaLen], &meta)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidArtifact, err)
+	}
+	meta.Checksum = fmt.Sprintf("%x", sum)
+	pos += metaLen
+
+	payloadLen := binary.BigEndian.Uint64(body[pos:])
+	pos += 8
+	signed := body
+	var signature []byte
+	if version >= 2 {
+		if len(body)-pos < 2 || payloadLen > uint64(len(body)-pos-2) {
+			return nil, fmt.Errorf("%w: truncated payload", ErrInvalidArtifact)
+		}
+		end := pos + int(payloadLen)
+		signed, signature = body[:end], body[end+2:]
+		if int(binary.BigEndian.Uint16(body[end:])) != len(signature) {
+			return nil, fmt.Errorf("%w: truncated signature", ErrInvalidArtifact)
+		}
+	}
+	if payloadLen != uint64(len(signed)-pos) {
+		return nil, fmt.Errorf("%w: truncated payload", ErrInvalidArtifact)
+	}
+
+	var automaton algorithm.Automaton
+	if flat {
+		automaton, err = algorithm.UnmarshalFlat(signed[pos:])
+	} else {
+		ac := algorithm.NewACAutomaton()
+		automaton, err = ac, ac.UnmarshalBinary(signed[pos:])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidArtifact, err)
+	}
+
+	digest := sha256.Sum256(signed)
+	return &Artifact{
+		Metadata:  &meta,
+		Automaton: automaton,
+		digest:    digest[:],
+		signature: signature,
+	}, nil
+}
+
+// Verify 校验加载的产物签名，key为nil时不要求签名
+func (a *Artifact) Verify(key ed25519.PublicKey) error {
+	if key == nil {
+		return nil
+	}
+	if len(a.signature) == 0 {
+		return fmt.Errorf("%w: version %s", ErrMissingSignature, a.Metadata.Version)
+	}
+	if !ed25519.Verify(key, a.digest, a.signature) {
+		return fmt.Errorf("%w: version %s", ErrInvalidSignature, a.Metadata.Version)
+	}
+	return nil
+}
+
+// Save 原子写入产物文件，key不为nil时签名
+func (a *Artifact) Save(path string, key ed25519.PrivateKey) error {
+	data, err := a.Marshal(key)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create artifact file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write artifact file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write artifact file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write artifact file: %w", err)
+	}
+	return nil
+}
+
+// Load 加载产物文件，自动机恢复为map布局
+func Load(path string) (*Artifact, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artifact file: %w", err)
+	}
+
+	return Unmarshal(data)
+}
+
+// LoadFlat 加载产物文件，自动机直接恢复为紧凑布局
+func LoadFlat(path string) (*Artifact, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read artifact file: %w", err)
+	}
+
+	return UnmarshalFlat(data)
+}
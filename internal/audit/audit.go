@@ -0,0 +1,114 @@
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"github.com/guardian/content-filter/internal/logging"
+	"github.com/guardian/content-filter/internal/trace"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// defaultMaxTextLength text为truncate时默认保留的字符数
+const defaultMaxTextLength = 200
+
+// defaultActions 默认记录的处理动作
+var defaultActions = []types.Action{types.ActionMask, types.ActionReview, types.ActionReject}
+
+// Auditor 按配置筛选和抽样审核决定，在后台按批写入输出
+type Auditor struct {
+	*writer
+	config  *types.AuditConfig
+	actions map[types.Action]bool
+	skipped atomic.Int64
+}
+
+// New 创建审计器并启动后台写入
+func New(config *types.AuditConfig, sink Sink, logger logging.Logger) *Auditor {
+	actions := config.Actions
+	if len(actions) == 0 {
+		actions = defaultActions
+	}
+
+	a := &Auditor{
+		writer:  newWriter(&config.SinkConfig, sink, logger, "audit"),
+		config:  config,
+		actions: make(map[types.Action]bool, len(actions)),
+	}
+	for _, action := range actions {
+		a.actions[action] = true
+	}
+	return a
+}
+
+// Record 记录一次审核决定，不需要记录、未抽中或缓冲区已满时直接返回，不阻塞调用方
+func (a *Auditor) Record(ctx context.Context, text string, result *types.FilterResult, version string) {
+	if !a.actions[result.Action] {
+		return
+	}
+	if rate := a.config.SampleRate; rate > 0 && rate < 1 && rand.Float64() >= rate {
+		a.skipped.Add(1)
+		return
+	}
+
+	record := newRecord(ctx, text, result, version)
+	switch a.config.Text {
+	case types.AuditTextFull:
+		record.Text = text
+	case types.AuditTextTruncate:
+		record.Text = truncate(text, a.config.MaxTextLength)
+	}
+	a.send(record)
+}
+
+// newRecord 生成不含原文的记录
+func newRecord(ctx context.Context, text string, result *types.FilterResult, version string) *types.AuditRecord {
+	tenant, caller := trace.Caller(ctx)
+	hash := sha256.Sum256([]byte(text))
+	return &types.AuditRecord{
+		Time:       time.Now(),
+		TraceID:    trace.TraceID(ctx),
+		Tenant:     tenant,
+		Caller:     caller,
+		TextHash:   hex.EncodeToString(hash[:]),
+		TextLength: len(text),
+		Action:     result.Action,
+		ReasonCode: result.ReasonCode,
+		Words:      result.Words,
+		Categories: result.Categories,
+		MaxLevel:   result.MaxLevel,
+		RiskScore:  result.RiskScore,
+		Degraded:   result.Degraded,
+		Version:    version,
+	}
+}
+
+// truncate 保留前n个字符，n不大于0时使用默认长度
+func truncate(text string, n int) string {
+	if n <= 0 {
+		n = defaultMaxTextLength
+	}
+	for i := range text {
+		if n == 0 {
+			return text[:i]
+		}
+		n--
+	}
+	return text
+}
+
+// Stats 返回写入统计
+func (a *Auditor) Stats() *types.AuditStats {
+	stats := a.stats()
+	stats.Skipped = a.skipped.Load()
+	return stats
+}
+
+// Close 写完缓冲区中的记录后关闭输出
+func (a *Auditor) Close() error {
+	return a.close()
+}
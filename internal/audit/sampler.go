@@ -0,0 +1,54 @@
+package audit
+
+import (
+	"context"
+	"math/rand"
+	"sync/atomic"
+
+	"github.com/guardian/content-filter/internal/logging"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// Sampler 按比例抽取未通过和通过的结果写入复核队列，记录包含完整原文
+type Sampler struct {
+	*writer
+	config  *types.SamplingConfig
+	skipped atomic.Int64
+}
+
+// NewSampler 创建抽样器并启动后台写入
+func NewSampler(config *types.SamplingConfig, sink Sink, logger logging.Logger) *Sampler {
+	return &Sampler{
+		writer: newWriter(&config.SinkConfig, sink, logger, "sampling"),
+		config: config,
+	}
+}
+
+// Record 按结果是否通过使用对应比例抽样，未抽中或缓冲区已满时直接返回，不阻塞调用方
+func (s *Sampler) Record(ctx context.Context, text string, result *types.FilterResult, version string) {
+	sample, rate := types.SampleBlocked, s.config.BlockedRate
+	if result.Passed {
+		sample, rate = types.SamplePassed, s.config.PassedRate
+	}
+	if rate < 1 && rand.Float64() >= rate {
+		s.skipped.Add(1)
+		return
+	}
+
+	record := newRecord(ctx, text, result, version)
+	record.Text = text
+	record.Sample = sample
+	s.send(record)
+}
+
+// Stats 返回写入统计
+func (s *Sampler) Stats() *types.AuditStats {
+	stats := s.stats()
+	stats.Skipped = s.skipped.Load()
+	return stats
+}
+
+// Close 写完缓冲区中的记录后关闭输出
+func (s *Sampler) Close() error {
+	return s.close()
+}
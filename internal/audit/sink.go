@@ -0,0 +1,163 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/guardian/content-filter/internal/kafkarest"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// defaultTimeout http和kafka请求的默认超时
+const defaultTimeout = 5 * time.Second
+
+// Sink 审计记录的输出，Write 按批调用，同一时刻只有一个批次在写入
+type Sink interface {
+	// Write 写入一批记录
+	Write(ctx context.Context, records []*types.AuditRecord) error
+	// Close 关闭输出
+	Close() error
+}
+
+// NewSink 按配置创建审计记录输出
+func NewSink(config *types.SinkConfig) (Sink, error) {
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	switch config.Sink {
+	case types.AuditSinkFile:
+		return NewFileSink(config.Path)
+	case types.AuditSinkHTTP:
+		return NewHTTPSink(config.URL, config.Headers, timeout), nil
+	case types.AuditSinkKafka:
+		return NewKafkaSink(config.URL, config.Topic, config.Headers, timeout), nil
+	default:
+		return nil, fmt.Errorf("unsupported audit sink: %s", config.Sink)
+	}
+}
+
+// FileSink 按JSON Lines追加写入本地文件
+type FileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileSink 打开（不存在时创建）审计日志文件
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	return &FileSink{file: file}, nil
+}
+
+// Write 每条记录写为一行JSON
+func (s *FileSink) Write(ctx context.Context, records []*types.AuditRecord) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return fmt.Errorf("failed to marshal audit record: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.file.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// Close 关闭文件
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+// HTTPSink 将每批记录以JSON数组POST到指定地址，非2xx响应视为失败
+type HTTPSink struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+// NewHTTPSink 创建HTTP输出
+func NewHTTPSink(endpoint string, headers map[string]string, timeout time.Duration) *HTTPSink {
+	return &HTTPSink{url: endpoint, headers: headers, client: &http.Client{Timeout: timeout}}
+}
+
+// Write 发送一批记录
+func (s *HTTPSink) Write(ctx context.Context, records []*types.AuditRecord) error {
+	return post(ctx, s.client, s.url, s.headers, records)
+}
+
+// Close 无需关闭
+func (s *HTTPSink) Close() error {
+	return nil
+}
+
+// KafkaSink 通过 Kafka REST Proxy（v2 接口）写入主题，每条记录为一条消息
+type KafkaSink struct {
+	producer *kafkarest.Producer
+}
+
+// NewKafkaSink 创建Kafka输出，proxyURL为REST Proxy地址
+func NewKafkaSink(proxyURL, topic string, headers map[string]string, timeout time.Duration) *KafkaSink {
+	return &KafkaSink{producer: kafkarest.NewProducer(proxyURL, topic, headers, timeout)}
+}
+
+// Write 发送一批记录
+func (s *KafkaSink) Write(ctx context.Context, records []*types.AuditRecord) error {
+	values := make([]interface{}, len(records))
+	for i, record := range records {
+		values[i] = record
+	}
+	return s.producer.Produce(ctx, values...)
+}
+
+// Close 无需关闭
+func (s *KafkaSink) Close() error {
+	return nil
+}
+
+// post 以JSON发送请求体，非2xx响应视为失败
+func post(ctx context.Context, client *http.Client, endpoint string, headers map[string]string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit records: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send audit records: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("audit endpoint returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
@@ -0,0 +1,143 @@
+package audit
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/guardian/content-filter/internal/logging"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// 后台写入默认参数
+const (
+	defaultBufferSize    = 10000
+	defaultBatchSize     = 100
+	defaultFlushInterval = time.Second
+)
+
+// writer 缓冲记录并在后台按批写入输出，审计日志和抽样复核共用
+type writer struct {
+	config  *types.SinkConfig
+	sink    Sink
+	logger  logging.Logger
+	name    string
+	records chan *types.AuditRecord
+	done    chan struct{}
+	stopped sync.WaitGroup
+	once    sync.Once
+	written atomic.Int64
+	dropped atomic.Int64
+	failed  atomic.Int64
+}
+
+// newWriter 创建并启动后台写入，name用于日志
+func newWriter(config *types.SinkConfig, sink Sink, logger logging.Logger, name string) *writer {
+	bufferSize := config.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = defaultBufferSize
+	}
+
+	w := &writer{
+		config:  config,
+		sink:    sink,
+		logger:  logger,
+		name:    name,
+		records: make(chan *types.AuditRecord, bufferSize),
+		done:    make(chan struct{}),
+	}
+	w.stopped.Add(1)
+	go w.run()
+	return w
+}
+
+// send 将记录放入缓冲区，已关闭或缓冲区已满时直接返回，不阻塞调用方
+func (w *writer) send(record *types.AuditRecord) {
+	select {
+	case <-w.done:
+		return
+	default:
+	}
+
+	select {
+	case w.records <- record:
+	default:
+		w.dropped.Add(1)
+	}
+}
+
+// run 后台按批写入，满一批或到达刷新间隔时写入；关闭时写完缓冲区中的记录
+func (w *writer) run() {
+	defer w.stopped.Done()
+
+	batchSize := w.config.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	interval := w.config.FlushInterval
+	if interval <= 0 {
+		interval = defaultFlushInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	batch := make([]*types.AuditRecord, 0, batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := w.sink.Write(context.Background(), batch); err != nil {
+			w.failed.Add(int64(len(batch)))
+			w.logger.Errorf("Failed to write %d %s record(s): %v", len(batch), w.name, err)
+		} else {
+			w.written.Add(int64(len(batch)))
+		}
+		batch = make([]*types.AuditRecord, 0, batchSize)
+	}
+
+	for {
+		select {
+		case record := <-w.records:
+			batch = append(batch, record)
+			if len(batch) >= batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-w.done:
+			for {
+				select {
+				case record := <-w.records:
+					batch = append(batch, record)
+					if len(batch) >= batchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// stats 返回写入统计，Skipped由调用方填写
+func (w *writer) stats() *types.AuditStats {
+	return &types.AuditStats{
+		Written: w.written.Load(),
+		Dropped: w.dropped.Load(),
+		Failed:  w.failed.Load(),
+	}
+}
+
+// close 写完缓冲区中的记录后关闭输出
+func (w *writer) close() error {
+	var err error
+	w.once.Do(func() {
+		close(w.done)
+		w.stopped.Wait()
+		err = w.sink.Close()
+	})
+	return err
+}
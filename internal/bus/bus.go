@@ -0,0 +1,68 @@
+package bus
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"time"
+)
+
+// EventType 事件类型
+type EventType string
+
+const (
+	EventInvalidate EventType = "invalidate" // 丢弃缓存的过滤结果
+	EventReload     EventType = "reload"     // 从词库源重新加载词库（同时丢弃缓存）
+)
+
+// Event 集群广播事件
+type Event struct {
+	ID      string    `json:"id"`                 // 事件ID
+	Type    EventType `json:"type"`               // 事件类型
+	Source  string    `json:"source"`             // 发送方实例ID
+	Version string    `json:"version"`            // 发送方词库版本
+	Reason  string    `json:"reason"`             // 触发原因
+	TraceID string    `json:"trace_id,omitempty"` // 触发该事件的请求追踪ID
+	Time    time.Time `json:"time"`               // 发送时间
+}
+
+// Bus 缓存失效广播总线
+type Bus interface {
+	// Publish 广播事件
+	Publish(event *Event) error
+	// Subscribe 订阅其他实例发出的事件，本实例发出的事件不会回调
+	Subscribe(handler func(event *Event)) error
+	// Close 关闭总线
+	Close() error
+}
+
+// NewEvent 创建事件
+func NewEvent(eventType EventType, source, version, reason string) *Event {
+	return &Event{
+		ID:      randomID(),
+		Type:    eventType,
+		Source:  source,
+		Version: version,
+		Reason:  reason,
+		Time:    time.Now(),
+	}
+}
+
+// NewInstanceID 生成实例ID
+func NewInstanceID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), randomID()[:8])
+}
+
+// randomID 生成随机ID
+func randomID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,61 @@
+package bus
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/guardian/content-filter/internal/logging"
+	"github.com/guardian/content-filter/internal/source"
+)
+
+// ConfigBus 基于配置中心的广播总线，事件以JSON写入专用的dataId
+type ConfigBus struct {
+	client     source.ConfigSource
+	dataId     string
+	group      string
+	instanceId string
+	logger     logging.Logger
+}
+
+// NewConfigBus 创建基于配置中心（Nacos、etcd等）的广播总线
+func NewConfigBus(client source.ConfigSource, dataId, group, instanceId string, logger logging.Logger) *ConfigBus {
+	return &ConfigBus{
+		client:     client,
+		dataId:     dataId,
+		group:      group,
+		instanceId: instanceId,
+		logger:     logger,
+	}
+}
+
+// Publish 广播事件
+func (b *ConfigBus) Publish(event *Event) error {
+	content, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal bus event: %w", err)
+	}
+
+	return b.client.PublishConfig(b.dataId, b.group, string(content))
+}
+
+// Subscribe 订阅事件
+func (b *ConfigBus) Subscribe(handler func(event *Event)) error {
+	return b.client.ListenConfig(b.dataId, b.group, func(content string) {
+		var event Event
+		if err := json.Unmarshal([]byte(content), &event); err != nil {
+			b.logger.Errorf("Failed to unmarshal bus event: %v", err)
+			return
+		}
+
+		if event.Source == b.instanceId {
+			return
+		}
+
+		handler(&event)
+	})
+}
+
+// Close 关闭总线
+func (b *ConfigBus) Close() error {
+	return nil
+}
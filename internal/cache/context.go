@@ -0,0 +1,17 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// ContextCache 检查结果缓存，Get、Set 带调用方的上下文，远程存储（Redis等）的读写随请求取消或超时
+// 内置的分段LRU缓存和Redis两级缓存都实现了该接口
+type ContextCache interface {
+	Get(ctx context.Context, key string) (*types.FilterResult, bool)
+	Set(ctx context.Context, key string, value *types.FilterResult)
+	Clear()
+	Stats() map[string]interface{}
+	Close()
+}
@@ -0,0 +1,189 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/guardian/content-filter/internal/logging"
+	"github.com/guardian/content-filter/internal/redis"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+const (
+	// defaultKeyPrefix 默认键前缀
+	defaultKeyPrefix = "guardian"
+	// clearBatch 清空时每批扫描和删除的键数
+	clearBatch = 500
+)
+
+// RedisCache 两级缓存：进程内LRU为L1，Redis为L2
+// 多个实例共享L2，相同文本在任一实例检查过后其他实例直接命中，重启后缓存仍然有效
+// 键为 {prefix}:cache:{key}，值为结果的JSON；Redis不可用时只使用L1，不影响检查
+type RedisCache struct {
+	local   ContextCache
+	client  goredis.UniversalClient
+	prefix  string
+	ttl     time.Duration
+	timeout time.Duration
+	logger  logging.Logger
+	hits    atomic.Int64 // L1未命中、L2命中的次数
+	misses  atomic.Int64 // L1和L2都未命中的次数
+	errors  atomic.Int64 // Redis读写失败的次数
+}
+
+// NewRedisCache 连接Redis并创建两级缓存，local为L1，ttl为L2中结果的过期时间
+func NewRedisCache(config *types.RedisConfig, local ContextCache, ttl time.Duration, logger logging.Logger) (*RedisCache, error) {
+	prefix := config.KeyPrefix
+	if prefix == "" {
+		prefix = defaultKeyPrefix
+	}
+
+	client, timeout := redis.NewUniversalClient(config)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+
+	return &RedisCache{
+		local:   local,
+		client:  client,
+		prefix:  prefix + ":cache:",
+		ttl:     ttl,
+		timeout: timeout,
+		logger:  logger,
+	}, nil
+}
+
+// Get 依次查找L1和L2，L2命中时写回L1；读取L2的超时不超过调用方上下文的截止时间
+func (c *RedisCache) Get(ctx context.Context, key string) (*types.FilterResult, bool) {
+	if result, found := c.local.Get(ctx, key); found {
+		return result, true
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	data, err := c.client.Get(ctx, c.prefix+key).Bytes()
+	if err == goredis.Nil {
+		c.misses.Add(1)
+		return nil, false
+	}
+	if err != nil {
+		c.errors.Add(1)
+		c.logger.Warnf("Failed to read cached result from redis: %v", err)
+		return nil, false
+	}
+
+	var result types.FilterResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		c.errors.Add(1)
+		c.logger.Warnf("Failed to decode cached result from redis: %v", err)
+		return nil, false
+	}
+	c.hits.Add(1)
+	c.local.Set(ctx, key, &result)
+	return &result, true
+}
+
+// Set 同时写入L1和L2
+func (c *RedisCache) Set(ctx context.Context, key string, value *types.FilterResult) {
+	c.local.Set(ctx, key, value)
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		c.errors.Add(1)
+		c.logger.Warnf("Failed to encode result for redis cache: %v", err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	if err := c.client.Set(ctx, c.prefix+key, data, c.ttl).Err(); err != nil {
+		c.errors.Add(1)
+		c.logger.Warnf("Failed to write cached result to redis: %v", err)
+	}
+}
+
+// Clear 清空L1并删除L2中的所有结果，词库或策略变更后调用；L2由所有实例共享，任一实例变更都会清空
+func (c *RedisCache) Clear() {
+	c.local.Clear()
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	if err := c.clearRemote(ctx); err != nil {
+		c.errors.Add(1)
+		c.logger.Warnf("Failed to clear redis cache: %v", err)
+	}
+}
+
+// clearRemote 分批扫描并删除前缀下的键；集群模式下逐个主节点扫描，键分布在不同槽位，逐个删除
+func (c *RedisCache) clearRemote(ctx context.Context) error {
+	if cluster, ok := c.client.(*goredis.ClusterClient); ok {
+		return cluster.ForEachMaster(ctx, func(ctx context.Context, node *goredis.Client) error {
+			return c.scanDelete(ctx, node)
+		})
+	}
+	return c.scanDelete(ctx, c.client)
+}
+
+// scanDelete 扫描一个节点上前缀下的键，每批通过流水线删除
+func (c *RedisCache) scanDelete(ctx context.Context, node goredis.Cmdable) error {
+	iter := node.Scan(ctx, 0, c.prefix+"*", clearBatch).Iterator()
+	keys := make([]string, 0, clearBatch)
+	unlink := func() error {
+		_, err := c.client.Pipelined(ctx, func(pipe goredis.Pipeliner) error {
+			for _, key := range keys {
+				pipe.Unlink(ctx, key)
+			}
+			return nil
+		})
+		keys = keys[:0]
+		return err
+	}
+
+	for iter.Next(ctx) {
+		if keys = append(keys, iter.Val()); len(keys) == clearBatch {
+			if err := unlink(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	if len(keys) > 0 {
+		return unlink()
+	}
+	return nil
+}
+
+// Stats 返回L1的统计和L2的命中、未命中、错误次数
+func (c *RedisCache) Stats() map[string]interface{} {
+	stats := c.local.Stats()
+	stats["backend"] = types.CacheStoreRedis
+	stats["redis_hits"] = c.hits.Load()
+	stats["redis_misses"] = c.misses.Load()
+	stats["redis_errors"] = c.errors.Load()
+	return stats
+}
+
+// Usage 返回L1的容量使用情况，L2的容量由Redis管理
+func (c *RedisCache) Usage() Usage {
+	if reporter, ok := c.local.(UsageReporter); ok {
+		return reporter.Usage()
+	}
+	return Usage{}
+}
+
+// Close 关闭Redis连接
+func (c *RedisCache) Close() {
+	c.local.Close()
+	if err := c.client.Close(); err != nil {
+		c.logger.Warnf("Failed to close redis cache: %v", err)
+	}
+}
@@ -0,0 +1,200 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// ShardedLRUCache 分段LRU缓存：按键的哈希分到N个独立加锁的分段，高并发下不再争用同一把锁
+// 每个分段独立淘汰，容量为总容量均分到各分段
+type ShardedLRUCache struct {
+	shards []*lruShard
+}
+
+// lruShard 一个分段
+type lruShard struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	ll       *list.List
+	items    map[string]*list.Element
+	hits     int64
+	misses   int64
+	evicted  int64
+	bytes    int64 // 条目占用内存的估算值
+}
+
+// shardEntry 分段中的条目
+type shardEntry struct {
+	key     string
+	value   *types.FilterResult
+	expires time.Time // ttl不大于0时为零值，不过期
+	size    int64     // 估算的内存字节数
+}
+
+// NewShardedLRUCache 创建分段LRU缓存，shards不大于1时只有一个分段，超过size时按size分段
+func NewShardedLRUCache(size, shards int, ttl time.Duration) *ShardedLRUCache {
+	if shards < 1 {
+		shards = 1
+	}
+	if size > 0 && shards > size {
+		shards = size
+	}
+
+	c := &ShardedLRUCache{shards: make([]*lruShard, shards)}
+	for i := range c.shards {
+		capacity := size / shards
+		if i < size%shards {
+			capacity++
+		}
+		c.shards[i] = &lruShard{
+			capacity: capacity,
+			ttl:      ttl,
+			ll:       list.New(),
+			items:    make(map[string]*list.Element),
+		}
+	}
+	return c
+}
+
+// shard 键所在的分段
+func (c *ShardedLRUCache) shard(key string) *lruShard {
+	return c.shards[xxhash.Sum64String(key)%uint64(len(c.shards))]
+}
+
+// Get 获取缓存的结果，过期的条目视为未命中并移除；进程内读写不使用上下文
+func (c *ShardedLRUCache) Get(_ context.Context, key string) (*types.FilterResult, bool) {
+	return c.shard(key).get(key)
+}
+
+// Set 写入结果，分段已满时淘汰最久未使用的条目
+func (c *ShardedLRUCache) Set(_ context.Context, key string, value *types.FilterResult) {
+	c.shard(key).set(key, value)
+}
+
+// Clear 清空所有分段
+func (c *ShardedLRUCache) Clear() {
+	for _, s := range c.shards {
+		s.mu.Lock()
+		s.ll.Init()
+		s.items = make(map[string]*list.Element)
+		s.bytes = 0
+		s.mu.Unlock()
+	}
+}
+
+// Stats 返回合计的条目数、容量、命中、未命中和淘汰次数，shard_stats 为各分段的统计
+func (c *ShardedLRUCache) Stats() map[string]interface{} {
+	var size, capacity int
+	var hits, misses, evicted, bytes int64
+	shardStats := make([]map[string]interface{}, len(c.shards))
+	for i, s := range c.shards {
+		s.mu.Lock()
+		shardStats[i] = map[string]interface{}{
+			"size":         s.ll.Len(),
+			"hits":         s.hits,
+			"misses":       s.misses,
+			"evicted":      s.evicted,
+			"memory_bytes": s.bytes,
+		}
+		size += s.ll.Len()
+		capacity += s.capacity
+		hits += s.hits
+		misses += s.misses
+		evicted += s.evicted
+		bytes += s.bytes
+		s.mu.Unlock()
+	}
+
+	var hitRate float64
+	if hits+misses > 0 {
+		hitRate = float64(hits) / float64(hits+misses)
+	}
+	return map[string]interface{}{
+		"size":         size,
+		"capacity":     capacity,
+		"hits":         hits,
+		"misses":       misses,
+		"evicted":      evicted,
+		"hit_rate":     hitRate,
+		"memory_bytes": bytes,
+		"shards":       len(c.shards),
+		"shard_stats":  shardStats,
+	}
+}
+
+// Usage 返回所有分段合计的容量使用情况
+func (c *ShardedLRUCache) Usage() Usage {
+	var usage Usage
+	for _, s := range c.shards {
+		s.mu.Lock()
+		usage.Entries += s.ll.Len()
+		usage.Capacity += s.capacity
+		usage.Evictions += s.evicted
+		usage.MemoryBytes += s.bytes
+		s.mu.Unlock()
+	}
+	return usage
+}
+
+// Close 分段缓存没有需要释放的资源
+func (c *ShardedLRUCache) Close() {}
+
+func (s *lruShard) get(key string) (*types.FilterResult, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element, ok := s.items[key]
+	if !ok {
+		s.misses++
+		return nil, false
+	}
+	entry := element.Value.(*shardEntry)
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		s.remove(element)
+		s.misses++
+		return nil, false
+	}
+	s.ll.MoveToFront(element)
+	s.hits++
+	return entry.value, true
+}
+
+func (s *lruShard) set(key string, value *types.FilterResult) {
+	var expires time.Time
+	if s.ttl > 0 {
+		expires = time.Now().Add(s.ttl)
+	}
+
+	entry := &shardEntry{key: key, value: value, expires: expires, size: EstimateSize(key, value)}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.items[key]; ok {
+		s.bytes += entry.size - element.Value.(*shardEntry).size
+		element.Value = entry
+		s.ll.MoveToFront(element)
+		return
+	}
+	s.items[key] = s.ll.PushFront(entry)
+	s.bytes += entry.size
+	for s.capacity > 0 && s.ll.Len() > s.capacity {
+		s.remove(s.ll.Back())
+		s.evicted++
+	}
+}
+
+// remove 移除条目，调用方需持有锁
+func (s *lruShard) remove(element *list.Element) {
+	entry := element.Value.(*shardEntry)
+	s.ll.Remove(element)
+	delete(s.items, entry.key)
+	s.bytes -= entry.size
+}
@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+func TestShardedLRUCacheCapacity(t *testing.T) {
+	ctx := context.Background()
+	c := NewShardedLRUCache(10, 4, 0)
+	for i := 0; i < 100; i++ {
+		c.Set(ctx, strconv.Itoa(i), &types.FilterResult{Passed: true})
+	}
+
+	stats := c.Stats()
+	if stats["capacity"] != 10 || stats["shards"] != 4 {
+		t.Errorf("Stats() = %v, want capacity 10 in 4 shards", stats)
+	}
+	if size := stats["size"].(int); size > 10 {
+		t.Errorf("size = %d, want at most 10", size)
+	}
+	if evicted := stats["evicted"].(int64); evicted != int64(100-stats["size"].(int)) {
+		t.Errorf("evicted = %d, want %d", evicted, 100-stats["size"].(int))
+	}
+	if _, found := c.Get(ctx, "99"); !found {
+		t.Error("Get() of the most recent key = not found")
+	}
+
+	c.Clear()
+	if _, found := c.Get(ctx, "99"); found || c.Stats()["size"] != 0 {
+		t.Error("Get() after Clear() = found, want empty cache")
+	}
+}
+
+func TestShardedLRUCacheTTL(t *testing.T) {
+	ctx := context.Background()
+	c := NewShardedLRUCache(10, 2, 20*time.Millisecond)
+	c.Set(ctx, "k", &types.FilterResult{Passed: true})
+	if _, found := c.Get(ctx, "k"); !found {
+		t.Fatal("Get() = not found, want cached result")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if _, found := c.Get(ctx, "k"); found {
+		t.Error("Get() after ttl = found, want expired")
+	}
+	if stats := c.Stats(); stats["hits"] != int64(1) || stats["misses"] != int64(1) {
+		t.Errorf("Stats() = %v, want 1 hit and 1 miss", stats)
+	}
+}
@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// entryOverhead 每个条目除内容外的估算开销：结果结构体、链表节点、映射槽位等
+const entryOverhead = 400
+
+// Usage 缓存的容量使用情况
+type Usage struct {
+	Entries     int   // 当前条目数
+	Capacity    int   // 条目数上限，0表示不限制
+	Evictions   int64 // 因容量淘汰的条目数，不含过期
+	MemoryBytes int64 // 条目占用内存的估算值
+}
+
+// UsageReporter 可报告容量使用情况的缓存，内置缓存都实现了该接口，自定义缓存可选实现
+type UsageReporter interface {
+	Usage() Usage
+}
+
+// EstimateSize 估算一个缓存条目占用的内存字节数
+func EstimateSize(key string, result *types.FilterResult) int64 {
+	size := entryOverhead + len(key) + len(result.DegradedReason) + len(result.Message) + len(result.FilteredText) +
+		len(result.ScanStrategy) + len(result.ReasonCode) + len(result.Reason) + len(result.Action) +
+		16*len(result.InvisiblePositions)
+	size += stringsSize(result.Categories) + stringsSize(result.Words)
+	for key, value := range result.Details {
+		size += 32 + len(key) + len(value)
+	}
+	for category := range result.CategoryCounts {
+		size += 32 + len(category)
+	}
+	for _, matches := range [][]types.MatchDetail{result.Matches, result.Monitored} {
+		for _, match := range matches {
+			size += 64 + len(match.Word) + stringsSize(match.Categories) + 16*len(match.Positions)
+		}
+	}
+	return int64(size)
+}
+
+// stringsSize 字符串切片的估算字节数
+func stringsSize(values []string) int {
+	size := 24
+	for _, value := range values {
+		size += 16 + len(value)
+	}
+	return size
+}
@@ -0,0 +1,95 @@
+package classifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// defaultTimeout 远程推理接口的默认超时
+const defaultTimeout = time.Second
+
+// Classifier 文本分类模型，返回文本违规（辱骂、引战等）的概率
+// 可以是本地模型（如ONNX Runtime）或远程推理接口，需要并发安全
+type Classifier interface {
+	// Classify 返回文本违规的概率，0到1之间
+	Classify(ctx context.Context, text string) (float64, error)
+}
+
+// Func 将函数适配为 Classifier
+type Func func(ctx context.Context, text string) (float64, error)
+
+// Classify 调用函数本身
+func (fn Func) Classify(ctx context.Context, text string) (float64, error) {
+	return fn(ctx, text)
+}
+
+// request 远程推理接口的请求体
+type request struct {
+	Text string `json:"text"`
+}
+
+// response 远程推理接口的响应体
+type response struct {
+	Probability float64 `json:"probability"`
+}
+
+// HTTPClassifier 远程推理接口，POST {"text": "..."}，返回 {"probability": 0.87}
+type HTTPClassifier struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+// NewHTTP 按配置创建远程推理接口
+func NewHTTP(config *types.ClassifierConfig) *HTTPClassifier {
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &HTTPClassifier{url: config.URL, headers: config.Headers, client: &http.Client{Timeout: timeout}}
+}
+
+// Classify 调用推理接口，非2xx响应、无法解析或超出0到1的概率视为失败
+func (c *HTTPClassifier) Classify(ctx context.Context, text string) (float64, error) {
+	data, err := json.Marshal(request{Text: text})
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal classifier request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(data))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range c.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to call classifier: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return 0, fmt.Errorf("classifier returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	var result response
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("failed to decode classifier response: %w", err)
+	}
+	if result.Probability < 0 || result.Probability > 1 {
+		return 0, fmt.Errorf("classifier returned probability %v out of [0, 1]", result.Probability)
+	}
+	return result.Probability, nil
+}
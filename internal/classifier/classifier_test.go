@@ -0,0 +1,43 @@
+package classifier
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+func TestHTTPClassifier(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    float64
+		wantErr bool
+	}{
+		{name: "ok", status: http.StatusOK, body: `{"probability":0.87}`, want: 0.87},
+		{name: "server error", status: http.StatusInternalServerError, body: `oops`, wantErr: true},
+		{name: "out of range", status: http.StatusOK, body: `{"probability":3}`, wantErr: true},
+		{name: "invalid body", status: http.StatusOK, body: `not json`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			got, err := NewHTTP(&types.ClassifierConfig{URL: server.URL}).Classify(context.Background(), "text")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Classify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Classify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
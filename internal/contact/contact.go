@@ -0,0 +1,201 @@
+package contact
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/guardian/content-filter/internal/algorithm"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// defaultLevel 检测结果的默认敏感级别
+const defaultLevel = 3
+
+// 内置检测规则，作用于标准化（小写、半角）后的文本
+var (
+	emailPattern = regexp.MustCompile(`[a-z0-9._%+\-]+@[a-z0-9\-]+(?:\.[a-z0-9\-]+)*\.[a-z]{2,}`)
+	urlPattern   = regexp.MustCompile(`(?:https?://|www\.)[^\s<>"'，。！？、）]+` +
+		`|\b[a-z0-9][a-z0-9\-]*(?:\.[a-z0-9\-]+)*\.(?:com|cn|net|org|io|cc|co|me|top|xyz|vip|club|site|info|app)\b(?:/[^\s<>"'，。！？、）]*)?`)
+	phonePattern = regexp.MustCompile(`(?:\+?86[\s\-]?)?1[3-9]\d(?:[\s\-]?\d{4}){2}`)
+)
+
+// mobilePrefixes 中国大陆手机号段（前三位）
+var mobilePrefixes = func() map[string]bool {
+	prefixes := make(map[string]bool)
+	for _, group := range []string{
+		"130 131 132 133 134 135 136 137 138 139",
+		"145 146 147 148 149",
+		"150 151 152 153 155 156 157 158 159",
+		"162 165 166 167",
+		"170 171 172 173 174 175 176 177 178",
+		"180 181 182 183 184 185 186 187 188 189",
+		"190 191 192 193 195 196 197 198 199",
+	} {
+		for _, prefix := range strings.Fields(group) {
+			prefixes[prefix] = true
+		}
+	}
+	return prefixes
+}()
+
+// rule 一条检测规则
+type rule struct {
+	name     string
+	pattern  *regexp.Regexp
+	output   algorithm.Output
+	validate func(text string, start, end int) (int, int, bool) // 校验并修正命中区间
+}
+
+// Detector 联系方式检测器
+type Detector struct {
+	rules []rule
+}
+
+// New 按配置创建检测器，未启用时返回nil
+func New(config *types.ContactConfig) (*Detector, error) {
+	if config == nil || !config.Enabled {
+		return nil, nil
+	}
+
+	level := config.Level
+	if level <= 0 {
+		level = defaultLevel
+	}
+
+	enabled := make(map[string]bool)
+	for _, name := range config.Detectors {
+		enabled[name] = true
+	}
+	builtin := func(name string) bool {
+		return len(enabled) == 0 || enabled[name]
+	}
+
+	// 邮箱先于网址检测，避免邮箱的域名部分被再次识别为网址
+	d := &Detector{}
+	if builtin(types.DetectorEmail) {
+		d.rules = append(d.rules, rule{
+			name:    types.DetectorEmail,
+			pattern: emailPattern,
+			output:  algorithm.Output{Categories: []string{types.CategoryContact}, Level: level},
+		})
+	}
+	if builtin(types.DetectorURL) {
+		d.rules = append(d.rules, rule{
+			name:     types.DetectorURL,
+			pattern:  urlPattern,
+			output:   algorithm.Output{Categories: []string{types.CategoryURL}, Level: level},
+			validate: trimURL,
+		})
+	}
+	if builtin(types.DetectorPhone) {
+		d.rules = append(d.rules, rule{
+			name:     types.DetectorPhone,
+			pattern:  phonePattern,
+			output:   algorithm.Output{Categories: []string{types.CategoryContact}, Level: level},
+			validate: validPhone,
+		})
+	}
+
+	for _, p := range config.Patterns {
+		re, err := regexp.Compile(p.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile contact pattern %s: %w", p.Name, err)
+		}
+		category := p.Category
+		if category == "" {
+			category = types.CategoryContact
+		}
+		patternLevel := p.Level
+		if patternLevel <= 0 {
+			patternLevel = level
+		}
+		d.rules = append(d.rules, rule{
+			name:    p.Name,
+			pattern: re,
+			output:  algorithm.Output{Categories: []string{category}, Level: patternLevel},
+		})
+	}
+
+	return d, nil
+}
+
+// Find 检测文本中的联系方式，命中以敏感词匹配的形式返回，Word为命中的原文片段
+// 与先前规则的命中重叠的结果会被忽略；检测器为nil时返回nil
+func (d *Detector) Find(text string, options *algorithm.SearchOptions) []algorithm.Match {
+	if d == nil {
+		return nil
+	}
+
+	var matches []algorithm.Match
+	for _, r := range d.rules {
+		if !options.Allows(&r.output) {
+			continue
+		}
+
+		for _, loc := range r.pattern.FindAllStringIndex(text, -1) {
+			start, end := loc[0], loc[1]
+			if r.validate != nil {
+				var ok bool
+				if start, end, ok = r.validate(text, start, end); !ok {
+					continue
+				}
+			}
+			if start >= end || overlaps(matches, start, end) {
+				continue
+			}
+
+			output := r.output
+			output.Word = text[start:end]
+			matches = append(matches, algorithm.Match{Output: &output, Start: start, End: end})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Start < matches[j].Start
+	})
+	return matches
+}
+
+// overlaps 区间是否与已有命中重叠
+func overlaps(matches []algorithm.Match, start, end int) bool {
+	for _, m := range matches {
+		if start < m.End && m.Start < end {
+			return true
+		}
+	}
+	return false
+}
+
+// trimURL 去除网址末尾的标点
+func trimURL(text string, start, end int) (int, int, bool) {
+	for end > start && strings.IndexByte(".,;:!?)]}'\"", text[end-1]) >= 0 {
+		end--
+	}
+	return start, end, true
+}
+
+// validPhone 校验手机号：前后不能紧邻数字，号段必须有效
+func validPhone(text string, start, end int) (int, int, bool) {
+	if start > 0 && isDigit(text[start-1]) || end < len(text) && isDigit(text[end]) {
+		return 0, 0, false
+	}
+
+	digits := make([]byte, 0, 13)
+	for i := start; i < end; i++ {
+		if isDigit(text[i]) {
+			digits = append(digits, text[i])
+		}
+	}
+	number := strings.TrimPrefix(string(digits), "86")
+	if len(number) != 11 || !mobilePrefixes[number[:3]] {
+		return 0, 0, false
+	}
+	return start, end, true
+}
+
+// isDigit 是否为ASCII数字
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
@@ -0,0 +1,66 @@
+package contact
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/guardian/content-filter/internal/algorithm"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+func TestDetectorFind(t *testing.T) {
+	detector, err := New(&types.ContactConfig{
+		Enabled: true,
+		Patterns: []types.ContactPattern{
+			{Name: "qq", Pattern: `qq[:：]?\d{5,11}`},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"email", "联系 spam@example.com 领取", []string{"contact:spam@example.com"}},
+		{"url", "访问https://example.com/a?b=1。", []string{"url:https://example.com/a?b=1"}},
+		{"bare domain", "上 abc123.top 看看", []string{"url:abc123.top"}},
+		{"mobile", "加我13812345678，或 +86 139-1234-5678", []string{"contact:13812345678", "contact:+86 139-1234-5678"}},
+		{"invalid prefix", "编号12012345678", nil},
+		{"longer number", "订单138123456789", nil},
+		{"custom pattern", "qq:123456", []string{"contact:qq:123456"}},
+		{"clean", "今天天气不错", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, m := range detector.Find(tt.text, nil) {
+				got = append(got, m.Categories[0]+":"+m.Word)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Find(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectorOptions(t *testing.T) {
+	detector, err := New(&types.ContactConfig{Enabled: true, Detectors: []string{types.DetectorPhone}, Level: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text := "13812345678 a@b.com"
+	if got := detector.Find(text, nil); len(got) != 1 || got[0].Level != 2 {
+		t.Errorf("expected only the phone number at level 2, got %v", got)
+	}
+	if got := detector.Find(text, &algorithm.SearchOptions{MinLevel: 3}); len(got) != 0 {
+		t.Errorf("expected no matches above level 2, got %v", got)
+	}
+	if got := detector.Find(text, &algorithm.SearchOptions{Categories: []string{"porn"}}); len(got) != 0 {
+		t.Errorf("expected no matches outside requested categories, got %v", got)
+	}
+}
@@ -0,0 +1,54 @@
+package escalation
+
+import (
+	"sync"
+	"time"
+)
+
+// breaker 熔断器：连续失败达到阈值后熔断，冷却时间过后放行一次试探请求，成功则恢复，失败则继续熔断
+type breaker struct {
+	mu        sync.Mutex
+	threshold int           // 连续失败多少次后熔断
+	cooldown  time.Duration // 熔断持续时间
+	failures  int           // 连续失败次数
+	openUntil time.Time     // 熔断结束时间
+	probing   bool          // 试探请求进行中
+}
+
+// allow 是否可以发出请求
+func (b *breaker) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return true
+	}
+	if now.Before(b.openUntil) || b.probing {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// record 记录请求结果
+func (b *breaker) record(err error, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if err == nil {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openUntil = now.Add(b.cooldown)
+	}
+}
+
+// open 是否处于熔断中
+func (b *breaker) open(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.failures >= b.threshold && now.Before(b.openUntil)
+}
@@ -0,0 +1,74 @@
+package escalation
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// cacheEntry 缓存的结论
+type cacheEntry struct {
+	key       [32]byte
+	verdict   *Verdict
+	expiresAt time.Time
+}
+
+// cache 按文本摘要缓存审核结论，超过容量时淘汰最久未使用的条目
+type cache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	order   *list.List // 最近使用的在前
+	entries map[[32]byte]*list.Element
+}
+
+// newCache 创建结论缓存
+func newCache(size int, ttl time.Duration) *cache {
+	return &cache{size: size, ttl: ttl, order: list.New(), entries: make(map[[32]byte]*list.Element)}
+}
+
+// get 返回未过期的结论，缓存为nil时不命中
+func (c *cache) get(key [32]byte, now time.Time) (*Verdict, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*cacheEntry)
+	if now.After(entry.expiresAt) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return entry.verdict, true
+}
+
+// set 缓存结论，缓存为nil时忽略
+func (c *cache) set(key [32]byte, verdict *Verdict, now time.Time) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = &cacheEntry{key: key, verdict: verdict, expiresAt: now.Add(c.ttl)}
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, verdict: verdict, expiresAt: now.Add(c.ttl)})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
@@ -0,0 +1,166 @@
+package escalation
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// 默认配置
+const (
+	defaultTimeout          = 2 * time.Second
+	defaultFailureThreshold = 5
+	defaultCooldown         = 30 * time.Second
+	defaultCacheSize        = 10000
+	defaultCacheTTL         = 10 * time.Minute
+)
+
+// ErrCircuitOpen 连续失败后熔断，熔断期间不调用审核接口
+var ErrCircuitOpen = errors.New("escalation circuit breaker is open")
+
+// Verdict 第三方审核接口的结论
+type Verdict struct {
+	Flagged    bool     `json:"flagged"`    // 是否违规
+	Score      float64  `json:"score"`      // 违规概率，0到1之间
+	Categories []string `json:"categories"` // 违规分类
+}
+
+// request 审核接口的请求体
+type request struct {
+	Text string `json:"text"`
+}
+
+// Client 调用第三方审核接口，带超时、熔断和结论缓存
+type Client struct {
+	url       string
+	headers   map[string]string
+	client    *http.Client
+	breaker   *breaker
+	cache     *cache // 未启用缓存时为nil
+	requests  atomic.Int64
+	cacheHits atomic.Int64
+	failures  atomic.Int64
+	skipped   atomic.Int64
+	flagged   atomic.Int64
+}
+
+// New 按配置创建审核接口客户端
+func New(config *types.EscalationConfig) *Client {
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	threshold := config.FailureThreshold
+	if threshold <= 0 {
+		threshold = defaultFailureThreshold
+	}
+	cooldown := config.Cooldown
+	if cooldown <= 0 {
+		cooldown = defaultCooldown
+	}
+
+	c := &Client{
+		url:     config.URL,
+		headers: config.Headers,
+		client:  &http.Client{Timeout: timeout},
+		breaker: &breaker{threshold: threshold, cooldown: cooldown},
+	}
+	if config.CacheSize >= 0 {
+		size := config.CacheSize
+		if size == 0 {
+			size = defaultCacheSize
+		}
+		ttl := config.CacheTTL
+		if ttl <= 0 {
+			ttl = defaultCacheTTL
+		}
+		c.cache = newCache(size, ttl)
+	}
+	return c
+}
+
+// Moderate 返回审核接口对文本的结论，相同文本在缓存有效期内直接返回缓存的结论
+// 熔断期间返回 ErrCircuitOpen
+func (c *Client) Moderate(ctx context.Context, text string) (*Verdict, error) {
+	key := sha256.Sum256([]byte(text))
+	now := time.Now()
+	if verdict, ok := c.cache.get(key, now); ok {
+		c.cacheHits.Add(1)
+		return verdict, nil
+	}
+
+	if !c.breaker.allow(now) {
+		c.skipped.Add(1)
+		return nil, ErrCircuitOpen
+	}
+
+	c.requests.Add(1)
+	verdict, err := c.call(ctx, text)
+	c.breaker.record(err, time.Now())
+	if err != nil {
+		c.failures.Add(1)
+		return nil, err
+	}
+
+	if verdict.Flagged {
+		c.flagged.Add(1)
+	}
+	c.cache.set(key, verdict, time.Now())
+	return verdict, nil
+}
+
+// call 调用审核接口，非2xx响应或无法解析的响应视为失败
+func (c *Client) call(ctx context.Context, text string) (*Verdict, error) {
+	data, err := json.Marshal(request{Text: text})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal moderation request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range c.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call moderation api: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("moderation api returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	var verdict Verdict
+	if err := json.NewDecoder(resp.Body).Decode(&verdict); err != nil {
+		return nil, fmt.Errorf("failed to decode moderation response: %w", err)
+	}
+	return &verdict, nil
+}
+
+// Stats 调用统计
+func (c *Client) Stats() *types.EscalationStats {
+	return &types.EscalationStats{
+		Requests:    c.requests.Load(),
+		CacheHits:   c.cacheHits.Load(),
+		Failures:    c.failures.Load(),
+		Skipped:     c.skipped.Load(),
+		Flagged:     c.flagged.Load(),
+		CircuitOpen: c.breaker.open(time.Now()),
+	}
+}
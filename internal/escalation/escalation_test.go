@@ -0,0 +1,95 @@
+package escalation
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+func TestModerateCachesVerdict(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Write([]byte(`{"flagged":true,"score":0.9,"categories":["abuse"]}`))
+	}))
+	defer server.Close()
+
+	c := New(&types.EscalationConfig{URL: server.URL})
+	for i := 0; i < 3; i++ {
+		verdict, err := c.Moderate(context.Background(), "text")
+		if err != nil {
+			t.Fatalf("Moderate() error = %v", err)
+		}
+		if !verdict.Flagged || verdict.Categories[0] != "abuse" {
+			t.Errorf("Moderate() = %+v, want flagged abuse", verdict)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Errorf("api called %d times, want 1", calls.Load())
+	}
+	if stats := c.Stats(); stats.Requests != 1 || stats.CacheHits != 2 || stats.Flagged != 1 {
+		t.Errorf("Stats() = %+v", stats)
+	}
+}
+
+func TestModerateCircuitBreaker(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c := New(&types.EscalationConfig{URL: server.URL, FailureThreshold: 2, Cooldown: time.Hour})
+	for i := 0; i < 2; i++ {
+		if _, err := c.Moderate(context.Background(), "text"); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("Moderate() error = %v, want api error", err)
+		}
+	}
+	if _, err := c.Moderate(context.Background(), "text"); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Moderate() error = %v, want ErrCircuitOpen", err)
+	}
+	if calls.Load() != 2 || !c.Stats().CircuitOpen {
+		t.Errorf("calls = %d, stats = %+v, want circuit open after 2 calls", calls.Load(), c.Stats())
+	}
+}
+
+func TestBreakerHalfOpen(t *testing.T) {
+	b := &breaker{threshold: 1, cooldown: time.Minute}
+	now := time.Now()
+	b.record(errors.New("failed"), now)
+	if b.allow(now.Add(time.Second)) {
+		t.Error("allow() during cooldown = true")
+	}
+
+	// 冷却后只放行一次试探请求
+	later := now.Add(2 * time.Minute)
+	if !b.allow(later) || b.allow(later) {
+		t.Error("allow() after cooldown should admit exactly one probe")
+	}
+	b.record(nil, later)
+	if !b.allow(later) {
+		t.Error("allow() after successful probe = false")
+	}
+}
+
+func TestCacheEviction(t *testing.T) {
+	c := newCache(2, time.Minute)
+	now := time.Now()
+	keys := [][32]byte{{1}, {2}, {3}}
+	for _, key := range keys {
+		c.set(key, &Verdict{}, now)
+	}
+	if _, ok := c.get(keys[0], now); ok {
+		t.Error("oldest entry not evicted")
+	}
+	if _, ok := c.get(keys[2], now.Add(2*time.Minute)); ok {
+		t.Error("expired entry returned")
+	}
+}
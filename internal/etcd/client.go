@@ -0,0 +1,165 @@
+package etcd
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/guardian/content-filter/internal/logging"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// defaultKeyPrefix 默认键前缀
+const defaultKeyPrefix = "/guardian"
+
+// Client etcd客户端，将 DataId/Group 映射为键 {prefix}/{group}/{dataId}
+type Client struct {
+	client  *clientv3.Client
+	config  *types.EtcdConfig
+	logger  logging.Logger
+	timeout time.Duration
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewClient 创建新的etcd客户端
+func NewClient(config *types.EtcdConfig, logger logging.Logger) (*Client, error) {
+	timeout := time.Duration(config.TimeoutMs) * time.Millisecond
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   config.Endpoints,
+		Username:    config.Username,
+		Password:    config.Password,
+		DialTimeout: timeout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create etcd client: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Client{
+		client:  client,
+		config:  config,
+		logger:  logger,
+		timeout: timeout,
+		ctx:     ctx,
+		cancel:  cancel,
+	}, nil
+}
+
+// Key 返回 DataId/Group 对应的etcd键
+func (c *Client) Key(dataId, group string) string {
+	prefix := c.config.KeyPrefix
+	if prefix == "" {
+		prefix = defaultKeyPrefix
+	}
+	return path.Join(prefix, group, dataId)
+}
+
+// GetConfig 获取配置
+func (c *Client) GetConfig(dataId, group string) (string, error) {
+	ctx, cancel := context.WithTimeout(c.ctx, c.timeout)
+	defer cancel()
+
+	key := c.Key(dataId, group)
+	resp, err := c.client.Get(ctx, key)
+	if err != nil {
+		return "", fmt.Errorf("%w: failed to get config from etcd: %w", types.ErrWordSourceUnavailable, err)
+	}
+	if len(resp.Kvs) == 0 {
+		return "", fmt.Errorf("%w: config not found: %s", types.ErrEmptyDictionary, key)
+	}
+
+	return string(resp.Kvs[0].Value), nil
+}
+
+// ListenConfig 监听配置变化
+func (c *Client) ListenConfig(dataId, group string, callback func(string)) error {
+	key := c.Key(dataId, group)
+	watchChan := c.client.Watch(c.ctx, key)
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		for resp := range watchChan {
+			if err := resp.Err(); err != nil {
+				c.logger.Errorf("Etcd watch error on %s: %v", key, err)
+				continue
+			}
+			for _, event := range resp.Events {
+				if event.Type != clientv3.EventTypePut {
+					continue
+				}
+				c.logger.Infof("Config changed: key=%s, revision=%d", key, event.Kv.ModRevision)
+				callback(string(event.Kv.Value))
+			}
+		}
+	}()
+
+	return nil
+}
+
+// PublishConfig 发布配置
+func (c *Client) PublishConfig(dataId, group, content string) error {
+	ctx, cancel := context.WithTimeout(c.ctx, c.timeout)
+	defer cancel()
+
+	key := c.Key(dataId, group)
+	if _, err := c.client.Put(ctx, key, content); err != nil {
+		return fmt.Errorf("failed to publish config: %w", err)
+	}
+
+	c.logger.Infof("Config published successfully: key=%s", key)
+	return nil
+}
+
+// PublishConfigIf 键的值仍为expected时发布，否则返回 types.ErrConfigModified
+// 比较和写入在同一个事务中完成
+func (c *Client) PublishConfigIf(dataId, group, content, expected string) error {
+	ctx, cancel := context.WithTimeout(c.ctx, c.timeout)
+	defer cancel()
+
+	key := c.Key(dataId, group)
+	resp, err := c.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.Value(key), "=", expected)).
+		Then(clientv3.OpPut(key, content)).
+		Commit()
+	if err != nil {
+		return fmt.Errorf("failed to publish config: %w", err)
+	}
+	if !resp.Succeeded {
+		return fmt.Errorf("%w: key=%s", types.ErrConfigModified, key)
+	}
+
+	c.logger.Infof("Config published successfully: key=%s", key)
+	return nil
+}
+
+// HealthCheck 健康检查
+func (c *Client) HealthCheck() error {
+	ctx, cancel := context.WithTimeout(c.ctx, c.timeout)
+	defer cancel()
+
+	for _, endpoint := range c.client.Endpoints() {
+		if _, err := c.client.Status(ctx, endpoint); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("no healthy etcd endpoint in %v", c.client.Endpoints())
+}
+
+// Close 关闭客户端并停止所有监听
+func (c *Client) Close() error {
+	c.cancel()
+	err := c.client.Close()
+	c.wg.Wait()
+	return err
+}
@@ -0,0 +1,101 @@
+package filter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/guardian/content-filter/internal/alert"
+	"github.com/guardian/content-filter/internal/anomaly"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// startAnomaly 按配置启动拦截率突变检测，每个时间片判定一次
+func (f *ContentFilter) startAnomaly() {
+	if !f.config.Anomaly.Enabled {
+		return
+	}
+
+	f.anomaly = anomaly.New(&f.config.Anomaly)
+	f.notifier = f.notifiers
+	if f.config.Anomaly.WebhookURL != "" {
+		f.notifier = alert.Join(alert.NewWebhookNotifier(f.config.Anomaly.WebhookURL, f.config.Anomaly.Headers), f.notifiers)
+	}
+
+	ticker := time.NewTicker(f.anomaly.Interval())
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case now := <-ticker.C:
+				f.evaluateAnomaly(now)
+			case <-f.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// recordAnomaly 将检查结果计入拦截率突变检测，降级结果不计入
+func (f *ContentFilter) recordAnomaly(result *types.FilterResult) {
+	if f.anomaly != nil && !result.Degraded {
+		f.anomaly.Record(!result.Passed, time.Now())
+	}
+}
+
+// evaluateAnomaly 判定拦截率是否突变，进入异常或持续异常超过冷却时间时告警，恢复时记录日志
+func (f *ContentFilter) evaluateAnomaly(now time.Time) {
+	status, alerting, resolved := f.anomaly.Evaluate(now)
+	version := f.state.Load().version
+
+	switch {
+	case alerting:
+		f.logger.Warnf("Block rate anomaly: %.1f%% in the last window (%d checks) vs %.1f%% baseline (%d checks), version: %s",
+			status.WindowRate*100, status.WindowChecks, status.BaselineRate*100, status.BaselineChecks, version)
+		f.notifyAnomaly(types.AlertWarning, status, version, now)
+	case resolved:
+		f.logger.Infof("Block rate back to normal: %.1f%% vs %.1f%% baseline, version: %s",
+			status.WindowRate*100, status.BaselineRate*100, version)
+		f.notifyAnomaly(types.AlertResolved, status, version, now)
+	}
+}
+
+// notifyAnomaly 通过告警回调发送拦截率突变告警，未配置回调时不发送
+func (f *ContentFilter) notifyAnomaly(level string, status types.AnomalyStatus, version string, now time.Time) {
+	if f.notifier == nil {
+		return
+	}
+
+	message := fmt.Sprintf("拦截率 %.1f%%，基线 %.1f%%", status.WindowRate*100, status.BaselineRate*100)
+	if level == types.AlertResolved {
+		message = "拦截率已恢复正常: " + message
+	}
+	a := &types.Alert{
+		Type:    types.AlertBlockRateAnomaly,
+		Level:   level,
+		Time:    now,
+		Message: message,
+		Version: version,
+		Details: map[string]interface{}{
+			"window_rate":     status.WindowRate,
+			"window_checks":   status.WindowChecks,
+			"baseline_rate":   status.BaselineRate,
+			"baseline_checks": status.BaselineChecks,
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), f.anomaly.Interval())
+	defer cancel()
+	if err := f.notifier.Notify(ctx, a); err != nil {
+		f.logger.Errorf("Failed to send block rate alert: %v", err)
+	}
+}
+
+// anomalyStats 拦截率突变检测的最近一次判定，未启用时返回nil
+func (f *ContentFilter) anomalyStats() *types.AnomalyStatus {
+	if f.anomaly == nil {
+		return nil
+	}
+	status := f.anomaly.Status()
+	return &status
+}
@@ -0,0 +1,216 @@
+package filter
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/guardian/content-filter/internal/algorithm"
+	"github.com/guardian/content-filter/internal/artifact"
+	"github.com/guardian/content-filter/internal/logging"
+	"github.com/guardian/content-filter/internal/normalize"
+	"github.com/guardian/content-filter/internal/source"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// compileArtifact 编译词库产物并写入path，key不为nil时签名
+func compileArtifact(t *testing.T, path string, wordDB *types.WordDatabase, options *artifact.CompileOptions, key ed25519.PrivateKey) {
+	t.Helper()
+
+	a, err := artifact.Compile(wordDB, normalize.New(&types.NormalizeConfig{}), options)
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	if err := a.Save(path, key); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newArtifactFilter 编译词库产物并创建从产物加载的过滤器
+func newArtifactFilter(t *testing.T, wordDB *types.WordDatabase, compileWith, filterWith *types.NormalizeConfig) (*ContentFilter, error) {
+	t.Helper()
+
+	a, err := artifact.Compile(wordDB, normalize.New(compileWith), &artifact.CompileOptions{WordStats: true})
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "words.gda")
+	if err := a.Save(path, nil); err != nil {
+		t.Fatal(err)
+	}
+	config := &types.FilterConfig{DataId: "words", Group: "test", ArtifactPath: path, Normalize: *filterWith}
+	f, err := NewContentFilter(source.NewMemory(), config, logging.Discard())
+	if err != nil {
+		return nil, err
+	}
+	t.Cleanup(func() { f.Close() })
+	return f, nil
+}
+
+// 产物中的敏感词按标准化规则编译，大小写不同的文本同样命中
+func TestArtifactNormalizesWords(t *testing.T) {
+	wordDB := &types.WordDatabase{Version: "1.0.0", Blacklist: []types.SensitiveWord{{Word: "BadWord", Level: 3}}}
+	f, err := newArtifactFilter(t, wordDB, &types.NormalizeConfig{}, &types.NormalizeConfig{})
+	if err != nil {
+		t.Fatalf("NewContentFilter() error = %v", err)
+	}
+
+	for _, text := range []string{"this is a BadWord", "this is a badword", "this is a BADWORD"} {
+		if result := f.Filter(text, nil); result.Passed {
+			t.Errorf("Filter(%q) = %+v, want blocked", text, result)
+		}
+	}
+}
+
+// 标准化规则与编译时不同的产物被拒绝
+func TestArtifactRejectsNormalizeMismatch(t *testing.T) {
+	wordDB := &types.WordDatabase{Version: "1.0.0", Blacklist: []types.SensitiveWord{{Word: "敏感詞", Level: 3}}}
+	_, err := newArtifactFilter(t, wordDB, &types.NormalizeConfig{}, &types.NormalizeConfig{ChineseConversion: types.ConvertT2S})
+	if !errors.Is(err, artifact.ErrInvalidArtifact) {
+		t.Fatalf("NewContentFilter() error = %v, want ErrInvalidArtifact", err)
+	}
+}
+
+// 从产物加载的词库与JSON词库一致：替换词生效，快照保留词库内容
+func TestArtifactCarriesReplacements(t *testing.T) {
+	wordDB := &types.WordDatabase{
+		Version:      "1.0.0",
+		Blacklist:    []types.SensitiveWord{{Word: "违禁词", Level: 3}},
+		Replacements: map[string]string{"违禁词": "好词"},
+	}
+	f, err := newArtifactFilter(t, wordDB, &types.NormalizeConfig{}, &types.NormalizeConfig{})
+	if err != nil {
+		t.Fatalf("NewContentFilter() error = %v", err)
+	}
+
+	if result := f.Filter("这里有违禁词", &types.FilterOptions{ReplaceMode: true}); result.FilteredText != "这里有好词" {
+		t.Errorf("FilteredText = %q, want the replacement applied", result.FilteredText)
+	}
+	if state := f.state.Load(); state.wordDB == nil || len(state.wordDB.Words()) != 1 {
+		t.Errorf("state.wordDB = %+v, want the compiled words", state.wordDB)
+	}
+}
+
+// 默认编译的产物不保存完整的敏感词，只保存匹配所需的内容
+func TestArtifactOmitsWordsByDefault(t *testing.T) {
+	wordDB := &types.WordDatabase{Version: "1.0.0", Blacklist: []types.SensitiveWord{{Word: "违禁词", Level: 3}}}
+	path := filepath.Join(t.TempDir(), "words.gda")
+	compileArtifact(t, path, wordDB, nil, nil)
+
+	f, err := NewContentFilter(source.NewMemory(), &types.FilterConfig{DataId: "words", Group: "test", ArtifactPath: path}, logging.Discard())
+	if err != nil {
+		t.Fatalf("NewContentFilter() error = %v", err)
+	}
+	defer f.Close()
+
+	if result := f.Filter("这里有违禁词", nil); result.Passed {
+		t.Errorf("Filter() = %+v, want blocked", result)
+	}
+	if state := f.state.Load(); len(state.wordDB.Words()) != 0 || state.wordCount != 1 {
+		t.Errorf("state.wordDB words = %d, wordCount = %d, want no stored words and 1 compiled word", len(state.wordDB.Words()), state.wordCount)
+	}
+}
+
+// 配置了公钥时只加载签名有效的产物
+func TestArtifactSignature(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, other, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wordDB := &types.WordDatabase{Version: "1.0.0", Blacklist: []types.SensitiveWord{{Word: "违禁词", Level: 3}}}
+
+	tests := []struct {
+		name string
+		key  ed25519.PrivateKey
+		want error
+	}{
+		{"signed", private, nil},
+		{"unsigned", nil, artifact.ErrMissingSignature},
+		{"wrong key", other, artifact.ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "words.gda")
+			compileArtifact(t, path, wordDB, nil, tt.key)
+
+			config := &types.FilterConfig{DataId: "words", Group: "test", ArtifactPath: path,
+				PublicKey: base64.StdEncoding.EncodeToString(public)}
+			f, err := NewContentFilter(source.NewMemory(), config, logging.Discard())
+			if err == nil {
+				f.Close()
+			}
+			if !errors.Is(err, tt.want) || (tt.want != nil && !errors.Is(err, types.ErrInvalidWordDB)) {
+				t.Errorf("NewContentFilter() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// 重新加载时拒绝比已加载版本旧的产物
+func TestArtifactRejectsOlderVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words.gda")
+	compileArtifact(t, path, &types.WordDatabase{Version: "2.0.0", Blacklist: []types.SensitiveWord{{Word: "违禁词", Level: 3}}}, nil, nil)
+	f, err := NewContentFilter(source.NewMemory(), &types.FilterConfig{DataId: "words", Group: "test", ArtifactPath: path}, logging.Discard())
+	if err != nil {
+		t.Fatalf("NewContentFilter() error = %v", err)
+	}
+	defer f.Close()
+
+	compileArtifact(t, path, &types.WordDatabase{Version: "1.0.0", Blacklist: []types.SensitiveWord{{Word: "旧词", Level: 3}}}, nil, nil)
+	if err := f.loadArtifact(); !errors.Is(err, ErrStaleVersion) {
+		t.Fatalf("loadArtifact() error = %v, want ErrStaleVersion", err)
+	}
+	if version := f.state.Load().version; version != "2.0.0" {
+		t.Errorf("version = %s, want 2.0.0 kept", version)
+	}
+}
+
+// 定时生效的敏感词无法编译为产物
+func TestCompileRejectsScheduledWords(t *testing.T) {
+	from := time.Now().Add(time.Hour)
+	wordDB := &types.WordDatabase{Version: "1.0.0", Blacklist: []types.SensitiveWord{{Word: "活动词", Level: 3, ActiveFrom: &from}}}
+	if _, err := artifact.Compile(wordDB, normalize.New(&types.NormalizeConfig{}), nil); !errors.Is(err, artifact.ErrScheduledWord) {
+		t.Fatalf("Compile() error = %v, want ErrScheduledWord", err)
+	}
+}
+
+// 紧凑布局直接构建并写入自动机缓存，下次启动从缓存直接恢复为紧凑布局
+func TestFlatAutomatonCache(t *testing.T) {
+	wordDB := &types.WordDatabase{Version: "1.0.0", Blacklist: []types.SensitiveWord{{Word: "违禁词", Level: 3}, {Word: "禁词", Level: 2}}}
+	content, err := json.Marshal(wordDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := source.NewMemory()
+	config := &types.FilterConfig{DataId: "words", Group: "test", AutomatonLayout: types.LayoutFlat,
+		AutomatonCachePath: filepath.Join(t.TempDir(), "automaton.gda")}
+	if err := src.PublishConfig(config.DataId, config.Group, string(content)); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		f, err := NewContentFilter(src, config, logging.Discard())
+		if err != nil {
+			t.Fatalf("NewContentFilter() error = %v", err)
+		}
+		t.Cleanup(func() { f.Close() })
+
+		if _, ok := f.state.Load().automaton.(*algorithm.FlatAutomaton); !ok {
+			t.Errorf("automaton = %T, want *algorithm.FlatAutomaton", f.state.Load().automaton)
+		}
+		if result := f.Filter("这里有违禁词", nil); result.Passed || len(result.Words) != 2 {
+			t.Errorf("Filter() = %+v, want both words hit", result)
+		}
+		if _, cached, err := f.buildState(f.state.Load().wordDB, false); err != nil || !cached {
+			t.Errorf("buildState() cached = %v, error = %v, want loaded from the automaton cache", cached, err)
+		}
+	}
+}
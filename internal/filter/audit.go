@@ -0,0 +1,95 @@
+package filter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/guardian/content-filter/internal/audit"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// startAudit 按配置启动审计日志
+func (f *ContentFilter) startAudit() error {
+	if !f.config.Audit.Enabled {
+		return nil
+	}
+
+	sink, err := audit.NewSink(&f.config.Audit.SinkConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create audit sink: %w", err)
+	}
+	f.auditor.Store(audit.New(&f.config.Audit, sink, f.logger))
+	return nil
+}
+
+// SetAuditSink 使用自定义输出记录审计日志，替换配置中的输出；筛选和抽样仍按 audit 配置
+// 原输出写完已缓冲的记录后关闭；sink为nil时停止记录
+func (f *ContentFilter) SetAuditSink(sink audit.Sink) {
+	var auditor *audit.Auditor
+	if sink != nil {
+		auditor = audit.New(&f.config.Audit, sink, f.logger)
+	}
+	if previous := f.auditor.Swap(auditor); previous != nil {
+		if err := previous.Close(); err != nil {
+			f.logger.Errorf("Failed to close audit sink: %v", err)
+		}
+	}
+}
+
+// recordAudit 将审核决定交给审计日志
+func (f *ContentFilter) recordAudit(ctx context.Context, text string, result *types.FilterResult) {
+	if auditor := f.auditor.Load(); auditor != nil && result != nil {
+		auditor.Record(ctx, text, result, f.state.Load().version)
+	}
+}
+
+// auditStats 审计日志写入统计，未启用时返回nil
+func (f *ContentFilter) auditStats() *types.AuditStats {
+	if auditor := f.auditor.Load(); auditor != nil {
+		return auditor.Stats()
+	}
+	return nil
+}
+
+// startSampling 按配置启动抽样复核
+func (f *ContentFilter) startSampling() error {
+	if !f.config.Sampling.Enabled {
+		return nil
+	}
+
+	sink, err := audit.NewSink(&f.config.Sampling.SinkConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create sampling sink: %w", err)
+	}
+	f.sampler.Store(audit.NewSampler(&f.config.Sampling, sink, f.logger))
+	return nil
+}
+
+// SetSamplingSink 使用自定义输出（如复核队列）接收抽样结果，替换配置中的输出；抽样比例仍按 sampling 配置
+// 原输出写完已缓冲的记录后关闭；sink为nil时停止抽样
+func (f *ContentFilter) SetSamplingSink(sink audit.Sink) {
+	var sampler *audit.Sampler
+	if sink != nil {
+		sampler = audit.NewSampler(&f.config.Sampling, sink, f.logger)
+	}
+	if previous := f.sampler.Swap(sampler); previous != nil {
+		if err := previous.Close(); err != nil {
+			f.logger.Errorf("Failed to close sampling sink: %v", err)
+		}
+	}
+}
+
+// recordSample 将结果交给抽样复核
+func (f *ContentFilter) recordSample(ctx context.Context, text string, result *types.FilterResult) {
+	if sampler := f.sampler.Load(); sampler != nil && result != nil {
+		sampler.Record(ctx, text, result, f.state.Load().version)
+	}
+}
+
+// samplingStats 抽样复核写入统计，未启用时返回nil
+func (f *ContentFilter) samplingStats() *types.AuditStats {
+	if sampler := f.sampler.Load(); sampler != nil {
+		return sampler.Stats()
+	}
+	return nil
+}
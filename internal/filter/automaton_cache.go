@@ -0,0 +1,58 @@
+package filter
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"github.com/guardian/content-filter/internal/algorithm"
+	"github.com/guardian/content-filter/internal/artifact"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// loadAutomatonCache 从磁盘缓存加载与词库同一版本的自动机，版本、更新时间、词数或标准化规则不一致时视为未命中
+func (f *ContentFilter) loadAutomatonCache(wordDB *types.WordDatabase, wordCount int) (algorithm.Automaton, bool) {
+	if f.config.AutomatonCachePath == "" || wordDB.Version == "" {
+		return nil, false
+	}
+
+	a, err := f.loadArtifactFile(f.config.AutomatonCachePath)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			f.logger.Warnf("Ignoring unreadable automaton cache: %v", err)
+		}
+		return nil, false
+	}
+
+	meta := a.Metadata
+	if meta.Version != wordDB.Version || !meta.UpdateTime.Equal(wordDB.UpdateTime) || meta.WordCount != wordCount ||
+		meta.Normalize != f.normalizer.Signature() {
+		f.logger.Debugf("Automaton cache is for version %s, rebuilding for version %s", meta.Version, wordDB.Version)
+		return nil, false
+	}
+
+	return a.Automaton, true
+}
+
+// saveAutomatonCache 将编译好的自动机写入磁盘缓存，写入失败只记录日志
+func (f *ContentFilter) saveAutomatonCache(automaton algorithm.Automaton, wordDB *types.WordDatabase, wordCount int) {
+	if f.config.AutomatonCachePath == "" || wordDB.Version == "" {
+		return
+	}
+
+	a := &artifact.Artifact{
+		Metadata: &artifact.Metadata{
+			Version:    wordDB.Version,
+			UpdateTime: wordDB.UpdateTime,
+			BuildTime:  time.Now(),
+			WordCount:  wordCount,
+			NodeCount:  automaton.GetNodeCount(),
+			Whitelist:  wordDB.Whitelist,
+			Normalize:  f.normalizer.Signature(),
+		},
+		Automaton: automaton,
+	}
+	if err := a.Save(f.config.AutomatonCachePath, nil); err != nil {
+		f.logger.Errorf("Failed to save automaton cache: %v", err)
+	}
+}
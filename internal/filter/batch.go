@@ -0,0 +1,75 @@
+package filter
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// FilterBatch 并发检查多条文本，结果与输入顺序一致，并发数为 batch_workers
+// 上下文取消或超时时不再开始新的检查，等待进行中的检查结束后返回上下文错误
+func (f *ContentFilter) FilterBatch(ctx context.Context, texts []string, options *types.FilterOptions) ([]*types.FilterResult, error) {
+	results := make([]*types.FilterResult, len(texts))
+	workers := f.batchWorkers(len(texts))
+	if workers <= 1 {
+		for i, text := range texts {
+			result, err := f.FilterContext(ctx, text, options)
+			if err != nil {
+				return nil, err
+			}
+			results[i] = result
+		}
+		return results, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var next atomic.Int64 // 下一条待检查文本的下标
+	var once sync.Once
+	var firstErr error
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= len(texts) || ctx.Err() != nil {
+					return
+				}
+				result, err := f.FilterContext(ctx, texts[i], options)
+				if err != nil {
+					once.Do(func() { firstErr = err })
+					cancel()
+					return
+				}
+				results[i] = result
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// batchWorkers 检查n条文本使用的并发数
+func (f *ContentFilter) batchWorkers(n int) int {
+	workers := f.config.BatchWorkers
+	if workers == 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > n {
+		workers = n
+	}
+	return workers
+}
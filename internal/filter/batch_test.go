@@ -0,0 +1,56 @@
+package filter
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/guardian/content-filter/internal/classifier"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+func TestFilterBatchPreservesOrder(t *testing.T) {
+	f := newTestFilter(t, &types.WordDatabase{Version: "1.0.0", Blacklist: []types.SensitiveWord{{Word: "违禁词", Level: 3}}})
+	f.config.BatchWorkers = 4
+
+	texts := make([]string, 100)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("第%d条正常文本", i)
+		if i%3 == 0 {
+			texts[i] = fmt.Sprintf("第%d条违禁词", i)
+		}
+	}
+	results, err := f.FilterBatch(context.Background(), texts, nil)
+	if err != nil {
+		t.Fatalf("FilterBatch() error = %v", err)
+	}
+	for i, result := range results {
+		if result.Passed != (i%3 != 0) {
+			t.Errorf("results[%d].Passed = %v for %q", i, result.Passed, texts[i])
+		}
+	}
+}
+
+func TestFilterBatchCancel(t *testing.T) {
+	f := newTestFilter(t, &types.WordDatabase{Version: "1.0.0", Blacklist: []types.SensitiveWord{{Word: "违禁词", Level: 3}}})
+	f.config.BatchWorkers = 2
+	f.SetClassifier(classifier.Func(func(ctx context.Context, text string) (float64, error) {
+		time.Sleep(20 * time.Millisecond)
+		return 0.1, nil
+	}))
+
+	texts := make([]string, 100)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("文本%d", i)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := f.FilterBatch(ctx, texts, nil); err != context.DeadlineExceeded {
+		t.Errorf("FilterBatch() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("FilterBatch() took %v after cancellation, want it to stop early", elapsed)
+	}
+}
@@ -0,0 +1,206 @@
+package filter
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash/maphash"
+	"io"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/guardian/content-filter/internal/cache"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+const (
+	// defaultCacheShards 进程内缓存的默认分段数
+	defaultCacheShards = 16
+	// defaultCacheTTL 缓存结果的默认有效期
+	defaultCacheTTL = 10 * time.Minute
+	// defaultCacheMaxTextLength 缓存的文本默认最大字节数
+	defaultCacheMaxTextLength = 64 << 10
+)
+
+// resultCache 包装检查结果缓存，使其可以原子替换
+type resultCache struct {
+	cache.ContextCache
+	shared bool // 缓存可能在实例间共享（Redis、自定义实现），缓存键使用SHA-256
+}
+
+// newResultCache 包装检查结果缓存，只有内置的进程内缓存视为不共享
+func newResultCache(c cache.ContextCache) *resultCache {
+	_, local := c.(*cache.ShardedLRUCache)
+	return &resultCache{ContextCache: c, shared: !local}
+}
+
+// SetCache 使用自定义的检查结果缓存，替换 enable_cache 创建的内置缓存，未启用 enable_cache 时同样生效
+// 被替换的缓存会被关闭；c为nil时停用缓存
+func (f *ContentFilter) SetCache(c cache.ContextCache) {
+	var next *resultCache
+	if c != nil {
+		next = newResultCache(c)
+	}
+	if previous := f.cache.Swap(next); previous != nil {
+		previous.Close()
+	}
+}
+
+// clearCache 清空检查结果缓存，词库、策略、分类开关、匹配器等影响结果的配置变更后调用
+func (f *ContentFilter) clearCache() {
+	if c := f.cache.Load(); c != nil {
+		c.Clear()
+	}
+}
+
+// cacheStats 汇总缓存统计，缓存实现了 cache.UsageReporter 时包含容量使用情况
+func (f *ContentFilter) cacheStats(c *resultCache) *types.CacheStats {
+	stats := &types.CacheStats{
+		Hits:    f.cacheHits.Load(),
+		Misses:  f.cacheMisses.Load(),
+		Skipped: f.cacheSkipped.Load(),
+	}
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(total)
+	}
+	if reporter, ok := c.ContextCache.(cache.UsageReporter); ok {
+		usage := reporter.Usage()
+		stats.Evictions = usage.Evictions
+		stats.Entries = usage.Entries
+		stats.Capacity = usage.Capacity
+		stats.MemoryBytes = usage.MemoryBytes
+	}
+	return stats
+}
+
+// filterOnce 缓存未命中时检查文本并写入缓存，同一缓存键的并发检查只执行一次，其余调用方等待并复用结果的副本
+// 检查使用不随调用方取消的上下文，调用方取消或超时时直接返回上下文错误，检查继续完成并写入缓存，不影响等待同一结果的其他调用方
+func (f *ContentFilter) filterOnce(ctx context.Context, store *resultCache, cacheKey, text string, options *types.FilterOptions) (*types.FilterResult, error) {
+	leader := false
+	ch := f.inflight.DoChan(cacheKey, func() (interface{}, error) {
+		leader = true
+		return f.filterAndCache(context.WithoutCancel(ctx), store, cacheKey, text, options)
+	})
+
+	select {
+	case r := <-ch:
+		if r.Err != nil {
+			return nil, r.Err
+		}
+		result := r.Val.(*types.FilterResult)
+		if r.Shared {
+			// 所有调用方拿到的是同一个结果，各自复制
+			result = result.Clone()
+		}
+		if !leader {
+			f.coalesced.Add(1)
+		}
+		return result, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// cacheable 文本是否读写缓存，超过 cache_max_text_length 的文本每次重新检查
+func (f *ContentFilter) cacheable(text string) bool {
+	limit := f.config.CacheMaxTextLength
+	if limit == 0 {
+		limit = defaultCacheMaxTextLength
+	}
+	return limit < 0 || len(text) <= limit
+}
+
+// generateCacheKey 生成缓存键，包含服务该文本的词库版本、定时生效的时段和白名单指纹
+// 词库重载、定时生效或白名单修改后旧结果不会再被命中，即使外部缓存没有被清空
+// shared为true时键为SHA-256的前128位：缓存通过Redis在所有实例间共享，非密码学哈希可以被构造碰撞，使违规文本命中正常文本的结果；
+// 进程内缓存使用两个随机种子的maphash，种子不离开进程，无法离线构造碰撞，计算开销远低于SHA-256
+func (f *ContentFilter) generateCacheKey(state *wordState, text string, options *types.FilterOptions, shared bool) string {
+	var scratch [256]byte
+	header := appendKeyString(scratch[:0], state.version)
+	header = appendKeyUint(header, uint64(state.lastChange.Unix()))
+	header = appendKeyUint(header, state.whitelistGen)
+	header = appendKeyOptions(header, options)
+	// 文本只写入长度前缀，内容直接写入哈希，不复制
+	header = appendKeyUint(header, uint64(len(text)))
+
+	if !shared {
+		return localCacheKey(header, text)
+	}
+	digest := sha256.New()
+	digest.Write(header)
+	io.WriteString(digest, text)
+	var sum [sha256.Size]byte
+	return hex.EncodeToString(digest.Sum(sum[:0])[:cacheKeyBytes])
+}
+
+// cacheKeyBytes 缓存键取哈希的字节数
+const cacheKeyBytes = 16
+
+// localKeySeeds 进程内缓存键的哈希种子，进程启动时随机生成
+var localKeySeeds = [2]maphash.Seed{maphash.MakeSeed(), maphash.MakeSeed()}
+
+// localCacheKey 进程内缓存键，两个不同种子的64位maphash拼接为128位，长度与SHA-256截取的键相同
+func localCacheKey(header []byte, text string) string {
+	var h maphash.Hash
+	var sum [cacheKeyBytes]byte
+	for i, seed := range localKeySeeds {
+		h.SetSeed(seed)
+		h.Write(header)
+		h.WriteString(text)
+		binary.LittleEndian.PutUint64(sum[8*i:], h.Sum64())
+	}
+	return hex.EncodeToString(sum[:])
+}
+
+// 缓存键的各部分按确定的二进制格式追加到缓冲区
+// 字符串带长度前缀、数值按固定宽度写入，相邻字段拼接不会产生歧义
+
+// appendKeyOptions 写入影响结果的检查选项，结果与用户无关，不写入 UserID；新增影响结果的选项时需要同时写入
+func appendKeyOptions(b []byte, options *types.FilterOptions) []byte {
+	if options == nil {
+		return appendKeyBool(b, false)
+	}
+	b = appendKeyBool(b, true)
+	b = appendKeyBool(b, options.EnableWhitelist)
+	b = appendKeySet(b, options.Categories)
+	b = appendKeyUint(b, uint64(options.MinLevel))
+	b = appendKeyBool(b, options.ReplaceMode)
+	b = appendKeyString(b, options.Locale)
+	b = appendKeyString(b, options.ReplaceChar)
+	b = appendKeySet(b, options.AllowTerms)
+	b = appendKeyString(b, options.Format)
+	b = appendKeyBool(b, options.Classifier.Disabled)
+	b = appendKeyUint(b, math.Float64bits(options.Classifier.Weight))
+	return appendKeyUint(b, math.Float64bits(options.Classifier.Threshold))
+}
+
+// appendKeySet 排序后写入字符串集合，顺序不同的相同分类或放行词使用同一个键
+func appendKeySet(b []byte, values []string) []byte {
+	if !slices.IsSorted(values) {
+		values = slices.Clone(values)
+		slices.Sort(values)
+	}
+	b = appendKeyUint(b, uint64(len(values)))
+	for _, value := range values {
+		b = appendKeyString(b, value)
+	}
+	return b
+}
+
+func appendKeyString(b []byte, s string) []byte {
+	b = appendKeyUint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+func appendKeyUint(b []byte, v uint64) []byte {
+	return binary.LittleEndian.AppendUint64(b, v)
+}
+
+func appendKeyBool(b []byte, v bool) []byte {
+	if v {
+		return appendKeyUint(b, 1)
+	}
+	return appendKeyUint(b, 0)
+}
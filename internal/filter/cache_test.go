@@ -0,0 +1,231 @@
+package filter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/guardian/content-filter/internal/cache"
+	"github.com/guardian/content-filter/internal/classifier"
+	"github.com/guardian/content-filter/internal/logging"
+	"github.com/guardian/content-filter/internal/source"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+func newTestFilter(t *testing.T, wordDB *types.WordDatabase) *ContentFilter {
+	t.Helper()
+
+	config := &types.FilterConfig{DataId: "words", Group: "test", EnableCache: true, CacheSize: 100}
+	content, err := json.Marshal(wordDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := source.NewMemory()
+	if err := src.PublishConfig(config.DataId, config.Group, string(content)); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := NewContentFilter(src, config, logging.Discard())
+	if err != nil {
+		t.Fatalf("NewContentFilter() error = %v", err)
+	}
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+// 调用方修改返回的结果不影响缓存中的结果和其他调用方，使用 -race 运行
+func TestCachedResultsAreNotShared(t *testing.T) {
+	f := newTestFilter(t, &types.WordDatabase{
+		Version:   "1.0.0",
+		Blacklist: []types.SensitiveWord{{Word: "违禁词", Categories: []string{"abuse"}, Level: 3}},
+	})
+	text := "这里有违禁词"
+	f.Filter(text, nil) // 写入缓存
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				result := f.Filter(text, nil)
+				if len(result.Words) != 1 || result.Words[0] != "违禁词" || len(result.Matches) != 1 {
+					t.Errorf("Filter() = %+v, want the cached match", result)
+					return
+				}
+				result.Words[0] = "changed"
+				result.Words = append(result.Words, "appended")
+				result.Categories[0] = "changed"
+				result.Matches[0].Positions[0].Start = -1
+				result.Details["changed"] = "true"
+			}
+		}()
+	}
+	wg.Wait()
+
+	result := f.Filter(text, nil)
+	if len(result.Words) != 1 || result.Words[0] != "违禁词" || result.Categories[0] != "abuse" ||
+		result.Matches[0].Positions[0].Start < 0 || result.Details["changed"] != "" {
+		t.Errorf("cached result was modified by callers: %+v", result)
+	}
+}
+
+func TestCacheMaxTextLength(t *testing.T) {
+	f := newTestFilter(t, &types.WordDatabase{Version: "1.0.0", Blacklist: []types.SensitiveWord{{Word: "违禁词", Level: 3}}})
+	f.config.CacheMaxTextLength = 16
+
+	f.Filter("短文本", nil)
+	f.Filter("这是一段超过十六个字节的长文本", nil)
+	if size := f.GetStats().Cache["size"]; size != 1 {
+		t.Errorf("cache size = %v, want only the short text cached", size)
+	}
+}
+
+func TestConcurrentChecksCoalesce(t *testing.T) {
+	f := newTestFilter(t, &types.WordDatabase{Version: "1.0.0", Blacklist: []types.SensitiveWord{{Word: "违禁词", Level: 3}}})
+	var calls atomic.Int32
+	f.SetClassifier(classifier.Func(func(ctx context.Context, text string) (float64, error) {
+		calls.Add(1)
+		time.Sleep(50 * time.Millisecond)
+		return 0.1, nil
+	}))
+
+	var wg sync.WaitGroup
+	results := make([]*types.FilterResult, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = f.Filter("热门的违禁词文案", nil)
+		}(i)
+	}
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("classifier called %d times, want 1", calls.Load())
+	}
+	if coalesced := f.GetStats().Coalesced; coalesced != 9 {
+		t.Errorf("Coalesced = %d, want 9", coalesced)
+	}
+	for i, result := range results {
+		if result.Passed || (i > 0 && result == results[0]) {
+			t.Errorf("results[%d] = %p %+v, want a blocked result owned by each caller", i, result, result)
+		}
+	}
+}
+
+func TestCoalescedCallerCancel(t *testing.T) {
+	f := newTestFilter(t, &types.WordDatabase{Version: "1.0.0", Blacklist: []types.SensitiveWord{{Word: "违禁词", Level: 3}}})
+	release := make(chan struct{})
+	f.SetClassifier(classifier.Func(func(ctx context.Context, text string) (float64, error) {
+		<-release
+		return 0.1, nil
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := f.FilterContext(ctx, "违禁词", nil)
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("FilterContext() error = %v, want context.Canceled", err)
+	}
+
+	// 检查在取消后继续完成并写入缓存
+	close(release)
+	deadline := time.Now().Add(time.Second)
+	for f.GetStats().Cache["size"] != 1 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if result := f.Filter("违禁词", nil); result.Passed {
+		t.Errorf("Filter() = %+v, want blocked", result)
+	}
+}
+
+func TestCacheStats(t *testing.T) {
+	f := newTestFilter(t, &types.WordDatabase{Version: "1.0.0", Blacklist: []types.SensitiveWord{{Word: "违禁词", Level: 3}}})
+	f.config.CacheMaxTextLength = 16
+
+	f.Filter("违禁词", nil)
+	f.Filter("违禁词", nil)
+	f.Filter("正常文本", nil)
+	f.Filter("这是一段超过十六个字节的长文本", nil)
+
+	stats := f.GetStats().CacheSummary
+	if stats == nil {
+		t.Fatal("CacheSummary = nil, want cache stats")
+	}
+	if stats.Hits != 1 || stats.Misses != 2 || stats.Skipped != 1 || stats.HitRate != 1.0/3 {
+		t.Errorf("CacheSummary = %+v, want 1 hit, 2 misses, 1 skipped", stats)
+	}
+	if stats.Entries != 2 || stats.Capacity != 100 || stats.MemoryBytes <= 0 {
+		t.Errorf("CacheSummary = %+v, want 2 entries of 100 with a memory estimate", stats)
+	}
+
+	f.clearCache()
+	if stats := f.GetStats().CacheSummary; stats.Entries != 0 || stats.MemoryBytes != 0 {
+		t.Errorf("CacheSummary after clear = %+v, want no entries and no memory", stats)
+	}
+}
+
+func TestGenerateCacheKey(t *testing.T) {
+	f := newTestFilter(t, &types.WordDatabase{Version: "1.0.0", Blacklist: []types.SensitiveWord{{Word: "违禁词", Level: 3}}})
+	state := f.state.Load()
+
+	for _, shared := range []bool{false, true} {
+		key := func(categories ...string) string {
+			return f.generateCacheKey(state, "文本", &types.FilterOptions{Categories: categories}, shared)
+		}
+
+		if k := key("a", "b"); len(k) != 2*cacheKeyBytes {
+			t.Errorf("shared %v: key %q has %d characters, want %d", shared, k, len(k), 2*cacheKeyBytes)
+		}
+		if key("a", "b") != key("b", "a") {
+			t.Errorf("shared %v: keys differ for the same categories in a different order", shared)
+		}
+		if key("a", "b") == key("ab") || key("a", "b") == key("a", "b", "b") {
+			t.Errorf("shared %v: keys collide for different categories", shared)
+		}
+	}
+
+	// 只有内置的进程内缓存使用maphash，Redis和自定义缓存可能在实例间共享
+	if f.cache.Load().shared {
+		t.Error("built-in memory cache is marked as shared")
+	}
+	custom := struct{ cache.ContextCache }{cache.NewShardedLRUCache(10, 1, time.Minute)}
+	if !newResultCache(custom).shared {
+		t.Error("custom cache is not marked as shared")
+	}
+}
+
+// 进程内缓存键使用maphash，与SHA-256对比，使用 -bench GenerateCacheKey 运行
+func BenchmarkGenerateCacheKey(b *testing.B) {
+	state := &wordState{version: "1.0.0", lastChange: time.Now(), whitelistGen: 1}
+	options := types.DefaultFilterOptions()
+	f := &ContentFilter{}
+
+	for _, size := range []int{64, 1 << 10, 16 << 10} {
+		text := strings.Repeat("文", size/3)
+		for _, shared := range []bool{false, true} {
+			name := fmt.Sprintf("maphash/%d", size)
+			if shared {
+				name = fmt.Sprintf("sha256/%d", size)
+			}
+			b.Run(name, func(b *testing.B) {
+				b.SetBytes(int64(len(text)))
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					f.generateCacheKey(state, text, options, shared)
+				}
+			})
+		}
+	}
+}
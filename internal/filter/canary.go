@@ -0,0 +1,294 @@
+package filter
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/guardian/content-filter/internal/integrity"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// 灰度默认参数
+const (
+	defaultCanaryDuration    = 10 * time.Minute // 默认观察时长
+	defaultCanaryMinRequests = 1000             // 默认每组最少请求数
+	defaultCanaryMaxDelta    = 0.05             // 默认允许的拦截率上升幅度
+	canarySlots              = 10000            // 按文本哈希分桶的桶数，灰度比例精确到0.01%
+)
+
+// ErrNoCanary 当前没有灰度中的词库
+var ErrNoCanary = errors.New("no canary word database")
+
+// canary 灰度中的词库，与正在服务的词库分别统计拦截率
+type canary struct {
+	state     *wordState
+	startedAt time.Time
+	timer     *time.Timer
+	stable    canaryCounter // 正在服务的词库
+	candidate canaryCounter // 灰度词库
+}
+
+// rejectedCanary 被拒绝或放弃的灰度词库，发布不同的版本或内容前定期重载不再灰度
+type rejectedCanary struct {
+	version  string
+	checksum string
+}
+
+// canaryCounter 请求数和拦截数
+type canaryCounter struct {
+	requests atomic.Int64
+	blocked  atomic.Int64
+}
+
+// record 记录一次检查结果
+func (c *canaryCounter) record(result *types.FilterResult) {
+	c.requests.Add(1)
+	if !result.Passed {
+		c.blocked.Add(1)
+	}
+}
+
+// blockRate 拦截率，没有请求时为0
+func (c *canaryCounter) blockRate() float64 {
+	requests := c.requests.Load()
+	if requests == 0 {
+		return 0
+	}
+	return float64(c.blocked.Load()) / float64(requests)
+}
+
+// canaryEligible 新词库是否需要先灰度：启用灰度、已有正在服务的词库且不是本地的运行时修改
+func (f *ContentFilter) canaryEligible(wordDB *types.WordDatabase) bool {
+	current := f.state.Load()
+	return f.config.Canary.Enabled && current.wordCount > 0 && wordDB.Version != current.version &&
+		!strings.Contains(wordDB.Version, localVersionSuffix)
+}
+
+// canaryDuration 灰度观察时长
+func (f *ContentFilter) canaryDuration() time.Duration {
+	if f.config.Canary.Duration > 0 {
+		return f.config.Canary.Duration
+	}
+	return defaultCanaryDuration
+}
+
+// startCanary 开始灰度新词库，替换尚未结束的灰度，调用方需持有 buildMu
+func (f *ContentFilter) startCanary(state *wordState) {
+	duration := f.canaryDuration()
+	c := &canary{state: state, startedAt: time.Now()}
+	c.timer = time.AfterFunc(duration, func() { f.evaluateCanary(c) })
+	if previous := f.canary.Swap(c); previous != nil {
+		previous.timer.Stop()
+		f.logger.Infof("Canary for version %s superseded by version %s", previous.state.version, state.version)
+	}
+
+	// 分流到灰度词库的文本可能已缓存了旧词库的结果
+	if !f.config.Canary.Shadow {
+		f.clearCache()
+	}
+
+	f.logger.Infof("Canary started for word database version %s (stable %s), percent: %.2f, shadow: %v, duration: %v",
+		state.version, f.state.Load().version, f.config.Canary.Percent, f.config.Canary.Shadow, duration)
+}
+
+// discardCanary 词库被直接替换时丢弃灰度中的词库，调用方需持有 buildMu
+func (f *ContentFilter) discardCanary(version string) {
+	if c := f.canary.Swap(nil); c != nil {
+		c.timer.Stop()
+		f.logger.Warnf("Canary for version %s discarded, superseded by version %s", c.state.version, version)
+	}
+}
+
+// evaluateCanary 观察期结束后比较拦截率，上升幅度不超过阈值时全量生效，否则放弃灰度词库
+// 任一组请求数不足时延长观察期
+func (f *ContentFilter) evaluateCanary(c *canary) {
+	f.buildMu.Lock()
+	defer f.buildMu.Unlock()
+
+	if f.canary.Load() != c {
+		return
+	}
+
+	minRequests := int64(f.config.Canary.MinRequests)
+	if minRequests <= 0 {
+		minRequests = defaultCanaryMinRequests
+	}
+	if c.stable.requests.Load() < minRequests || c.candidate.requests.Load() < minRequests {
+		f.logger.Infof("Canary for version %s has too few requests (stable %d, canary %d), extending observation",
+			c.state.version, c.stable.requests.Load(), c.candidate.requests.Load())
+		c.timer.Reset(f.canaryDuration())
+		return
+	}
+
+	maxDelta := f.config.Canary.MaxBlockRateDelta
+	if maxDelta <= 0 {
+		maxDelta = defaultCanaryMaxDelta
+	}
+	delta := c.candidate.blockRate() - c.stable.blockRate()
+	if delta > maxDelta {
+		f.canary.Store(nil)
+		f.rejectCanary(c)
+		f.logger.Errorf("Canary for version %s rejected: block rate %.4f vs stable %.4f (delta %.4f exceeds %.4f), keeping version %s",
+			c.state.version, c.candidate.blockRate(), c.stable.blockRate(), delta, maxDelta, f.state.Load().version)
+		return
+	}
+
+	f.logger.Infof("Canary for version %s passed: block rate %.4f vs stable %.4f", c.state.version, c.candidate.blockRate(), c.stable.blockRate())
+	f.promote(c)
+}
+
+// promote 灰度词库全量生效，调用方需持有 buildMu
+func (f *ContentFilter) promote(c *canary) {
+	f.canary.Store(nil)
+	c.timer.Stop()
+
+	previous := f.state.Load().wordDB
+	old := f.reloadHookStats()
+	state := *c.state
+	state.loadedAt = time.Now()
+	f.swapState(&state)
+	f.scheduleWordChange(&state)
+	f.recordDiff(previous, state.wordDB)
+	f.runReloadHooks(old)
+	if state.wordDB != nil {
+		f.saveSnapshot(state.wordDB)
+	}
+}
+
+// rejectCanary 记录被拒绝的灰度词库，调用方需持有 buildMu
+func (f *ContentFilter) rejectCanary(c *canary) {
+	f.rejected = rejectedCanary{version: c.state.version, checksum: wordChecksum(c.state.wordDB)}
+}
+
+// canaryRejected 词库是否为已被拒绝的灰度词库（版本和内容都相同），调用方需持有 buildMu
+func (f *ContentFilter) canaryRejected(wordDB *types.WordDatabase) bool {
+	return f.rejected.version != "" && f.rejected.version == wordDB.Version && f.rejected.checksum == wordChecksum(wordDB)
+}
+
+// wordChecksum 词库内容的校验和，词库已带校验和时直接使用
+func wordChecksum(wordDB *types.WordDatabase) string {
+	if wordDB == nil {
+		return ""
+	}
+	if wordDB.Checksum != "" {
+		return wordDB.Checksum
+	}
+	checksum, err := integrity.Checksum(wordDB)
+	if err != nil {
+		return ""
+	}
+	return checksum
+}
+
+// PromoteCanary 立即全量生效灰度中的词库
+func (f *ContentFilter) PromoteCanary() error {
+	f.buildMu.Lock()
+	defer f.buildMu.Unlock()
+
+	c := f.canary.Load()
+	if c == nil {
+		return ErrNoCanary
+	}
+	f.logger.Infof("Canary for version %s promoted manually", c.state.version)
+	f.promote(c)
+	return nil
+}
+
+// AbortCanary 放弃灰度中的词库，继续使用当前词库
+func (f *ContentFilter) AbortCanary() error {
+	f.buildMu.Lock()
+	defer f.buildMu.Unlock()
+
+	c := f.canary.Swap(nil)
+	if c == nil {
+		return ErrNoCanary
+	}
+	c.timer.Stop()
+	f.rejectCanary(c)
+	if !f.config.Canary.Shadow {
+		f.clearCache()
+	}
+	f.logger.Warnf("Canary for version %s aborted manually", c.state.version)
+	return nil
+}
+
+// CanaryStatus 返回灰度状态，没有灰度中的词库时返回nil
+func (f *ContentFilter) CanaryStatus() *types.CanaryStatus {
+	c := f.canary.Load()
+	if c == nil {
+		return nil
+	}
+	return &types.CanaryStatus{
+		Version:            c.state.version,
+		StableVersion:      f.state.Load().version,
+		StartedAt:          c.startedAt,
+		Percent:            f.config.Canary.Percent,
+		Shadow:             f.config.Canary.Shadow,
+		StableRequests:     c.stable.requests.Load(),
+		StableBlockRate:    c.stable.blockRate(),
+		CandidateRequests:  c.candidate.requests.Load(),
+		CandidateBlockRate: c.candidate.blockRate(),
+		BlockRateDelta:     c.candidate.blockRate() - c.stable.blockRate(),
+	}
+}
+
+// canaryFilter 执行过滤：按文本哈希将配置比例的请求分流到灰度词库，影子模式下这部分请求同时用灰度词库检查但不影响结果
+// 同一文本总是分到同一组，缓存的结果与所用词库一致
+func (f *ContentFilter) canaryFilter(ctx context.Context, text string, options *types.FilterOptions) (*types.FilterResult, error) {
+	stable := f.state.Load()
+	c := f.canary.Load()
+	if c == nil {
+		return f.filterState(ctx, stable, text, options)
+	}
+
+	sampled := f.canarySampled(text)
+	if sampled && !f.config.Canary.Shadow {
+		result, err := f.filterState(ctx, c.state, text, options)
+		if err == nil {
+			c.candidate.record(result)
+		}
+		return result, err
+	}
+
+	result, err := f.filterState(ctx, stable, text, options)
+	if err != nil {
+		return nil, err
+	}
+	if !f.config.Canary.Shadow {
+		c.stable.record(result)
+		return result, nil
+	}
+
+	if sampled {
+		shadow, err := f.filterState(ctx, c.state, text, options)
+		if err == nil {
+			c.stable.record(result)
+			c.candidate.record(shadow)
+		}
+	}
+	return result, nil
+}
+
+// servingState 返回检查该文本所用的词库快照：非影子模式下分流到灰度的文本使用灰度词库，其余使用正在服务的词库
+func (f *ContentFilter) servingState(text string) *wordState {
+	if c := f.canary.Load(); c != nil && !f.config.Canary.Shadow && f.canarySampled(text) {
+		return c.state
+	}
+	return f.state.Load()
+}
+
+// canarySampled 文本是否分流到灰度组
+func (f *ContentFilter) canarySampled(text string) bool {
+	return canarySlot(text) < int(f.config.Canary.Percent*canarySlots/100)
+}
+
+// canarySlot 文本所在的分桶
+func canarySlot(text string) int {
+	h := fnv.New32a()
+	h.Write([]byte(text))
+	return int(h.Sum32() % canarySlots)
+}
@@ -0,0 +1,135 @@
+package filter
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/guardian/content-filter/internal/logging"
+	"github.com/guardian/content-filter/internal/source"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// newCanaryFilter 创建影子模式全量灰度的过滤器，观察期足够长，由测试直接调用 evaluateCanary
+func newCanaryFilter(t *testing.T, minRequests int) *ContentFilter {
+	t.Helper()
+
+	config := &types.FilterConfig{
+		DataId: "words",
+		Group:  "test",
+		Canary: types.CanaryConfig{Enabled: true, Percent: 100, Shadow: true, Duration: time.Hour, MinRequests: minRequests},
+	}
+	src := source.NewMemory()
+	publishWords(t, src, config, "1.0.0", "违禁词")
+	f, err := NewContentFilter(src, config, logging.Discard())
+	if err != nil {
+		t.Fatalf("NewContentFilter() error = %v", err)
+	}
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+// publishWords 向词库源发布只含黑名单的词库
+func publishWords(t *testing.T, src source.ConfigSource, config *types.FilterConfig, version string, words ...string) {
+	t.Helper()
+
+	wordDB := &types.WordDatabase{Version: version}
+	for _, word := range words {
+		wordDB.Blacklist = append(wordDB.Blacklist, types.SensitiveWord{Word: word, Level: 3})
+	}
+	content, err := json.Marshal(wordDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := src.PublishConfig(config.DataId, config.Group, string(content)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// reloadCanary 发布新词库并重载，返回开始的灰度
+func reloadCanary(t *testing.T, f *ContentFilter, version string, words ...string) *canary {
+	t.Helper()
+
+	publishWords(t, f.source, f.config, version, words...)
+	if err := f.loadWordDatabase(); err != nil {
+		t.Fatalf("loadWordDatabase() error = %v", err)
+	}
+	c := f.canary.Load()
+	if c == nil || c.state.version != version {
+		t.Fatalf("canary = %+v, want version %s in canary", f.CanaryStatus(), version)
+	}
+	return c
+}
+
+func TestCanaryEvaluation(t *testing.T) {
+	tests := []struct {
+		name        string
+		minRequests int
+		texts       []string
+		wantVersion string // 评估后正在服务的词库版本
+		wantCanary  bool   // 评估后是否仍在灰度
+	}{
+		{name: "promote", minRequests: 2, texts: []string{"正常文本", "这里有违禁词"}, wantVersion: "2.0.0"},
+		{name: "reject", minRequests: 2, texts: []string{"新词出现了", "又是新词"}, wantVersion: "1.0.0"},
+		{name: "extend on low traffic", minRequests: 1000, texts: []string{"正常文本"}, wantVersion: "1.0.0", wantCanary: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newCanaryFilter(t, tt.minRequests)
+			c := reloadCanary(t, f, "2.0.0", "违禁词", "新词")
+			for _, text := range tt.texts {
+				f.Filter(text, nil)
+			}
+
+			f.evaluateCanary(c)
+			if got := f.state.Load().version; got != tt.wantVersion {
+				t.Errorf("serving version = %s, want %s", got, tt.wantVersion)
+			}
+			if got := f.canary.Load() == c; got != tt.wantCanary {
+				t.Errorf("canary still running = %v, want %v", got, tt.wantCanary)
+			}
+		})
+	}
+}
+
+// 被拒绝的版本在定期重载时不再灰度，发布新版本后重新灰度
+func TestRejectedCanaryIsNotRetried(t *testing.T) {
+	f := newCanaryFilter(t, 1)
+	c := reloadCanary(t, f, "2.0.0", "违禁词", "新词")
+	f.Filter("新词出现了", nil)
+	f.evaluateCanary(c)
+	if f.canary.Load() != nil {
+		t.Fatal("canary still running, want rejected")
+	}
+
+	if err := f.loadWordDatabase(); err != nil {
+		t.Fatalf("loadWordDatabase() error = %v", err)
+	}
+	if status := f.CanaryStatus(); status != nil {
+		t.Errorf("CanaryStatus() = %+v after reload, want the rejected version skipped", status)
+	}
+	if got := f.state.Load().version; got != "1.0.0" {
+		t.Errorf("serving version = %s, want 1.0.0", got)
+	}
+
+	reloadCanary(t, f, "2.0.1", "违禁词")
+}
+
+// 灰度期间发布的新版本替换灰度中的词库，旧灰度的评估不再生效
+func TestCanarySuperseded(t *testing.T) {
+	f := newCanaryFilter(t, 1)
+	previous := reloadCanary(t, f, "2.0.0", "违禁词", "新词")
+	current := reloadCanary(t, f, "3.0.0", "违禁词", "另一个词")
+	f.Filter("正常文本", nil)
+
+	f.evaluateCanary(previous)
+	if f.canary.Load() != current || f.state.Load().version != "1.0.0" {
+		t.Errorf("after evaluating the superseded canary: canary = %+v, serving %s, want 3.0.0 still in canary",
+			f.CanaryStatus(), f.state.Load().version)
+	}
+
+	f.evaluateCanary(current)
+	if got := f.state.Load().version; got != "3.0.0" {
+		t.Errorf("serving version = %s, want 3.0.0 promoted", got)
+	}
+}
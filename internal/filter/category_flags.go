@@ -0,0 +1,96 @@
+package filter
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// startCategoryFlags 加载分类开关并监听变化
+func (f *ContentFilter) startCategoryFlags() error {
+	if f.config.FlagsDataId == "" {
+		return nil
+	}
+
+	content, err := f.source.GetConfig(f.config.FlagsDataId, f.config.Group)
+	if err != nil {
+		// 开关配置不存在时按全部启用处理
+		f.logger.Warnf("Failed to load category flags, all categories enabled: %v", err)
+	} else if content != "" {
+		if err := f.applyCategoryFlags(content); err != nil {
+			return err
+		}
+	}
+
+	return f.source.ListenConfig(f.config.FlagsDataId, f.config.Group, func(content string) {
+		if err := f.applyCategoryFlags(content); err != nil {
+			f.logger.Errorf("Failed to apply category flags: %v", err)
+		}
+	})
+}
+
+// applyCategoryFlags 解析并应用分类开关
+func (f *ContentFilter) applyCategoryFlags(content string) error {
+	var flags types.CategoryFlags
+	if err := json.Unmarshal([]byte(content), &flags); err != nil {
+		return fmt.Errorf("failed to unmarshal category flags: %w", err)
+	}
+
+	for category, flag := range flags.Categories {
+		switch flag {
+		case types.CategoryEnabled, types.CategoryDisabled, types.CategoryReview, types.CategoryMonitor:
+		default:
+			return fmt.Errorf("invalid flag %q for category %s", flag, category)
+		}
+	}
+
+	f.flags.Store(&flags)
+	f.clearCache()
+
+	f.logger.Infof("Category flags updated, version: %s, flags: %v", flags.Version, flags.Categories)
+	return nil
+}
+
+// filterCategories 按分类开关和 monitor_categories 过滤匹配分类，返回仍生效的分类、只观察的分类以及是否需要强制人审
+func (f *ContentFilter) filterCategories(flags *types.CategoryFlags, categories []string) ([]string, []string, bool) {
+	if (flags == nil || len(flags.Categories) == 0) && len(f.config.MonitorCategories) == 0 {
+		return categories, nil, false
+	}
+
+	active := make([]string, 0, len(categories))
+	var monitored []string
+	review := false
+	for _, category := range categories {
+		var flag types.CategoryFlag
+		if flags != nil {
+			flag = flags.Categories[category]
+		}
+		if flag == "" && containsString(f.config.MonitorCategories, category) {
+			flag = types.CategoryMonitor
+		}
+
+		switch flag {
+		case types.CategoryDisabled:
+			continue
+		case types.CategoryMonitor:
+			monitored = append(monitored, category)
+			continue
+		case types.CategoryReview:
+			review = true
+		}
+		active = append(active, category)
+	}
+
+	return active, monitored, review
+}
+
+// containsString 列表中是否包含字符串
+func containsString(items []string, s string) bool {
+	for _, item := range items {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package filter
+
+import (
+	"context"
+	"slices"
+	"strconv"
+
+	"github.com/guardian/content-filter/internal/classifier"
+	"github.com/guardian/content-filter/internal/markup"
+	"github.com/guardian/content-filter/internal/trace"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// 分类模型的默认配置
+const (
+	defaultClassifierWeight    = 10
+	defaultClassifierThreshold = 0.5
+)
+
+// textClassifier 包装分类模型，便于原子替换
+type textClassifier struct {
+	classifier.Classifier
+}
+
+// startClassifier 按配置创建远程推理接口
+func (f *ContentFilter) startClassifier() {
+	if f.config.Classifier.Enabled {
+		f.classifier.Store(&textClassifier{classifier.NewHTTP(&f.config.Classifier)})
+	}
+}
+
+// SetClassifier 使用自定义分类模型（如本地ONNX模型），替换配置中的远程推理接口，权重和阈值仍按 classifier 配置
+// c为nil时停止调用分类模型；设置后清空结果缓存
+func (f *ContentFilter) SetClassifier(c classifier.Classifier) {
+	if c == nil {
+		f.classifier.Store(nil)
+	} else {
+		f.classifier.Store(&textClassifier{c})
+	}
+
+	f.clearCache()
+}
+
+// classify 调用分类模型，违规概率达到阈值时按权重计入风险分并重新决定处理动作
+// 调用失败时保留原结果
+func (f *ContentFilter) classify(ctx context.Context, text string, result *types.FilterResult, options *types.FilterOptions) {
+	c := f.classifier.Load()
+	if c == nil || options.Classifier.Disabled {
+		return
+	}
+
+	probability, err := c.Classify(ctx, markup.Extract(text, options.Format).String())
+	if err != nil {
+		trace.Entry(ctx, f.logger).Warnf("Classifier failed, keeping dictionary result: %v", err)
+		result.Details["classifier"] = "unavailable"
+		return
+	}
+	result.Details["classifier_probability"] = strconv.FormatFloat(probability, 'f', 3, 64)
+
+	weight, threshold := f.classifierParams(options)
+	if probability < threshold {
+		return
+	}
+
+	category := f.config.Classifier.Category
+	if category == "" {
+		category = types.CategoryToxicity
+	}
+	if !slices.Contains(result.Categories, category) {
+		result.Categories = append(result.Categories, category)
+	}
+	if result.CategoryCounts == nil {
+		result.CategoryCounts = make(map[string]int)
+	}
+	result.CategoryCounts[category]++
+	result.RiskScore += probability * weight
+
+	// 未命中敏感词的结果按命中处理，由风险分阈值和处理策略重新决定
+	result.Passed = false
+	f.decide(result, options)
+}
+
+// classifierParams 返回本次检查的权重和阈值，检查选项优先于配置
+func (f *ContentFilter) classifierParams(options *types.FilterOptions) (float64, float64) {
+	weight, threshold := options.Classifier.Weight, options.Classifier.Threshold
+	if weight <= 0 {
+		weight = f.config.Classifier.Weight
+	}
+	if weight <= 0 {
+		weight = defaultClassifierWeight
+	}
+	if threshold <= 0 {
+		threshold = f.config.Classifier.Threshold
+	}
+	if threshold <= 0 {
+		threshold = defaultClassifierThreshold
+	}
+	return weight, threshold
+}
@@ -1,140 +1,600 @@
 package filter
 
 import (
-	"crypto/md5"
-	"encoding/json"
+	"context"
+	"crypto/ed25519"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/singleflight"
 
+	"github.com/guardian/content-filter/internal/alert"
 	"github.com/guardian/content-filter/internal/algorithm"
+	"github.com/guardian/content-filter/internal/anomaly"
+	"github.com/guardian/content-filter/internal/artifact"
+	"github.com/guardian/content-filter/internal/audit"
+	"github.com/guardian/content-filter/internal/bus"
 	"github.com/guardian/content-filter/internal/cache"
-	"github.com/guardian/content-filter/internal/nacos"
+	"github.com/guardian/content-filter/internal/contact"
+	"github.com/guardian/content-filter/internal/escalation"
+	"github.com/guardian/content-filter/internal/integrity"
+	"github.com/guardian/content-filter/internal/logging"
+	"github.com/guardian/content-filter/internal/markup"
+	"github.com/guardian/content-filter/internal/matcher"
+	"github.com/guardian/content-filter/internal/message"
+	"github.com/guardian/content-filter/internal/normalize"
+	"github.com/guardian/content-filter/internal/offender"
+	"github.com/guardian/content-filter/internal/replace"
+	"github.com/guardian/content-filter/internal/review"
+	"github.com/guardian/content-filter/internal/source"
+	"github.com/guardian/content-filter/internal/trace"
 	"github.com/guardian/content-filter/internal/types"
+	"github.com/guardian/content-filter/internal/webhook"
 )
 
 // ContentFilter 内容过滤器
 type ContentFilter struct {
-	automaton    *algorithm.ACAutomaton
-	nacosClient  *nacos.Client
-	cache        cache.Cache
-	config       *types.FilterConfig
-	logger       *logrus.Logger
-	whitelist    map[string]bool
-	mu           sync.RWMutex
-	lastUpdate   time.Time
-	version      string
-	stopChan     chan struct{}
-	reloadTicker *time.Ticker
-}
-
-// NewContentFilter 创建新的内容过滤器
-func NewContentFilter(nacosClient *nacos.Client, config *types.FilterConfig, logger *logrus.Logger) (*ContentFilter, error) {
+	state         atomic.Pointer[wordState] // 正在服务的词库快照，读取无需加锁，更新时整体替换
+	source        source.ConfigSource
+	cache         atomic.Pointer[resultCache] // 检查结果缓存，未启用时为nil
+	inflight      singleflight.Group          // 缓存未命中时按缓存键合并并发的相同检查
+	coalesced     atomic.Int64                // 合并到其他调用方、复用其结果的检查次数
+	cacheHits     atomic.Int64                // 缓存命中次数
+	cacheMisses   atomic.Int64                // 缓存未命中次数
+	cacheSkipped  atomic.Int64                // 文本过长、未读写缓存的检查次数
+	config        *types.FilterConfig
+	logger        logging.FieldLogger
+	mu            sync.RWMutex // 保护 reloadErr
+	buildMu       sync.Mutex   // 串行化词库构建，避免先开始的构建覆盖后到的新版本
+	stopChan      chan struct{}
+	reloadTicker  *time.Ticker
+	bus           bus.Bus
+	instanceId    string
+	reloadErr     error                               // 最近一次重载的错误
+	flags         atomic.Pointer[types.CategoryFlags] // 分类开关
+	policy        atomic.Pointer[types.Policy]        // 处理策略
+	messages      *message.Catalog                    // 提示语目录
+	normalizer    *normalize.Normalizer               // 文本标准化，文本和词库使用同一规则
+	contact       *contact.Detector                   // 联系方式检测，未启用时为nil
+	updateChan    chan *types.WordDatabase
+	progressMu    sync.Mutex
+	progress      algorithm.BuildProgress
+	editMu        sync.Mutex                        // 保护 pendingEdits 和 rebuildTimer
+	pendingEdits  []wordEdit                        // 等待防抖重建的运行时敏感词修改
+	rebuildTimer  *time.Timer                       // 防抖重建定时器
+	partsMu       sync.Mutex                        // 保护 parts
+	parts         []*types.WordDatabase             // 各词库的最新内容，与 wordDataSets 一一对应，用于合并
+	publicKey     ed25519.PublicKey                 // 词库签名公钥，为nil时只校验已设置的校验和
+	diffMu        sync.Mutex                        // 保护 diffs
+	diffs         []*types.ReloadDiff               // 最近的词库重载差异，最新的在后
+	canary        atomic.Pointer[canary]            // 灰度中的词库，没有时为nil
+	rejected      rejectedCanary                    // 最近被拒绝或放弃的灰度词库，受 buildMu 保护
+	monitorHits   sync.Map                          // 只观察的敏感词 -> *atomic.Int64 累计命中次数
+	scheduleTimer *time.Timer                       // 敏感词定时生效或失效的重建定时器，受 buildMu 保护
+	lintReport    atomic.Pointer[types.LintReport]  // 最近一次词库检查的结果
+	auditor       atomic.Pointer[audit.Auditor]     // 审计日志，未启用时为nil
+	sampler       atomic.Pointer[audit.Sampler]     // 抽样复核，未启用时为nil
+	reviews       atomic.Pointer[review.Dispatcher] // 人工复核队列，未启用时为nil
+	metrics       metrics                           // 检查次数、处理动作、分类和耗时统计
+	anomaly       *anomaly.Detector                 // 拦截率突变检测，未启用时为nil
+	notifier      alert.Notifier                    // 拦截率突变告警的发送通道，未配置时为nil
+	notifiers     alert.Notifier                    // alert_notifiers 配置的告警通道，所有告警都会发送，未配置时为nil
+	reloadAlert   reloadAlertState                  // 词库重载告警
+	hooks         hooks                             // OnMatch、OnReload 注册的回调
+	matchers      atomic.Pointer[[]matcher.Matcher] // 注册的自定义匹配器，受 hooks.mu 保护写入
+	escalation    *escalation.Client                // 外部审核接口，未启用时为nil
+	classifier    atomic.Pointer[textClassifier]    // 文本分类模型，未启用时为nil
+	offenders     offender.Store                    // 用户违规统计，未启用时为nil
+	webhooks      *webhook.Dispatcher               // 违规回调推送，未配置时为nil
+}
+
+// NewContentFilter 创建新的内容过滤器，src为词库配置源（Nacos、etcd等），logger为nil时使用默认日志
+func NewContentFilter(src source.ConfigSource, config *types.FilterConfig, logger logging.Logger) (*ContentFilter, error) {
+	// 库方式使用时不经过配置文件加载，同样拒绝无效配置，如 public_key 与 persist_* 同时开启会发布去掉签名的词库
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	detector, err := contact.New(&config.Contact)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create contact detector: %w", err)
+	}
+
+	var publicKey ed25519.PublicKey
+	if config.PublicKey != "" {
+		if publicKey, err = integrity.ParsePublicKey(config.PublicKey); err != nil {
+			return nil, fmt.Errorf("failed to parse word database public key: %w", err)
+		}
+	}
+
 	filter := &ContentFilter{
-		automaton:   algorithm.NewACAutomaton(),
-		nacosClient: nacosClient,
-		config:      config,
-		logger:      logger,
-		whitelist:   make(map[string]bool),
-		stopChan:    make(chan struct{}),
+		source:     src,
+		config:     config,
+		logger:     logging.From(logger),
+		stopChan:   make(chan struct{}),
+		instanceId: bus.NewInstanceID(),
+		updateChan: make(chan *types.WordDatabase, 1),
+		messages:   message.NewCatalog(config.Messages, config.DefaultLocale),
+		normalizer: normalize.New(&config.Normalize),
+		contact:    detector,
+		publicKey:  publicKey,
+	}
+	filter.state.Store(emptyWordState())
+
+	// 创建告警通道
+	notifiers := make([]alert.Notifier, 0, len(config.AlertNotifiers))
+	for i := range config.AlertNotifiers {
+		notifier, err := alert.New(&config.AlertNotifiers[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to create alert notifier: %w", err)
+		}
+		notifiers = append(notifiers, notifier)
 	}
+	filter.notifiers = alert.Join(notifiers...)
 
 	// 初始化缓存
 	if config.EnableCache {
-		filter.cache = cache.NewLRUCache(config.CacheSize, 10*time.Minute)
+		shards := config.CacheShards
+		if shards == 0 {
+			shards = defaultCacheShards
+		}
+		ttl := config.CacheTTL
+		if ttl == 0 {
+			ttl = defaultCacheTTL
+		}
+		var resultStore cache.ContextCache = cache.NewShardedLRUCache(config.CacheSize, shards, ttl)
+		if config.CacheStore == types.CacheStoreRedis {
+			redisCache, err := cache.NewRedisCache(&config.CacheRedis, resultStore, ttl, filter.logger)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create redis cache: %w", err)
+			}
+			resultStore = redisCache
+		}
+		filter.cache.Store(newResultCache(resultStore))
 	}
 
-	// 加载初始配置
-	if err := filter.loadWordDatabase(); err != nil {
+	// 加载初始配置，配置源不可用时按 word_sources 回退
+	if err := filter.loadInitialWordDatabase(); err != nil {
 		return nil, fmt.Errorf("failed to load initial word database: %w", err)
 	}
 
+	// 启动后台构建
+	filter.startUpdateWorker()
+
 	// 启动配置监听
 	if err := filter.startConfigListener(); err != nil {
 		return nil, fmt.Errorf("failed to start config listener: %w", err)
 	}
 
+	// 加载分类开关
+	if err := filter.startCategoryFlags(); err != nil {
+		return nil, fmt.Errorf("failed to start category flags: %w", err)
+	}
+
+	// 加载处理策略
+	if err := filter.startPolicy(); err != nil {
+		return nil, fmt.Errorf("failed to start policy: %w", err)
+	}
+
+	// 启动集群缓存失效广播
+	if err := filter.startInvalidationBus(); err != nil {
+		return nil, fmt.Errorf("failed to start invalidation bus: %w", err)
+	}
+
+	// 启动审计日志
+	if err := filter.startAudit(); err != nil {
+		return nil, err
+	}
+
+	// 启动抽样复核
+	if err := filter.startSampling(); err != nil {
+		return nil, err
+	}
+
+	// 启动人工复核队列
+	if err := filter.startReview(); err != nil {
+		return nil, err
+	}
+
+	// 启动拦截率突变检测
+	filter.startAnomaly()
+
+	// 创建外部审核接口客户端
+	filter.startEscalation()
+
+	// 创建文本分类模型
+	filter.startClassifier()
+
+	// 创建用户违规统计
+	if err := filter.startOffender(); err != nil {
+		return nil, err
+	}
+
+	// 启动违规回调推送
+	filter.startWebhooks()
+
+	// 启动词库重载告警
+	filter.startReloadAlert()
+
 	// 启动定期重载
 	filter.startPeriodicReload()
 
 	return filter, nil
 }
 
-// loadWordDatabase 加载词库
+// loadWordDatabase 加载词库，并记录本次加载结果
 func (f *ContentFilter) loadWordDatabase() error {
-	wordDB, err := f.nacosClient.GetWordDatabase(f.config.DataId, f.config.Group)
+	err := f.fetchWordDatabase()
+	f.setReloadResult(err)
+	return err
+}
+
+// fetchWordDatabase 从词库源获取并应用词库
+func (f *ContentFilter) fetchWordDatabase() error {
+	if f.config.ArtifactPath != "" {
+		return f.loadArtifact()
+	}
+
+	wordDB, err := f.getWordDatabase()
 	if err != nil {
-		return fmt.Errorf("failed to get word database from nacos: %w", err)
+		return fmt.Errorf("failed to get word database from source: %w", err)
+	}
+
+	if err := f.updateWordDatabase(wordDB, true); err != nil {
+		return err
 	}
 
-	return f.updateWordDatabase(wordDB)
+	f.saveServingSnapshot(wordDB)
+	return nil
 }
 
+// buildProgressEvery 构建进度报告间隔（敏感词数）
+const buildProgressEvery = 100000
+
 // updateWordDatabase 更新词库
-func (f *ContentFilter) updateWordDatabase(wordDB *types.WordDatabase) error {
-	f.mu.Lock()
-	defer f.mu.Unlock()
+// 新快照在后台完整构建，构建期间旧快照继续服务，完成后一次性替换；超出内存预算时保留旧快照
+// canary为true且启用了灰度时，新快照先灰度，观察期结束后再决定是否替换
+func (f *ContentFilter) updateWordDatabase(wordDB *types.WordDatabase, canary bool) error {
+	if wordDB == nil {
+		return fmt.Errorf("%w: word database is nil", types.ErrEmptyDictionary)
+	}
+
+	f.buildMu.Lock()
+	defer f.buildMu.Unlock()
 
-	// 清空现有数据
-	f.automaton.Clear()
-	f.whitelist = make(map[string]bool)
+	// 定期重载拿到灰度中的同一版本时继续观察
+	if c := f.canary.Load(); canary && c != nil && c.state.version == wordDB.Version {
+		return nil
+	}
+	// 被拒绝的灰度词库在发布不同的版本或内容前不再灰度
+	if canary && f.canaryRejected(wordDB) {
+		f.logger.Debugf("Skipping rejected canary version %s", wordDB.Version)
+		return nil
+	}
 
-	// 更新白名单
-	for _, word := range wordDB.Whitelist {
-		f.whitelist[strings.ToLower(word)] = true
+	// 检查词库内容，按严格程度拒绝有问题的词库
+	if err := f.lintWordDatabase(wordDB); err != nil {
+		return err
 	}
 
-	// 更新黑名单
-	for _, word := range wordDB.Blacklist {
-		f.automaton.AddWord(word.Word, word.Categories, word.Level)
+	start := time.Now()
+	state, cached, err := f.buildState(wordDB, false)
+	if err != nil {
+		return err
+	}
+	if canary && f.canaryEligible(wordDB) {
+		f.logger.Infof("Word database built, version: %s, words: %d, build time: %v, from cache: %v",
+			wordDB.Version, state.wordCount, time.Since(start), cached)
+		f.startCanary(state)
+		return nil
 	}
 
-	// 更新分类敏感词
-	for _, words := range wordDB.Categories {
-		for _, word := range words {
-			f.automaton.AddWord(word.Word, word.Categories, word.Level)
+	f.discardCanary(wordDB.Version)
+	previous := f.state.Load().wordDB
+	old := f.reloadHookStats()
+	f.swapState(state)
+	f.scheduleWordChange(state)
+
+	f.logger.Infof("Word database updated successfully, version: %s, words: %d, build time: %v, from cache: %v",
+		wordDB.Version, state.wordCount, time.Since(start), cached)
+	f.recordDiff(previous, wordDB)
+	f.runReloadHooks(old)
+
+	return nil
+}
+
+// buildState 从词库构建新快照，只包含当前生效的敏感词
+// dryRun为true时只用于预览，不读写自动机磁盘缓存，也不报告构建进度
+func (f *ContentFilter) buildState(wordDB *types.WordDatabase, dryRun bool) (*wordState, bool, error) {
+	// 收集黑名单和分类敏感词，跳过当前不在生效时段内的
+	now := time.Now()
+	sensitiveWords := wordDB.Words()
+	words := make([]algorithm.WordEntry, 0, len(sensitiveWords))
+	wholeWords := make([]string, 0)
+	monitorWords := make([]string, 0)
+	exclusions := make(map[string][]string)
+	weights := make(map[string]float64)
+	scheduled := false
+	for _, word := range sensitiveWords {
+		if word.Scheduled() {
+			scheduled = true
+			if !word.ActiveAt(now) {
+				continue
+			}
+		}
+		words = append(words, algorithm.WordEntry{Word: f.normalizer.Normalize(word.Word), Categories: word.Categories, Level: word.Level, Pinyin: word.Pinyin, Fuzzy: word.Fuzzy})
+		if word.WholeWord {
+			wholeWords = append(wholeWords, word.Word)
+		}
+		if len(word.Exclusions) > 0 {
+			exclusions[word.Word] = append(exclusions[word.Word], word.Exclusions...)
+		}
+		if word.Weight > 0 {
+			weights[word.Word] = word.Weight
+		}
+		if word.Monitor {
+			monitorWords = append(monitorWords, word.Word)
 		}
 	}
 
-	// 构建AC自动机
-	f.automaton.BuildFailPointers()
-	f.automaton.SetVersion(wordDB.Version)
+	// 构建AC自动机，磁盘缓存中有同一版本的编译结果时直接加载
+	// 有定时生效的词语时同一版本的生效词语会随时间变化，不使用磁盘缓存
+	var automaton algorithm.Automaton
+	cached := false
+	diskCache := !scheduled && !dryRun
+	if diskCache {
+		automaton, cached = f.loadAutomatonCache(wordDB, len(words))
+	}
+	if !cached {
+		options := &algorithm.BuildOptions{
+			MemoryBudget:  int64(f.config.BuildMemoryBudgetMB) << 20,
+			ProgressEvery: buildProgressEvery,
+		}
+		if !dryRun {
+			options.OnProgress = f.reportBuildProgress
+		}
+
+		var err error
+		automaton, err = f.buildAutomaton(words, wordDB.Version, options)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to build automaton for version %s: %w", wordDB.Version, err)
+		}
+		if diskCache {
+			f.saveAutomatonCache(automaton, wordDB, len(words))
+		}
+	}
+
+	whitelist := f.newWhitelist(wordDB.Whitelist)
+	state := &wordState{
+		automaton:    automaton,
+		firstChars:   automaton.FirstChars(),
+		maxMatchLen:  automaton.MaxMatchLen(),
+		whitelist:    whitelist,
+		allow:        f.newAllowList(whitelist),
+		replacements: f.normalizeReplacements(wordDB.Replacements),
+		wholeWords:   f.newWordSet(wholeWords),
+		exclusions:   f.newExclusions(exclusions),
+		weights:      f.newWeights(weights),
+		monitorWords: f.newWordSet(monitorWords),
+		wordDB:       wordDB,
+		version:      wordDB.Version,
+		lastUpdate:   wordDB.UpdateTime,
+		wordCount:    len(words),
+		loadedAt:     time.Now(),
+		nextChange:   types.NextScheduleChange(sensitiveWords, now),
+		lastChange:   types.LastScheduleChange(sensitiveWords, now),
+		whitelistGen: whitelistFingerprint(whitelist),
+		hits:         &wordHits{},
+	}
+	return state, cached, nil
+}
 
-	// 更新版本和时间
-	f.version = wordDB.Version
-	f.lastUpdate = wordDB.UpdateTime
+// normalizeReplacements 按文本标准化规则转换替换词的键，使其与自动机中的敏感词一致
+func (f *ContentFilter) normalizeReplacements(replacements map[string]string) map[string]string {
+	normalized := make(map[string]string, len(replacements))
+	for word, replacement := range replacements {
+		normalized[f.normalizer.Normalize(word)] = replacement
+	}
+	return normalized
+}
+
+// swapState 原子替换正在服务的词库快照并清空缓存，进行中的过滤继续使用旧快照
+func (f *ContentFilter) swapState(state *wordState) {
+	f.state.Store(state)
+
+	f.mu.Lock()
+	f.reloadErr = nil
+	f.mu.Unlock()
 
 	// 清空缓存
-	if f.cache != nil {
-		f.cache.Clear()
+	f.clearCache()
+}
+
+// reportBuildProgress 记录并输出构建进度
+func (f *ContentFilter) reportBuildProgress(progress algorithm.BuildProgress) {
+	f.progressMu.Lock()
+	f.progress = progress
+	f.progressMu.Unlock()
+
+	if progress.Total >= buildProgressEvery {
+		f.logger.Infof("Building automaton: phase=%s, words=%d/%d, nodes=%d, estimated memory=%dMB",
+			progress.Phase, progress.Done, progress.Total, progress.Nodes, progress.EstimatedBytes>>20)
 	}
+}
+
+// enqueueUpdate 将词库更新交给后台构建协程，未处理的旧更新会被新的更新覆盖
+func (f *ContentFilter) enqueueUpdate(wordDB *types.WordDatabase) {
+	for {
+		select {
+		case f.updateChan <- wordDB:
+			return
+		default:
+		}
 
-	f.logger.Infof("Word database updated successfully, version: %s, words: %d", 
-		wordDB.Version, f.automaton.GetNodeCount())
+		// 丢弃尚未开始构建的旧版本
+		select {
+		case stale := <-f.updateChan:
+			f.logger.Infof("Skipping superseded word database version: %s", stale.Version)
+		default:
+		}
+	}
+}
+
+// startUpdateWorker 启动后台构建协程
+func (f *ContentFilter) startUpdateWorker() {
+	go func() {
+		for {
+			select {
+			case wordDB := <-f.updateChan:
+				err := f.updateWordDatabase(wordDB, true)
+				if err != nil {
+					f.logger.Errorf("Failed to update word database: %v", err)
+				} else {
+					f.saveServingSnapshot(wordDB)
+				}
+				f.setReloadResult(err)
+			case <-f.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// loadArtifact 从预编译产物加载词库，与配置源的词库一样校验签名、检查词库并拒绝版本回退
+func (f *ContentFilter) loadArtifact() error {
+	a, err := f.loadArtifactFile(f.config.ArtifactPath)
+	if err != nil {
+		return fmt.Errorf("failed to load word list artifact: %w", err)
+	}
+	if err := a.Verify(f.publicKey); err != nil {
+		return fmt.Errorf("rejected word list artifact: %w: %w", types.ErrInvalidWordDB, err)
+	}
+	// 自动机中的敏感词按编译时的规则标准化，与本实例的规则不同时无法命中
+	if signature := f.normalizer.Signature(); a.Metadata.Normalize != signature {
+		return fmt.Errorf("%w: compiled with normalize rules %q, filter uses %q, recompile the artifact with the same filter_config.normalize",
+			artifact.ErrInvalidArtifact, a.Metadata.Normalize, signature)
+	}
+
+	f.buildMu.Lock()
+	defer f.buildMu.Unlock()
+
+	current := f.state.Load()
+	if a.Metadata.Version == current.version && a.Metadata.Version != "" {
+		refreshed := *current
+		refreshed.loadedAt = time.Now()
+		f.state.Store(&refreshed)
+		return nil
+	}
+	if !f.config.AllowVersionRollback && staleVersion(a.Metadata.Version, current.version) {
+		return fmt.Errorf("%w: artifact version %s is older than loaded version %s", ErrStaleVersion, a.Metadata.Version, current.version)
+	}
+
+	// 产物默认不保存完整的敏感词，只检查元数据中记录的内容
+	wordDB := a.Metadata.WordDatabase()
+	if err := f.lintWordDatabase(wordDB); err != nil {
+		return err
+	}
+
+	whitelist := f.newWhitelist(a.Metadata.Whitelist)
+	old := f.reloadHookStats()
+	f.swapState(&wordState{
+		automaton:    a.Automaton,
+		firstChars:   a.Automaton.FirstChars(),
+		maxMatchLen:  a.Automaton.MaxMatchLen(),
+		whitelist:    whitelist,
+		allow:        f.newAllowList(whitelist),
+		replacements: f.normalizeReplacements(a.Metadata.Replacements),
+		wholeWords:   f.newWordSet(a.Metadata.WholeWords),
+		exclusions:   f.newExclusions(a.Metadata.Exclusions),
+		weights:      f.newWeights(a.Metadata.Weights),
+		monitorWords: f.newWordSet(a.Metadata.Monitor),
+		wordDB:       wordDB,
+		version:      a.Metadata.Version,
+		lastUpdate:   a.Metadata.UpdateTime,
+		wordCount:    a.Metadata.WordCount,
+		loadedAt:     time.Now(),
+		whitelistGen: whitelistFingerprint(whitelist),
+		hits:         &wordHits{},
+	})
+
+	f.logger.Infof("Word list artifact loaded successfully, version: %s, words: %d, checksum: %s",
+		a.Metadata.Version, a.Metadata.WordCount, a.Metadata.Checksum)
+	f.runReloadHooks(old)
 
 	return nil
 }
 
-// startConfigListener 启动配置监听
+// startConfigListener 启动配置监听，每个词库分别监听
 func (f *ContentFilter) startConfigListener() error {
-	return f.nacosClient.ListenConfig(f.config.DataId, f.config.Group, func(content string) {
-		f.logger.Info("Received config change notification")
-		
-		// 解析新的词库配置
-		var wordDB types.WordDatabase
-		if err := json.Unmarshal([]byte(content), &wordDB); err != nil {
-			f.logger.Errorf("Failed to unmarshal word database: %v", err)
-			return
+	for i, set := range f.wordDataSets() {
+		i, set := i, set
+		err := f.source.ListenConfig(set.DataId, set.Group, func(content string) {
+			f.logger.Infof("Received config change notification for %s/%s", set.Group, set.DataId)
+
+			// 合并后交给后台构建，避免阻塞配置回调
+			if err := f.applyWordDataChange(i, content); err != nil {
+				f.logger.Errorf("Failed to apply word database change: %v", err)
+			}
+		})
+		if err != nil {
+			return fmt.Errorf("failed to listen word database %s/%s: %w", set.Group, set.DataId, err)
 		}
+	}
+	return nil
+}
+
+// startInvalidationBus 启动集群缓存失效广播
+func (f *ContentFilter) startInvalidationBus() error {
+	if f.config.InvalidationDataId == "" {
+		return nil
+	}
 
-		// 更新词库
-		if err := f.updateWordDatabase(&wordDB); err != nil {
-			f.logger.Errorf("Failed to update word database: %v", err)
+	f.bus = bus.NewConfigBus(f.source, f.config.InvalidationDataId, f.config.Group, f.instanceId, f.logger)
+	return f.bus.Subscribe(f.handleBusEvent)
+}
+
+// handleBusEvent 处理其他实例广播的事件
+func (f *ContentFilter) handleBusEvent(event *bus.Event) {
+	f.logger.WithField(trace.FieldTraceID, event.TraceID).Infof("Received %s event from %s, version: %s, reason: %s",
+		event.Type, event.Source, event.Version, event.Reason)
+
+	switch event.Type {
+	case bus.EventInvalidate:
+		f.clearCache()
+	case bus.EventReload:
+		if err := f.loadWordDatabase(); err != nil {
+			f.logger.Errorf("Failed to reload word database on bus event: %v", err)
 		}
-	})
+	default:
+		f.logger.Warnf("Unknown bus event type: %s", event.Type)
+	}
+}
+
+// broadcast 向集群广播事件
+func (f *ContentFilter) broadcast(ctx context.Context, eventType bus.EventType, reason string) {
+	if f.bus == nil {
+		return
+	}
+
+	event := bus.NewEvent(eventType, f.instanceId, f.state.Load().version, reason)
+	event.TraceID = trace.TraceID(ctx)
+	if err := f.bus.Publish(event); err != nil {
+		trace.Entry(ctx, f.logger).Errorf("Failed to broadcast %s event: %v", eventType, err)
+	}
+}
+
+// BroadcastReload 通知集群内所有实例从词库源重新加载词库
+func (f *ContentFilter) BroadcastReload(ctx context.Context, reason string) error {
+	if err := f.loadWordDatabase(); err != nil {
+		return err
+	}
+
+	f.broadcast(ctx, bus.EventReload, reason)
+	return nil
 }
 
 // startPeriodicReload 启动定期重载
@@ -158,47 +618,151 @@ func (f *ContentFilter) startPeriodicReload() {
 	}()
 }
 
-// Filter 过滤内容
+// Filter 过滤内容，options为nil时使用默认选项
 func (f *ContentFilter) Filter(text string, options *types.FilterOptions) *types.FilterResult {
-	// 检查缓存
-	if f.cache != nil {
-		cacheKey := f.generateCacheKey(text, options)
-		if result, found := f.cache.Get(cacheKey); found {
-			return result
+	// 不可取消的上下文不会返回错误
+	result, _ := f.FilterContext(context.Background(), text, options)
+	return result
+}
+
+// FilterContext 过滤内容，上下文取消或超时时返回上下文错误，上下文中的追踪ID会写入相关日志
+func (f *ContentFilter) FilterContext(ctx context.Context, text string, options *types.FilterOptions) (*types.FilterResult, error) {
+	result, _, err := f.audited(ctx, text, options)
+	return result, err
+}
+
+// FilterStrict 过滤内容，结果为降级或兜底结果时同时返回 *types.FilterError，文本超长被拒绝时同时返回 types.ErrTextTooLong
+// 调用方可据此区分"内容正常"和"过滤没有正常完成"；上下文取消或超时时只返回上下文错误
+func (f *ContentFilter) FilterStrict(ctx context.Context, text string, options *types.FilterOptions) (*types.FilterResult, error) {
+	result, cause, err := f.audited(ctx, text, options)
+	if err != nil {
+		return nil, err
+	}
+	if result.Degraded {
+		return result, &types.FilterError{Reason: result.DegradedReason, Err: cause}
+	}
+	return result, result.Err()
+}
+
+// audited 过滤内容，记录检查统计、审计日志和抽样复核，需要人工复核的结果加入复核队列
+func (f *ContentFilter) audited(ctx context.Context, text string, options *types.FilterOptions) (result *types.FilterResult, cause error, err error) {
+	start := time.Now()
+	result, cause, err = f.filterContext(ctx, text, options)
+	if err == nil {
+		result = f.trackOffender(ctx, result, options)
+		f.metrics.record(result, time.Since(start))
+		f.recordAnomaly(result)
+		f.recordAudit(ctx, text, result)
+		f.recordSample(ctx, text, result)
+		f.submitReview(ctx, text, result)
+		f.notifyViolation(ctx, text, result, options)
+		f.runMatchHooks(ctx, text, result)
+	}
+	return result, cause, err
+}
+
+// filterContext 过滤内容，cause为结果降级的原因，未降级时为nil；err只在上下文取消或超时时返回
+func (f *ContentFilter) filterContext(ctx context.Context, text string, options *types.FilterOptions) (result *types.FilterResult, cause error, err error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	options = f.resolveOptions(options)
+
+	// 降级状态下不读写缓存，结果带上降级原因并按异常处理策略放行或拒绝
+	if reason, cause := f.degradedReason(); reason != "" {
+		result, err := f.safeFilter(ctx, text, options)
+		if err != nil {
+			result, ctxErr := f.handleFilterError(ctx, err, options)
+			return result, err, ctxErr
 		}
+		trace.Entry(ctx, f.logger).Debugf("Serving degraded result, reason: %s", reason)
+		f.recordMonitored(ctx, result)
+		f.recordHits(result)
+		return f.applyFailurePolicy(result, reason, options), cause, nil
 	}
 
-	// 执行过滤
-	result := f.doFilter(text, options)
+	// 检查缓存，缓存中的结果与返回给调用方的结果互不共享，调用方修改结果不影响其他调用方
+	var cacheKey string
+	var store *resultCache
+	if f.cacheable(text) {
+		store = f.cache.Load()
+	} else if f.cache.Load() != nil {
+		f.cacheSkipped.Add(1)
+	}
+	if store != nil {
+		cacheKey = f.generateCacheKey(f.servingState(text), text, options, store.shared)
+		if result, found := store.Get(ctx, cacheKey); found {
+			f.cacheHits.Add(1)
+			f.recordMonitored(ctx, result)
+			f.recordHits(result)
+			return result.Clone(), nil, nil
+		}
+		f.cacheMisses.Add(1)
+	}
 
-	// 缓存结果
-	if f.cache != nil {
-		cacheKey := f.generateCacheKey(text, options)
-		f.cache.Set(cacheKey, result)
+	// 执行过滤，启用缓存时相同文本的并发检查合并为一次
+	if store != nil {
+		result, err = f.filterOnce(ctx, store, cacheKey, text, options)
+	} else {
+		result, err = f.filterAndCache(ctx, nil, "", text, options)
+	}
+	if err != nil {
+		result, ctxErr := f.handleFilterError(ctx, err, options)
+		return result, err, ctxErr
 	}
 
-	return result
+	f.recordMonitored(ctx, result)
+	f.recordHits(result)
+	return result, nil, nil
 }
 
-// doFilter 执行过滤逻辑
-func (f *ContentFilter) doFilter(text string, options *types.FilterOptions) *types.FilterResult {
-	f.mu.RLock()
-	defer f.mu.RUnlock()
-
-	// 检查白名单
-	if options != nil && options.EnableWhitelist && f.config.EnableWhitelist {
-		if f.isInWhitelist(text) {
-			return &types.FilterResult{
-				Passed:     true,
-				Categories: []string{},
-				Words:      []string{},
-				Details:    map[string]string{"reason": "whitelist"},
-			}
-		}
+// filterAndCache 执行过滤、分类模型和外部审核，store不为nil时缓存结果
+func (f *ContentFilter) filterAndCache(ctx context.Context, store *resultCache, cacheKey, text string, options *types.FilterOptions) (*types.FilterResult, error) {
+	result, err := f.safeFilter(ctx, text, options)
+	if err != nil {
+		return nil, err
+	}
+	f.classify(ctx, text, result, options)
+	f.escalate(ctx, text, result, options)
+	if !result.Passed {
+		trace.Entry(ctx, f.logger).Debugf("Content blocked, words: %v, categories: %v", result.Words, result.Categories)
+	}
+
+	// 缓存结果，分类模型或外部审核接口不可用时不缓存，以便之后重试
+	if store != nil && result.Details["classifier"] != "unavailable" && result.Details["escalation"] != "unavailable" {
+		store.Set(ctx, cacheKey, result.Clone())
+	}
+	return result, nil
+}
+
+// degradedReason 返回当前的降级原因和对应的错误，正常时返回空字符串
+// 只取决于正在服务的词库（为空或超过 max_staleness 未刷新）；被拒绝的重载不影响正在服务的词库，通过统计和告警报告
+func (f *ContentFilter) degradedReason() (string, error) {
+	state := f.state.Load()
+
+	switch {
+	case state.wordCount == 0:
+		return types.DegradedEmptyDictionary, types.ErrEmptyDictionary
+	case f.config.MaxStaleness > 0 && time.Since(state.loadedAt) > f.config.MaxStaleness:
+		return types.DegradedStaleDictionary, fmt.Errorf("word database not refreshed since %s", state.loadedAt.Format(time.RFC3339))
+	default:
+		return "", nil
 	}
+}
+
+// doFilter 执行过滤逻辑，有灰度中的词库时按配置分流
+func (f *ContentFilter) doFilter(ctx context.Context, text string, options *types.FilterOptions) (*types.FilterResult, error) {
+	return f.canaryFilter(ctx, text, options)
+}
 
-	// 标准化文本
-	normalizedText := algorithm.NormalizeText(text)
+// filterState 使用指定的词库快照过滤
+// 整个过滤过程使用同一份快照，重载不会阻塞或影响进行中的过滤
+func (f *ContentFilter) filterState(ctx context.Context, state *wordState, text string, options *types.FilterOptions) (*types.FilterResult, error) {
+	flags := f.flags.Load()
+
+	// 按输入格式提取纯文本并标准化
+	content := markup.Extract(text, options.Format)
+	normalized := f.normalizer.NormalizeText(content.String())
 
 	// 构建搜索选项
 	searchOptions := &algorithm.SearchOptions{
@@ -206,60 +770,204 @@ func (f *ContentFilter) doFilter(text string, options *types.FilterOptions) *typ
 		MinLevel:   options.MinLevel,
 	}
 
-	// 搜索敏感词
-	outputs := f.automaton.SearchWithOptions(normalizedText, searchOptions)
+	// 标准化时去除的不可见字符，大量使用本身就是垃圾内容的信号，无论是否命中都写入结果
+	invisible := invisiblePositions(content, normalized)
+
+	// 检测联系方式、运行自定义匹配器，结果排在敏感词命中之后一起处理
+	contacts := f.contact.Find(normalized.String(), searchOptions)
+	custom := f.customMatches(normalized.String(), searchOptions)
+
+	// 快速路径：文本不含任何敏感词的首字符时不可能命中，跳过自动机遍历
+	if len(contacts) == 0 && len(custom) == 0 && !state.firstChars.MayMatch(normalized.String()) {
+		return withInvisible(&types.FilterResult{
+			Passed:     true,
+			Categories: []string{},
+			Words:      []string{},
+			Details:    map[string]string{},
+			Action:     types.ActionPass,
+			ReasonCode: types.ReasonClean,
+		}, invisible), nil
+	}
+
+	// 搜索敏感词，超长文本按配置的策略抽样扫描
+	outputs, scan, err := f.scan(ctx, state, normalized.String(), searchOptions)
+	if err != nil {
+		return nil, err
+	}
+	outputs = append(outputs, contacts...)
+	outputs = append(outputs, custom...)
+	allowTerms := f.allowTerms(options.AllowTerms)
+
+	// 白名单只豁免落在白名单短语内的命中，文本中其余命中照常处理
+	var allowed []algorithm.Match
+	if options.EnableWhitelist && f.config.EnableWhitelist && len(outputs) > 0 {
+		allowed = state.allow.spans(normalized.String())
+	}
+	whitelisted := false
 
 	if len(outputs) == 0 {
-		return &types.FilterResult{
+		return withInvisible(scan.apply(&types.FilterResult{
 			Passed:     true,
 			Categories: []string{},
 			Words:      []string{},
 			Details:    map[string]string{},
-		}
+			Action:     types.ActionPass,
+			ReasonCode: types.ReasonClean,
+		}), invisible), nil
 	}
 
 	// 收集结果
 	categories := make([]string, 0)
 	words := make([]string, 0)
 	details := make(map[string]string)
+	spans := make([]replace.Span, 0, len(outputs))
+	needsReview := false
+	riskScore := 0.0
+	maxLevel := 0
+	totalMatches := 0
+	matches := make([]types.MatchDetail, 0)
+	matchIndex := make(map[string]int)
+	categoryCounts := make(map[string]int)
+	var monitored []types.MatchDetail
+	monitorIndex := make(map[string]int)
 
 	for _, output := range outputs {
+		// 整词匹配的敏感词要求前后不能紧邻字母，如 "ass" 不命中 "classic"
+		if f.wholeWordOnly(state, output.Output) && !algorithm.IsWholeWord(normalized.String(), output.Start, output.End) {
+			continue
+		}
+
+		// 命中落在排除语境或本次请求的放行词内时忽略，如 "禁止出售" 中的 "出售"
+		if isExcluded(state, normalized.String(), output) || isAllowed(allowTerms, normalized.String(), output) {
+			continue
+		}
+		if covers(allowed, output) {
+			whitelisted = true
+			continue
+		}
+
+		// 应用分类开关，所属分类全部被关闭的匹配直接忽略
+		outputCategories, monitoredCategories, review := f.filterCategories(flags, output.Categories)
+		if len(output.Categories) > 0 && len(outputCategories) == 0 && len(monitoredCategories) == 0 {
+			continue
+		}
+
+		// 只观察的敏感词，或所属分类全部只观察：只记录命中，不影响结果
+		pieces := content.OriginalSpans(normalized.OriginalSpan(output.Start, output.End))
+		position := types.Position{Start: pieces[0].Start, End: pieces[len(pieces)-1].End}
+		if state.monitorWords[output.Word] || (len(output.Categories) > 0 && len(outputCategories) == 0) {
+			addMatchDetail(&monitored, monitorIndex, output, append(outputCategories, monitoredCategories...), position)
+			continue
+		}
+		needsReview = needsReview || review
+
+		maxLevel = max(maxLevel, output.Level)
+		totalMatches++
 		words = append(words, output.Word)
-		categories = append(categories, output.Categories...)
-		details[output.Word] = fmt.Sprintf("level:%d,categories:%s", 
-			output.Level, strings.Join(output.Categories, ","))
+		categories = append(categories, outputCategories...)
+		for _, category := range outputCategories {
+			categoryCounts[category]++
+		}
+		if f.config.LegacyDetails {
+			details[output.Word] = fmt.Sprintf("level:%d,categories:%s",
+				output.Level, strings.Join(outputCategories, ","))
+		}
+
+		// 按敏感词合并命中详情，同一敏感词多次命中只计一次风险分
+		if addMatchDetail(&matches, matchIndex, output, outputCategories, position) {
+			riskScore += wordWeight(state, output.Output)
+		}
+
+		// 命中跨越标记时只对文本片段打码，保留标记本身；此时不使用替换词
+		if len(pieces) > 1 {
+			for _, piece := range pieces {
+				spans = append(spans, replace.Span{Start: piece.Start, End: piece.End})
+			}
+			continue
+		}
+		spans = append(spans, replace.Span{
+			Start:       position.Start,
+			End:         position.End,
+			Replacement: state.replacements[output.Word],
+		})
+	}
+
+	if len(words) == 0 {
+		result := &types.FilterResult{
+			Passed:     true,
+			Categories: []string{},
+			Words:      []string{},
+			Details:    map[string]string{},
+			Action:     types.ActionPass,
+			ReasonCode: types.ReasonClean,
+			Monitored:  sortMatchPositions(monitored),
+		}
+		// 全部命中均被白名单豁免
+		if whitelisted {
+			result.Details["reason"] = "whitelist"
+			result.ReasonCode = types.ReasonWhitelist
+		}
+		return withInvisible(scan.apply(result), invisible), nil
 	}
 
 	// 去重
 	categories = f.removeDuplicates(categories)
 	words = f.removeDuplicates(words)
 
-	return &types.FilterResult{
-		Passed:     false,
-		Categories: categories,
-		Words:      words,
-		Details:    details,
+	result := &types.FilterResult{
+		Passed:         false,
+		Categories:     categories,
+		Words:          words,
+		Details:        details,
+		NeedsReview:    needsReview,
+		RiskScore:      riskScore,
+		Matches:        sortMatchPositions(matches),
+		MaxLevel:       maxLevel,
+		TotalMatches:   totalMatches,
+		CategoryCounts: categoryCounts,
+		Monitored:      sortMatchPositions(monitored),
 	}
-}
 
-// isInWhitelist 检查是否在白名单中
-func (f *ContentFilter) isInWhitelist(text string) bool {
-	normalizedText := strings.ToLower(algorithm.NormalizeText(text))
-	
-	// 检查完整文本
-	if f.whitelist[normalizedText] {
-		return true
+	// 按风险分阈值决定放行、转人工审核或拒绝，配置了处理策略时以策略为准
+	f.decide(result, options)
+
+	// 替换模式或打码动作：按字素簇打码，避免截断emoji和组合字符
+	if options.ReplaceMode || result.Action == types.ActionMask {
+		result.FilteredText = replace.Mask(text, spans, options.ReplaceChar)
 	}
 
-	// 检查文本片段
-	words := strings.Fields(normalizedText)
-	for _, word := range words {
-		if f.whitelist[word] {
-			return true
-		}
+	return withInvisible(scan.apply(result), invisible), nil
+}
+
+// addMatchDetail 按敏感词合并命中详情，返回是否为该敏感词的首次命中
+func addMatchDetail(matches *[]types.MatchDetail, index map[string]int, output algorithm.Match, categories []string, position types.Position) bool {
+	if i, ok := index[output.Word]; ok {
+		match := &(*matches)[i]
+		match.Count++
+		match.Positions = append(match.Positions, position)
+		match.Level = max(match.Level, output.Level)
+		return false
 	}
 
-	return false
+	index[output.Word] = len(*matches)
+	*matches = append(*matches, types.MatchDetail{
+		Word:       output.Word,
+		Categories: categories,
+		Level:      output.Level,
+		Positions:  []types.Position{position},
+		Count:      1,
+	})
+	return true
+}
+
+// sortMatchPositions 将每个命中详情的位置按出现顺序排列，拼音和编辑距离命中在精确命中之后才追加
+func sortMatchPositions(matches []types.MatchDetail) []types.MatchDetail {
+	for _, match := range matches {
+		sort.Slice(match.Positions, func(i, j int) bool {
+			return match.Positions[i].Start < match.Positions[j].Start
+		})
+	}
+	return matches
 }
 
 // removeDuplicates 去重
@@ -277,81 +985,153 @@ func (f *ContentFilter) removeDuplicates(slice []string) []string {
 	return result
 }
 
-// generateCacheKey 生成缓存键
-func (f *ContentFilter) generateCacheKey(text string, options *types.FilterOptions) string {
-	var optionsStr string
-	if options != nil {
-		optionsStr = fmt.Sprintf("%v", options)
+// GetStats 获取统计信息
+func (f *ContentFilter) GetStats() *types.Stats {
+	state := f.state.Load()
+
+	stats := &types.Stats{
+		Version:        state.version,
+		LastUpdate:     state.lastUpdate,
+		LoadedAt:       state.loadedAt,
+		NodeCount:      state.automaton.GetNodeCount(),
+		WordCount:      state.wordCount,
+		WhitelistSize:  len(state.whitelist),
+		LastReloadDiff: f.lastDiffStats(),
+		Lint:           f.lintStats(),
+		Canary:         f.CanaryStatus(),
+		WordHits:       f.hitStats(),
+		Audit:          f.auditStats(),
+		Sampling:       f.samplingStats(),
+		Review:         f.reviewStats(),
+		Anomaly:        f.anomalyStats(),
+		Escalation:     f.escalationStats(),
+		Webhooks:       f.webhookStats(),
+		CategoryFlags:  f.flags.Load(),
 	}
-	
-	key := fmt.Sprintf("%s:%s", text, optionsStr)
-	hash := md5.Sum([]byte(key))
-	return fmt.Sprintf("%x", hash)
-}
+	f.metrics.fill(stats)
 
-// GetStats 获取统计信息
-func (f *ContentFilter) GetStats() map[string]interface{} {
 	f.mu.RLock()
-	defer f.mu.RUnlock()
+	if f.reloadErr != nil {
+		stats.ReloadError = f.reloadErr.Error()
+	}
+	f.mu.RUnlock()
+	stats.ReloadFailures = f.reloadFailures()
 
-	stats := map[string]interface{}{
-		"version":        f.version,
-		"last_update":    f.lastUpdate,
-		"node_count":     f.automaton.GetNodeCount(),
-		"whitelist_size": len(f.whitelist),
+	if monitor := f.monitorStats(); len(monitor) > 0 {
+		stats.MonitorHits = monitor
 	}
 
-	if f.cache != nil {
-		stats["cache_stats"] = f.cache.Stats()
+	f.progressMu.Lock()
+	stats.BuildProgress = types.BuildStats(f.progress)
+	f.progressMu.Unlock()
+
+	if c := f.cache.Load(); c != nil {
+		stats.CacheSummary = f.cacheStats(c)
+		stats.Cache = c.Stats()
 	}
+	stats.Coalesced = f.coalesced.Load()
 
 	return stats
 }
 
 // UpdateWordDatabase 手动更新词库
 func (f *ContentFilter) UpdateWordDatabase(wordDB *types.WordDatabase) error {
-	return f.updateWordDatabase(wordDB)
+	if err := f.updateWordDatabase(wordDB, false); err != nil {
+		return err
+	}
+	f.saveSnapshot(wordDB)
+
+	// 通知其他实例丢弃旧缓存
+	f.broadcast(context.Background(), bus.EventInvalidate, "manual word database update")
+	return nil
 }
 
-// AddToWhitelist 添加到白名单
-func (f *ContentFilter) AddToWhitelist(word string) {
-	f.mu.Lock()
-	defer f.mu.Unlock()
-	f.whitelist[strings.ToLower(word)] = true
+// AddToWhitelist 添加到白名单，立即在本实例生效；开启 persist_whitelist 时写回词库源，写回失败返回错误
+func (f *ContentFilter) AddToWhitelist(word string) error {
+	f.updateWhitelist(func(whitelist map[string]bool) {
+		if key := f.whitelistKey(word); key != "" {
+			whitelist[key] = true
+		}
+	})
+	return f.persistWhitelist(f.addWhitelistEntry(word))
 }
 
-// RemoveFromWhitelist 从白名单移除
-func (f *ContentFilter) RemoveFromWhitelist(word string) {
-	f.mu.Lock()
-	defer f.mu.Unlock()
-	delete(f.whitelist, strings.ToLower(word))
+// RemoveFromWhitelist 从白名单移除，立即在本实例生效；开启 persist_whitelist 时写回词库源，写回失败返回错误
+func (f *ContentFilter) RemoveFromWhitelist(word string) error {
+	f.updateWhitelist(func(whitelist map[string]bool) {
+		delete(whitelist, f.whitelistKey(word))
+	})
+	return f.persistWhitelist(f.removeWhitelistEntry(word))
 }
 
 // Close 关闭过滤器
 func (f *ContentFilter) Close() error {
 	close(f.stopChan)
-	
+
 	if f.reloadTicker != nil {
 		f.reloadTicker.Stop()
 	}
-	
-	if f.cache != nil {
-		f.cache.Close()
+
+	f.editMu.Lock()
+	if f.rebuildTimer != nil {
+		f.rebuildTimer.Stop()
+	}
+	f.editMu.Unlock()
+
+	f.buildMu.Lock()
+	if c := f.canary.Load(); c != nil {
+		c.timer.Stop()
+	}
+	if f.scheduleTimer != nil {
+		f.scheduleTimer.Stop()
 	}
-	
-	return f.nacosClient.Close()
+	f.buildMu.Unlock()
+
+	if c := f.cache.Swap(nil); c != nil {
+		c.Close()
+	}
+
+	if auditor := f.auditor.Swap(nil); auditor != nil {
+		if err := auditor.Close(); err != nil {
+			f.logger.Errorf("Failed to close audit sink: %v", err)
+		}
+	}
+	if sampler := f.sampler.Swap(nil); sampler != nil {
+		if err := sampler.Close(); err != nil {
+			f.logger.Errorf("Failed to close sampling sink: %v", err)
+		}
+	}
+	if dispatcher := f.reviews.Swap(nil); dispatcher != nil {
+		if err := dispatcher.Close(); err != nil {
+			f.logger.Errorf("Failed to close review queue: %v", err)
+		}
+	}
+	if f.offenders != nil {
+		if err := f.offenders.Close(); err != nil {
+			f.logger.Errorf("Failed to close offender store: %v", err)
+		}
+	}
+	if f.webhooks != nil {
+		f.webhooks.Close()
+	}
+
+	if f.bus != nil {
+		f.bus.Close()
+	}
+
+	return f.source.Close()
 }
 
 // HealthCheck 健康检查
 func (f *ContentFilter) HealthCheck() error {
-	// 检查Nacos连接
-	if err := f.nacosClient.HealthCheck(); err != nil {
-		return fmt.Errorf("nacos health check failed: %w", err)
+	// 检查词库配置源连接
+	if err := f.source.HealthCheck(); err != nil {
+		return fmt.Errorf("%w: word source health check failed: %w", types.ErrWordSourceUnavailable, err)
 	}
 
 	// 检查自动机状态
-	if f.automaton.GetNodeCount() == 0 {
-		return fmt.Errorf("automaton is empty")
+	if f.state.Load().wordCount == 0 {
+		return fmt.Errorf("%w: automaton is empty", types.ErrEmptyDictionary)
 	}
 
 	return nil
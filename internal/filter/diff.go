@@ -0,0 +1,62 @@
+package filter
+
+import (
+	"github.com/guardian/content-filter/internal/types"
+	"github.com/guardian/content-filter/internal/wordlist"
+)
+
+// defaultDiffHistory 默认保留的重载差异数
+const defaultDiffHistory = 10
+
+// recordDiff 计算并记录词库重载前后的差异，只保留最近 diff_history 条
+func (f *ContentFilter) recordDiff(previous, wordDB *types.WordDatabase) {
+	diff := wordlist.Diff(previous, wordDB)
+	f.logger.Infof("Word database changed %s -> %s: %d added, %d removed, %d level changes, whitelist %d added, %d removed",
+		diff.FromVersion, diff.ToVersion, len(diff.Added), len(diff.Removed), len(diff.LevelChanges),
+		len(diff.WhitelistAdded), len(diff.WhitelistRemoved))
+
+	limit := f.config.DiffHistory
+	if limit <= 0 {
+		limit = defaultDiffHistory
+	}
+
+	f.diffMu.Lock()
+	defer f.diffMu.Unlock()
+	f.diffs = append(f.diffs, diff)
+	if len(f.diffs) > limit {
+		f.diffs = append(f.diffs[:0:0], f.diffs[len(f.diffs)-limit:]...)
+	}
+}
+
+// ReloadDiffs 返回最近的词库重载差异，最新的在前
+func (f *ContentFilter) ReloadDiffs() []*types.ReloadDiff {
+	f.diffMu.Lock()
+	defer f.diffMu.Unlock()
+
+	diffs := make([]*types.ReloadDiff, len(f.diffs))
+	for i, diff := range f.diffs {
+		diffs[len(f.diffs)-1-i] = diff
+	}
+	return diffs
+}
+
+// lastDiffStats 最近一次重载差异的统计信息，没有重载记录时返回nil
+func (f *ContentFilter) lastDiffStats() *types.ReloadDiffSummary {
+	f.diffMu.Lock()
+	defer f.diffMu.Unlock()
+
+	if len(f.diffs) == 0 {
+		return nil
+	}
+	diff := f.diffs[len(f.diffs)-1]
+	return &types.ReloadDiffSummary{
+		FromVersion:      diff.FromVersion,
+		ToVersion:        diff.ToVersion,
+		Time:             diff.Time,
+		Added:            len(diff.Added),
+		Removed:          len(diff.Removed),
+		LevelChanges:     len(diff.LevelChanges),
+		WhitelistAdded:   len(diff.WhitelistAdded),
+		WhitelistRemoved: len(diff.WhitelistRemoved),
+	}
+}
@@ -0,0 +1,87 @@
+package filter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/guardian/content-filter/internal/types"
+	"github.com/guardian/content-filter/internal/wordlist"
+)
+
+// DryRun 使用候选词库检查样本文本，返回与当前词库结果不同的样本，不影响正在服务的词库、缓存和统计
+func (f *ContentFilter) DryRun(ctx context.Context, candidate *types.WordDatabase, texts []string, options *types.FilterOptions) (*types.DryRunResult, error) {
+	if candidate == nil {
+		return nil, fmt.Errorf("%w: candidate word database is nil", types.ErrEmptyDictionary)
+	}
+
+	options = f.resolveOptions(options)
+	live := f.state.Load()
+	state, _, err := f.buildState(candidate, true)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &types.DryRunResult{
+		LiveVersion:      live.version,
+		CandidateVersion: candidate.Version,
+		Diff:             wordlist.Diff(live.wordDB, candidate),
+		Lint:             wordlist.LintWithConfig(candidate, &f.config.Lint),
+		Texts:            len(texts),
+		Changes:          []types.DryRunChange{},
+	}
+
+	for i, text := range texts {
+		before, err := f.filterState(ctx, live, text, options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check sample %d with live word database: %w", i, err)
+		}
+		after, err := f.filterState(ctx, state, text, options)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check sample %d with candidate word database: %w", i, err)
+		}
+
+		added, removed := wordChanges(before.Words, after.Words)
+		if before.Passed == after.Passed && before.Action == after.Action && len(added) == 0 && len(removed) == 0 {
+			continue
+		}
+
+		result.Changed++
+		switch {
+		case before.Passed && !after.Passed:
+			result.NewlyBlocked++
+		case !before.Passed && after.Passed:
+			result.NewlyPassed++
+		}
+		result.Changes = append(result.Changes, types.DryRunChange{
+			Index:           i,
+			Text:            text,
+			LivePassed:      before.Passed,
+			CandidatePassed: after.Passed,
+			LiveAction:      before.Action,
+			CandidateAction: after.Action,
+			AddedWords:      added,
+			RemovedWords:    removed,
+		})
+	}
+
+	return result, nil
+}
+
+// wordChanges 返回只在after中和只在before中的敏感词，按词语排序
+func wordChanges(before, after []string) (added, removed []string) {
+	added, removed = []string{}, []string{}
+	for _, word := range after {
+		if !containsString(before, word) {
+			added = append(added, word)
+		}
+	}
+	for _, word := range before {
+		if !containsString(after, word) {
+			removed = append(removed, word)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
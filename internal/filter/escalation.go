@@ -0,0 +1,65 @@
+package filter
+
+import (
+	"context"
+	"errors"
+
+	"github.com/guardian/content-filter/internal/escalation"
+	"github.com/guardian/content-filter/internal/trace"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// startEscalation 按配置创建外部审核接口客户端
+func (f *ContentFilter) startEscalation() {
+	if f.config.Escalation.Enabled {
+		f.escalation = escalation.New(&f.config.Escalation)
+	}
+}
+
+// escalate 风险分处于临界区间时调用外部审核接口，判定违规则拒绝，判定正常则放行
+// 只处理按风险分或默认规则得出的结果，强制人审的分类和处理策略的决定不受影响；调用失败或熔断时保留本地结果
+func (f *ContentFilter) escalate(ctx context.Context, text string, result *types.FilterResult, options *types.FilterOptions) {
+	if f.escalation == nil {
+		return
+	}
+	switch result.ReasonCode {
+	case types.ReasonMatched, types.ReasonRiskPass, types.ReasonRiskReview, types.ReasonRiskReject:
+	default:
+		return
+	}
+	minScore, maxScore := f.config.Escalation.ScoreRange(f.config.RiskThresholds)
+	if result.RiskScore < minScore || (maxScore > 0 && result.RiskScore >= maxScore) {
+		return
+	}
+
+	verdict, err := f.escalation.Moderate(ctx, text)
+	if err != nil {
+		if !errors.Is(err, escalation.ErrCircuitOpen) {
+			trace.Entry(ctx, f.logger).Warnf("External moderation failed, keeping local result: %v", err)
+		}
+		result.Details["escalation"] = "unavailable"
+		return
+	}
+
+	if verdict.Flagged {
+		result.Categories = f.removeDuplicates(append(result.Categories, verdict.Categories...))
+		applyAction(result, types.ActionReject)
+		result.ReasonCode = types.ReasonExternalReject
+		result.Message = f.messages.Message(options.Locale, result.Categories, false)
+		result.Details["escalation"] = "flagged"
+	} else {
+		applyAction(result, types.ActionPass)
+		result.ReasonCode = types.ReasonExternalPass
+		result.Message = ""
+		result.Details["escalation"] = "clean"
+	}
+	result.Reason = f.messages.Reason(options.Locale, result.ReasonCode)
+}
+
+// escalationStats 外部审核接口调用统计，未启用时返回nil
+func (f *ContentFilter) escalationStats() *types.EscalationStats {
+	if f.escalation == nil {
+		return nil
+	}
+	return f.escalation.Stats()
+}
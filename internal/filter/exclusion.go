@@ -0,0 +1,85 @@
+package filter
+
+import (
+	"strings"
+
+	"github.com/guardian/content-filter/internal/algorithm"
+)
+
+// newExclusions 构建敏感词的排除语境，敏感词和排除短语按与自动机相同的规则标准化
+func (f *ContentFilter) newExclusions(exclusions map[string][]string) map[string][]string {
+	normalized := make(map[string][]string, len(exclusions))
+	for word, phrases := range exclusions {
+		key := f.normalizer.Normalize(word)
+		for _, phrase := range phrases {
+			normalized[key] = append(normalized[key], f.normalizer.Normalize(phrase))
+		}
+	}
+	return normalized
+}
+
+// isExcluded 检查命中是否落在敏感词声明的排除短语内，如 "出售" 出现在 "禁止出售" 中时不算命中
+func isExcluded(state *wordState, text string, match algorithm.Match) bool {
+	for _, phrase := range state.exclusions[match.Word] {
+		if phraseCovers(text, phrase, match.Start, match.End) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowTerms 按与自动机相同的规则标准化请求级放行词
+func (f *ContentFilter) allowTerms(terms []string) []string {
+	if len(terms) == 0 {
+		return nil
+	}
+
+	normalized := make([]string, 0, len(terms))
+	for _, term := range terms {
+		if term = f.normalizer.Normalize(term); term != "" {
+			normalized = append(normalized, term)
+		}
+	}
+	return normalized
+}
+
+// isAllowed 检查命中是否落在请求级放行词内
+func isAllowed(terms []string, text string, match algorithm.Match) bool {
+	for _, term := range terms {
+		if phraseCovers(text, term, match.Start, match.End) {
+			return true
+		}
+	}
+	return false
+}
+
+// phraseCovers 检查文本中是否有一处短语完整覆盖 [start, end)
+func phraseCovers(text, phrase string, start, end int) bool {
+	if len(phrase) < end-start {
+		return false
+	}
+
+	// 只需在命中位置前后短语长度的范围内查找
+	from := end - len(phrase)
+	if from < 0 {
+		from = 0
+	}
+	to := start + len(phrase)
+	if to > len(text) {
+		to = len(text)
+	}
+
+	window := text[from:to]
+	for offset := 0; offset < len(window); {
+		i := strings.Index(window[offset:], phrase)
+		if i < 0 {
+			return false
+		}
+		phraseStart := from + offset + i
+		if phraseStart <= start && phraseStart+len(phrase) >= end {
+			return true
+		}
+		offset += i + 1
+	}
+	return false
+}
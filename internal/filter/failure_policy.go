@@ -0,0 +1,95 @@
+package filter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/guardian/content-filter/internal/trace"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// failClosed 是否在异常时拒绝内容
+func (f *ContentFilter) failClosed() bool {
+	return f.config.FailurePolicy == types.FailClosed
+}
+
+// applyFailurePolicy 将结果标记为降级，fail-closed 策略下原本通过的内容改为拒绝并转人工审核
+func (f *ContentFilter) applyFailurePolicy(result *types.FilterResult, reason string, options *types.FilterOptions) *types.FilterResult {
+	result.Degraded = true
+	result.DegradedReason = reason
+
+	if !f.failClosed() || !result.Passed {
+		return result
+	}
+
+	result.Passed = false
+	result.NeedsReview = true
+	result.Action = types.ActionReview
+	result.ReasonCode = types.ReasonFailClosed
+	if result.Details == nil {
+		result.Details = map[string]string{}
+	}
+	result.Details["reason"] = "fail_closed"
+
+	var locale string
+	if options != nil {
+		locale = options.Locale
+	}
+	result.Message = f.messages.Message(locale, nil, true)
+	result.Reason = f.messages.Reason(locale, types.ReasonFailClosed)
+
+	return result
+}
+
+// failureResult 过滤出错时按策略生成兜底结果
+func (f *ContentFilter) failureResult(reason string, options *types.FilterOptions) *types.FilterResult {
+	return f.applyFailurePolicy(&types.FilterResult{
+		Passed:     true,
+		Categories: []string{},
+		Words:      []string{},
+		Details:    map[string]string{},
+		Action:     types.ActionPass,
+		ReasonCode: reason,
+	}, reason, options)
+}
+
+// safeFilter 执行过滤，将过滤过程中的panic转换为错误
+func (f *ContentFilter) safeFilter(ctx context.Context, text string, options *types.FilterOptions) (result *types.FilterResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = nil
+			err = fmt.Errorf("filter panicked: %v", r)
+		}
+	}()
+
+	// 超过最大长度的文本按配置截断后检查或直接拒绝
+	text, truncated, rejected := f.limitLength(text, options)
+	if rejected != nil {
+		return rejected, nil
+	}
+
+	result, err = f.doFilter(ctx, text, options)
+	if err == nil && truncated {
+		result.Truncated = true
+	}
+	return result, err
+}
+
+// handleFilterError 处理过滤错误：上下文取消或超时原样返回，其余错误按策略返回兜底结果
+func (f *ContentFilter) handleFilterError(ctx context.Context, err error, options *types.FilterOptions) (*types.FilterResult, error) {
+	if ctx.Err() != nil {
+		trace.Entry(ctx, f.logger).Debugf("Filter aborted: %v", err)
+		return nil, err
+	}
+
+	trace.Entry(ctx, f.logger).Errorf("Filter failed, applying %s failure policy: %v", f.failurePolicy(), err)
+	return f.failureResult(types.DegradedFilterError, options), nil
+}
+
+// failurePolicy 返回生效的异常处理策略
+func (f *ContentFilter) failurePolicy() string {
+	if f.failClosed() {
+		return types.FailClosed
+	}
+	return types.FailOpen
+}
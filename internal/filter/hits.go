@@ -0,0 +1,99 @@
+package filter
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// statsTopWords 统计信息中列出的命中最多的敏感词数量
+const statsTopWords = 10
+
+// wordHits 词库快照生效以来各敏感词的命中次数
+type wordHits struct {
+	counts sync.Map // 敏感词（标准化后） -> *atomic.Int64
+}
+
+// record 累加结果中各敏感词的命中次数
+func (h *wordHits) record(result *types.FilterResult) {
+	for _, match := range result.Matches {
+		counter, _ := h.counts.LoadOrStore(match.Word, new(atomic.Int64))
+		counter.(*atomic.Int64).Add(int64(match.Count))
+	}
+}
+
+// top 按命中次数降序返回最多limit个敏感词，次数相同时按词语排序；limit不大于0时返回全部
+func (h *wordHits) top(limit int) []types.WordHit {
+	hits := []types.WordHit{}
+	h.counts.Range(func(word, counter interface{}) bool {
+		hits = append(hits, types.WordHit{Word: word.(string), Count: counter.(*atomic.Int64).Load()})
+		return true
+	})
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Count != hits[j].Count {
+			return hits[i].Count > hits[j].Count
+		}
+		return hits[i].Word < hits[j].Word
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// recordHits 将命中计入当前词库快照，缓存命中的结果同样计入
+func (f *ContentFilter) recordHits(result *types.FilterResult) {
+	if result == nil || len(result.Matches) == 0 {
+		return
+	}
+	if hits := f.state.Load().hits; hits != nil {
+		hits.record(result)
+	}
+}
+
+// WordHits 返回当前词库生效以来的敏感词命中统计，词库重载后重新计数
+// limit限制命中最多和未命中的敏感词的返回数量，不大于0时返回全部；从产物加载时无法列出未命中的敏感词
+func (f *ContentFilter) WordHits(limit int) *types.WordHitReport {
+	state := f.state.Load()
+	report := &types.WordHitReport{
+		Version: state.version,
+		Since:   state.loadedAt,
+		Top:     []types.WordHit{},
+		Unused:  []string{},
+	}
+	if state.hits == nil {
+		return report
+	}
+	report.Top = state.hits.top(limit)
+	if state.wordDB == nil {
+		return report
+	}
+
+	seen := make(map[string]bool)
+	for _, word := range state.wordDB.Words() {
+		normalized := f.normalizer.Normalize(word.Word)
+		if seen[normalized] || state.monitorWords[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		if _, ok := state.hits.counts.Load(normalized); !ok {
+			report.Unused = append(report.Unused, word.Word)
+		}
+	}
+	sort.Strings(report.Unused)
+	report.UnusedCount = len(report.Unused)
+	if limit > 0 && len(report.Unused) > limit {
+		report.Unused = report.Unused[:limit]
+	}
+	return report
+}
+
+// hitStats 命中最多的敏感词，用于统计信息
+func (f *ContentFilter) hitStats() []types.WordHit {
+	if hits := f.state.Load().hits; hits != nil {
+		return hits.top(statsTopWords)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package filter
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/guardian/content-filter/internal/trace"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// MatchHook 命中敏感词时调用的回调，result 即返回给调用方的结果，不应修改
+type MatchHook func(ctx context.Context, text string, result *types.FilterResult)
+
+// ReloadHook 正在服务的词库替换后调用的回调，参数为替换前后的统计信息
+type ReloadHook func(old, new *types.Stats)
+
+// hooks 注册的回调，写时复制，检查时读取无需加锁
+type hooks struct {
+	mu     sync.Mutex // 串行化注册
+	match  atomic.Pointer[[]MatchHook]
+	reload atomic.Pointer[[]ReloadHook]
+}
+
+// OnMatch 注册命中敏感词时的回调，在检查返回前同步调用，不应长时间阻塞
+func (f *ContentFilter) OnMatch(hook MatchHook) {
+	f.hooks.mu.Lock()
+	defer f.hooks.mu.Unlock()
+
+	var registered []MatchHook
+	if current := f.hooks.match.Load(); current != nil {
+		registered = append(registered, *current...)
+	}
+	registered = append(registered, hook)
+	f.hooks.match.Store(&registered)
+}
+
+// OnReload 注册词库替换后的回调，包括重载、灰度全量、定时生效和运行时修改，在替换词库的协程中同步调用
+func (f *ContentFilter) OnReload(hook ReloadHook) {
+	f.hooks.mu.Lock()
+	defer f.hooks.mu.Unlock()
+
+	var registered []ReloadHook
+	if current := f.hooks.reload.Load(); current != nil {
+		registered = append(registered, *current...)
+	}
+	registered = append(registered, hook)
+	f.hooks.reload.Store(&registered)
+}
+
+// runMatchHooks 结果命中敏感词时调用 OnMatch 注册的回调
+func (f *ContentFilter) runMatchHooks(ctx context.Context, text string, result *types.FilterResult) {
+	registered := f.hooks.match.Load()
+	if registered == nil || len(result.Words) == 0 {
+		return
+	}
+
+	for _, hook := range *registered {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					trace.Entry(ctx, f.logger).Errorf("Match hook panicked: %v", r)
+				}
+			}()
+			hook(ctx, text, result)
+		}()
+	}
+}
+
+// reloadHookStats 替换词库前的统计信息，没有注册 OnReload 回调时返回nil，避免无谓的统计开销
+func (f *ContentFilter) reloadHookStats() *types.Stats {
+	if f.hooks.reload.Load() == nil {
+		return nil
+	}
+	return f.GetStats()
+}
+
+// runReloadHooks 词库替换后调用 OnReload 注册的回调，old为替换前 reloadHookStats 的结果
+func (f *ContentFilter) runReloadHooks(old *types.Stats) {
+	registered := f.hooks.reload.Load()
+	if registered == nil || old == nil {
+		return
+	}
+
+	current := f.GetStats()
+	for _, hook := range *registered {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					f.logger.Errorf("Reload hook panicked: %v", r)
+				}
+			}()
+			hook(old, current)
+		}()
+	}
+}
@@ -0,0 +1,29 @@
+package filter
+
+import (
+	"github.com/guardian/content-filter/internal/markup"
+	"github.com/guardian/content-filter/internal/normalize"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// invisiblePositions 返回标准化时去除的不可见字符在原文中的位置
+func invisiblePositions(content *markup.Text, normalized *normalize.Text) []types.Position {
+	invisible := normalized.Invisible()
+	if len(invisible) == 0 {
+		return nil
+	}
+
+	positions := make([]types.Position, 0, len(invisible))
+	for _, p := range invisible {
+		pieces := content.OriginalSpans(p.Start, p.End)
+		positions = append(positions, types.Position{Start: pieces[0].Start, End: pieces[len(pieces)-1].End})
+	}
+	return positions
+}
+
+// withInvisible 将不可见字符的数量和位置写入结果
+func withInvisible(result *types.FilterResult, positions []types.Position) *types.FilterResult {
+	result.InvisibleCount = len(positions)
+	result.InvisiblePositions = positions
+	return result
+}
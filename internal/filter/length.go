@@ -0,0 +1,45 @@
+package filter
+
+import (
+	"unicode/utf8"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// limitLength 处理超过最大长度的文本：按配置截断到完整字符边界，或返回拒绝结果
+func (f *ContentFilter) limitLength(text string, options *types.FilterOptions) (string, bool, *types.FilterResult) {
+	limit := f.config.MaxTextLength
+	if limit <= 0 || len(text) <= limit {
+		return text, false, nil
+	}
+
+	if f.config.MaxTextLengthAction == types.TextReject {
+		return "", false, f.textTooLongResult(options)
+	}
+
+	// 截断位置向前移动到字符起始位置，避免截断多字节字符
+	end := limit
+	for end > 0 && !utf8.RuneStart(text[end]) {
+		end--
+	}
+	return text[:end], true, nil
+}
+
+// textTooLongResult 文本超过最大长度时的拒绝结果
+func (f *ContentFilter) textTooLongResult(options *types.FilterOptions) *types.FilterResult {
+	var locale string
+	if options != nil {
+		locale = options.Locale
+	}
+
+	return &types.FilterResult{
+		Passed:     false,
+		Categories: []string{},
+		Words:      []string{},
+		Details:    map[string]string{"reason": types.ReasonTextTooLong},
+		Action:     types.ActionReject,
+		ReasonCode: types.ReasonTextTooLong,
+		Reason:     f.messages.Reason(locale, types.ReasonTextTooLong),
+		Message:    f.messages.Message(locale, nil, false),
+	}
+}
@@ -0,0 +1,84 @@
+package filter
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/guardian/content-filter/internal/types"
+	"github.com/guardian/content-filter/internal/wordlist"
+)
+
+// ErrLintFailed 词库检查未通过，按 lint.strictness 拒绝了该词库，同时满足 errors.Is(err, types.ErrInvalidWordDB)
+var ErrLintFailed = fmt.Errorf("%w: lint failed", types.ErrInvalidWordDB)
+
+// maxLoggedLintIssues 每次检查最多逐条记录日志的问题数
+const maxLoggedLintIssues = 20
+
+// lintWordDatabase 检查即将生效的词库，记录结果并按严格程度决定是否拒绝
+func (f *ContentFilter) lintWordDatabase(wordDB *types.WordDatabase) error {
+	strictness := f.config.Lint.Strictness
+	if strictness == types.LintOff {
+		return nil
+	}
+
+	report := &types.LintReport{Version: wordDB.Version, Time: time.Now(), Issues: wordlist.LintWithConfig(wordDB, &f.config.Lint)}
+	for _, issue := range report.Issues {
+		if issue.Severity == types.LintError {
+			report.Errors++
+		} else {
+			report.Warnings++
+		}
+	}
+
+	switch strictness {
+	case types.LintReject:
+		report.Rejected = report.Errors > 0
+	case types.LintStrict:
+		report.Rejected = len(report.Issues) > 0
+	}
+	f.lintReport.Store(report)
+
+	if len(report.Issues) == 0 {
+		return nil
+	}
+	for i, issue := range report.Issues {
+		if i == maxLoggedLintIssues {
+			f.logger.Warnf("Word database lint: %d more issue(s) omitted", len(report.Issues)-i)
+			break
+		}
+		f.logger.Warnf("Word database lint %s [%s] %q %s: %s", issue.Severity, issue.Rule, issue.Word, issue.Category, issue.Message)
+	}
+	f.logger.Warnf("Word database version %s lint found %d error(s), %d warning(s), rejected: %v",
+		wordDB.Version, report.Errors, report.Warnings, report.Rejected)
+
+	if report.Rejected {
+		return fmt.Errorf("%w: version %s has %d error(s), %d warning(s)", ErrLintFailed, wordDB.Version, report.Errors, report.Warnings)
+	}
+	return nil
+}
+
+// LintReport 返回最近一次词库检查的结果，未检查过时返回nil
+func (f *ContentFilter) LintReport() *types.LintReport {
+	return f.lintReport.Load()
+}
+
+// lintStats 最近一次词库检查的统计信息，按规则汇总问题数；未检查过时返回nil
+func (f *ContentFilter) lintStats() *types.LintSummary {
+	report := f.lintReport.Load()
+	if report == nil {
+		return nil
+	}
+
+	rules := make(map[string]int)
+	for _, issue := range report.Issues {
+		rules[issue.Rule]++
+	}
+	return &types.LintSummary{
+		Version:  report.Version,
+		Time:     report.Time,
+		Errors:   report.Errors,
+		Warnings: report.Warnings,
+		Rejected: report.Rejected,
+		Rules:    rules,
+	}
+}
@@ -0,0 +1,30 @@
+package filter
+
+import (
+	"github.com/guardian/content-filter/internal/algorithm"
+	"github.com/guardian/content-filter/internal/matcher"
+)
+
+// RegisterMatcher 注册自定义匹配器，在自动机之后运行，命中与敏感词命中合并到结果中
+// 注册后清空结果缓存，避免返回注册前的结果
+func (f *ContentFilter) RegisterMatcher(m matcher.Matcher) {
+	f.hooks.mu.Lock()
+	var registered []matcher.Matcher
+	if current := f.matchers.Load(); current != nil {
+		registered = append(registered, *current...)
+	}
+	registered = append(registered, m)
+	f.matchers.Store(&registered)
+	f.hooks.mu.Unlock()
+
+	f.clearCache()
+}
+
+// customMatches 运行注册的自定义匹配器
+func (f *ContentFilter) customMatches(text string, options *algorithm.SearchOptions) []algorithm.Match {
+	registered := f.matchers.Load()
+	if registered == nil {
+		return nil
+	}
+	return matcher.Find(*registered, text, options)
+}
@@ -0,0 +1,122 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/guardian/content-filter/internal/integrity"
+	"github.com/guardian/content-filter/internal/source"
+	"github.com/guardian/content-filter/internal/types"
+	"github.com/guardian/content-filter/internal/wordlist"
+)
+
+// ErrStaleVersion 配置源返回的词库版本比已加载的版本旧
+var ErrStaleVersion = errors.New("stale word database version")
+
+// wordDataSets 返回需要加载的词库：data_id 在前，merge_data_ids 按配置顺序在后
+func (f *ContentFilter) wordDataSets() []types.WordDataSet {
+	sets := make([]types.WordDataSet, 0, 1+len(f.config.MergeDataIds))
+	sets = append(sets, types.WordDataSet{DataId: f.config.DataId, Group: f.config.Group, Format: f.config.WordFormat})
+	for _, set := range f.config.MergeDataIds {
+		if set.Group == "" {
+			set.Group = f.config.Group
+		}
+		sets = append(sets, set)
+	}
+	return sets
+}
+
+// getWordDatabase 从词库源获取全部词库并合并
+func (f *ContentFilter) getWordDatabase() (*types.WordDatabase, error) {
+	sets := f.wordDataSets()
+	parts := make([]*types.WordDatabase, len(sets))
+	for i, set := range sets {
+		wordDB, err := f.getWordData(set)
+		if err != nil {
+			return nil, err
+		}
+		parts[i] = wordDB
+	}
+
+	f.partsMu.Lock()
+	defer f.partsMu.Unlock()
+
+	for i, set := range sets {
+		if err := f.checkVersion(i, set, parts[i]); err != nil {
+			return nil, err
+		}
+	}
+	f.parts = parts
+
+	return wordlist.Merge(parts, f.config.MergeStrategy), nil
+}
+
+// applyWordDataChange 处理单个词库的变更通知，与其余词库的最新内容合并后交给后台构建
+func (f *ContentFilter) applyWordDataChange(i int, content string) error {
+	sets := f.wordDataSets()
+	wordDB, err := source.ParseWordDatabase(f.source, sets[i].DataId, sets[i].Group, sets[i].Format, content)
+	if err != nil {
+		return err
+	}
+	if err := integrity.Verify(wordDB, f.publicKey); err != nil {
+		return fmt.Errorf("rejected word database %s/%s: %w: %w", sets[i].Group, sets[i].DataId, types.ErrInvalidWordDB, err)
+	}
+
+	f.partsMu.Lock()
+	defer f.partsMu.Unlock()
+
+	// 启动时从快照等非配置源加载的，其余词库尚未获取
+	if len(f.parts) != len(sets) {
+		f.parts = make([]*types.WordDatabase, len(sets))
+	}
+	if err := f.checkVersion(i, sets[i], wordDB); err != nil {
+		return err
+	}
+	f.parts[i] = wordDB
+	for j, set := range sets {
+		if f.parts[j] != nil {
+			continue
+		}
+		part, err := f.getWordData(set)
+		if err != nil {
+			return err
+		}
+		f.parts[j] = part
+	}
+
+	f.enqueueUpdate(wordlist.Merge(f.parts, f.config.MergeStrategy))
+	return nil
+}
+
+// getWordData 从词库源获取单个词库并校验校验和与签名
+func (f *ContentFilter) getWordData(set types.WordDataSet) (*types.WordDatabase, error) {
+	wordDB, err := source.GetWordDatabase(f.source, set.DataId, set.Group, set.Format)
+	if err != nil {
+		return nil, fmt.Errorf("%s/%s: %w", set.Group, set.DataId, err)
+	}
+	if err := integrity.Verify(wordDB, f.publicKey); err != nil {
+		return nil, fmt.Errorf("rejected word database %s/%s: %w: %w", set.Group, set.DataId, types.ErrInvalidWordDB, err)
+	}
+	return wordDB, nil
+}
+
+// checkVersion 拒绝比该词库已加载版本更旧的内容，如配置中心故障切换后返回的旧缓存，调用方需持有 partsMu
+// 开启 allow_version_rollback、任一版本为空或为内容摘要版本（文本、CSV词表）时不检查
+func (f *ContentFilter) checkVersion(i int, set types.WordDataSet, wordDB *types.WordDatabase) error {
+	if f.config.AllowVersionRollback || i >= len(f.parts) || f.parts[i] == nil {
+		return nil
+	}
+
+	current := f.parts[i].Version
+	if !staleVersion(wordDB.Version, current) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s/%s version %s is older than loaded version %s",
+		ErrStaleVersion, set.Group, set.DataId, wordDB.Version, current)
+}
+
+// staleVersion 判断version是否比已加载的current旧，任一版本为空或为内容摘要版本时无法比较，视为不旧
+func staleVersion(version, current string) bool {
+	return current != "" && version != "" && !types.IsContentVersion(current) && !types.IsContentVersion(version) &&
+		types.CompareVersions(version, current) < 0
+}
@@ -0,0 +1,64 @@
+package filter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// 拒绝比已加载版本旧的词库，无法比较先后的版本不拒绝
+func TestCheckVersion(t *testing.T) {
+	contentVersion := types.ContentVersion([]byte("违禁词\n"))
+
+	tests := []struct {
+		name     string
+		current  string
+		version  string
+		rollback bool
+		stale    bool
+	}{
+		{"newer", "1.0.0", "1.0.1", false, false},
+		{"equal", "1.0.0", "1.0.0", false, false},
+		{"older", "1.0.10", "1.0.9", false, true},
+		{"older major", "2.0.0", "1.9.9", false, true},
+		{"rollback allowed", "1.0.1", "1.0.0", true, false},
+		{"v prefix", "v1.0.0", "1.0.0", false, false},
+		{"pre-release older than release", "1.0.0", "1.0.0-rc.1", false, true},
+		{"release newer than pre-release", "1.0.0-rc.1", "1.0.0", false, false},
+		{"pre-release numeric", "1.0.0-rc.10", "1.0.0-rc.9", false, true},
+		{"local edits newer", "1.0.0-local.1", "1.0.0-local.2", false, false},
+		{"local edits older", "1.0.0-local.10", "1.0.0-local.9", false, true},
+		{"release after local edits", "1.0.0-local.3", "1.0.1", false, false},
+		{"empty current", "", "1.0.0", false, false},
+		{"empty version", "1.0.0", "", false, false},
+		{"content version", contentVersion, "1.0.0", false, false},
+		{"replaced by content version", "1.0.0", contentVersion, false, false},
+		{"malformed shorter", "1.0.0", "1.0", false, true},
+		{"malformed text", "1.0.0", "latest", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &ContentFilter{
+				config: &types.FilterConfig{AllowVersionRollback: tt.rollback},
+				parts:  []*types.WordDatabase{{Version: tt.current}},
+			}
+			set := types.WordDataSet{DataId: "words", Group: "test"}
+			err := f.checkVersion(0, set, &types.WordDatabase{Version: tt.version})
+			if stale := errors.Is(err, ErrStaleVersion); stale != tt.stale || (err != nil && !stale) {
+				t.Errorf("checkVersion(%q -> %q) error = %v, want stale %v", tt.current, tt.version, err, tt.stale)
+			}
+		})
+	}
+}
+
+// 尚未加载过的词库不检查版本
+func TestCheckVersionWithoutLoadedPart(t *testing.T) {
+	f := &ContentFilter{config: &types.FilterConfig{}, parts: make([]*types.WordDatabase, 2)}
+	set := types.WordDataSet{DataId: "extra", Group: "test"}
+	for i := 0; i < 3; i++ {
+		if err := f.checkVersion(i, set, &types.WordDatabase{Version: "0.0.1"}); err != nil {
+			t.Errorf("checkVersion(%d) error = %v, want nil", i, err)
+		}
+	}
+}
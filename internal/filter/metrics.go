@@ -0,0 +1,139 @@
+package filter
+
+import (
+	"math/bits"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// latencyBuckets 耗时分桶数，第i个桶的上界为 2^i 微秒，最后一个桶收集更长的耗时
+const latencyBuckets = 28
+
+// metrics 进程启动以来的检查统计，全部使用原子计数，不阻塞检查
+type metrics struct {
+	checks     atomic.Int64
+	blocked    atomic.Int64
+	degraded   atomic.Int64
+	actions    sync.Map // types.Action -> *atomic.Int64
+	categories sync.Map // 分类 -> *atomic.Int64
+	latency    [latencyBuckets]atomic.Int64
+	totalNanos atomic.Int64
+	maxNanos   atomic.Int64
+}
+
+// record 记录一次检查的结果和耗时
+func (m *metrics) record(result *types.FilterResult, elapsed time.Duration) {
+	m.checks.Add(1)
+	if !result.Passed {
+		m.blocked.Add(1)
+	}
+	if result.Degraded {
+		m.degraded.Add(1)
+	}
+	if result.Action != "" {
+		increment(&m.actions, result.Action)
+	}
+	for _, category := range result.Categories {
+		increment(&m.categories, category)
+	}
+
+	nanos := elapsed.Nanoseconds()
+	m.latency[latencyBucket(elapsed)].Add(1)
+	m.totalNanos.Add(nanos)
+	for {
+		max := m.maxNanos.Load()
+		if nanos <= max || m.maxNanos.CompareAndSwap(max, nanos) {
+			break
+		}
+	}
+}
+
+// increment 将键对应的计数加一
+func increment(counters *sync.Map, key interface{}) {
+	counter, _ := counters.LoadOrStore(key, new(atomic.Int64))
+	counter.(*atomic.Int64).Add(1)
+}
+
+// latencyBucket 耗时所在的桶：不超过 2^i 微秒的最小i
+func latencyBucket(elapsed time.Duration) int {
+	micros := elapsed.Microseconds()
+	if micros <= 1 {
+		return 0
+	}
+	bucket := bits.Len64(uint64(micros - 1))
+	if bucket >= latencyBuckets {
+		bucket = latencyBuckets - 1
+	}
+	return bucket
+}
+
+// fill 将统计写入 stats
+func (m *metrics) fill(stats *types.Stats) {
+	stats.Checks = m.checks.Load()
+	stats.Blocked = m.blocked.Load()
+	stats.Degraded = m.degraded.Load()
+	if stats.Checks > 0 {
+		stats.BlockRate = float64(stats.Blocked) / float64(stats.Checks)
+	}
+
+	stats.Actions = make(map[types.Action]int64)
+	m.actions.Range(func(action, counter interface{}) bool {
+		stats.Actions[action.(types.Action)] = counter.(*atomic.Int64).Load()
+		return true
+	})
+	stats.Categories = make(map[string]int64)
+	m.categories.Range(func(category, counter interface{}) bool {
+		stats.Categories[category.(string)] = counter.(*atomic.Int64).Load()
+		return true
+	})
+
+	stats.Latency = m.latencyStats()
+}
+
+// latencyStats 按分桶估算耗时分位数，取所在桶的上界
+func (m *metrics) latencyStats() types.LatencyStats {
+	var counts [latencyBuckets]int64
+	var total int64
+	for i := range m.latency {
+		counts[i] = m.latency[i].Load()
+		total += counts[i]
+	}
+	if total == 0 {
+		return types.LatencyStats{}
+	}
+
+	maxMs := millis(m.maxNanos.Load())
+	percentile := func(p float64) float64 {
+		rank := int64(p * float64(total))
+		if rank < 1 {
+			rank = 1
+		}
+		var seen int64
+		for i, count := range counts {
+			seen += count
+			if seen >= rank {
+				if bound := float64(int64(1)<<i) / 1000; bound < maxMs {
+					return bound
+				}
+				return maxMs
+			}
+		}
+		return maxMs
+	}
+
+	return types.LatencyStats{
+		AvgMs: millis(m.totalNanos.Load()) / float64(total),
+		P50Ms: percentile(0.50),
+		P90Ms: percentile(0.90),
+		P99Ms: percentile(0.99),
+		MaxMs: maxMs,
+	}
+}
+
+// millis 纳秒转换为毫秒
+func millis(nanos int64) float64 {
+	return float64(nanos) / float64(time.Millisecond)
+}
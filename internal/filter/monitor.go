@@ -0,0 +1,34 @@
+package filter
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/guardian/content-filter/internal/logging"
+	"github.com/guardian/content-filter/internal/trace"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// recordMonitored 统计并记录只观察的命中，缓存命中的结果同样计入
+func (f *ContentFilter) recordMonitored(ctx context.Context, result *types.FilterResult) {
+	if result == nil || len(result.Monitored) == 0 {
+		return
+	}
+
+	for _, match := range result.Monitored {
+		counter, _ := f.monitorHits.LoadOrStore(match.Word, new(atomic.Int64))
+		counter.(*atomic.Int64).Add(int64(match.Count))
+	}
+	logging.WithField(trace.Entry(ctx, f.logger), "monitored", result.Monitored).
+		Infof("Monitor-only matches, passed: %v", result.Passed)
+}
+
+// monitorStats 只观察的敏感词 -> 累计命中次数
+func (f *ContentFilter) monitorStats() map[string]int64 {
+	stats := make(map[string]int64)
+	f.monitorHits.Range(func(word, counter interface{}) bool {
+		stats[word.(string)] = counter.(*atomic.Int64).Load()
+		return true
+	})
+	return stats
+}
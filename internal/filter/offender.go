@@ -0,0 +1,71 @@
+package filter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/guardian/content-filter/internal/offender"
+	"github.com/guardian/content-filter/internal/trace"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// startOffender 按配置创建用户违规统计存储
+func (f *ContentFilter) startOffender() error {
+	if !f.config.Offender.Enabled {
+		return nil
+	}
+
+	store, err := offender.NewStore(&f.config.Offender)
+	if err != nil {
+		return fmt.Errorf("failed to create offender store: %w", err)
+	}
+	f.offenders = store
+	return nil
+}
+
+// trackOffender 检查选项带 UserID 时，未通过的结果计为该用户的一次违规，将违规次数写入结果
+// 降级结果不计入；存储不可用时结果不带违规次数
+func (f *ContentFilter) trackOffender(ctx context.Context, result *types.FilterResult, options *types.FilterOptions) *types.FilterResult {
+	if f.offenders == nil || options == nil || options.UserID == "" {
+		return result
+	}
+
+	var (
+		violations int64
+		err        error
+	)
+	if !result.Passed && !result.Degraded {
+		violations, err = f.offenders.Add(ctx, options.UserID, time.Now())
+	} else {
+		violations, err = f.offenders.Count(ctx, options.UserID, time.Now())
+	}
+	if err != nil {
+		trace.Entry(ctx, f.logger).Warnf("Failed to track violations of user %s: %v", options.UserID, err)
+		return result
+	}
+
+	result.Offender = &types.OffenderStatus{UserID: options.UserID, Violations: violations}
+	return result
+}
+
+// OffenderStatus 返回用户在统计窗口内的违规次数，未启用违规统计时返回 offender.ErrDisabled
+func (f *ContentFilter) OffenderStatus(ctx context.Context, userID string) (*types.OffenderStatus, error) {
+	if f.offenders == nil {
+		return nil, offender.ErrDisabled
+	}
+
+	violations, err := f.offenders.Count(ctx, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &types.OffenderStatus{UserID: userID, Violations: violations}, nil
+}
+
+// ResetOffender 清空用户的违规记录，未启用违规统计时返回 offender.ErrDisabled
+func (f *ContentFilter) ResetOffender(ctx context.Context, userID string) error {
+	if f.offenders == nil {
+		return offender.ErrDisabled
+	}
+	return f.offenders.Reset(ctx, userID)
+}
@@ -0,0 +1,13 @@
+package filter
+
+import "github.com/guardian/content-filter/internal/types"
+
+// resolveOptions 补全调用方未设置的选项，并按服务端配置收紧：服务端关闭白名单时请求不能开启
+// 返回新的选项，nil使用默认选项；所有过滤入口先经过这里，直接使用 ContentFilter 和经过 Guardian、HTTP 接口的行为一致
+func (f *ContentFilter) resolveOptions(options *types.FilterOptions) *types.FilterOptions {
+	resolved := options.WithDefaults()
+	if !f.config.EnableWhitelist {
+		resolved.EnableWhitelist = false
+	}
+	return resolved
+}
@@ -0,0 +1,53 @@
+package filter
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/guardian/content-filter/internal/logging"
+	"github.com/guardian/content-filter/internal/source"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// 请求只设置部分选项时，未设置的选项使用默认值，白名单保持开启
+func TestPartialOptionsKeepWhitelist(t *testing.T) {
+	config := &types.FilterConfig{DataId: "words", Group: "test", EnableWhitelist: true}
+	content, err := json.Marshal(&types.WordDatabase{
+		Version:   "1.0.0",
+		Blacklist: []types.SensitiveWord{{Word: "违禁", Level: 3}},
+		Whitelist: []string{"违禁品检测"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := source.NewMemory()
+	if err := src.PublishConfig(config.DataId, config.Group, string(content)); err != nil {
+		t.Fatal(err)
+	}
+	f, err := NewContentFilter(src, config, logging.Discard())
+	if err != nil {
+		t.Fatalf("NewContentFilter() error = %v", err)
+	}
+	t.Cleanup(func() { f.Close() })
+
+	tests := []struct {
+		name    string
+		options string
+		passed  bool
+	}{
+		{"min level only", `{"min_level":2}`, true},
+		{"empty", `{}`, true},
+		{"whitelist disabled", `{"min_level":2,"enable_whitelist":false}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var options types.FilterOptions
+			if err := json.Unmarshal([]byte(tt.options), &options); err != nil {
+				t.Fatal(err)
+			}
+			if result := f.Filter("开展违禁品检测工作", &options); result.Passed != tt.passed {
+				t.Errorf("Filter() with options %s passed = %v, want %v", tt.options, result.Passed, tt.passed)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package filter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/guardian/content-filter/internal/jsonpath"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// FilterJSON 按JSONPath风格的选择器检查JSON文档中的字符串字段，按选择器顺序返回每个字段的结果
+// 多个选择器命中同一字段时只检查一次；选择器无效或文档无法解析时返回错误
+func (f *ContentFilter) FilterJSON(ctx context.Context, data []byte, selectors []string, options *types.FilterOptions) ([]types.FieldResult, error) {
+	paths := make([]*jsonpath.Path, 0, len(selectors))
+	for _, selector := range selectors {
+		path, err := jsonpath.Compile(selector)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse json payload: %w", err)
+	}
+
+	type field struct {
+		path  string
+		value string
+	}
+	fields := make([]field, 0)
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		path.Strings(doc, func(fieldPath, value string) {
+			if seen[fieldPath] {
+				return
+			}
+			seen[fieldPath] = true
+			fields = append(fields, field{path: fieldPath, value: value})
+		})
+	}
+
+	results := make([]types.FieldResult, 0, len(fields))
+	for _, field := range fields {
+		result, err := f.FilterContext(ctx, field.value, options)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, types.FieldResult{Path: field.path, Result: result})
+	}
+
+	return results, nil
+}
@@ -0,0 +1,208 @@
+package filter
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/guardian/content-filter/internal/integrity"
+	"github.com/guardian/content-filter/internal/source"
+	"github.com/guardian/content-filter/internal/types"
+	"github.com/guardian/content-filter/internal/wordlist"
+)
+
+// persistRetries 写回被并发修改覆盖时的最大尝试次数
+const persistRetries = 3
+
+var (
+	// ErrConcurrentUpdate 写回词库时词库被其他实例并发修改，重试后仍未成功
+	ErrConcurrentUpdate = errors.New("word database was modified concurrently")
+	// ErrPersistFormat 词库源中的内容不是JSON格式，写回会覆盖原有格式
+	ErrPersistFormat = errors.New("persisting word database changes requires a JSON word database")
+	// ErrWordExists 要新增的敏感词已存在
+	ErrWordExists = errors.New("sensitive word already exists")
+)
+
+// WordDatabaseEdit 对词库源中最新词库的一次修改，返回错误时放弃发布并原样返回该错误
+type WordDatabaseEdit func(wordDB *types.WordDatabase) error
+
+// PublishWordDatabase 读取词库源中的最新词库，应用修改、递增版本并签名后发布，返回发布的词库
+// 与 persist_whitelist、persist_words 共用写回流程（条件发布、回读校验、拒绝覆盖非JSON词库），不要求开启这两个配置
+// key 为nil时只写入校验和
+func (f *ContentFilter) PublishWordDatabase(edit WordDatabaseEdit, key ed25519.PrivateKey) (*types.WordDatabase, error) {
+	return f.persistWordDatabase(func(wordDB *types.WordDatabase) (bool, error) {
+		if err := edit(wordDB); err != nil {
+			return false, err
+		}
+		return true, nil
+	}, key)
+}
+
+// InsertWord 返回新增敏感词的修改，同名敏感词（按标准化后的形式比较）已存在时返回 ErrWordExists
+func (f *ContentFilter) InsertWord(word types.SensitiveWord) WordDatabaseEdit {
+	return func(wordDB *types.WordDatabase) error {
+		if f.findWord(wordDB, word.Word) != nil {
+			return fmt.Errorf("%w: %s", ErrWordExists, word.Word)
+		}
+		wordDB.Blacklist = append(wordDB.Blacklist, word)
+		return nil
+	}
+}
+
+// ReplaceWord 返回替换敏感词的修改，敏感词不存在时返回 ErrWordNotFound
+func (f *ContentFilter) ReplaceWord(word types.SensitiveWord) WordDatabaseEdit {
+	return func(wordDB *types.WordDatabase) error {
+		existing := f.findWord(wordDB, word.Word)
+		if existing == nil {
+			return fmt.Errorf("%w: %s", ErrWordNotFound, word.Word)
+		}
+		*existing = word
+		return nil
+	}
+}
+
+// DeleteWord 返回从黑名单和所有分类中删除敏感词的修改，敏感词不存在时返回 ErrWordNotFound
+func (f *ContentFilter) DeleteWord(word string) WordDatabaseEdit {
+	remove := f.removeWord(word)
+	return func(wordDB *types.WordDatabase) error {
+		if !remove(wordDB) {
+			return fmt.Errorf("%w: %s", ErrWordNotFound, word)
+		}
+		return nil
+	}
+}
+
+// MergeWords 返回合并敏感词的修改，已存在的同名敏感词被替换，其余加入黑名单
+func (f *ContentFilter) MergeWords(words []types.SensitiveWord) WordDatabaseEdit {
+	return func(wordDB *types.WordDatabase) error {
+		for _, word := range words {
+			f.upsertWord(word)(wordDB)
+		}
+		return nil
+	}
+}
+
+// persistWhitelist 将白名单修改写回词库源，未开启 persist_whitelist 时不做任何事
+func (f *ContentFilter) persistWhitelist(update func(entries []string) ([]string, bool)) error {
+	if !f.config.PersistWhitelist {
+		return nil
+	}
+	_, err := f.persistWordDatabase(func(wordDB *types.WordDatabase) (bool, error) {
+		whitelist, changed := update(wordDB.Whitelist)
+		wordDB.Whitelist = whitelist
+		return changed, nil
+	}, nil)
+	return err
+}
+
+// persistWords 将敏感词修改写回词库源，未开启 persist_words 时不做任何事
+func (f *ContentFilter) persistWords(update func(wordDB *types.WordDatabase) bool) error {
+	if !f.config.PersistWords {
+		return nil
+	}
+	_, err := f.persistWordDatabase(func(wordDB *types.WordDatabase) (bool, error) {
+		return update(wordDB), nil
+	}, nil)
+	return err
+}
+
+// persistWordDatabase 读取最新词库、应用修改并递增版本后发布，被其他实例的并发修改覆盖时重新读取并重试
+// 配置源支持条件发布（etcd、Redis、内存）时只在内容与读取时一致才写入；不支持时（Nacos、Apollo）
+// 只能在发布后回读，按校验和比较词库内容，两个实例几乎同时写回时仍可能丢失其中一个修改
+// 发布后各实例（包括本实例）通过配置监听重载词库，修改在重载和重启后依然有效
+// 写回总是发布JSON词库，词库源中的内容不是JSON格式（文本、CSV、YAML词表）时返回 ErrPersistFormat，避免覆盖原有格式
+// update 返回false时词库未变化，不发布并返回nil词库
+func (f *ContentFilter) persistWordDatabase(update func(wordDB *types.WordDatabase) (bool, error), key ed25519.PrivateKey) (*types.WordDatabase, error) {
+	if f.config.ArtifactPath != "" {
+		return nil, errors.New("persisting word database changes is not supported when loading from an artifact")
+	}
+
+	for attempt := 1; attempt <= persistRetries; attempt++ {
+		content, err := f.source.GetConfig(f.config.DataId, f.config.Group)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read word database: %w", err)
+		}
+		format, err := source.ResolveFormat(f.config.DataId, f.config.WordFormat, content)
+		if err != nil {
+			return nil, err
+		}
+		if format != wordlist.FormatJSON {
+			return nil, fmt.Errorf("%w, %s/%s is %s", ErrPersistFormat, f.config.Group, f.config.DataId, format)
+		}
+		wordDB, err := source.ParseWordDatabase(f.source, f.config.DataId, f.config.Group, f.config.WordFormat, content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read word database: %w", err)
+		}
+
+		changed, err := update(wordDB)
+		if err != nil || !changed {
+			return nil, err
+		}
+		wordDB.Version = types.NextVersion(wordDB.Version)
+		wordDB.UpdateTime = time.Now()
+		if err := integrity.Seal(wordDB, key); err != nil {
+			return nil, err
+		}
+
+		if conditional, ok := f.source.(source.ConditionalPublisher); ok {
+			err = source.PublishWordDatabaseShardsIf(conditional, f.config.DataId, f.config.Group, wordDB, f.config.ShardSize, content)
+		} else {
+			err = source.PublishWordDatabaseShards(f.source, f.config.DataId, f.config.Group, wordDB, f.config.ShardSize)
+		}
+		if errors.Is(err, types.ErrConfigModified) {
+			f.logger.Warnf("Word database was modified while persisting changes (attempt %d/%d)", attempt, persistRetries)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to publish word database: %w", err)
+		}
+
+		// 回读确认发布的内容：并发写回的两个实例会得到相同的版本号，只能按内容比较；
+		// 分片发布时其他实例还可能覆盖了本次写入的分片
+		published, err := source.GetWordDatabase(f.source, f.config.DataId, f.config.Group, f.config.WordFormat)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify published word database: %w", err)
+		}
+		checksum, err := integrity.Checksum(published)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify published word database: %w", err)
+		}
+		if checksum == wordDB.Checksum {
+			f.logger.Infof("Word database changes persisted to word source, version: %s", wordDB.Version)
+			return wordDB, nil
+		}
+
+		f.logger.Warnf("Word database was overwritten with version %s while persisting changes (attempt %d/%d)",
+			published.Version, attempt, persistRetries)
+	}
+
+	return nil, ErrConcurrentUpdate
+}
+
+// addWhitelistEntry 返回添加条目后的白名单，条目已存在时不修改
+func (f *ContentFilter) addWhitelistEntry(word string) func([]string) ([]string, bool) {
+	key := f.whitelistKey(word)
+	return func(entries []string) ([]string, bool) {
+		for _, entry := range entries {
+			if f.whitelistKey(entry) == key {
+				return entries, false
+			}
+		}
+		return append(entries, word), true
+	}
+}
+
+// removeWhitelistEntry 返回移除条目后的白名单，按标准化后的形式比较
+func (f *ContentFilter) removeWhitelistEntry(word string) func([]string) ([]string, bool) {
+	key := f.whitelistKey(word)
+	return func(entries []string) ([]string, bool) {
+		kept := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			if f.whitelistKey(entry) != key {
+				kept = append(kept, entry)
+			}
+		}
+		return kept, len(kept) != len(entries)
+	}
+}
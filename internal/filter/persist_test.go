@@ -0,0 +1,180 @@
+package filter
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/guardian/content-filter/internal/integrity"
+	"github.com/guardian/content-filter/internal/logging"
+	"github.com/guardian/content-filter/internal/source"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// 词库源中是文本词表时拒绝写回，原内容保持不变
+func TestPersistRefusesNonJSONWordDatabase(t *testing.T) {
+	const content = "badword\nevil\n"
+	config := &types.FilterConfig{DataId: "words.txt", Group: "test", PersistWhitelist: true}
+	src := source.NewMemory()
+	if err := src.PublishConfig(config.DataId, config.Group, content); err != nil {
+		t.Fatal(err)
+	}
+	f, err := NewContentFilter(src, config, logging.Discard())
+	if err != nil {
+		t.Fatalf("NewContentFilter() error = %v", err)
+	}
+	t.Cleanup(func() { f.Close() })
+
+	if err := f.AddToWhitelist("evil"); err == nil {
+		t.Fatal("AddToWhitelist() error = nil, want the text word list refused")
+	}
+	got, err := src.GetConfig(config.DataId, config.Group)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != content {
+		t.Errorf("word source content = %q, want %q unchanged", got, content)
+	}
+}
+
+// 配置了公钥时拒绝写回词库，写回的词库无法签名，发布后会被所有实例拒绝
+func TestNewContentFilterRejectsPersistWithPublicKey(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wordDB := &types.WordDatabase{Version: "1.0.0", Blacklist: []types.SensitiveWord{{Word: "违禁词", Level: 3}}}
+	if err := integrity.Seal(wordDB, private); err != nil {
+		t.Fatal(err)
+	}
+	content, err := json.Marshal(wordDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := source.NewMemory()
+	if err := src.PublishConfig("words", "test", string(content)); err != nil {
+		t.Fatal(err)
+	}
+	for _, config := range []*types.FilterConfig{
+		{DataId: "words", Group: "test", PersistWhitelist: true, PublicKey: base64.StdEncoding.EncodeToString(public)},
+		{DataId: "words", Group: "test", PersistWords: true, PublicKey: base64.StdEncoding.EncodeToString(public)},
+	} {
+		if f, err := NewContentFilter(src, config, logging.Discard()); err == nil {
+			f.Close()
+			t.Errorf("NewContentFilter(persist_whitelist=%v, persist_words=%v) error = nil, want the config rejected",
+				config.PersistWhitelist, config.PersistWords)
+		}
+	}
+}
+
+// 两个实例同时写回白名单，两个修改都保留在词库中
+func TestConcurrentPersistKeepsBothChanges(t *testing.T) {
+	config := &types.FilterConfig{DataId: "words", Group: "test", PersistWhitelist: true}
+	content, err := json.Marshal(&types.WordDatabase{Version: "1.0.0", Blacklist: []types.SensitiveWord{{Word: "违禁词", Level: 3}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := source.NewMemory()
+	if err := src.PublishConfig(config.DataId, config.Group, string(content)); err != nil {
+		t.Fatal(err)
+	}
+
+	words := []string{"白名单一", "白名单二"}
+	filters := make([]*ContentFilter, len(words))
+	for i := range filters {
+		f, err := NewContentFilter(src, config, logging.Discard())
+		if err != nil {
+			t.Fatalf("NewContentFilter() error = %v", err)
+		}
+		t.Cleanup(func() { f.Close() })
+		filters[i] = f
+	}
+
+	var wg sync.WaitGroup
+	for i, word := range words {
+		wg.Add(1)
+		go func(f *ContentFilter, word string) {
+			defer wg.Done()
+			if err := f.AddToWhitelist(word); err != nil {
+				t.Errorf("AddToWhitelist(%q) error = %v", word, err)
+			}
+		}(filters[i], word)
+	}
+	wg.Wait()
+
+	wordDB, err := source.GetWordDatabase(src, config.DataId, config.Group, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, word := range words {
+		found := false
+		for _, entry := range wordDB.Whitelist {
+			found = found || entry == word
+		}
+		if !found {
+			t.Errorf("whitelist = %v, want %q persisted", wordDB.Whitelist, word)
+		}
+	}
+}
+
+// 管理接口的修改按标准化后的形式匹配敏感词，与运行时增删一致
+func TestPublishWordDatabaseMatchesNormalizedWords(t *testing.T) {
+	config := &types.FilterConfig{DataId: "words", Group: "test"}
+	content, err := json.Marshal(&types.WordDatabase{Version: "1.0.0", Blacklist: []types.SensitiveWord{{Word: "BadWord", Level: 3}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := source.NewMemory()
+	if err := src.PublishConfig(config.DataId, config.Group, string(content)); err != nil {
+		t.Fatal(err)
+	}
+	f, err := NewContentFilter(src, config, logging.Discard())
+	if err != nil {
+		t.Fatalf("NewContentFilter() error = %v", err)
+	}
+	t.Cleanup(func() { f.Close() })
+
+	if _, err := f.PublishWordDatabase(f.InsertWord(types.SensitiveWord{Word: "ｂａｄｗｏｒｄ", Level: 2}), nil); !errors.Is(err, ErrWordExists) {
+		t.Errorf("PublishWordDatabase(InsertWord) error = %v, want ErrWordExists", err)
+	}
+	wordDB, err := f.PublishWordDatabase(f.DeleteWord("badword"), nil)
+	if err != nil {
+		t.Fatalf("PublishWordDatabase(DeleteWord) error = %v", err)
+	}
+	if wordDB.Version != "1.0.1" || len(wordDB.Words()) != 0 {
+		t.Errorf("published version %s with words %v, want 1.0.1 without words", wordDB.Version, wordDB.Words())
+	}
+	if _, err := f.PublishWordDatabase(f.DeleteWord("badword"), nil); !errors.Is(err, ErrWordNotFound) {
+		t.Errorf("PublishWordDatabase(DeleteWord) again error = %v, want ErrWordNotFound", err)
+	}
+}
+
+// word_format为auto且词库源是CSV词表时，管理接口的修改同样拒绝发布，原内容保持不变
+func TestPublishWordDatabaseRefusesCSVWordDatabase(t *testing.T) {
+	const content = "word,categories,level\nbadword,abuse,3\n"
+	config := &types.FilterConfig{DataId: "words.csv", Group: "test", WordFormat: types.WordFormatAuto}
+	src := source.NewMemory()
+	if err := src.PublishConfig(config.DataId, config.Group, content); err != nil {
+		t.Fatal(err)
+	}
+	f, err := NewContentFilter(src, config, logging.Discard())
+	if err != nil {
+		t.Fatalf("NewContentFilter() error = %v", err)
+	}
+	t.Cleanup(func() { f.Close() })
+
+	_, err = f.PublishWordDatabase(f.MergeWords([]types.SensitiveWord{{Word: "evil", Level: 2}}), nil)
+	if !errors.Is(err, ErrPersistFormat) {
+		t.Fatalf("PublishWordDatabase() error = %v, want ErrPersistFormat", err)
+	}
+	got, err := src.GetConfig(config.DataId, config.Group)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != content {
+		t.Errorf("word source content = %q, want %q unchanged", got, content)
+	}
+}
@@ -0,0 +1,95 @@
+package filter
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// startPolicy 加载处理策略并监听变化
+func (f *ContentFilter) startPolicy() error {
+	if f.config.PolicyDataId == "" {
+		return nil
+	}
+
+	content, err := f.source.GetConfig(f.config.PolicyDataId, f.config.Group)
+	if err != nil {
+		// 策略配置不存在时按默认判定处理
+		f.logger.Warnf("Failed to load policy, using default decisions: %v", err)
+	} else if content != "" {
+		if err := f.applyPolicy(content); err != nil {
+			return err
+		}
+	}
+
+	return f.source.ListenConfig(f.config.PolicyDataId, f.config.Group, func(content string) {
+		if err := f.applyPolicy(content); err != nil {
+			f.logger.Errorf("Failed to apply policy: %v", err)
+		}
+	})
+}
+
+// applyPolicy 解析并应用处理策略
+func (f *ContentFilter) applyPolicy(content string) error {
+	var policy types.Policy
+	if err := json.Unmarshal([]byte(content), &policy); err != nil {
+		return fmt.Errorf("failed to unmarshal policy: %w", err)
+	}
+
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
+	f.policy.Store(&policy)
+	f.clearCache()
+
+	f.logger.Infof("Policy updated, version: %s, rules: %d", policy.Version, len(policy.Rules))
+	return nil
+}
+
+// decideAction 按策略决定命中后的处理动作及原因码，没有规则匹配且未设置默认动作时沿用阈值和分类开关的判定
+func decideAction(policy *types.Policy, result *types.FilterResult, reason string) (types.Action, string) {
+	if policy != nil {
+		for _, rule := range policy.Rules {
+			if rule.Matches(result.Categories, result.MaxLevel, result.RiskScore) {
+				if rule.ReasonCode != "" {
+					return rule.Action, rule.ReasonCode
+				}
+				return rule.Action, types.ReasonPolicy
+			}
+		}
+		if policy.Default != "" {
+			return policy.Default, types.ReasonPolicy
+		}
+	}
+
+	return resultAction(result), reason
+}
+
+// resultAction 按通过和人审标记推导处理动作
+func resultAction(result *types.FilterResult) types.Action {
+	switch {
+	case result.Passed:
+		return types.ActionPass
+	case result.NeedsReview:
+		return types.ActionReview
+	default:
+		return types.ActionReject
+	}
+}
+
+// applyAction 按处理动作设置结果的通过和人审标记
+func applyAction(result *types.FilterResult, action types.Action) {
+	result.Action = action
+	switch action {
+	case types.ActionPass, types.ActionMask:
+		result.Passed = true
+		result.NeedsReview = false
+	case types.ActionReview:
+		result.Passed = false
+		result.NeedsReview = true
+	case types.ActionReject:
+		result.Passed = false
+	}
+}
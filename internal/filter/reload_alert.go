@@ -0,0 +1,195 @@
+package filter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/guardian/content-filter/internal/alert"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+const (
+	// defaultReloadFailureThreshold 连续重载失败多少次后告警的默认值
+	defaultReloadFailureThreshold = 3
+	// reloadAlertTimeout 发送词库重载告警的超时
+	reloadAlertTimeout = 10 * time.Second
+)
+
+// reloadAlertState 词库重载告警的状态，每种问题只在出现时告警一次，消除后发送恢复告警
+type reloadAlertState struct {
+	mu       sync.Mutex
+	failures int                  // 连续重载失败次数
+	failing  bool                 // 已发送连续失败告警，尚未恢复
+	stale    bool                 // 已发送词库过期告警，尚未恢复
+	handlers []func(*types.Alert) // OnReloadError 注册的回调
+	notifier alert.Notifier       // 告警发送通道，未配置时为nil
+}
+
+// startReloadAlert 按配置启动词库过期检查，告警发送到回调地址和 alert_notifiers 配置的通道
+func (f *ContentFilter) startReloadAlert() {
+	f.reloadAlert.notifier = f.notifiers
+	if f.config.ReloadAlert.WebhookURL != "" {
+		f.reloadAlert.notifier = alert.Join(alert.NewWebhookNotifier(f.config.ReloadAlert.WebhookURL, f.config.ReloadAlert.Headers), f.notifiers)
+	}
+
+	staleAfter := f.staleAfter()
+	if staleAfter <= 0 {
+		return
+	}
+
+	// 检查间隔取阈值的1/10，最长1分钟
+	interval := staleAfter / 10
+	if interval > time.Minute || interval <= 0 {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case now := <-ticker.C:
+				f.checkStale(now)
+			case <-f.stopChan:
+				return
+			}
+		}
+	}()
+}
+
+// OnReloadError 注册词库重载告警的回调，连续重载失败达到阈值或词库过期时调用，问题消除后以 resolved 级别再次调用
+// 回调在重载或检查协程中同步执行，不应长时间阻塞
+func (f *ContentFilter) OnReloadError(handler func(*types.Alert)) {
+	f.reloadAlert.mu.Lock()
+	defer f.reloadAlert.mu.Unlock()
+	f.reloadAlert.handlers = append(f.reloadAlert.handlers, handler)
+}
+
+// setReloadResult 记录一次重载的结果，连续失败达到阈值时告警，此前已告警的在重载成功后发送恢复告警
+func (f *ContentFilter) setReloadResult(err error) {
+	f.mu.Lock()
+	f.reloadErr = err
+	f.mu.Unlock()
+
+	threshold := f.config.ReloadAlert.FailureThreshold
+	if threshold <= 0 {
+		threshold = defaultReloadFailureThreshold
+	}
+
+	s := &f.reloadAlert
+	s.mu.Lock()
+	var level string
+	failures := s.failures
+	switch {
+	case err != nil:
+		s.failures++
+		failures = s.failures
+		if !s.failing && s.failures >= threshold {
+			s.failing = true
+			level = types.AlertCritical
+		}
+	case s.failing:
+		s.failures = 0
+		s.failing = false
+		level = types.AlertResolved
+	default:
+		s.failures = 0
+	}
+	s.mu.Unlock()
+
+	if level == "" {
+		return
+	}
+
+	a := &types.Alert{
+		Type:    types.AlertReloadFailed,
+		Level:   level,
+		Time:    time.Now(),
+		Version: f.state.Load().version,
+		Details: map[string]interface{}{"failures": failures},
+	}
+	if level == types.AlertResolved {
+		a.Message = fmt.Sprintf("词库重载已恢复，此前连续失败 %d 次", failures)
+		f.logger.Infof("Word database reload recovered after %d consecutive failures, version: %s", failures, a.Version)
+	} else {
+		a.Message = fmt.Sprintf("词库连续重载失败 %d 次: %v", failures, err)
+		a.Details["error"] = err.Error()
+		f.logger.Errorf("Word database reload failed %d times in a row, still serving version: %s", failures, a.Version)
+	}
+	f.sendReloadAlert(a)
+}
+
+// checkStale 检查词库是否超过阈值时长未成功刷新，过期时告警，刷新后发送恢复告警
+func (f *ContentFilter) checkStale(now time.Time) {
+	state := f.state.Load()
+	age := now.Sub(state.loadedAt)
+	stale := age > f.staleAfter()
+
+	s := &f.reloadAlert
+	s.mu.Lock()
+	changed := stale != s.stale
+	s.stale = stale
+	s.mu.Unlock()
+
+	if !changed {
+		return
+	}
+
+	a := &types.Alert{
+		Type:    types.AlertDictionaryStale,
+		Level:   types.AlertCritical,
+		Time:    now,
+		Version: state.version,
+		Details: map[string]interface{}{
+			"loaded_at":   state.loadedAt,
+			"age_seconds": int64(age.Seconds()),
+		},
+	}
+	if stale {
+		a.Message = fmt.Sprintf("词库自 %s 起未成功刷新，已超过 %s", state.loadedAt.Format(time.RFC3339), f.staleAfter())
+		f.logger.Errorf("Word database not refreshed since %s, version: %s", state.loadedAt.Format(time.RFC3339), state.version)
+	} else {
+		a.Level = types.AlertResolved
+		a.Message = "词库已刷新"
+		f.logger.Infof("Word database refreshed, no longer stale, version: %s", state.version)
+	}
+	f.sendReloadAlert(a)
+}
+
+// staleAfter 词库过期告警的阈值，未配置时使用 max_staleness
+func (f *ContentFilter) staleAfter() time.Duration {
+	if f.config.ReloadAlert.StaleAfter > 0 {
+		return f.config.ReloadAlert.StaleAfter
+	}
+	return f.config.MaxStaleness
+}
+
+// sendReloadAlert 调用注册的回调并通过告警回调地址发送词库重载告警
+func (f *ContentFilter) sendReloadAlert(a *types.Alert) {
+	s := &f.reloadAlert
+	s.mu.Lock()
+	handlers := s.handlers
+	notifier := s.notifier
+	s.mu.Unlock()
+
+	for _, handler := range handlers {
+		handler(a)
+	}
+
+	if notifier == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), reloadAlertTimeout)
+	defer cancel()
+	if err := notifier.Notify(ctx, a); err != nil {
+		f.logger.Errorf("Failed to send reload alert: %v", err)
+	}
+}
+
+// reloadFailures 连续重载失败次数
+func (f *ContentFilter) reloadFailures() int {
+	f.reloadAlert.mu.Lock()
+	defer f.reloadAlert.mu.Unlock()
+	return f.reloadAlert.failures
+}
@@ -0,0 +1,37 @@
+package filter
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// 被拒绝的重载不影响正在服务的词库，结果不标记为降级
+func TestRejectedReloadDoesNotDegrade(t *testing.T) {
+	f := newTestFilter(t, &types.WordDatabase{Version: "2.0.0", Blacklist: []types.SensitiveWord{{Word: "违禁词", Level: 3}}})
+
+	stale, err := json.Marshal(&types.WordDatabase{Version: "1.0.0", Blacklist: []types.SensitiveWord{{Word: "旧词", Level: 3}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.source.PublishConfig(f.config.DataId, f.config.Group, string(stale)); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.loadWordDatabase(); !errors.Is(err, ErrStaleVersion) {
+		t.Fatalf("loadWordDatabase() error = %v, want ErrStaleVersion", err)
+	}
+	if f.GetStats().ReloadError == "" {
+		t.Error("ReloadError is empty, want the rejected reload reported")
+	}
+
+	for _, text := range []string{"正常文本", "这里有违禁词"} {
+		if result := f.Filter(text, nil); result.Degraded {
+			t.Errorf("Filter(%q) = %+v, want a result from the serving dictionary", text, result)
+		}
+	}
+	if result := f.Filter("这里有违禁词", nil); result.Passed {
+		t.Errorf("Filter() = %+v, want blocked by the serving dictionary", result)
+	}
+}
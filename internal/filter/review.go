@@ -0,0 +1,99 @@
+package filter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/guardian/content-filter/internal/review"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// startReview 按配置启动人工复核队列
+func (f *ContentFilter) startReview() error {
+	if !f.config.Review.Enabled {
+		return nil
+	}
+
+	queue, err := review.NewQueue(&f.config.Review)
+	if err != nil {
+		return fmt.Errorf("failed to create review queue: %w", err)
+	}
+	f.reviews.Store(review.NewDispatcher(&f.config.Review, queue, f.logger))
+	return nil
+}
+
+// SetReviewQueue 使用自定义复核队列，替换配置中的队列；原队列入队已缓冲的条目后关闭，queue为nil时停止入队
+func (f *ContentFilter) SetReviewQueue(queue review.Queue) {
+	var dispatcher *review.Dispatcher
+	if queue != nil {
+		dispatcher = review.NewDispatcher(&f.config.Review, queue, f.logger)
+	}
+	if previous := f.reviews.Swap(dispatcher); previous != nil {
+		if err := previous.Close(); err != nil {
+			f.logger.Errorf("Failed to close review queue: %v", err)
+		}
+	}
+}
+
+// submitReview 处理动作为review的结果加入复核队列
+func (f *ContentFilter) submitReview(ctx context.Context, text string, result *types.FilterResult) {
+	if dispatcher := f.reviews.Load(); dispatcher != nil && result != nil {
+		dispatcher.Submit(ctx, text, result, f.state.Load().version)
+	}
+}
+
+// reviewQueue 返回当前复核队列，未启用时返回 review.ErrUnsupported
+func (f *ContentFilter) reviewQueue() (review.Queue, error) {
+	dispatcher := f.reviews.Load()
+	if dispatcher == nil {
+		return nil, fmt.Errorf("%w: review queue is not enabled", review.ErrUnsupported)
+	}
+	return dispatcher.Queue(), nil
+}
+
+// PendingReviews 按入队顺序返回最多limit个待复核条目，limit不大于0时返回全部
+func (f *ContentFilter) PendingReviews(ctx context.Context, limit int) ([]*types.ReviewItem, error) {
+	queue, err := f.reviewQueue()
+	if err != nil {
+		return nil, err
+	}
+	return queue.Pending(ctx, limit)
+}
+
+// ReviewItem 返回复核条目，不存在时返回 review.ErrNotFound
+func (f *ContentFilter) ReviewItem(ctx context.Context, id string) (*types.ReviewItem, error) {
+	queue, err := f.reviewQueue()
+	if err != nil {
+		return nil, err
+	}
+	return queue.Get(ctx, id)
+}
+
+// ResolveReview 记录复核结论，Time为空时使用当前时间
+func (f *ContentFilter) ResolveReview(ctx context.Context, decision *types.ReviewDecision) error {
+	if err := review.ValidateDecision(decision); err != nil {
+		return err
+	}
+	queue, err := f.reviewQueue()
+	if err != nil {
+		return err
+	}
+
+	if decision.Time.IsZero() {
+		decision.Time = time.Now()
+	}
+	if err := queue.Resolve(ctx, decision); err != nil {
+		return err
+	}
+	f.logger.Infof("Review item %s %s by %s", decision.ID, decision.Status, decision.Reviewer)
+	return nil
+}
+
+// reviewStats 复核队列入队统计，未启用时返回nil
+func (f *ContentFilter) reviewStats() *types.ReviewStats {
+	if dispatcher := f.reviews.Load(); dispatcher != nil {
+		return dispatcher.Stats()
+	}
+	return nil
+}
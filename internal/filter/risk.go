@@ -0,0 +1,63 @@
+package filter
+
+import (
+	"github.com/guardian/content-filter/internal/algorithm"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// newWeights 构建敏感词的风险权重表，敏感词按与自动机相同的规则标准化
+func (f *ContentFilter) newWeights(weights map[string]float64) map[string]float64 {
+	normalized := make(map[string]float64, len(weights))
+	for word, weight := range weights {
+		normalized[f.normalizer.Normalize(word)] = weight
+	}
+	return normalized
+}
+
+// wordWeight 返回敏感词的风险权重，未设置权重时使用敏感级别
+func wordWeight(state *wordState, output *algorithm.Output) float64 {
+	if weight, ok := state.weights[output.Word]; ok {
+		return weight
+	}
+	return float64(output.Level)
+}
+
+// applyRiskThresholds 按风险分阈值决定命中后的处理并返回原因码：低于审核阈值放行，达到审核阈值转人工审核，达到拒绝阈值拒绝
+// 未配置阈值时任何命中均拒绝；命中被强制人审的分类时至少转人工审核
+func (f *ContentFilter) applyRiskThresholds(result *types.FilterResult) string {
+	thresholds := f.config.RiskThresholds
+	if thresholds.Review <= 0 && thresholds.Reject <= 0 {
+		if result.NeedsReview {
+			return types.ReasonCategoryReview
+		}
+		return types.ReasonMatched
+	}
+
+	switch {
+	case thresholds.Reject > 0 && result.RiskScore >= thresholds.Reject:
+		result.Passed = false
+		return types.ReasonRiskReject
+	case thresholds.Review > 0 && result.RiskScore >= thresholds.Review:
+		result.Passed = false
+		result.NeedsReview = true
+		return types.ReasonRiskReview
+	case result.NeedsReview:
+		result.Passed = false
+		return types.ReasonCategoryReview
+	default:
+		result.Passed = true
+		return types.ReasonRiskPass
+	}
+}
+
+// decide 按风险分阈值决定放行、转人工审核或拒绝，配置了处理策略时以策略为准，并生成原因和提示语
+func (f *ContentFilter) decide(result *types.FilterResult, options *types.FilterOptions) {
+	reason := f.applyRiskThresholds(result)
+	action, reason := decideAction(f.policy.Load(), result, reason)
+	applyAction(result, action)
+	result.ReasonCode = reason
+	result.Reason = f.messages.Reason(options.Locale, reason)
+	if !result.Passed {
+		result.Message = f.messages.Message(options.Locale, result.Categories, result.NeedsReview)
+	}
+}
@@ -0,0 +1,177 @@
+package filter
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/guardian/content-filter/internal/algorithm"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// 长文本抽样默认参数
+const (
+	defaultWindowSize = 4096    // 默认窗口大小（字节）
+	defaultCoverage   = 0.3     // 随机窗口策略的默认覆盖率
+	defaultChunkSize  = 1 << 20 // 全文扫描的默认分块大小（字节）
+)
+
+// scanInfo 本次扫描使用的策略
+type scanInfo struct {
+	strategy string
+	coverage float64
+}
+
+// apply 将扫描策略写入结果，只有超过阈值的长文本才会标注
+func (s scanInfo) apply(result *types.FilterResult) *types.FilterResult {
+	result.ScanStrategy = s.strategy
+	result.ScanCoverage = s.coverage
+	return result
+}
+
+// window 扫描窗口（字节偏移，左闭右开）
+type window struct {
+	start, end int
+}
+
+// scan 搜索敏感词，超过长度阈值的文本按配置的策略抽样扫描
+func (f *ContentFilter) scan(ctx context.Context, state *wordState, text string, options *algorithm.SearchOptions) ([]algorithm.Match, scanInfo, error) {
+	automaton := state.automaton
+	config := f.config.LongText
+	if config.Threshold <= 0 || len(text) <= config.Threshold {
+		matches, err := f.scanChunks(ctx, state, text, options)
+		return matches, scanInfo{}, err
+	}
+
+	windows := sampleWindows(text, config)
+	if windows == nil {
+		matches, err := f.scanChunks(ctx, state, text, options)
+		return matches, scanInfo{strategy: types.ScanFull, coverage: 1}, err
+	}
+
+	matches := make([]algorithm.Match, 0)
+	covered := 0
+	for _, w := range windows {
+		windowMatches, err := automaton.FindAllContext(ctx, text[w.start:w.end], options)
+		if err != nil {
+			return nil, scanInfo{}, err
+		}
+		for _, match := range windowMatches {
+			match.Start += w.start
+			match.End += w.start
+			matches = append(matches, match)
+		}
+		covered += w.end - w.start
+	}
+
+	return matches, scanInfo{
+		strategy: config.Strategy,
+		coverage: float64(covered) / float64(len(text)),
+	}, nil
+}
+
+// scanChunks 全文扫描，超过分块大小的文本分块扫描
+// 相邻块重叠最长命中的长度，跨越块边界的命中只在其结束所在的块中保留
+func (f *ContentFilter) scanChunks(ctx context.Context, state *wordState, text string, options *algorithm.SearchOptions) ([]algorithm.Match, error) {
+	size := f.config.LongText.ChunkSize
+	if size <= 0 {
+		size = defaultChunkSize
+	}
+	overlap := state.maxMatchLen
+	size = max(size, 2*overlap)
+	if len(text) <= size {
+		return state.automaton.FindAllContext(ctx, text, options)
+	}
+
+	matches := make([]algorithm.Match, 0)
+	scanned := 0 // 已扫描到的位置，结束位置不超过它的命中已在上一块中保留
+	for start := 0; start < len(text); {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		end := alignRuneStart(text, min(start+size, len(text)))
+		chunkMatches, err := state.automaton.FindAllContext(ctx, text[start:end], options)
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range chunkMatches {
+			match.Start += start
+			match.End += start
+			if match.End > scanned {
+				matches = append(matches, match)
+			}
+		}
+		scanned = end
+
+		if end == len(text) {
+			break
+		}
+		start = alignRuneStart(text, end-overlap)
+	}
+
+	return matches, nil
+}
+
+// sampleWindows 按策略选择扫描窗口，返回nil表示需要全文扫描
+func sampleWindows(text string, config types.LongTextConfig) []window {
+	size := config.WindowSize
+	if size <= 0 {
+		size = defaultWindowSize
+	}
+
+	var windows []window
+	switch config.Strategy {
+	case types.ScanHeadTail:
+		if 2*size >= len(text) {
+			return nil
+		}
+		windows = []window{{0, size}, {len(text) - size, len(text)}}
+
+	case types.ScanRandom:
+		coverage := config.Coverage
+		if coverage <= 0 || coverage > 1 {
+			coverage = defaultCoverage
+		}
+
+		slots := (len(text) + size - 1) / size
+		count := int(float64(slots)*coverage + 0.5)
+		if count < 1 {
+			count = 1
+		}
+		if count >= slots {
+			return nil
+		}
+
+		picked := rand.Perm(slots)[:count]
+		sort.Ints(picked)
+		windows = make([]window, 0, count)
+		for _, slot := range picked {
+			end := (slot + 1) * size
+			if end > len(text) {
+				end = len(text)
+			}
+			windows = append(windows, window{slot * size, end})
+		}
+
+	default:
+		return nil
+	}
+
+	// 窗口边界对齐到字符起始位置
+	for i := range windows {
+		windows[i].start = alignRuneStart(text, windows[i].start)
+		windows[i].end = alignRuneStart(text, windows[i].end)
+	}
+
+	return windows
+}
+
+// alignRuneStart 将偏移向后移动到最近的UTF-8字符起始位置
+func alignRuneStart(text string, offset int) int {
+	for offset < len(text) && !utf8.RuneStart(text[offset]) {
+		offset++
+	}
+	return offset
+}
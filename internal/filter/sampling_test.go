@@ -0,0 +1,120 @@
+package filter
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/guardian/content-filter/internal/algorithm"
+	"github.com/guardian/content-filter/internal/logging"
+	"github.com/guardian/content-filter/internal/source"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// newLongTextFilter 创建使用指定超长文本配置的过滤器，words为3级敏感词
+func newLongTextFilter(t *testing.T, longText types.LongTextConfig, words ...string) *ContentFilter {
+	t.Helper()
+
+	wordDB := &types.WordDatabase{Version: "1.0.0"}
+	for _, word := range words {
+		wordDB.Blacklist = append(wordDB.Blacklist, types.SensitiveWord{Word: word, Level: 3})
+	}
+	content, err := json.Marshal(wordDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &types.FilterConfig{DataId: "words", Group: "test", LongText: longText}
+	src := source.NewMemory()
+	if err := src.PublishConfig(config.DataId, config.Group, string(content)); err != nil {
+		t.Fatal(err)
+	}
+	f, err := NewContentFilter(src, config, logging.Discard())
+	if err != nil {
+		t.Fatalf("NewContentFilter() error = %v", err)
+	}
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+// sortMatches 按位置排序命中，便于比较
+func sortMatches(matches []algorithm.Match) []algorithm.Match {
+	slices.SortFunc(matches, func(a, b algorithm.Match) int {
+		if a.Start != b.Start {
+			return a.Start - b.Start
+		}
+		return a.End - b.End
+	})
+	return matches
+}
+
+// 分块扫描与整段扫描的结果一致：跨越块边界的命中只保留一次，位置为在全文中的偏移
+func TestScanChunks(t *testing.T) {
+	f := newLongTextFilter(t, types.LongTextConfig{ChunkSize: 16}, "违禁词", "bad")
+	state := f.state.Load()
+	size := max(f.config.LongText.ChunkSize, 2*state.maxMatchLen)
+
+	// 逐字节移动起始位置，命中依次落在块边界的每个位置上
+	for padding := 0; padding <= 2*size; padding++ {
+		text := strings.Repeat("x", padding) + "违禁词bad" + strings.Repeat("好", 5) + "违禁词" + strings.Repeat("x", size)
+		got, err := f.scanChunks(context.Background(), state, text, &algorithm.SearchOptions{})
+		if err != nil {
+			t.Fatalf("scanChunks() error = %v", err)
+		}
+		want := state.automaton.FindAll(text, &algorithm.SearchOptions{})
+		if len(got) != len(want) {
+			t.Fatalf("padding %d: scanChunks() = %d matches, want %d", padding, len(got), len(want))
+		}
+		sortMatches(got)
+		sortMatches(want)
+		for i := range want {
+			if got[i].Start != want[i].Start || got[i].End != want[i].End || got[i].Word != want[i].Word {
+				t.Errorf("padding %d: match %d = %q [%d, %d), want %q [%d, %d)",
+					padding, i, got[i].Word, got[i].Start, got[i].End, want[i].Word, want[i].Start, want[i].End)
+			}
+		}
+	}
+}
+
+// 抽样窗口对齐到字符起始位置，窗口内的命中位置为在全文中的偏移
+func TestScanSampledWindows(t *testing.T) {
+	const word = "违禁词"
+	longText := types.LongTextConfig{Threshold: 100, Strategy: types.ScanHeadTail, WindowSize: 64}
+	f := newLongTextFilter(t, longText, word)
+
+	text := word + strings.Repeat("好", 40) + word + strings.Repeat("好", 40) + word + strings.Repeat("好", 10)
+	windows := sampleWindows(text, longText)
+	if len(windows) != 2 {
+		t.Fatalf("sampleWindows() = %v, want head and tail windows", windows)
+	}
+	for _, w := range windows {
+		for _, offset := range []int{w.start, w.end} {
+			if offset < len(text) && !utf8.RuneStart(text[offset]) {
+				t.Errorf("window %v is not aligned to rune starts", w)
+			}
+		}
+	}
+
+	matches, info, err := f.scan(context.Background(), f.state.Load(), text, &algorithm.SearchOptions{})
+	if err != nil {
+		t.Fatalf("scan() error = %v", err)
+	}
+	if info.strategy != types.ScanHeadTail || info.coverage <= 0 || info.coverage >= 1 {
+		t.Errorf("scan() info = %+v, want partial head_tail coverage", info)
+	}
+
+	// 中间的命中不在窗口内
+	want := []int{0, strings.LastIndex(text, word)}
+	got := make([]int, 0, len(matches))
+	for _, match := range sortMatches(matches) {
+		if text[match.Start:match.End] != word {
+			t.Errorf("match [%d, %d) covers %q, want %q", match.Start, match.End, text[match.Start:match.End], word)
+		}
+		got = append(got, match.Start)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("scan() match starts = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,50 @@
+package filter
+
+import (
+	"time"
+)
+
+// scheduleWordChange 在下一个敏感词生效或失效时间到达时按同一词库重建快照，替换之前的定时器
+// 调用方需持有 buildMu
+func (f *ContentFilter) scheduleWordChange(state *wordState) {
+	if f.scheduleTimer != nil {
+		f.scheduleTimer.Stop()
+		f.scheduleTimer = nil
+	}
+	if state.nextChange.IsZero() || state.wordDB == nil {
+		return
+	}
+
+	f.scheduleTimer = time.AfterFunc(time.Until(state.nextChange), func() {
+		f.applyScheduledChange(state)
+	})
+	f.logger.Debugf("Next scheduled word change at %s", state.nextChange.Format(time.RFC3339))
+}
+
+// applyScheduledChange 定时重建快照，使到期的敏感词生效或失效；快照已被新版本替换时不做处理
+func (f *ContentFilter) applyScheduledChange(previous *wordState) {
+	f.buildMu.Lock()
+	defer f.buildMu.Unlock()
+
+	select {
+	case <-f.stopChan:
+		return
+	default:
+	}
+	if f.state.Load() != previous {
+		return
+	}
+
+	state, _, err := f.buildState(previous.wordDB, false)
+	if err != nil {
+		f.logger.Errorf("Failed to apply scheduled word change: %v", err)
+		return
+	}
+	old := f.reloadHookStats()
+	f.swapState(state)
+	f.scheduleWordChange(state)
+
+	f.logger.Infof("Scheduled word change applied, version: %s, active words: %d -> %d",
+		state.version, previous.wordCount, state.wordCount)
+	f.runReloadHooks(old)
+}
@@ -0,0 +1,81 @@
+package filter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// saveServingSnapshot 词库正在服务时写入快照，灰度中的词库在全量生效时才写入
+func (f *ContentFilter) saveServingSnapshot(wordDB *types.WordDatabase) {
+	if f.state.Load().wordDB == wordDB {
+		f.saveSnapshot(wordDB)
+	}
+}
+
+// saveSnapshot 将成功加载的词库原子写入快照文件，写入失败只记录日志
+func (f *ContentFilter) saveSnapshot(wordDB *types.WordDatabase) {
+	if f.config.SnapshotPath == "" {
+		return
+	}
+
+	if err := writeSnapshot(f.config.SnapshotPath, wordDB); err != nil {
+		f.logger.Errorf("Failed to save word database snapshot: %v", err)
+		return
+	}
+	f.logger.Debugf("Word database snapshot saved, version: %s, path: %s", wordDB.Version, f.config.SnapshotPath)
+}
+
+// loadSnapshot 从快照文件恢复词库
+func (f *ContentFilter) loadSnapshot() error {
+	data, err := os.ReadFile(f.config.SnapshotPath)
+	if err != nil {
+		return fmt.Errorf("failed to read word database snapshot: %w", err)
+	}
+
+	var wordDB types.WordDatabase
+	if err := json.Unmarshal(data, &wordDB); err != nil {
+		return fmt.Errorf("%w: failed to unmarshal word database snapshot: %w", types.ErrInvalidWordDB, err)
+	}
+
+	if err := f.updateWordDatabase(&wordDB, false); err != nil {
+		return err
+	}
+
+	f.logger.Infof("Word database restored from snapshot, version: %s, update time: %v", wordDB.Version, wordDB.UpdateTime)
+	return nil
+}
+
+// writeSnapshot 先写临时文件再重命名，避免进程中断留下不完整的快照
+func writeSnapshot(path string, wordDB *types.WordDatabase) error {
+	data, err := json.Marshal(wordDB)
+	if err != nil {
+		return fmt.Errorf("failed to marshal word database: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package filter
+
+import (
+	"hash/fnv"
+	"time"
+
+	"github.com/guardian/content-filter/internal/algorithm"
+	"github.com/guardian/content-filter/internal/artifact"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// wordState 词库快照
+// 自动机、白名单和版本信息在后台作为一个整体构建，完成后一次性替换正在服务的快照
+type wordState struct {
+	automaton    algorithm.Matcher
+	firstChars   *algorithm.CharSet  // 敏感词首字符集合，用于跳过不可能命中的文本
+	maxMatchLen  int                 // 一次命中可能跨越的最大字节数，用于分块扫描
+	whitelist    map[string]bool     // 白名单条目，短语和通配符按与自动机相同的规则标准化
+	allow        *allowList          // 编译后的白名单，用于查找命中所在的白名单短语
+	replacements map[string]string   // 敏感词 -> 替换词
+	wholeWords   map[string]bool     // 只匹配完整单词的敏感词
+	exclusions   map[string][]string // 敏感词 -> 排除语境
+	weights      map[string]float64  // 敏感词 -> 风险权重，未设置的使用敏感级别
+	monitorWords map[string]bool     // 只观察的敏感词
+	wordDB       *types.WordDatabase // 构建该快照的词库，用于运行时增删敏感词；从产物加载时由产物元数据组装
+	version      string
+	lastUpdate   time.Time // 词库自身的更新时间
+	wordCount    int       // 敏感词数量
+	loadedAt     time.Time // 快照生效时间
+	nextChange   time.Time // 下一个敏感词定时生效或失效的时间，没有时为零值
+	lastChange   time.Time // 最近一次敏感词定时生效或失效的时间，同一版本定时重建后改变，没有时为零值
+	whitelistGen uint64    // 白名单内容的指纹，白名单修改后改变，白名单相同的实例取值相同
+	hits         *wordHits // 该快照生效以来各敏感词的命中次数
+}
+
+// emptyWordState 创建空快照
+func emptyWordState() *wordState {
+	automaton := algorithm.NewACAutomaton()
+	return &wordState{
+		automaton:    automaton,
+		firstChars:   automaton.FirstChars(),
+		whitelist:    make(map[string]bool),
+		replacements: make(map[string]string),
+		wholeWords:   make(map[string]bool),
+		wordDB:       &types.WordDatabase{},
+		hits:         &wordHits{},
+	}
+}
+
+// buildAutomaton 按配置的布局构建自动机，紧凑布局直接构建连续数组，内存预算按该布局计算
+func (f *ContentFilter) buildAutomaton(words []algorithm.WordEntry, version string, options *algorithm.BuildOptions) (algorithm.Automaton, error) {
+	if f.config.AutomatonLayout == types.LayoutFlat {
+		fa, err := algorithm.BuildFlat(words, options)
+		if err != nil {
+			return nil, err
+		}
+		fa.SetVersion(version)
+		return fa, nil
+	}
+
+	ac, err := algorithm.Build(words, options)
+	if err != nil {
+		return nil, err
+	}
+	ac.SetVersion(version)
+	return ac, nil
+}
+
+// loadArtifactFile 加载产物或自动机缓存文件，自动机直接恢复为配置的布局
+func (f *ContentFilter) loadArtifactFile(path string) (*artifact.Artifact, error) {
+	if f.config.AutomatonLayout == types.LayoutFlat {
+		return artifact.LoadFlat(path)
+	}
+	return artifact.Load(path)
+}
+
+// newWordSet 构建敏感词集合（如只匹配完整单词、只观察的敏感词），敏感词按与自动机相同的规则标准化
+func (f *ContentFilter) newWordSet(words []string) map[string]bool {
+	wholeWords := make(map[string]bool, len(words))
+	for _, word := range words {
+		wholeWords[f.normalizer.Normalize(word)] = true
+	}
+	return wholeWords
+}
+
+// wholeWordOnly 检查命中的敏感词是否要求整词匹配
+func (f *ContentFilter) wholeWordOnly(state *wordState, output *algorithm.Output) bool {
+	if state.wholeWords[output.Word] {
+		return true
+	}
+	for _, category := range output.Categories {
+		for _, wholeWordCategory := range f.config.WholeWordCategories {
+			if category == wholeWordCategory {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// updateWhitelist 复制白名单修改后替换快照，不修改正在被读取的快照
+func (f *ContentFilter) updateWhitelist(update func(whitelist map[string]bool)) {
+	for {
+		current := f.state.Load()
+
+		next := *current
+		next.whitelist = make(map[string]bool, len(current.whitelist)+1)
+		for word := range current.whitelist {
+			next.whitelist[word] = true
+		}
+		update(next.whitelist)
+		next.allow = f.newAllowList(next.whitelist)
+		next.whitelistGen = whitelistFingerprint(next.whitelist)
+
+		if f.state.CompareAndSwap(current, &next) {
+			return
+		}
+	}
+}
+
+// whitelistFingerprint 白名单内容的指纹，与条目顺序无关，用于缓存键
+func whitelistFingerprint(whitelist map[string]bool) uint64 {
+	var sum uint64
+	for word := range whitelist {
+		h := fnv.New64a()
+		h.Write([]byte(word))
+		sum += h.Sum64()
+	}
+	return sum
+}
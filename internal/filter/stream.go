@@ -0,0 +1,119 @@
+package filter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// 流式扫描参数
+const (
+	streamChunkSize     = 64 << 10 // 每次读取的字节数
+	streamOverlapFactor = 4        // 块间重叠为最长命中的倍数，覆盖重复字符折叠、全角等标准化后变短的写法
+	minStreamOverlap    = 256      // 块间最小重叠字节数
+)
+
+// FilterReader 分块扫描任意大小的输入，每发现一次命中调用一次onMatch
+// 相邻块之间保留重叠区域，跨越块边界的命中只会在其结束所在的块中报告一次；onMatch返回错误时停止扫描并返回该错误
+func (f *ContentFilter) FilterReader(ctx context.Context, r io.Reader, options *types.FilterOptions, onMatch func(types.StreamMatch) error) error {
+	state := f.state.Load()
+	overlap := max(state.maxMatchLen*streamOverlapFactor, minStreamOverlap)
+
+	// 按块扫描时不打码，避免对每个窗口重复生成替换文本
+	scanOptions := f.resolveOptions(options)
+	scanOptions.ReplaceMode = false
+
+	// 超长文本阈值会使窗口被抽样扫描，窗口大小不超过阈值以保证全文扫描
+	windowSize := streamChunkSize + overlap
+	if threshold := f.config.LongText.Threshold; threshold > 0 && threshold < windowSize {
+		windowSize = max(threshold, 2*overlap)
+	}
+
+	buf := make([]byte, windowSize)
+	var (
+		carry    int   // 缓冲区开头来自上一窗口的字节数
+		base     int64 // 缓冲区开头在输入中的偏移
+		reported int64 // 已报告到的位置，结束位置不超过它的命中已在上一窗口报告
+		eof      bool
+	)
+
+	for !eof {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		n, err := io.ReadFull(r, buf[carry:])
+		switch {
+		case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+			eof = true
+		case err != nil:
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+
+		// 窗口在完整字符处截断，不完整的字符留到下一窗口
+		size := carry + n
+		end := size
+		if !eof {
+			end = lastRuneBoundary(buf[:size])
+		}
+
+		result, err := f.filterState(ctx, state, string(buf[:end]), scanOptions)
+		if err != nil {
+			return err
+		}
+
+		for _, match := range result.Matches {
+			for _, position := range match.Positions {
+				streamMatch := types.StreamMatch{
+					Word:       match.Word,
+					Categories: match.Categories,
+					Level:      match.Level,
+					Start:      base + int64(position.Start),
+					End:        base + int64(position.End),
+				}
+				if streamMatch.End <= reported {
+					continue
+				}
+				if err := onMatch(streamMatch); err != nil {
+					return err
+				}
+			}
+		}
+		reported = base + int64(end)
+
+		// 保留窗口末尾的重叠区域和未读完的字符
+		keep := 0
+		if end > overlap {
+			keep = nextRuneBoundary(buf[:end], end-overlap)
+		}
+		carry = copy(buf, buf[keep:size])
+		base += int64(keep)
+	}
+
+	return nil
+}
+
+// lastRuneBoundary 返回最后一个完整字符之后的位置
+func lastRuneBoundary(b []byte) int {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(b[i]) {
+			if utf8.FullRune(b[i:]) {
+				return len(b)
+			}
+			return i
+		}
+	}
+	return len(b)
+}
+
+// nextRuneBoundary 返回不早于i的第一个字符起始位置
+func nextRuneBoundary(b []byte, i int) int {
+	for i < len(b) && !utf8.RuneStart(b[i]) {
+		i++
+	}
+	return i
+}
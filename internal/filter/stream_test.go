@@ -0,0 +1,69 @@
+package filter
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// newStreamFilter 创建窗口最小的过滤器：超长文本阈值使 FilterReader 按 2*minStreamOverlap 字节的窗口扫描
+func newStreamFilter(t *testing.T, words ...string) *ContentFilter {
+	t.Helper()
+	return newLongTextFilter(t, types.LongTextConfig{Threshold: 1, Strategy: types.ScanFull}, words...)
+}
+
+// 跨越窗口边界的命中只报告一次，位置为在整个输入中的偏移，按位置打码与整段替换一致
+func TestFilterReaderWindowBoundaries(t *testing.T) {
+	const word = "违禁词"
+	window := 2 * minStreamOverlap
+	f := newStreamFilter(t, word)
+
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"word split across windows", strings.Repeat("x", window-2) + word},
+		{"rune split across windows", strings.Repeat("x", window-1) + word + "x"},
+		{"word in window overlap", strings.Repeat("x", window-minStreamOverlap+10) + word + strings.Repeat("x", window)},
+		{"multi-byte padding", strings.Repeat("好", window/3) + word + strings.Repeat("好", window/3)},
+		{"words in many windows", strings.Repeat(strings.Repeat("x", window/2-1)+word, 6)},
+		{"word at both ends", word + strings.Repeat("x", 3*window) + word},
+	}
+	for _, tt := range tests {
+		readers := []struct {
+			name string
+			new  func() io.Reader
+		}{
+			{"whole", func() io.Reader { return strings.NewReader(tt.text) }},
+			{"one byte", func() io.Reader { return iotest.OneByteReader(strings.NewReader(tt.text)) }},
+		}
+		for _, reader := range readers {
+			t.Run(tt.name+"/"+reader.name, func(t *testing.T) {
+				masked := []byte(tt.text)
+				count := 0
+				err := f.FilterReader(context.Background(), reader.new(), nil, func(match types.StreamMatch) error {
+					if match.Word != word || tt.text[match.Start:match.End] != word {
+						t.Errorf("match %+v covers %q, want %q", match, tt.text[match.Start:match.End], word)
+						return nil
+					}
+					copy(masked[match.Start:match.End], strings.Repeat("*", len(word)))
+					count++
+					return nil
+				})
+				if err != nil {
+					t.Fatalf("FilterReader() error = %v", err)
+				}
+				if want := strings.Count(tt.text, word); count != want {
+					t.Errorf("FilterReader() reported %d matches, want %d", count, want)
+				}
+				if want := strings.ReplaceAll(tt.text, word, strings.Repeat("*", len(word))); string(masked) != want {
+					t.Errorf("masked text differs from the whole-text replacement")
+				}
+			})
+		}
+	}
+}
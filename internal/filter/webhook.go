@@ -0,0 +1,28 @@
+package filter
+
+import (
+	"context"
+
+	"github.com/guardian/content-filter/internal/types"
+	"github.com/guardian/content-filter/internal/webhook"
+)
+
+// startWebhooks 按配置启动违规回调推送
+func (f *ContentFilter) startWebhooks() {
+	f.webhooks = webhook.New(f.config.ViolationWebhooks, f.logger)
+}
+
+// notifyViolation 未通过的结果按各回调的级别和分类条件推送违规事件
+func (f *ContentFilter) notifyViolation(ctx context.Context, text string, result *types.FilterResult, options *types.FilterOptions) {
+	if f.webhooks != nil {
+		f.webhooks.Submit(ctx, text, result, options, f.state.Load().version)
+	}
+}
+
+// webhookStats 违规回调推送统计，未配置时返回nil
+func (f *ContentFilter) webhookStats() *types.WebhookStats {
+	if f.webhooks == nil {
+		return nil
+	}
+	return f.webhooks.Stats()
+}
@@ -0,0 +1,112 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/guardian/content-filter/internal/algorithm"
+)
+
+// 白名单条目语法
+const (
+	whitelistRegexPrefix = "re:" // 正则条目前缀，如 "re:第\d+人民医院"
+	whitelistWildcard    = "*"   // 通配符，如 "陕西省*人民医院"
+	wildcardMaxChars     = 16    // 通配符最多匹配的非空白字符数，避免一个条目豁免整段文本
+)
+
+// allowList 编译后的白名单
+type allowList struct {
+	phrases  *algorithm.ACAutomaton // 普通短语
+	patterns []*regexp.Regexp       // 通配符和正则条目
+}
+
+// whitelistKey 返回白名单条目的存储形式：正则条目保持原样，短语和通配符按与自动机相同的规则标准化
+func (f *ContentFilter) whitelistKey(entry string) string {
+	if strings.HasPrefix(entry, whitelistRegexPrefix) {
+		return entry
+	}
+	return f.normalizer.Normalize(entry)
+}
+
+// newWhitelist 构建白名单
+func (f *ContentFilter) newWhitelist(entries []string) map[string]bool {
+	whitelist := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		if key := f.whitelistKey(entry); key != "" {
+			whitelist[key] = true
+		}
+	}
+	return whitelist
+}
+
+// newAllowList 编译白名单，无效的正则条目记录警告后忽略
+func (f *ContentFilter) newAllowList(whitelist map[string]bool) *allowList {
+	list := &allowList{}
+	var phrases []string
+	for entry := range whitelist {
+		pattern, err := compileWhitelistEntry(entry)
+		switch {
+		case err != nil:
+			f.logger.Warnf("Ignoring invalid whitelist entry %q: %v", entry, err)
+		case pattern != nil:
+			list.patterns = append(list.patterns, pattern)
+		default:
+			phrases = append(phrases, entry)
+		}
+	}
+
+	if len(phrases) > 0 {
+		list.phrases = algorithm.NewACAutomaton()
+		for _, phrase := range phrases {
+			list.phrases.AddWord(phrase, nil, 0)
+		}
+		list.phrases.BuildFailPointers()
+	}
+	return list
+}
+
+// compileWhitelistEntry 将正则和通配符条目编译为正则表达式，普通短语返回nil
+func compileWhitelistEntry(entry string) (*regexp.Regexp, error) {
+	if expr, ok := strings.CutPrefix(entry, whitelistRegexPrefix); ok {
+		// 文本已做大小写折叠，正则按不区分大小写匹配
+		return regexp.Compile("(?i)" + expr)
+	}
+	if !strings.Contains(entry, whitelistWildcard) {
+		return nil, nil
+	}
+
+	parts := strings.Split(entry, whitelistWildcard)
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return regexp.Compile(strings.Join(parts, fmt.Sprintf(`\S{0,%d}?`, wildcardMaxChars)))
+}
+
+// spans 查找文本中出现的白名单短语
+func (a *allowList) spans(text string) []algorithm.Match {
+	if a == nil {
+		return nil
+	}
+
+	var spans []algorithm.Match
+	if a.phrases != nil {
+		spans = a.phrases.FindAll(text, &algorithm.SearchOptions{})
+	}
+	for _, pattern := range a.patterns {
+		for _, loc := range pattern.FindAllStringIndex(text, -1) {
+			spans = append(spans, algorithm.Match{Start: loc[0], End: loc[1]})
+		}
+	}
+	return spans
+}
+
+// covers 检查命中是否完整落在某个白名单短语内，如白名单 "大保健品牌" 豁免其中的 "保健"，但不豁免同一文本中其他位置的命中
+func covers(spans []algorithm.Match, match algorithm.Match) bool {
+	for _, span := range spans {
+		if span.Start <= match.Start && match.End <= span.End {
+			return true
+		}
+	}
+	return false
+}
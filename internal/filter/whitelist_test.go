@@ -0,0 +1,59 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/guardian/content-filter/internal/algorithm"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// 只有完整落在白名单短语内的命中被豁免，与短语部分重叠、包含短语或与短语相邻的命中不豁免
+func TestAllowListCovers(t *testing.T) {
+	f := newTestFilter(t, &types.WordDatabase{Version: "1.0.0"})
+
+	tests := []struct {
+		name      string
+		whitelist string
+		text      string
+		hit       string // 命中的敏感词，取文本中最后一次出现的位置
+		want      bool
+	}{
+		{"term contains hit", "大保健品牌", "大保健品牌很好", "保健", true},
+		{"term equals hit", "保健", "大保健", "保健", true},
+		{"term overlaps hit", "保健品", "大保健品", "大保健", false},
+		{"hit contains term", "保健", "大保健", "大保健", false},
+		{"term adjacent to hit", "品牌", "保健品牌", "保健", false},
+		{"term elsewhere in text", "大保健品牌", "大保健品牌和保健", "保健", false},
+		{"wildcard contains hit", "陕西省*人民医院", "陕西省第二人民医院", "人民", true},
+		{"wildcard adjacent to hit", "陕西省*医院", "陕西省医院人民", "人民", false},
+		{"regex contains hit", `re:第\d+人民医院`, "第3人民医院", "人民", true},
+		{"regex overlaps hit", `re:第\d+人民`, "第3人民医院", "人民医院", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allow := f.newAllowList(f.newWhitelist([]string{tt.whitelist}))
+			start := strings.LastIndex(tt.text, tt.hit)
+			match := algorithm.Match{Start: start, End: start + len(tt.hit)}
+
+			spans := allow.spans(tt.text)
+			if len(spans) == 0 {
+				t.Fatalf("spans(%q) found no whitelist term %q", tt.text, tt.whitelist)
+			}
+			if got := covers(spans, match); got != tt.want {
+				t.Errorf("covers(%v, %q) = %v, want %v", spans, tt.hit, got, tt.want)
+			}
+		})
+	}
+}
+
+// 白名单为空时没有豁免的范围
+func TestAllowListEmpty(t *testing.T) {
+	var allow *allowList
+	if spans := allow.spans("大保健"); spans != nil {
+		t.Errorf("spans() = %v, want nil", spans)
+	}
+	if covers(nil, algorithm.Match{Start: 0, End: 3}) {
+		t.Error("covers() = true, want false without whitelist terms")
+	}
+}
@@ -0,0 +1,101 @@
+package filter
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/guardian/content-filter/internal/types"
+	"github.com/guardian/content-filter/internal/wordlist"
+)
+
+// embeddedWordDatabase 内置默认词库，所有来源都不可用时兜底
+//
+//go:embed defaults/sensitive_words.json
+var embeddedWordDatabase []byte
+
+// loadInitialWordDatabase 按 word_sources 顺序加载初始词库，首个成功的来源生效
+// 回退到非配置源时记录配置源的错误，结果标记为降级，后续重载继续尝试配置源
+func (f *ContentFilter) loadInitialWordDatabase() error {
+	sources := f.config.WordSources
+	if len(sources) == 0 {
+		if f.config.SnapshotPath == "" {
+			return f.loadWordDatabase()
+		}
+		// 配置了快照时默认回退到快照
+		sources = []types.WordSource{{Type: types.WordSourceRemote}, {Type: types.WordSourceSnapshot}}
+	}
+
+	var (
+		remoteErr error
+		errs      []error
+	)
+	for _, src := range sources {
+		var err error
+		switch src.Type {
+		case types.WordSourceRemote:
+			err = f.fetchWordDatabase()
+			remoteErr = err
+		case types.WordSourceFile:
+			err = f.loadWordFile(src.Path)
+		case types.WordSourceSnapshot:
+			err = f.loadSnapshot()
+		case types.WordSourceEmbedded:
+			err = f.loadEmbeddedWordDatabase()
+		default:
+			err = fmt.Errorf("unsupported word source type: %s", src.Type)
+		}
+
+		if err == nil {
+			f.setReloadResult(remoteErr)
+
+			if src.Type != types.WordSourceRemote {
+				f.logger.Warnf("Word database loaded from fallback source %s, remote error: %v", src.Type, remoteErr)
+			}
+			return nil
+		}
+
+		f.logger.Warnf("Failed to load word database from %s: %v", src.Type, err)
+		errs = append(errs, fmt.Errorf("%s: %w", src.Type, err))
+	}
+
+	err := fmt.Errorf("all word sources failed: %w", errors.Join(errs...))
+	f.setReloadResult(err)
+	return err
+}
+
+// loadWordFile 从本地词库文件加载词库，格式按文件名和内容推断
+func (f *ContentFilter) loadWordFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read word file: %w", err)
+	}
+
+	wordDB, err := wordlist.Parse(bytes.NewReader(data), wordlist.DetectFormat(path, data), nil)
+	if err != nil {
+		return fmt.Errorf("%w: failed to parse word file %s: %w", types.ErrInvalidWordDB, path, err)
+	}
+
+	return f.updateWordDatabase(wordDB, false)
+}
+
+// loadEmbeddedWordDatabase 加载内置默认词库
+func (f *ContentFilter) loadEmbeddedWordDatabase() error {
+	wordDB, err := EmbeddedWordDatabase()
+	if err != nil {
+		return err
+	}
+
+	return f.updateWordDatabase(wordDB, false)
+}
+
+// EmbeddedWordDatabase 返回内置默认词库的副本
+func EmbeddedWordDatabase() (*types.WordDatabase, error) {
+	wordDB, err := wordlist.Parse(bytes.NewReader(embeddedWordDatabase), wordlist.FormatJSON, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to parse embedded word database: %w", types.ErrInvalidWordDB, err)
+	}
+	return wordDB, nil
+}
@@ -0,0 +1,192 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// defaultRebuildDebounce 运行时增删敏感词后默认等待的时间
+const defaultRebuildDebounce = time.Second
+
+// localVersionSuffix 运行时修改但未写回词库源的词库版本后缀，避免与词库源的版本及其自动机缓存混淆
+const localVersionSuffix = "-local"
+
+// ErrWordNotFound 要移除的敏感词不存在
+var ErrWordNotFound = errors.New("sensitive word not found")
+
+// wordEdit 一次运行时敏感词修改，返回词库是否发生变化
+type wordEdit func(wordDB *types.WordDatabase) bool
+
+// AddSensitiveWord 添加敏感词，已存在的同名敏感词（按标准化后的形式比较）被替换
+// 修改在防抖时间后合并重建自动机；开启 persist_words 时同时写回词库源，写回失败返回错误
+func (f *ContentFilter) AddSensitiveWord(word types.SensitiveWord) error {
+	if strings.TrimSpace(word.Word) == "" {
+		return errors.New("invalid sensitive word: word must not be empty")
+	}
+	if word.Level <= 0 {
+		return fmt.Errorf("invalid sensitive word %q: level must be positive", word.Word)
+	}
+
+	edit := f.upsertWord(word)
+	if err := f.editWords(edit); err != nil {
+		return err
+	}
+	return f.persistWords(edit)
+}
+
+// RemoveSensitiveWord 从黑名单和所有分类中移除敏感词，敏感词不存在时返回 ErrWordNotFound
+// 修改在防抖时间后合并重建自动机；开启 persist_words 时同时写回词库源，写回失败返回错误
+func (f *ContentFilter) RemoveSensitiveWord(word string) error {
+	edit := f.removeWord(word)
+	if wordDB := f.currentWords(); wordDB == nil || !edit(wordDB) {
+		return fmt.Errorf("%w: %s", ErrWordNotFound, word)
+	}
+
+	if err := f.editWords(edit); err != nil {
+		return err
+	}
+	return f.persistWords(edit)
+}
+
+// ListWords 返回当前生效的全部敏感词，包括尚未重建的运行时修改
+func (f *ContentFilter) ListWords() []types.SensitiveWord {
+	wordDB := f.currentWords()
+	if wordDB == nil {
+		return []types.SensitiveWord{}
+	}
+	return wordDB.Words()
+}
+
+// currentWords 返回应用了待重建修改的词库副本，从产物加载时返回nil
+func (f *ContentFilter) currentWords() *types.WordDatabase {
+	base := f.state.Load().wordDB
+	if base == nil {
+		return nil
+	}
+
+	f.editMu.Lock()
+	defer f.editMu.Unlock()
+
+	wordDB := base.Clone()
+	for _, edit := range f.pendingEdits {
+		edit(wordDB)
+	}
+	return wordDB
+}
+
+// editWords 记录运行时修改并安排防抖重建，防抖期间的多次修改只重建一次
+func (f *ContentFilter) editWords(edit wordEdit) error {
+	if f.config.ArtifactPath != "" {
+		return errors.New("runtime word changes are not supported when loading from an artifact")
+	}
+
+	debounce := f.config.RebuildDebounce
+	if debounce <= 0 {
+		debounce = defaultRebuildDebounce
+	}
+
+	f.editMu.Lock()
+	defer f.editMu.Unlock()
+
+	f.pendingEdits = append(f.pendingEdits, edit)
+	if f.rebuildTimer == nil {
+		f.rebuildTimer = time.AfterFunc(debounce, f.flushWordEdits)
+	}
+	return nil
+}
+
+// flushWordEdits 将待重建的修改应用到当前词库并交给后台构建
+// 修改基于重建时的最新词库重新应用，防抖期间词库源推送的新版本不会被覆盖
+func (f *ContentFilter) flushWordEdits() {
+	f.editMu.Lock()
+	edits := f.pendingEdits
+	f.pendingEdits = nil
+	f.rebuildTimer = nil
+	f.editMu.Unlock()
+
+	base := f.state.Load().wordDB
+	if base == nil || len(edits) == 0 {
+		return
+	}
+
+	wordDB := base.Clone()
+	changed := false
+	for _, edit := range edits {
+		changed = edit(wordDB) || changed
+	}
+	if !changed {
+		return
+	}
+
+	wordDB.Version = localVersion(base.Version)
+	wordDB.UpdateTime = time.Now()
+	f.logger.Infof("Rebuilding automaton for %d runtime word change(s), version: %s", len(edits), wordDB.Version)
+	f.enqueueUpdate(wordDB)
+}
+
+// upsertWord 添加或替换敏感词，同名敏感词在黑名单或分类中时原地替换，否则加入黑名单
+func (f *ContentFilter) upsertWord(word types.SensitiveWord) wordEdit {
+	return func(wordDB *types.WordDatabase) bool {
+		if existing := f.findWord(wordDB, word.Word); existing != nil {
+			*existing = word
+			return true
+		}
+		wordDB.Blacklist = append(wordDB.Blacklist, word)
+		return true
+	}
+}
+
+// findWord 在黑名单和分类中查找敏感词，按标准化后的形式比较
+func (f *ContentFilter) findWord(wordDB *types.WordDatabase, word string) *types.SensitiveWord {
+	key := f.normalizer.Normalize(word)
+	for i := range wordDB.Blacklist {
+		if f.normalizer.Normalize(wordDB.Blacklist[i].Word) == key {
+			return &wordDB.Blacklist[i]
+		}
+	}
+	for _, words := range wordDB.Categories {
+		for i := range words {
+			if f.normalizer.Normalize(words[i].Word) == key {
+				return &words[i]
+			}
+		}
+	}
+	return nil
+}
+
+// removeWord 从黑名单和所有分类中移除敏感词
+func (f *ContentFilter) removeWord(word string) wordEdit {
+	key := f.normalizer.Normalize(word)
+	keep := func(words []types.SensitiveWord) ([]types.SensitiveWord, bool) {
+		kept := words[:0:0]
+		for _, w := range words {
+			if f.normalizer.Normalize(w.Word) != key {
+				kept = append(kept, w)
+			}
+		}
+		return kept, len(kept) != len(words)
+	}
+
+	return func(wordDB *types.WordDatabase) bool {
+		var changed, removed bool
+		wordDB.Blacklist, removed = keep(wordDB.Blacklist)
+		changed = changed || removed
+		for category, words := range wordDB.Categories {
+			wordDB.Categories[category], removed = keep(words)
+			changed = changed || removed
+		}
+		return changed
+	}
+}
+
+// localVersion 运行时修改后的词库版本，如 "1.0.0" -> "1.0.0-local.1" -> "1.0.0-local.2"
+func localVersion(version string) string {
+	if strings.Contains(version, localVersionSuffix) {
+		return types.NextVersion(version)
+	}
+	return version + localVersionSuffix + ".1"
+}
@@ -0,0 +1,161 @@
+// Package integrity 词库校验和与签名
+//
+// 校验和为词库规范化JSON（不含checksum和signature字段）的SHA-256十六进制摘要，
+// 签名为Ed25519私钥对校验和字符串的签名（base64编码）。配置了公钥的实例只接受签名有效的词库，
+// 配置中心账号泄露时也无法在不被发现的情况下篡改或清空词库。
+package integrity
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+var (
+	// ErrChecksumMismatch 词库内容与校验和不一致
+	ErrChecksumMismatch = errors.New("word database checksum mismatch")
+	// ErrMissingSignature 要求签名但词库未签名
+	ErrMissingSignature = errors.New("word database is not signed")
+	// ErrInvalidSignature 签名与公钥不匹配
+	ErrInvalidSignature = errors.New("word database signature is invalid")
+)
+
+// Checksum 计算词库的校验和
+func Checksum(wordDB *types.WordDatabase) (string, error) {
+	data, err := canonical(wordDB)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Seal 更新词库的校验和，key不为nil时同时签名，否则清除已失效的签名
+// 修改词库内容后发布前调用
+func Seal(wordDB *types.WordDatabase, key ed25519.PrivateKey) error {
+	checksum, err := Checksum(wordDB)
+	if err != nil {
+		return err
+	}
+
+	wordDB.Checksum = checksum
+	wordDB.Signature = ""
+	if key != nil {
+		wordDB.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, []byte(checksum)))
+	}
+	return nil
+}
+
+// Verify 校验词库：设置了校验和时校验内容，key不为nil时要求签名有效
+func Verify(wordDB *types.WordDatabase, key ed25519.PublicKey) error {
+	if wordDB.Checksum == "" && key == nil {
+		return nil
+	}
+
+	checksum, err := Checksum(wordDB)
+	if err != nil {
+		return err
+	}
+	if wordDB.Checksum != "" && wordDB.Checksum != checksum {
+		return fmt.Errorf("%w: version %s", ErrChecksumMismatch, wordDB.Version)
+	}
+	if key == nil {
+		return nil
+	}
+
+	if wordDB.Signature == "" || wordDB.Checksum == "" {
+		return fmt.Errorf("%w: version %s", ErrMissingSignature, wordDB.Version)
+	}
+	signature, err := base64.StdEncoding.DecodeString(wordDB.Signature)
+	if err != nil || !ed25519.Verify(key, []byte(checksum), signature) {
+		return fmt.Errorf("%w: version %s", ErrInvalidSignature, wordDB.Version)
+	}
+	return nil
+}
+
+// ParsePublicKey 解析Ed25519公钥，支持PEM（PKIX）和base64编码的32字节原始公钥
+func ParsePublicKey(s string) (ed25519.PublicKey, error) {
+	if block, _ := pem.Decode([]byte(s)); block != nil {
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key: %w", err)
+		}
+		publicKey, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("public key is %T, expected ed25519", key)
+		}
+		return publicKey, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil || len(raw) != ed25519.PublicKeySize {
+		return nil, errors.New("public key must be PEM or base64 encoded 32-byte ed25519 key")
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+// ParsePrivateKey 解析Ed25519私钥，支持PEM（PKCS#8）和base64编码的32字节种子或64字节私钥
+func ParsePrivateKey(s string) (ed25519.PrivateKey, error) {
+	if block, _ := pem.Decode([]byte(s)); block != nil {
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse private key: %w", err)
+		}
+		privateKey, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("private key is %T, expected ed25519", key)
+		}
+		return privateKey, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, errors.New("private key must be PEM or base64 encoded ed25519 key")
+	}
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(raw), nil
+	default:
+		return nil, errors.New("private key must be PEM or base64 encoded ed25519 key")
+	}
+}
+
+// canonical 返回用于计算校验和的规范化JSON
+// 空名单与未设置等价、空分类被忽略，词库经分片、快照等往返后校验和不变
+func canonical(wordDB *types.WordDatabase) ([]byte, error) {
+	c := *wordDB
+	c.Checksum = ""
+	c.Signature = ""
+	if c.Whitelist == nil {
+		c.Whitelist = []string{}
+	}
+	if c.Blacklist == nil {
+		c.Blacklist = []types.SensitiveWord{}
+	}
+	c.Categories = make(map[string][]types.SensitiveWord, len(wordDB.Categories))
+	for category, words := range wordDB.Categories {
+		if len(words) > 0 {
+			c.Categories[category] = words
+		}
+	}
+	if c.Replacements == nil {
+		c.Replacements = map[string]string{}
+	}
+
+	data, err := json.Marshal(&c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal word database: %w", err)
+	}
+	return data, nil
+}
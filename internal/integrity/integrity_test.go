@@ -0,0 +1,66 @@
+package integrity
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+func TestSealAndVerify(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wordDB := &types.WordDatabase{
+		Version:    "1.0.0",
+		UpdateTime: time.Now(),
+		Blacklist:  []types.SensitiveWord{{Word: "敏感词", Categories: []string{"abuse"}, Level: 3}},
+		Categories: map[string][]types.SensitiveWord{"politics": nil},
+	}
+	if err := Seal(wordDB, privateKey); err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(wordDB, publicKey); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// JSON往返后校验和不变
+	data, err := json.Marshal(wordDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded types.WordDatabase
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(&decoded, publicKey); err != nil {
+		t.Fatalf("round trip: unexpected error: %v", err)
+	}
+
+	tampered := decoded.Clone()
+	tampered.Blacklist = nil
+	if err := Verify(tampered, publicKey); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("tampered: got %v, expected ErrChecksumMismatch", err)
+	}
+
+	// 篡改后重新计算校验和，签名不再有效
+	if err := Seal(tampered, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(tampered, nil); err != nil {
+		t.Errorf("checksum only: unexpected error: %v", err)
+	}
+	if err := Verify(tampered, publicKey); !errors.Is(err, ErrMissingSignature) {
+		t.Errorf("unsigned: got %v, expected ErrMissingSignature", err)
+	}
+	tampered.Signature = decoded.Signature
+	if err := Verify(tampered, publicKey); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("resigned: got %v, expected ErrInvalidSignature", err)
+	}
+}
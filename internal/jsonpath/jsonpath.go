@@ -0,0 +1,168 @@
+package jsonpath
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// step 路径中的一级选择
+type step struct {
+	key      string // 对象字段名
+	index    int    // 数组下标，key为空且wildcard为false时有效
+	wildcard bool   // 选择全部字段或元素
+	isIndex  bool   // 是否为数组下标
+}
+
+// Path 编译后的字段选择器
+type Path struct {
+	expr  string
+	steps []step
+}
+
+// String 返回选择器原文
+func (p *Path) String() string {
+	return p.expr
+}
+
+// Compile 编译JSONPath风格的字段选择器
+// 支持 "$.a.b"、"$.items[0].title"、"$.items[*].text"、"$.a.*"、"$['key with space']"，"$" 前缀可省略
+func Compile(expr string) (*Path, error) {
+	rest := strings.TrimSpace(expr)
+	if rest == "" {
+		return nil, errors.New("invalid selector: empty")
+	}
+	rest = strings.TrimPrefix(rest, "$")
+
+	p := &Path{expr: expr}
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			switch name {
+			case "":
+				return nil, fmt.Errorf("invalid selector %q: empty field name", expr)
+			case "*":
+				p.steps = append(p.steps, step{wildcard: true})
+			default:
+				p.steps = append(p.steps, step{key: name})
+			}
+			rest = rest[end:]
+
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid selector %q: unclosed bracket", expr)
+			}
+			s, err := parseBracket(rest[1:end])
+			if err != nil {
+				return nil, fmt.Errorf("invalid selector %q: %w", expr, err)
+			}
+			p.steps = append(p.steps, s)
+			rest = rest[end+1:]
+
+		default:
+			// 省略 "$." 时首个字段名直接开始
+			if len(p.steps) > 0 {
+				return nil, fmt.Errorf("invalid selector %q: unexpected %q", expr, rest[0])
+			}
+			rest = "." + rest
+		}
+	}
+
+	return p, nil
+}
+
+// parseBracket 解析方括号内的下标、通配符或带引号的字段名
+func parseBracket(s string) (step, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case s == "*":
+		return step{wildcard: true}, nil
+	case len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0]:
+		return step{key: s[1 : len(s)-1]}, nil
+	}
+
+	index, err := strconv.Atoi(s)
+	if err != nil || index < 0 {
+		return step{}, fmt.Errorf("invalid index %q", s)
+	}
+	return step{index: index, isIndex: true}, nil
+}
+
+// Strings 按文档顺序返回选择器命中的所有字符串字段及其具体路径，如 "$.items[0].text"
+// 对象字段按字段名排序；doc为 encoding/json 解码得到的 map[string]any / []any 结构
+func (p *Path) Strings(doc any, fn func(path string, value string)) {
+	walk(doc, p.steps, "$", fn)
+}
+
+// walk 递归匹配路径
+func walk(node any, steps []step, path string, fn func(string, string)) {
+	if len(steps) == 0 {
+		if s, ok := node.(string); ok {
+			fn(path, s)
+		}
+		return
+	}
+
+	s := steps[0]
+	switch v := node.(type) {
+	case map[string]any:
+		if s.isIndex {
+			return
+		}
+		if !s.wildcard {
+			if child, ok := v[s.key]; ok {
+				walk(child, steps[1:], childPath(path, s.key), fn)
+			}
+			return
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			walk(v[key], steps[1:], childPath(path, key), fn)
+		}
+
+	case []any:
+		switch {
+		case s.wildcard:
+			for i, child := range v {
+				walk(child, steps[1:], fmt.Sprintf("%s[%d]", path, i), fn)
+			}
+		case s.isIndex && s.index < len(v):
+			walk(v[s.index], steps[1:], fmt.Sprintf("%s[%d]", path, s.index), fn)
+		}
+	}
+}
+
+// childPath 拼接字段路径，非标识符字段名使用方括号形式
+func childPath(path, key string) string {
+	if isIdentifier(key) {
+		return path + "." + key
+	}
+	return path + "['" + strings.ReplaceAll(key, "'", "\\'") + "']"
+}
+
+// isIdentifier 字段名是否可以用点号形式表示
+func isIdentifier(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, c := range key {
+		if c == '_' || c == '$' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || (i > 0 && '0' <= c && c <= '9') {
+			continue
+		}
+		return false
+	}
+	return true
+}
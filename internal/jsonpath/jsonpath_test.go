@@ -0,0 +1,57 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestPathStrings(t *testing.T) {
+	var doc any
+	if err := json.Unmarshal([]byte(`{
+		"title": "标题",
+		"count": 3,
+		"comments": [{"text": "第一条"}, {"text": "第二条"}, {"text": 1}],
+		"author": {"name": "作者", "display name": "昵称"}
+	}`), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		selector string
+		want     []string
+	}{
+		{"$.title", []string{"$.title=标题"}},
+		{"title", []string{"$.title=标题"}},
+		{"$.count", nil},
+		{"$.comments[*].text", []string{"$.comments[0].text=第一条", "$.comments[1].text=第二条"}},
+		{"$.comments[1].text", []string{"$.comments[1].text=第二条"}},
+		{"$.comments[5].text", nil},
+		{"$.author.*", []string{"$.author['display name']=昵称", "$.author.name=作者"}},
+		{"$.author['display name']", []string{"$.author['display name']=昵称"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			path, err := Compile(tt.selector)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			path.Strings(doc, func(path, value string) {
+				got = append(got, path+"="+value)
+			})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s selected %v, want %v", tt.selector, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileInvalid(t *testing.T) {
+	for _, selector := range []string{"", "$.", "$.a[", "$.a[-1]", "$.a[x]"} {
+		if _, err := Compile(selector); err == nil {
+			t.Errorf("Compile(%q) should fail", selector)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package kafkarest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// v2ContentType REST Proxy v2 接口中不含消息的请求格式
+const v2ContentType = "application/vnd.kafka.v2+json"
+
+// Record 消费到的一条消息，Key 和 Value 为消息中的JSON原文
+type Record struct {
+	Topic     string          `json:"topic"`
+	Key       json.RawMessage `json:"key"`
+	Value     json.RawMessage `json:"value"`
+	Partition int32           `json:"partition"`
+	Offset    int64           `json:"offset"`
+}
+
+// Consumer 通过 Kafka REST Proxy（v2 接口）消费JSON消息的消费者实例，关闭自动提交，由调用方处理完成后 Commit
+// 同一消费者实例不能并发使用
+type Consumer struct {
+	baseURI string
+	headers map[string]string
+	timeout time.Duration // 单次请求超时，不含拉取时等待新消息的时间
+	client  *http.Client
+}
+
+// NewConsumer 在消费组中创建消费者实例，offsetReset为消费组没有已提交位移时的起始位置（latest或earliest）
+func NewConsumer(ctx context.Context, proxyURL, group, offsetReset string, headers map[string]string, timeout time.Duration) (*Consumer, error) {
+	c := &Consumer{headers: headers, timeout: timeout, client: &http.Client{}}
+
+	var instance struct {
+		InstanceID string `json:"instance_id"`
+		BaseURI    string `json:"base_uri"`
+	}
+	endpoint := strings.TrimRight(proxyURL, "/") + "/consumers/" + url.PathEscape(group)
+	err := c.do(ctx, http.MethodPost, endpoint, 0, map[string]string{
+		"format":             "json",
+		"auto.offset.reset":  offsetReset,
+		"auto.commit.enable": "false",
+	}, &instance)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka consumer: %w", err)
+	}
+	if instance.BaseURI == "" {
+		return nil, fmt.Errorf("failed to create kafka consumer: rest proxy returned no base_uri")
+	}
+	c.baseURI = strings.TrimRight(instance.BaseURI, "/")
+	return c, nil
+}
+
+// Subscribe 订阅主题
+func (c *Consumer) Subscribe(ctx context.Context, topics ...string) error {
+	body := map[string][]string{"topics": topics}
+	if err := c.do(ctx, http.MethodPost, c.baseURI+"/subscription", 0, body, nil); err != nil {
+		return fmt.Errorf("failed to subscribe kafka topics: %w", err)
+	}
+	return nil
+}
+
+// Fetch 拉取一批消息，没有新消息时最多等待wait后返回空结果
+func (c *Consumer) Fetch(ctx context.Context, wait time.Duration) ([]Record, error) {
+	endpoint := c.baseURI + "/records?timeout=" + strconv.FormatInt(wait.Milliseconds(), 10)
+
+	var records []Record
+	if err := c.do(ctx, http.MethodGet, endpoint, wait, nil, &records); err != nil {
+		return nil, fmt.Errorf("failed to fetch kafka records: %w", err)
+	}
+	return records, nil
+}
+
+// Commit 提交已处理的消息，每个分区提交其中最大的位移
+func (c *Consumer) Commit(ctx context.Context, records []Record) error {
+	type partitionOffset struct {
+		Topic     string `json:"topic"`
+		Partition int32  `json:"partition"`
+		Offset    int64  `json:"offset"`
+	}
+
+	latest := make(map[string]int)
+	var offsets []partitionOffset
+	for _, r := range records {
+		key := r.Topic + "/" + strconv.Itoa(int(r.Partition))
+		if i, ok := latest[key]; ok {
+			if r.Offset > offsets[i].Offset {
+				offsets[i].Offset = r.Offset
+			}
+			continue
+		}
+		latest[key] = len(offsets)
+		offsets = append(offsets, partitionOffset{Topic: r.Topic, Partition: r.Partition, Offset: r.Offset})
+	}
+	if len(offsets) == 0 {
+		return nil
+	}
+
+	body := map[string][]partitionOffset{"offsets": offsets}
+	if err := c.do(ctx, http.MethodPost, c.baseURI+"/offsets", 0, body, nil); err != nil {
+		return fmt.Errorf("failed to commit kafka offsets: %w", err)
+	}
+	return nil
+}
+
+// Close 删除消费者实例，消费组中的分区随即重新分配
+func (c *Consumer) Close(ctx context.Context) error {
+	if err := c.do(ctx, http.MethodDelete, c.baseURI, 0, nil, nil); err != nil {
+		return fmt.Errorf("failed to delete kafka consumer: %w", err)
+	}
+	return nil
+}
+
+// do 发送请求并解析JSON响应，out为nil时丢弃响应；wait为请求超时之外允许服务端等待的时间，非2xx响应视为失败
+func (c *Consumer) do(ctx context.Context, method, endpoint string, wait time.Duration, in, out interface{}) error {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout+wait)
+		defer cancel()
+	}
+
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", v2ContentType)
+	req.Header.Set("Accept", contentType+", "+v2ContentType) // 拉取消息的响应为JSON消息格式，其余为v2格式
+	for key, value := range c.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("kafka rest proxy returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
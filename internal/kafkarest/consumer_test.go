@@ -0,0 +1,86 @@
+package kafkarest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestConsumer(t *testing.T) {
+	var created, subscription, committed map[string]interface{}
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /consumers/guardian":
+			json.NewDecoder(r.Body).Decode(&created)
+			json.NewEncoder(w).Encode(map[string]string{"instance_id": "c1", "base_uri": server.URL + "/consumers/guardian/instances/c1"})
+		case "POST /consumers/guardian/instances/c1/subscription":
+			json.NewDecoder(r.Body).Decode(&subscription)
+			w.WriteHeader(http.StatusNoContent)
+		case "GET /consumers/guardian/instances/c1/records":
+			if got := r.URL.Query().Get("timeout"); got != "500" {
+				t.Errorf("records timeout = %q, want 500", got)
+			}
+			w.Write([]byte(`[
+				{"topic":"comments","key":null,"value":{"text":"a"},"partition":0,"offset":7},
+				{"topic":"comments","key":"k","value":{"text":"b"},"partition":1,"offset":3},
+				{"topic":"comments","key":null,"value":{"text":"c"},"partition":0,"offset":8}
+			]`))
+		case "POST /consumers/guardian/instances/c1/offsets":
+			json.NewDecoder(r.Body).Decode(&committed)
+			w.WriteHeader(http.StatusNoContent)
+		case "DELETE /consumers/guardian/instances/c1":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	c, err := NewConsumer(ctx, server.URL, "guardian", "earliest", nil, time.Second)
+	if err != nil {
+		t.Fatalf("NewConsumer() error = %v", err)
+	}
+	if created["auto.commit.enable"] != "false" || created["auto.offset.reset"] != "earliest" {
+		t.Errorf("consumer config = %v, want manual commit from earliest", created)
+	}
+	if err := c.Subscribe(ctx, "comments"); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	records, err := c.Fetch(ctx, 500*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if len(records) != 3 || string(records[1].Value) != `{"text":"b"}` {
+		t.Fatalf("Fetch() = %+v, want 3 records with raw values", records)
+	}
+
+	if err := c.Commit(ctx, records); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+	data, _ := json.Marshal(committed["offsets"])
+	want := `[{"offset":8,"partition":0,"topic":"comments"},{"offset":3,"partition":1,"topic":"comments"}]`
+	if string(data) != want {
+		t.Errorf("committed offsets = %s, want %s", data, want)
+	}
+
+	if err := c.Close(ctx); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
+
+func TestConsumerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error_code":40902,"message":"Consumer instance with the specified name already exists."}`, http.StatusConflict)
+	}))
+	defer server.Close()
+
+	if _, err := NewConsumer(context.Background(), server.URL, "guardian", "latest", nil, time.Second); err == nil {
+		t.Error("NewConsumer() error = nil, want rest proxy error")
+	}
+}
@@ -0,0 +1,73 @@
+package kafkarest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// contentType REST Proxy v2 接口的JSON消息格式
+const contentType = "application/vnd.kafka.json.v2+json"
+
+// Producer 通过 Kafka REST Proxy（v2 接口）向主题写入JSON消息
+type Producer struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+// record REST Proxy 的消息
+type record struct {
+	Value interface{} `json:"value"`
+}
+
+// NewProducer 创建写入指定主题的生产者，proxyURL为REST Proxy地址
+func NewProducer(proxyURL, topic string, headers map[string]string, timeout time.Duration) *Producer {
+	return &Producer{
+		url:     strings.TrimRight(proxyURL, "/") + "/topics/" + url.PathEscape(topic),
+		headers: headers,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// Produce 写入一批消息，每个值为一条消息；非2xx响应视为失败
+func (p *Producer) Produce(ctx context.Context, values ...interface{}) error {
+	body := struct {
+		Records []record `json:"records"`
+	}{Records: make([]record, len(values))}
+	for i, value := range values {
+		body.Records[i].Value = value
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal kafka records: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	for key, value := range p.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send kafka records: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("kafka rest proxy returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
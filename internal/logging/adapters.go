@@ -0,0 +1,51 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// Slog 适配标准库 log/slog，级别低于处理器设置的日志不会格式化消息
+func Slog(logger *slog.Logger) FieldLogger {
+	return &slogLogger{logger: logger}
+}
+
+// slogLogger slog适配器
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+// WithField 附加字段
+func (l *slogLogger) WithField(key string, value interface{}) Logger {
+	return &slogLogger{logger: l.logger.With(key, value)}
+}
+
+// log 按级别输出
+func (l *slogLogger) log(level slog.Level, format string, args []interface{}) {
+	ctx := context.Background()
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+	l.logger.Log(ctx, level, fmt.Sprintf(format, args...))
+}
+
+// Debugf 输出调试日志
+func (l *slogLogger) Debugf(format string, args ...interface{}) {
+	l.log(slog.LevelDebug, format, args)
+}
+
+// Infof 输出信息日志
+func (l *slogLogger) Infof(format string, args ...interface{}) {
+	l.log(slog.LevelInfo, format, args)
+}
+
+// Warnf 输出警告日志
+func (l *slogLogger) Warnf(format string, args ...interface{}) {
+	l.log(slog.LevelWarn, format, args)
+}
+
+// Errorf 输出错误日志
+func (l *slogLogger) Errorf(format string, args ...interface{}) {
+	l.log(slog.LevelError, format, args)
+}
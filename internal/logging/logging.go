@@ -0,0 +1,91 @@
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+// Logger 日志接口，内部各组件只依赖该接口
+// *slog.Logger 使用 Slog 适配；logrus、zap 的适配器在 pkg/guardian/logruslog、pkg/guardian/zaplog 中，核心不依赖它们
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// FieldLogger 支持附加结构化字段的日志，本包的适配器均实现该接口
+type FieldLogger interface {
+	Logger
+	// WithField 返回附加了字段的日志，不修改原日志
+	WithField(key string, value interface{}) Logger
+}
+
+// From 将日志转换为 FieldLogger，nil返回 Default
+// 未实现 FieldLogger 的日志（如直接传入的 *logrus.Logger）字段以 key=value 的形式写在消息前
+func From(logger Logger) FieldLogger {
+	switch l := logger.(type) {
+	case nil:
+		return Default()
+	case FieldLogger:
+		return l
+	default:
+		return &prefixLogger{logger: logger}
+	}
+}
+
+// WithField 返回附加了字段的日志
+func WithField(logger Logger, key string, value interface{}) Logger {
+	return From(logger).WithField(key, value)
+}
+
+// Default 返回默认日志：输出到标准错误的 slog 文本日志，级别为 info
+func Default() FieldLogger {
+	return Slog(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelInfo})))
+}
+
+// Discard 返回丢弃全部输出的日志
+func Discard() FieldLogger {
+	return discard{}
+}
+
+// prefixLogger 将字段写在消息前，用于不支持结构化字段的日志
+type prefixLogger struct {
+	logger Logger
+	prefix string
+}
+
+// WithField 附加字段
+func (l *prefixLogger) WithField(key string, value interface{}) Logger {
+	return &prefixLogger{logger: l.logger, prefix: fmt.Sprintf("%s%s=%v ", l.prefix, key, value)}
+}
+
+// Debugf 输出调试日志
+func (l *prefixLogger) Debugf(format string, args ...interface{}) {
+	l.logger.Debugf(l.prefix+format, args...)
+}
+
+// Infof 输出信息日志
+func (l *prefixLogger) Infof(format string, args ...interface{}) {
+	l.logger.Infof(l.prefix+format, args...)
+}
+
+// Warnf 输出警告日志
+func (l *prefixLogger) Warnf(format string, args ...interface{}) {
+	l.logger.Warnf(l.prefix+format, args...)
+}
+
+// Errorf 输出错误日志
+func (l *prefixLogger) Errorf(format string, args ...interface{}) {
+	l.logger.Errorf(l.prefix+format, args...)
+}
+
+// discard 丢弃全部输出
+type discard struct{}
+
+func (discard) WithField(string, interface{}) Logger { return discard{} }
+func (discard) Debugf(string, ...interface{})        {}
+func (discard) Infof(string, ...interface{})         {}
+func (discard) Warnf(string, ...interface{})         {}
+func (discard) Errorf(string, ...interface{})        {}
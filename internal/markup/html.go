@@ -0,0 +1,155 @@
+package markup
+
+import (
+	"html"
+	"strings"
+)
+
+// maxEntityLen 字符实体的最大长度，如 "&CounterClockwiseContourIntegral;"
+const maxEntityLen = 33
+
+// rawTextElements 内容不是可见文本的元素，整个元素连同内容一起去除
+var rawTextElements = []string{"script", "style"}
+
+// HTML 去除HTML标签、注释、脚本和样式，解码字符实体
+// 属性值随标签一起去除，不参与匹配
+func HTML(text string) *Text {
+	if !strings.ContainsAny(text, "<&") {
+		return &Text{text: text}
+	}
+
+	b := newBuilder(len(text))
+	last := 0 // 尚未写入的原文起始位置
+	for i := 0; i < len(text); {
+		switch text[i] {
+		case '<':
+			end, ok := skipMarkup(text, i)
+			if !ok {
+				i++
+				continue
+			}
+			b.copy(text, last, i)
+			i, last = end, end
+
+		case '&':
+			end, decoded, ok := decodeEntity(text, i)
+			if !ok {
+				i++
+				continue
+			}
+			b.copy(text, last, i)
+			b.write(decoded, i, end)
+			i, last = end, end
+
+		default:
+			i++
+		}
+	}
+	b.copy(text, last, len(text))
+
+	return b.build()
+}
+
+// skipMarkup 跳过从i开始的注释或标签，返回其后的位置；不是标记时返回false
+func skipMarkup(text string, i int) (int, bool) {
+	rest := text[i:]
+	if strings.HasPrefix(rest, "<!--") {
+		end := strings.Index(rest[4:], "-->")
+		if end < 0 {
+			return len(text), true
+		}
+		return i + 4 + end + 3, true
+	}
+
+	if len(rest) < 2 || !isTagStart(rest[1]) {
+		return 0, false
+	}
+
+	end := tagEnd(text, i+1)
+	for _, name := range rawTextElements {
+		if hasTagName(rest[1:], name) {
+			return skipRawText(text, end, name), true
+		}
+	}
+	return end, true
+}
+
+// isTagStart 是否为标签名、结束标签或声明的首字符
+func isTagStart(c byte) bool {
+	return c == '/' || c == '!' || c == '?' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+// tagEnd 返回标签结束位置，引号内的 ">" 不结束标签
+func tagEnd(text string, i int) int {
+	var quote byte
+	for ; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '>':
+			return i + 1
+		}
+	}
+	return len(text)
+}
+
+// hasTagName 标签名是否为name（不区分大小写）
+func hasTagName(tag, name string) bool {
+	if len(tag) < len(name) || !strings.EqualFold(tag[:len(name)], name) {
+		return false
+	}
+	if len(tag) == len(name) {
+		return true
+	}
+	switch tag[len(name)] {
+	case ' ', '\t', '\n', '\r', '\f', '/', '>':
+		return true
+	}
+	return false
+}
+
+// skipRawText 跳过脚本或样式内容直到对应的结束标签
+func skipRawText(text string, i int, name string) int {
+	closing := "</" + name
+	for i < len(text) {
+		j := indexFold(text[i:], closing)
+		if j < 0 {
+			return len(text)
+		}
+		start := i + j
+		if hasTagName(text[start+2:], name) {
+			return tagEnd(text, start+2)
+		}
+		i = start + len(closing)
+	}
+	return len(text)
+}
+
+// indexFold 不区分大小写查找ASCII子串
+func indexFold(s, substr string) int {
+	for i := 0; i+len(substr) <= len(s); i++ {
+		if strings.EqualFold(s[i:i+len(substr)], substr) {
+			return i
+		}
+	}
+	return -1
+}
+
+// decodeEntity 解码从i开始的字符实体，返回实体结束位置和解码结果
+func decodeEntity(text string, i int) (int, string, bool) {
+	end := strings.IndexByte(text[i:min(len(text), i+maxEntityLen)], ';')
+	if end < 2 {
+		return 0, "", false
+	}
+	entity := text[i : i+end+1]
+	decoded := html.UnescapeString(entity)
+	if decoded == entity {
+		return 0, "", false
+	}
+	return i + end + 1, decoded, true
+}
@@ -0,0 +1,189 @@
+package markup
+
+import (
+	"strings"
+)
+
+// Markdown 去除Markdown标记：强调和代码标记、标题/引用/列表前缀、链接和图片地址、反斜杠转义
+// 链接和图片保留文字部分，如 "[敏感词](https://example.com)" 按 "敏感词" 匹配
+func Markdown(text string) *Text {
+	if !strings.ContainsAny(text, "*_~`[]#>-+\\!<0123456789") {
+		return &Text{text: text}
+	}
+
+	b := newBuilder(len(text))
+	last := 0     // 尚未写入的原文起始位置
+	skipAt := -1  // 链接目标的起始位置，到达时跳过到skipEnd
+	skipEnd := -1 // 链接目标的结束位置
+	drop := func(i, n int) {
+		b.copy(text, last, i)
+		last = i + n
+	}
+
+	for i := 0; i < len(text); {
+		if i == skipAt {
+			drop(i, skipEnd-i)
+			i, skipAt = skipEnd, -1
+			continue
+		}
+
+		if i == 0 || text[i-1] == '\n' {
+			if n := linePrefix(text[i:]); n > 0 {
+				drop(i, n)
+				i += n
+				continue
+			}
+		}
+
+		switch c := text[i]; c {
+		case '\\':
+			// 转义的标点按字面保留
+			if i+1 < len(text) && isASCIIPunct(text[i+1]) {
+				drop(i, 1)
+				i += 2
+				continue
+			}
+
+		case '*', '~', '`':
+			drop(i, 1)
+
+		case '_':
+			// 单词内部的下划线不是强调标记，如 snake_case
+			if !(i > 0 && isWordByte(text[i-1]) && i+1 < len(text) && isWordByte(text[i+1])) {
+				drop(i, 1)
+			}
+
+		case '!', '[':
+			open := i
+			if c == '!' {
+				if i+1 >= len(text) || text[i+1] != '[' {
+					break
+				}
+				open = i + 1
+			}
+			if at, end, ok := linkTarget(text, open); ok {
+				drop(i, open+1-i)
+				skipAt, skipEnd = at, end
+				i = open + 1
+				continue
+			}
+
+		case '<':
+			// 自动链接整体去除
+			if end, ok := autolink(text, i); ok {
+				drop(i, end-i)
+				i = end
+				continue
+			}
+		}
+		i++
+	}
+	b.copy(text, last, len(text))
+
+	return b.build()
+}
+
+// linePrefix 返回行首标题、引用和列表标记的长度
+func linePrefix(line string) int {
+	n := 0
+	for n < len(line) && n < 3 && line[n] == ' ' {
+		n++
+	}
+	start := n
+
+	for {
+		rest := line[n:]
+		switch {
+		case strings.HasPrefix(rest, ">"):
+			n++
+			if n < len(line) && line[n] == ' ' {
+				n++
+			}
+			continue
+
+		case strings.HasPrefix(rest, "#"):
+			hashes := 0
+			for hashes < len(rest) && rest[hashes] == '#' {
+				hashes++
+			}
+			if hashes <= 6 && (hashes == len(rest) || rest[hashes] == ' ') {
+				n += hashes
+			}
+
+		case strings.HasPrefix(rest, "- "), strings.HasPrefix(rest, "* "), strings.HasPrefix(rest, "+ "):
+			n++
+
+		default:
+			digits := 0
+			for digits < len(rest) && digits < 9 && '0' <= rest[digits] && rest[digits] <= '9' {
+				digits++
+			}
+			if digits > 0 && digits+1 < len(rest) && (rest[digits] == '.' || rest[digits] == ')') && rest[digits+1] == ' ' {
+				n += digits + 1
+			}
+		}
+		break
+	}
+
+	if n == start {
+		return 0
+	}
+	for n < len(line) && line[n] == ' ' {
+		n++
+	}
+	return n
+}
+
+// linkTarget 查找从open处 "[" 开始的链接，返回链接目标 "](url)" 或 "][ref]" 的区间
+func linkTarget(text string, open int) (int, int, bool) {
+	closing := strings.IndexAny(text[open+1:], "]\n")
+	if closing < 0 || text[open+1+closing] != ']' {
+		return 0, 0, false
+	}
+	at := open + 1 + closing
+	if at+1 >= len(text) {
+		return 0, 0, false
+	}
+
+	var end int
+	switch text[at+1] {
+	case '(':
+		end = strings.IndexAny(text[at+2:], ")\n")
+		if end < 0 || text[at+2+end] != ')' {
+			return 0, 0, false
+		}
+		end += at + 3
+	case '[':
+		end = strings.IndexAny(text[at+2:], "]\n")
+		if end < 0 || text[at+2+end] != ']' {
+			return 0, 0, false
+		}
+		end += at + 3
+	default:
+		return 0, 0, false
+	}
+	return at, end, true
+}
+
+// autolink 识别 "<https://...>" 形式的自动链接
+func autolink(text string, i int) (int, bool) {
+	rest := text[i+1:]
+	if !strings.HasPrefix(rest, "http://") && !strings.HasPrefix(rest, "https://") && !strings.HasPrefix(rest, "mailto:") {
+		return 0, false
+	}
+	end := strings.IndexAny(rest, "> \n")
+	if end < 0 || rest[end] != '>' {
+		return 0, false
+	}
+	return i + 1 + end + 1, true
+}
+
+// isASCIIPunct 是否为可转义的ASCII标点
+func isASCIIPunct(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
+
+// isWordByte 是否为单词字符，非ASCII字节按单词字符处理
+func isWordByte(c byte) bool {
+	return c >= 0x80 || c == '_' || ('0' <= c && c <= '9') || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
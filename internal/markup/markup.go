@@ -0,0 +1,96 @@
+package markup
+
+import (
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// Span 原文中的字节区间（左闭右开）
+type Span struct {
+	Start int
+	End   int
+}
+
+// Text 去除标记后的纯文本，记录每个字节在原文中的区间，用于将匹配位置还原到原文
+type Text struct {
+	text   string
+	starts []int // 纯文本第i个字节来自原文的起始偏移，为nil时与原文逐字节对齐
+	ends   []int // 纯文本第i个字节来自原文的结束偏移（不含）
+}
+
+// String 返回去除标记后的纯文本
+func (t *Text) String() string {
+	return t.text
+}
+
+// OriginalSpans 将纯文本中的字节区间 [start, end) 映射回原文
+// 区间跨越被去除的标记时返回多个片段，片段之间即为标记，如 "敏<b>感</b>词" 返回 "敏"、"感"、"词" 三段
+func (t *Text) OriginalSpans(start, end int) []Span {
+	if start >= end {
+		return nil
+	}
+	if t.starts == nil {
+		return []Span{{Start: start, End: end}}
+	}
+
+	spans := []Span{{Start: t.starts[start], End: t.ends[start]}}
+	for i := start + 1; i < end; i++ {
+		last := &spans[len(spans)-1]
+		if t.starts[i] > last.End {
+			spans = append(spans, Span{Start: t.starts[i], End: t.ends[i]})
+			continue
+		}
+		last.End = max(last.End, t.ends[i])
+	}
+	return spans
+}
+
+// builder 逐段构建纯文本及其到原文的映射
+type builder struct {
+	text   []byte
+	starts []int
+	ends   []int
+}
+
+// newBuilder 创建构建器
+func newBuilder(size int) *builder {
+	return &builder{
+		text:   make([]byte, 0, size),
+		starts: make([]int, 0, size),
+		ends:   make([]int, 0, size),
+	}
+}
+
+// copy 原样保留原文区间 [start, end)
+func (b *builder) copy(source string, start, end int) {
+	b.text = append(b.text, source[start:end]...)
+	for i := start; i < end; i++ {
+		b.starts = append(b.starts, i)
+		b.ends = append(b.ends, i+1)
+	}
+}
+
+// write 写入由原文区间 [start, end) 转换得到的文本，如实体解码后的字符
+func (b *builder) write(s string, start, end int) {
+	b.text = append(b.text, s...)
+	for i := 0; i < len(s); i++ {
+		b.starts = append(b.starts, start)
+		b.ends = append(b.ends, end)
+	}
+}
+
+// build 生成纯文本
+func (b *builder) build() *Text {
+	return &Text{text: string(b.text), starts: b.starts, ends: b.ends}
+}
+
+// Extract 按输入格式提取用于匹配的纯文本，纯文本格式原样返回
+func Extract(text, format string) *Text {
+	switch format {
+	case types.FormatHTML:
+		return HTML(text)
+	case types.FormatMarkdown:
+		return Markdown(text)
+	default:
+		return &Text{text: text}
+	}
+}
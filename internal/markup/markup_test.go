@@ -0,0 +1,73 @@
+package markup
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHTML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"plain", "没有标签", "没有标签"},
+		{"inline tags", "敏<b>感</b>词", "敏感词"},
+		{"attributes", `<a href="x>敏感词" title='敏感词'>链接</a>`, "链接"},
+		{"entities", "a&amp;b &lt;i&gt; &#25935;&#x611F;词", "a&b <i> 敏感词"},
+		{"unknown entity", "a&foo;b &", "a&foo;b &"},
+		{"comments", "前<!-- 敏感词 -->后", "前后"},
+		{"script and style", "前<script>var s = '敏感词';</script><STYLE>p{}</STYLE>后", "前后"},
+		{"literal less than", "1 < 2", "1 < 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HTML(tt.input).String(); got != tt.want {
+				t.Errorf("HTML(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOriginalSpans(t *testing.T) {
+	input := "前敏<b>感</b>&#35789;后"
+	text := HTML(input)
+
+	start := strings.Index(text.String(), "敏感词")
+	spans := text.OriginalSpans(start, start+len("敏感词"))
+
+	var pieces []string
+	for _, span := range spans {
+		pieces = append(pieces, input[span.Start:span.End])
+	}
+	if got, want := strings.Join(pieces, "|"), "敏|感|&#35789;"; got != want {
+		t.Errorf("OriginalSpans maps to %q, want %q", got, want)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"plain", "没有标记", "没有标记"},
+		{"emphasis", "敏**感**词 和 ~~删除~~ `代码`", "敏感词 和 删除 代码"},
+		{"underscore", "_强调_ snake_case", "强调 snake_case"},
+		{"link", "看[敏感词](https://example.com/a_b)吧", "看敏感词吧"},
+		{"image", "![敏感图](x.png)", "敏感图"},
+		{"reference link", "[文字][ref]", "文字"},
+		{"autolink", "见 <https://example.com> 后", "见  后"},
+		{"line prefixes", "# 标题\n> 引用\n- 列表\n12. 序号", "标题\n引用\n列表\n序号"},
+		{"escapes", `\*不是强调\*`, "*不是强调*"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Markdown(tt.input).String(); got != tt.want {
+				t.Errorf("Markdown(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
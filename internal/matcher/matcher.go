@@ -0,0 +1,67 @@
+package matcher
+
+import (
+	"sort"
+	"unicode/utf8"
+
+	"github.com/guardian/content-filter/internal/algorithm"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// Matcher 自定义匹配器（机器学习分类、外部接口、自定义规则等），在自动机之后运行
+// 命中与敏感词命中合并处理：同样受分类开关、白名单、排除语境和只观察配置的影响，使用自己的分类和级别
+type Matcher interface {
+	// Match 在标准化后的文本中查找命中
+	// Positions 为标准化文本中的字节偏移，为空时视为命中全文；Word 为空时取命中的文本片段
+	Match(normalizedText string) []types.MatchDetail
+}
+
+// Func 将函数适配为 Matcher
+type Func func(normalizedText string) []types.MatchDetail
+
+// Match 调用函数本身
+func (fn Func) Match(normalizedText string) []types.MatchDetail {
+	return fn(normalizedText)
+}
+
+// Find 依次运行匹配器，将命中转换为自动机命中的形式并按位置排序
+// 不满足搜索选项（分类、最低级别）的命中和越界、不在字符边界上的位置被忽略
+func Find(matchers []Matcher, text string, options *algorithm.SearchOptions) []algorithm.Match {
+	var matches []algorithm.Match
+	for _, m := range matchers {
+		for _, detail := range m.Match(text) {
+			output := algorithm.Output{Word: detail.Word, Categories: detail.Categories, Level: detail.Level}
+			if !options.Allows(&output) {
+				continue
+			}
+
+			positions := detail.Positions
+			if len(positions) == 0 {
+				positions = []types.Position{{Start: 0, End: len(text)}}
+			}
+			for _, p := range positions {
+				if !validSpan(text, p.Start, p.End) {
+					continue
+				}
+				hit := output
+				if hit.Word == "" {
+					hit.Word = text[p.Start:p.End]
+				}
+				matches = append(matches, algorithm.Match{Output: &hit, Start: p.Start, End: p.End})
+			}
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Start < matches[j].Start
+	})
+	return matches
+}
+
+// validSpan 区间是否非空、不越界且两端都在字符边界上
+func validSpan(text string, start, end int) bool {
+	if start < 0 || end > len(text) || start >= end {
+		return false
+	}
+	return utf8.RuneStart(text[start]) && (end == len(text) || utf8.RuneStart(text[end]))
+}
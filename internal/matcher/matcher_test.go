@@ -0,0 +1,35 @@
+package matcher
+
+import (
+	"testing"
+
+	"github.com/guardian/content-filter/internal/algorithm"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+func TestFind(t *testing.T) {
+	text := "加我微信abc123"
+	m := Func(func(normalizedText string) []types.MatchDetail {
+		return []types.MatchDetail{
+			{Categories: []string{"contact"}, Level: 3, Positions: []types.Position{{Start: 12, End: 18}, {Start: 1, End: 4}, {Start: 10, End: 99}}},
+			{Word: "spam", Categories: []string{"spam"}, Level: 1},
+		}
+	})
+
+	matches := Find([]Matcher{m}, text, nil)
+	if len(matches) != 2 {
+		t.Fatalf("Find() returned %d matches, want 2", len(matches))
+	}
+	if matches[0].Word != "spam" || matches[0].Start != 0 || matches[0].End != len(text) {
+		t.Errorf("matches[0] = %+v %+v, want whole text spam", matches[0].Output, matches[0])
+	}
+	if matches[1].Word != "abc123" || matches[1].Level != 3 {
+		t.Errorf("matches[1] = %+v, want abc123 at level 3", matches[1].Output)
+	}
+
+	// 不满足最低级别的命中被忽略
+	matches = Find([]Matcher{m}, text, &algorithm.SearchOptions{MinLevel: 2})
+	if len(matches) != 1 || matches[0].Word != "abc123" {
+		t.Errorf("Find() with min level = %v, want only abc123", matches)
+	}
+}
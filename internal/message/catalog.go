@@ -0,0 +1,127 @@
+package message
+
+import "strings"
+
+// 特殊消息键
+const (
+	KeyDefault = "default" // 没有匹配分类消息时使用
+	KeyReview  = "review"  // 需要人工审核时使用
+
+	// ReasonPrefix 判定原因文案的键前缀，如 "reason.risk_reject"
+	ReasonPrefix = "reason."
+)
+
+// DefaultLocale 默认语言
+const DefaultLocale = "zh-CN"
+
+// builtinMessages 内置消息目录：语言 -> 消息键(分类) -> 文案
+var builtinMessages = map[string]map[string]string{
+	"zh-CN": {
+		KeyDefault: "内容包含违规词汇，已被屏蔽",
+		KeyReview:  "内容需要人工审核，请耐心等待",
+		"abuse":    "内容包含辱骂词汇，已被屏蔽",
+		"politics": "内容包含敏感政治词汇，已被屏蔽",
+		"violence": "内容包含暴力相关词汇，已被屏蔽",
+		"adult":    "内容包含色情低俗词汇，已被屏蔽",
+	},
+	"zh-TW": {
+		KeyDefault: "內容包含違規詞彙，已被屏蔽",
+		KeyReview:  "內容需要人工審核，請耐心等待",
+		"abuse":    "內容包含辱罵詞彙，已被屏蔽",
+		"politics": "內容包含敏感政治詞彙，已被屏蔽",
+		"violence": "內容包含暴力相關詞彙，已被屏蔽",
+		"adult":    "內容包含色情低俗詞彙，已被屏蔽",
+	},
+	"en": {
+		KeyDefault: "Your content contains prohibited words and has been blocked.",
+		KeyReview:  "Your content is pending manual review.",
+		"abuse":    "Your content contains abusive language and has been blocked.",
+		"politics": "Your content contains politically sensitive words and has been blocked.",
+		"violence": "Your content contains violent language and has been blocked.",
+		"adult":    "Your content contains adult or vulgar language and has been blocked.",
+	},
+}
+
+// Catalog 提示语目录，按语言和分类查找面向用户的文案
+type Catalog struct {
+	messages      map[string]map[string]string
+	defaultLocale string
+}
+
+// NewCatalog 创建消息目录，自定义文案会覆盖同语言同键的内置文案
+func NewCatalog(custom map[string]map[string]string, defaultLocale string) *Catalog {
+	if defaultLocale == "" {
+		defaultLocale = DefaultLocale
+	}
+
+	messages := make(map[string]map[string]string)
+	merge := func(source map[string]map[string]string) {
+		for locale, entries := range source {
+			key := normalizeLocale(locale)
+			if messages[key] == nil {
+				messages[key] = make(map[string]string)
+			}
+			for k, v := range entries {
+				messages[key][k] = v
+			}
+		}
+	}
+	merge(builtinMessages)
+	merge(custom)
+
+	return &Catalog{
+		messages:      messages,
+		defaultLocale: normalizeLocale(defaultLocale),
+	}
+}
+
+// Message 返回命中分类对应的文案，按分类顺序取第一个有文案的分类
+func (c *Catalog) Message(locale string, categories []string, review bool) string {
+	entries := c.lookup(locale)
+	if entries == nil {
+		return ""
+	}
+
+	if review {
+		if msg, ok := entries[KeyReview]; ok {
+			return msg
+		}
+	}
+
+	for _, category := range categories {
+		if msg, ok := entries[category]; ok {
+			return msg
+		}
+	}
+
+	return entries[KeyDefault]
+}
+
+// Reason 返回判定原因码对应的文案，未配置时返回空字符串
+func (c *Catalog) Reason(locale, code string) string {
+	if code == "" {
+		return ""
+	}
+	return c.lookup(locale)[ReasonPrefix+code]
+}
+
+// lookup 查找语言对应的文案，依次回退到主语言和默认语言
+func (c *Catalog) lookup(locale string) map[string]string {
+	locale = normalizeLocale(locale)
+	if entries, ok := c.messages[locale]; ok {
+		return entries
+	}
+
+	if i := strings.IndexByte(locale, '-'); i > 0 {
+		if entries, ok := c.messages[locale[:i]]; ok {
+			return entries
+		}
+	}
+
+	return c.messages[c.defaultLocale]
+}
+
+// normalizeLocale 统一语言标识格式，如 zh_cn -> zh-cn
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
@@ -9,8 +9,8 @@ import (
 	"github.com/nacos-group/nacos-sdk-go/clients/config_client"
 	"github.com/nacos-group/nacos-sdk-go/common/constant"
 	"github.com/nacos-group/nacos-sdk-go/vo"
-	"github.com/sirupsen/logrus"
 
+	"github.com/guardian/content-filter/internal/logging"
 	"github.com/guardian/content-filter/internal/types"
 )
 
@@ -18,11 +18,11 @@ import (
 type Client struct {
 	configClient config_client.IConfigClient
 	config       *types.NacosConfig
-	logger       *logrus.Logger
+	logger       logging.Logger
 }
 
 // NewClient 创建新的Nacos客户端
-func NewClient(config *types.NacosConfig, logger *logrus.Logger) (*Client, error) {
+func NewClient(config *types.NacosConfig, logger logging.Logger) (*Client, error) {
 	// 创建服务器配置
 	serverConfigs := make([]constant.ServerConfig, 0, len(config.ServerConfigs))
 	for _, serverConfig := range config.ServerConfigs {
@@ -67,7 +67,7 @@ func (c *Client) GetConfig(dataId, group string) (string, error) {
 		Group:  group,
 	})
 	if err != nil {
-		return "", fmt.Errorf("failed to get config from nacos: %w", err)
+		return "", fmt.Errorf("%w: failed to get config from nacos: %w", types.ErrWordSourceUnavailable, err)
 	}
 
 	return content, nil
@@ -118,7 +118,7 @@ func (c *Client) GetWordDatabase(dataId, group string) (*types.WordDatabase, err
 
 	var wordDB types.WordDatabase
 	if err := json.Unmarshal([]byte(content), &wordDB); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal word database: %w", err)
+		return nil, fmt.Errorf("%w: failed to unmarshal word database: %w", types.ErrInvalidWordDB, err)
 	}
 
 	return &wordDB, nil
@@ -0,0 +1,57 @@
+package normalize
+
+// t2sPairs 常用繁体字到简体字的映射，每两个字符为一组（繁、简），收录常用单字转换，不含词组级转换
+const t2sPairs = "愛爱罷罢備备貝贝筆笔畢毕邊边變变標标別别賓宾補补參参蠶蚕殘残慚惭燦灿蒼苍艙舱倉仓" +
+	"層层產产纏缠長长嘗尝場场廠厂暢畅車车徹彻塵尘陳陈襯衬稱称懲惩誠诚遲迟齒齿衝冲蟲虫" +
+	"寵宠醜丑處处觸触傳传創创純纯詞词辭辞聰聪從从叢丛湊凑錯错達达帶带貸贷擔担單单膽胆" +
+	"當当黨党檔档導导燈灯鄧邓敵敌遞递點点電电墊垫調调釣钓東东動动棟栋凍冻鬥斗獨独讀读" +
+	"賭赌斷断對对隊队噸吨奪夺墮堕惡恶兒儿爾尔餌饵發发罰罚閥阀範范飯饭訪访紡纺飛飞廢废" +
+	"費费紛纷墳坟奮奋憤愤糞粪豐丰風风瘋疯鋒锋鳳凤膚肤婦妇復复複复負负該该蓋盖幹干趕赶" +
+	"剛刚鋼钢崗岗綱纲個个給给鞏巩貢贡溝沟構构購购夠够穀谷顧顾颳刮關关觀观館馆慣惯廣广" +
+	"規规歸归龜龟櫃柜貴贵國国過过還还漢汉號号賀贺轟轰紅红後后壺壶護护滬沪畫画劃划話话" +
+	"懷怀壞坏歡欢環环換换黃黄揮挥輝辉會会穢秽彙汇匯汇諱讳誨诲繪绘葷荤渾浑夥伙獲获貨货" +
+	"禍祸擊击機机積积飢饥雞鸡極极輯辑級级擠挤幾几計计記记紀纪際际繼继濟济夾夹價价駕驾" +
+	"間间堅坚監监儉俭撿捡檢检減减簡简見见艦舰劍剑鑒鉴薦荐將将獎奖講讲醬酱膠胶驕骄嬌娇" +
+	"攪搅腳脚餃饺繳缴較较轎轿階阶節节結结潔洁傑杰緊紧僅仅謹谨進进盡尽勁劲經经驚惊鏡镜" +
+	"靜静競竞糾纠舊旧舉举劇剧據据懼惧絕绝覺觉軍军開开凱凯顆颗殼壳課课墾垦懇恳庫库誇夸" +
+	"塊块寬宽礦矿虧亏擴扩闊阔蠟蜡臘腊來来蘭兰攔拦欄栏爛烂濫滥藍蓝籃篮覽览懶懒勞劳樂乐" +
+	"淚泪類类壘垒離离禮礼裡里裏里歷历曆历麗丽勵励厲厉隸隶倆俩聯联蓮莲連连憐怜練练煉炼" +
+	"臉脸戀恋糧粮涼凉兩两輛辆諒谅療疗遼辽獵猎鄰邻臨临靈灵齡龄嶺岭領领劉刘龍龙聾聋籠笼" +
+	"樓楼婁娄爐炉盧卢廬庐滷卤虜虏魯鲁陸陆錄录綠绿驢驴亂乱掄抡輪轮論论羅罗蘿萝邏逻鑼锣" +
+	"騾骡駱骆絡络媽妈馬马碼码罵骂嗎吗買买賣卖麥麦邁迈脈脉蠻蛮滿满貓猫錨锚貿贸麼么沒没" +
+	"黴霉門门悶闷們们夢梦彌弥謎谜綿绵麵面廟庙滅灭憫悯鳴鸣銘铭謀谋畝亩納纳難难腦脑惱恼" +
+	"鬧闹內内擬拟膩腻釀酿鳥鸟聶聂寧宁擰拧濘泞農农濃浓諾诺歐欧毆殴嘔呕盤盘龐庞賠赔噴喷" +
+	"鵬鹏騙骗飄飘頻频貧贫蘋苹憑凭評评潑泼僕仆樸朴譜谱齊齐騎骑豈岂啟启氣气棄弃牽牵鉛铅" +
+	"遷迁簽签謙谦錢钱鉗钳潛潜淺浅譴谴槍枪嗆呛牆墙薔蔷強强搶抢橋桥喬乔僑侨翹翘竅窍竊窃" +
+	"親亲寢寝輕轻氫氢傾倾頃顷請请慶庆窮穷區区軀躯驅驱趨趋權权勸劝確确讓让饒饶擾扰繞绕" +
+	"熱热認认榮荣軟软銳锐潤润灑洒薩萨賽赛傘伞喪丧掃扫澀涩殺杀紗纱篩筛曬晒刪删閃闪陝陕" +
+	"贍赡傷伤賞赏燒烧紹绍賒赊攝摄懾慑設设紳绅審审嬸婶腎肾滲渗聲声繩绳勝胜聖圣師师獅狮" +
+	"濕湿詩诗時时蝕蚀實实識识駛驶勢势適适釋释飾饰視视試试壽寿獸兽樞枢輸输書书贖赎屬属" +
+	"術术樹树豎竖數数帥帅雙双誰谁稅税順顺說说碩硕爍烁絲丝飼饲鬆松聳耸頌颂訟讼誦诵擻擞" +
+	"蘇苏訴诉肅肃雖虽隨随綏绥歲岁孫孙損损筍笋縮缩瑣琐鎖锁獺獭撻挞態态攤摊貪贪癱瘫灘滩" +
+	"壇坛譚谭談谈嘆叹湯汤燙烫濤涛討讨騰腾謄誊銻锑題题體体屜屉條条貼贴鐵铁廳厅聽听烴烃" +
+	"銅铜統统頭头圖图塗涂團团頹颓蛻蜕脫脱鴕鸵馱驮駝驼橢椭窪洼襪袜彎弯灣湾頑顽萬万網网" +
+	"韋韦違违圍围為为濰潍維维偉伟偽伪緯纬謂谓衛卫溫温聞闻紋纹穩稳問问甕瓮撾挝蝸蜗渦涡" +
+	"窩窝臥卧嗚呜鎢钨烏乌誣诬無无蕪芜吳吴塢坞霧雾務务誤误錫锡犧牺襲袭習习銑铣戲戏細细" +
+	"蝦虾轄辖峽峡俠侠狹狭廈厦嚇吓鮮鲜纖纤鹹咸賢贤銜衔閒闲顯显險险現现獻献縣县餡馅羨羡" +
+	"憲宪線线廂厢鑲镶鄉乡詳详響响項项蕭萧囂嚣銷销曉晓嘯啸協协挾挟攜携脅胁諧谐寫写瀉泻" +
+	"謝谢鋅锌釁衅興兴洶汹鏽锈繡绣虛虚噓嘘須须許许敘叙緒绪續续軒轩懸悬選选癬癣絢绚學学" +
+	"勳勋詢询尋寻馴驯訓训訊讯遜逊壓压鴉鸦鴨鸭啞哑亞亚訝讶閹阉煙烟鹽盐嚴严顏颜閻阎豔艳" +
+	"厭厌硯砚彥彦諺谚驗验鴦鸯楊杨揚扬瘍疡陽阳癢痒養养樣样堯尧遙遥窯窑謠谣藥药爺爷頁页" +
+	"業业葉叶醫医銥铱頤颐遺遗儀仪蟻蚁藝艺億亿憶忆義义詣诣議议譯译異异繹绎蔭荫陰阴銀银" +
+	"飲饮隱隐櫻樱嬰婴鷹鹰應应纓缨瑩莹螢萤營营熒荧蠅蝇贏赢穎颖喲哟擁拥傭佣癰痈踴踊詠咏" +
+	"湧涌優优憂忧郵邮鈾铀猶犹誘诱輿舆魚鱼漁渔娛娱與与嶼屿語语獄狱譽誉預预馭驭鴛鸳淵渊" +
+	"轅辕園园員员圓圆緣缘遠远願愿約约躍跃鑰钥嶽岳粵粤悅悦閱阅雲云鄖郧勻匀隕陨運运蘊蕴" +
+	"醞酝暈晕韻韵雜杂災灾載载攢攒暫暂贊赞贓赃髒脏鑿凿棗枣竈灶責责擇择則则澤泽賊贼贈赠" +
+	"紮扎劄札軋轧鍘铡閘闸詐诈齋斋債债氈毡盞盏斬斩輾辗嶄崭棧栈戰战綻绽張张漲涨帳帐賬账" +
+	"脹胀趙赵蟄蛰轍辙鍺锗這这貞贞針针偵侦診诊鎮镇陣阵掙挣睜睁猙狰爭争幀帧鄭郑證证織织" +
+	"職职執执紙纸摯挚擲掷幟帜質质滯滞鐘钟終终種种腫肿眾众謅诌軸轴皺皱晝昼驟骤豬猪諸诸" +
+	"誅诛燭烛矚瞩囑嘱貯贮鑄铸築筑駐驻專专磚砖轉转賺赚樁桩莊庄裝装妝妆壯壮狀状錐锥贅赘" +
+	"墜坠綴缀諄谆準准濁浊茲兹資资漬渍蹤踪綜综總总縱纵鄒邹詛诅組组鑽钻彈弹滾滚兇凶砲炮" +
+	"屍尸賤贱騷骚鍵键閉闭闖闯華华奧奥報报製制誌志財财跡迹輔辅辦办韓韩髮发訂订鬍胡鬱郁" +
+	"籤签檯台臺台颱台嚐尝麪面於于佔占併并侶侣係系倖幸僱雇儘尽勛勋捨舍採采朧胧瀏浏燉炖" +
+	"瓏珑甦苏痺痹癡痴皚皑盜盗睏困礙碍禦御稈秆穌稣籲吁糰团絨绒綁绑緝缉縫缝繃绷纜缆罈坛" +
+	"羶膻翺翱脣唇臟脏艱艰蔔卜蔥葱蕩荡薑姜蘆芦蝨虱蠱蛊衊蔑襖袄覓觅訛讹訣诀詭诡諜谍謊谎" +
+	"譏讥貍狸賄贿賂赂蹟迹迴回週周遊游釐厘鉅巨鍊炼鍛锻鏈链鐲镯閨闺闆板隻只雋隽鞦秋韆千" +
+	"顫颤颶飓餘余餵喂饑饥骯肮鬨哄鯨鲸鴿鸽鵝鹅鶴鹤鹼碱麴曲鼴鼹齣出" +
+	"並并幣币戶户斃毙測测販贩"
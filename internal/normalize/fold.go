@@ -0,0 +1,211 @@
+package normalize
+
+import (
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+	"golang.org/x/text/width"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// Text 标准化后的文本，记录每个字节到原文字节区间的映射，用于将匹配位置还原到原文
+type Text struct {
+	text   string
+	starts []int // 标准化文本第i个字节来自原文的起始偏移，为nil时与原文逐字节对齐
+	ends   []int // 标准化文本第i个字节来自原文的结束偏移（不含）
+
+	invisible []types.Position // 被去除的不可见字符在原文中的位置
+}
+
+// String 返回标准化后的文本
+func (t *Text) String() string {
+	return t.text
+}
+
+// OriginalSpan 将标准化文本中的字节区间 [start, end) 映射回原文
+func (t *Text) OriginalSpan(start, end int) (int, int) {
+	if t.starts == nil || start >= end {
+		return start, end
+	}
+	return t.starts[start], t.ends[end-1]
+}
+
+// casers 大小写折叠器不能并发使用，按需复用
+var casers = sync.Pool{
+	New: func() any {
+		c := cases.Fold()
+		return &c
+	},
+}
+
+// Fold 对文本依次进行NFKC标准化、全角转半角和Unicode大小写折叠
+// 文本按NFKC边界分段处理，每段输出的字节都映射到该段在原文中的区间
+func Fold(text string) *Text {
+	if folded, ok := foldASCII(text); ok {
+		return &Text{text: folded}
+	}
+
+	caser := casers.Get().(*cases.Caser)
+	defer casers.Put(caser)
+
+	var buf strings.Builder
+	buf.Grow(len(text))
+	starts := make([]int, 0, len(text))
+	ends := make([]int, 0, len(text))
+	aligned := true
+
+	for pos := 0; pos < len(text); {
+		n := norm.NFKC.NextBoundaryInString(text[pos:], true)
+		if n <= 0 {
+			n = len(text) - pos
+		}
+		segment := text[pos : pos+n]
+
+		folded := foldSegment(segment, caser)
+		if len(folded) != len(segment) {
+			aligned = false
+		}
+		buf.WriteString(folded)
+		for i := 0; i < len(folded); i++ {
+			starts = append(starts, pos)
+			ends = append(ends, pos+n)
+		}
+
+		pos += n
+	}
+
+	if aligned {
+		return &Text{text: buf.String()}
+	}
+	return &Text{text: buf.String(), starts: starts, ends: ends}
+}
+
+// foldSegment 处理一个NFKC分段
+func foldSegment(segment string, caser *cases.Caser) string {
+	if len(segment) == 1 && segment[0] < utf8.RuneSelf {
+		if 'A' <= segment[0] && segment[0] <= 'Z' {
+			return string(segment[0] + 'a' - 'A')
+		}
+		return segment
+	}
+	return caser.String(width.Fold.String(norm.NFKC.String(segment)))
+}
+
+// foldASCII 纯ASCII文本只需转为小写，返回false表示文本包含非ASCII字符
+func foldASCII(text string) (string, bool) {
+	upper := false
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if c >= utf8.RuneSelf {
+			return "", false
+		}
+		upper = upper || ('A' <= c && c <= 'Z')
+	}
+	if !upper {
+		return text, true
+	}
+	return strings.ToLower(text), true
+}
+
+// collapseRepeats 将连续重复超过max次的字符折叠为max个，例如max为1时 "fuuuuck" -> "fuck"
+// 保留的最后一个字符映射到整段重复在原文中的区间，使命中位置覆盖被折叠的部分
+func (t *Text) collapseRepeats(max int) {
+	t.materialize()
+
+	var buf strings.Builder
+	buf.Grow(len(t.text))
+	starts := make([]int, 0, len(t.starts))
+	ends := make([]int, 0, len(t.ends))
+
+	for pos := 0; pos < len(t.text); {
+		char, size := utf8.DecodeRuneInString(t.text[pos:])
+
+		// 找到整段重复
+		runEnd := pos + size
+		count := 1
+		for runEnd < len(t.text) {
+			next, nextSize := utf8.DecodeRuneInString(t.text[runEnd:])
+			if next != char {
+				break
+			}
+			runEnd += nextSize
+			count++
+		}
+
+		keep := count
+		if keep > max {
+			keep = max
+		}
+		for i := 0; i < keep; i++ {
+			at := pos + i*size
+			buf.WriteString(t.text[at : at+size])
+			starts = append(starts, t.starts[at:at+size]...)
+			ends = append(ends, t.ends[at:at+size]...)
+		}
+		if keep < count {
+			// 最后保留的字符延伸到整段重复的结束位置
+			last := t.ends[runEnd-1]
+			for i := len(ends) - size; i < len(ends); i++ {
+				ends[i] = last
+			}
+		}
+
+		pos = runEnd
+	}
+
+	t.text = buf.String()
+	t.starts = starts
+	t.ends = ends
+}
+
+// materialize 为逐字节对齐的文本生成显式的偏移映射，在改变文本长度之前调用
+func (t *Text) materialize() {
+	if t.starts != nil {
+		return
+	}
+
+	t.starts = make([]int, len(t.text))
+	t.ends = make([]int, len(t.text))
+	for i := 0; i < len(t.text); i++ {
+		t.starts[i] = i
+		t.ends[i] = i + 1
+	}
+}
+
+// substitute 逐字替换文本，替换后的字符映射到被替换字符在原文中的区间
+func (t *Text) substitute(substitutions map[rune]rune) {
+	t.materialize()
+
+	var buf strings.Builder
+	buf.Grow(len(t.text))
+	starts := make([]int, 0, len(t.starts))
+	ends := make([]int, 0, len(t.ends))
+
+	for pos := 0; pos < len(t.text); {
+		char, size := utf8.DecodeRuneInString(t.text[pos:])
+		replacement, ok := substitutions[char]
+		if !ok {
+			buf.WriteString(t.text[pos : pos+size])
+			starts = append(starts, t.starts[pos:pos+size]...)
+			ends = append(ends, t.ends[pos:pos+size]...)
+			pos += size
+			continue
+		}
+
+		start, end := t.starts[pos], t.ends[pos+size-1]
+		n, _ := buf.WriteRune(replacement)
+		for i := 0; i < n; i++ {
+			starts = append(starts, start)
+			ends = append(ends, end)
+		}
+		pos += size
+	}
+
+	t.text = buf.String()
+	t.starts = starts
+	t.ends = ends
+}
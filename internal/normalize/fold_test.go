@@ -0,0 +1,118 @@
+package normalize
+
+import (
+	"testing"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"ascii", "Hello World", "hello world"},
+		{"full width", "ＢＡＤ　ｗｏｒｄ１２３", "bad word123"},
+		{"case folding", "STRASSE Straße ΣΊΣΥΦΟΣ", "strasse strasse σίσυφοσ"},
+		{"compatibility", "㈱ ﬁ ①", "(株) fi 1"},
+		{"chinese unchanged", "敏感词", "敏感词"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Fold(tt.input).String(); got != tt.want {
+				t.Errorf("Fold(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFoldOriginalSpan(t *testing.T) {
+	input := "前ＢＡＤ后"
+	folded := Fold(input)
+	if folded.String() != "前bad后" {
+		t.Fatalf("unexpected folded text %q", folded.String())
+	}
+
+	start, end := folded.OriginalSpan(3, 6)
+	if got := input[start:end]; got != "ＢＡＤ" {
+		t.Errorf("OriginalSpan(3, 6) maps to %q, want %q", got, "ＢＡＤ")
+	}
+}
+
+func TestNormalizerChineseConversion(t *testing.T) {
+	n := New(&types.NormalizeConfig{ChineseConversion: types.ConvertT2S})
+	if got := n.Normalize("敏感詞測試"); got != "敏感词测试" {
+		t.Errorf("t2s = %q, want %q", got, "敏感词测试")
+	}
+
+	n = New(&types.NormalizeConfig{ChineseConversion: types.ConvertS2T})
+	if got := n.Normalize("敏感词"); got != "敏感詞" {
+		t.Errorf("s2t = %q, want %q", got, "敏感詞")
+	}
+}
+
+func TestNormalizerMaxRepeat(t *testing.T) {
+	n := New(&types.NormalizeConfig{MaxRepeat: 1})
+	input := "傻傻傻逼 fuuuuck"
+	text := n.NormalizeText(input)
+	if text.String() != "傻逼 fuck" {
+		t.Fatalf("collapsed = %q, want %q", text.String(), "傻逼 fuck")
+	}
+
+	start, end := text.OriginalSpan(0, len("傻逼"))
+	if got := input[start:end]; got != "傻傻傻逼" {
+		t.Errorf("OriginalSpan maps to %q, want %q", got, "傻傻傻逼")
+	}
+
+	if got := New(&types.NormalizeConfig{MaxRepeat: 2}).Normalize("good goooood"); got != "good good" {
+		t.Errorf("max repeat 2 = %q, want %q", got, "good good")
+	}
+}
+
+func TestNormalizerSubstitutions(t *testing.T) {
+	n := New(&types.NormalizeConfig{
+		Substitutions: map[string]string{"1": "i", "3": "e", "0": "o", "@": "a"},
+	})
+
+	input := "Sh1t @ss h3ll0"
+	text := n.NormalizeText(input)
+	if text.String() != "shit ass hello" {
+		t.Fatalf("substituted = %q, want %q", text.String(), "shit ass hello")
+	}
+
+	start, end := text.OriginalSpan(5, 8)
+	if got := input[start:end]; got != "@ss" {
+		t.Errorf("OriginalSpan maps to %q, want %q", got, "@ss")
+	}
+}
+
+func TestNormalizerInvisible(t *testing.T) {
+	input := "敏​感­词 ＡＢ‮"
+	text := New(&types.NormalizeConfig{}).NormalizeText(input)
+	if text.String() != "敏感词 ab" {
+		t.Fatalf("stripped = %q, want %q", text.String(), "敏感词 ab")
+	}
+
+	start, end := text.OriginalSpan(0, len("敏感词"))
+	if got := input[start:end]; got != "敏​感­词" {
+		t.Errorf("OriginalSpan maps to %q, want %q", got, "敏​感­词")
+	}
+	start, end = text.OriginalSpan(len("敏感词 "), len("敏感词 ab"))
+	if got := input[start:end]; got != "ＡＢ" {
+		t.Errorf("OriginalSpan maps to %q, want %q", got, "ＡＢ")
+	}
+
+	var removed []string
+	for _, p := range text.Invisible() {
+		removed = append(removed, input[p.Start:p.End])
+	}
+	if len(removed) != 3 || removed[0] != "​" || removed[1] != "­" || removed[2] != "‮" {
+		t.Errorf("Invisible() = %q", removed)
+	}
+
+	if got := New(&types.NormalizeConfig{KeepInvisible: true}).Normalize("敏​感"); got != "敏​感" {
+		t.Errorf("keep_invisible = %q, want invisible characters kept", got)
+	}
+}
@@ -0,0 +1,91 @@
+package normalize
+
+import (
+	"unicode/utf8"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// isInvisible 是否为零宽字符、软连字符、双向控制字符、填充字符，或除制表、换行、回车外的控制字符
+// 变体选择符不在其中，emoji中大量正常使用
+func isInvisible(r rune) bool {
+	switch {
+	case r == '\t' || r == '\n' || r == '\r':
+		return false
+	case r < 0x20 || (0x7f <= r && r < 0xa0):
+		return true
+	}
+
+	switch r {
+	case 0x00AD, // 软连字符
+		0x034F,         // 组合用字形连接符
+		0x061C,         // 阿拉伯字母标记
+		0x115F, 0x1160, // 韩文填充符
+		0x17B4, 0x17B5, // 高棉文不发音元音
+		0x180E, // 蒙古文元音分隔符
+		0x3164, // 韩文填充符
+		0xFEFF, // 零宽不换行空格（BOM）
+		0xFFA0: // 半角韩文填充符
+		return true
+	}
+
+	return (0x200B <= r && r <= 0x200F) || // 零宽空格、零宽(不)连接符、左右方向标记
+		(0x202A <= r && r <= 0x202E) || // 双向嵌入和覆盖
+		(0x2060 <= r && r <= 0x2064) || // 词连接符、不可见运算符
+		(0x2066 <= r && r <= 0x206F) // 双向隔离和已弃用的格式字符
+}
+
+// stripInvisible 去除不可见字符，返回去除后的文本及被去除字符在原文中的位置
+func stripInvisible(text string) (*Text, []types.Position) {
+	var positions []types.Position
+	for i, r := range text {
+		if isInvisible(r) {
+			positions = append(positions, types.Position{Start: i, End: i + utf8.RuneLen(r)})
+		}
+	}
+	if len(positions) == 0 {
+		return &Text{text: text}, nil
+	}
+
+	size := len(text)
+	for _, p := range positions {
+		size -= p.End - p.Start
+	}
+	buf := make([]byte, 0, size)
+	starts := make([]int, 0, size)
+	ends := make([]int, 0, size)
+
+	last := 0
+	for _, p := range positions {
+		for i := last; i < p.Start; i++ {
+			buf = append(buf, text[i])
+			starts = append(starts, i)
+			ends = append(ends, i+1)
+		}
+		last = p.End
+	}
+	for i := last; i < len(text); i++ {
+		buf = append(buf, text[i])
+		starts = append(starts, i)
+		ends = append(ends, i+1)
+	}
+
+	return &Text{text: string(buf), starts: starts, ends: ends}, positions
+}
+
+// compose 将基于inner文本的偏移映射转换为基于inner原文的映射
+func (t *Text) compose(inner *Text) {
+	if inner.starts == nil {
+		return
+	}
+	t.materialize()
+	for i := range t.starts {
+		t.starts[i] = inner.starts[t.starts[i]]
+		t.ends[i] = inner.ends[t.ends[i]-1]
+	}
+}
+
+// Invisible 返回标准化时去除的不可见字符在原文中的位置
+func (t *Text) Invisible() []types.Position {
+	return t.invisible
+}
@@ -0,0 +1,167 @@
+package normalize
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// Normalizer 文本标准化器，匹配前对文本和词库使用同一套规则，使变体写法命中同一个敏感词
+type Normalizer struct {
+	conversion string        // 简繁转换方向
+	chinese    map[rune]rune // 简繁转换表
+	substitute map[rune]rune // 字符替换表，如 1 -> i、@ -> a
+	maxRepeat  int           // 连续重复字符最多保留的个数，0表示不折叠
+	keepHidden bool          // 保留零宽和控制字符
+}
+
+// New 按配置创建标准化器，config为nil时只进行不可见字符去除和折叠，不做简繁转换和重复字符折叠
+func New(config *types.NormalizeConfig) *Normalizer {
+	n := &Normalizer{}
+	if config == nil {
+		return n
+	}
+	n.keepHidden = config.KeepInvisible
+
+	switch config.ChineseConversion {
+	case types.ConvertT2S:
+		n.chinese = chineseTable(false)
+		n.conversion = config.ChineseConversion
+	case types.ConvertS2T:
+		n.chinese = chineseTable(true)
+		n.conversion = config.ChineseConversion
+	}
+
+	// 替换在折叠之后进行，替换表的键同样需要折叠，使 "Ａ" 与 "a" 的配置等价
+	for from, to := range config.Substitutions {
+		fromRunes, toRunes := []rune(Fold(from).String()), []rune(Fold(to).String())
+		if len(fromRunes) != 1 || len(toRunes) != 1 {
+			continue
+		}
+		if n.substitute == nil {
+			n.substitute = make(map[rune]rune)
+		}
+		n.substitute[fromRunes[0]] = toRunes[0]
+	}
+
+	if config.MaxRepeat > 0 {
+		n.maxRepeat = config.MaxRepeat
+	}
+
+	return n
+}
+
+// Normalize 标准化文本，用于词库中的敏感词、替换词等
+func (n *Normalizer) Normalize(text string) string {
+	return n.NormalizeText(text).String()
+}
+
+// NormalizeText 标准化待检查文本，并保留到原文的偏移映射
+// 依次进行不可见字符去除，NFKC、全角半角和大小写折叠，简繁转换，字符替换，重复字符折叠
+// 简繁转换逐字进行且字节长度不变，不影响偏移映射
+func (n *Normalizer) NormalizeText(text string) *Text {
+	if n == nil {
+		return Fold(text)
+	}
+
+	var t *Text
+	if n.keepHidden {
+		t = Fold(text)
+	} else {
+		stripped, invisible := stripInvisible(text)
+		t = Fold(stripped.text)
+		t.compose(stripped)
+		t.invisible = invisible
+	}
+
+	if n.chinese != nil {
+		t.text = strings.Map(func(r rune) rune {
+			if mapped, ok := n.chinese[r]; ok {
+				return mapped
+			}
+			return r
+		}, t.text)
+	}
+
+	if n.substitute != nil {
+		t.substitute(n.substitute)
+	}
+
+	if n.maxRepeat > 0 {
+		t.collapseRepeats(n.maxRepeat)
+	}
+
+	return t
+}
+
+// substituteSignature 按字符顺序描述替换表
+func substituteSignature(substitutions map[rune]rune) string {
+	from := make([]rune, 0, len(substitutions))
+	for char := range substitutions {
+		from = append(from, char)
+	}
+	sort.Slice(from, func(i, j int) bool { return from[i] < from[j] })
+
+	var signature strings.Builder
+	for _, char := range from {
+		signature.WriteRune(char)
+		signature.WriteRune(substitutions[char])
+	}
+	return signature.String()
+}
+
+// chineseTable 构建简繁转换表
+// 简转繁时一个简体字对应多个繁体字（如 发 -> 發/髮）的情况无法确定，保持原字不转换
+func chineseTable(s2t bool) map[rune]rune {
+	pairs := []rune(t2sPairs)
+	table := make(map[rune]rune, len(pairs)/2)
+
+	if !s2t {
+		for i := 0; i+1 < len(pairs); i += 2 {
+			table[pairs[i]] = pairs[i+1]
+		}
+		return table
+	}
+
+	ambiguous := make(map[rune]bool)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		traditional, simplified := pairs[i], pairs[i+1]
+		if _, ok := table[simplified]; ok {
+			ambiguous[simplified] = true
+			continue
+		}
+		table[simplified] = traditional
+	}
+	for simplified := range ambiguous {
+		delete(table, simplified)
+	}
+
+	return table
+}
+
+// foldSignature 折叠规则的描述，折叠规则变化时需同步修改，使旧的自动机缓存失效
+const foldSignature = "nfkc+width+casefold"
+
+// Signature 返回标准化规则的描述，用于判断按旧规则编译的自动机能否复用
+func (n *Normalizer) Signature() string {
+	if n == nil {
+		return foldSignature
+	}
+
+	signature := foldSignature
+	if !n.keepHidden {
+		signature = "strip+" + signature
+	}
+	if n.conversion != "" {
+		signature += "+" + n.conversion
+	}
+	if len(n.substitute) > 0 {
+		signature += "+substitute:" + substituteSignature(n.substitute)
+	}
+	if n.maxRepeat > 0 {
+		signature += fmt.Sprintf("+repeat%d", n.maxRepeat)
+	}
+	return signature
+}
@@ -0,0 +1,100 @@
+package offender
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// maxEvents 单个用户最多保留的违规时间数，超过后丢弃最早的，窗口内的次数不会超过该值
+const maxEvents = 10000
+
+// MemoryStore 进程内的违规统计，保存每个用户窗口内每次违规的时间
+type MemoryStore struct {
+	mu       sync.Mutex
+	window   time.Duration
+	capacity int
+	users    map[string][]time.Time // 用户 -> 违规时间，按时间排序
+}
+
+// NewMemoryStore 创建进程内违规统计，capacity为用户数上限
+func NewMemoryStore(window time.Duration, capacity int) *MemoryStore {
+	return &MemoryStore{window: window, capacity: capacity, users: make(map[string][]time.Time)}
+}
+
+// Add 记录用户的一次违规
+func (s *MemoryStore) Add(ctx context.Context, userID string, now time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	events, ok := s.users[userID]
+	if !ok && len(s.users) >= s.capacity {
+		s.evict(now)
+	}
+	events = append(s.prune(events, now), now)
+	if len(events) > maxEvents {
+		events = events[len(events)-maxEvents:]
+	}
+	s.users[userID] = events
+	return int64(len(events)), nil
+}
+
+// Count 返回用户在窗口内的违规次数
+func (s *MemoryStore) Count(ctx context.Context, userID string, now time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	events, ok := s.users[userID]
+	if !ok {
+		return 0, nil
+	}
+	events = s.prune(events, now)
+	if len(events) == 0 {
+		delete(s.users, userID)
+	} else {
+		s.users[userID] = events
+	}
+	return int64(len(events)), nil
+}
+
+// Reset 清空用户的违规记录
+func (s *MemoryStore) Reset(ctx context.Context, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.users, userID)
+	return nil
+}
+
+// Close 内存存储无需释放资源
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+// prune 去除窗口之外的违规时间
+func (s *MemoryStore) prune(events []time.Time, now time.Time) []time.Time {
+	cutoff := now.Add(-s.window)
+	i := 0
+	for i < len(events) && !events[i].After(cutoff) {
+		i++
+	}
+	return events[i:]
+}
+
+// evict 用户数达到上限时，先移除窗口内没有违规的用户，仍然超限时移除最近一次违规最早的用户
+func (s *MemoryStore) evict(now time.Time) {
+	var oldest string
+	var oldestAt time.Time
+	for userID, events := range s.users {
+		events = s.prune(events, now)
+		if len(events) == 0 {
+			delete(s.users, userID)
+			continue
+		}
+		if last := events[len(events)-1]; oldest == "" || last.Before(oldestAt) {
+			oldest, oldestAt = userID, last
+		}
+	}
+	if len(s.users) >= s.capacity && oldest != "" {
+		delete(s.users, oldest)
+	}
+}
@@ -0,0 +1,50 @@
+package offender
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreWindow(t *testing.T) {
+	s := NewMemoryStore(time.Hour, 10)
+	ctx := context.Background()
+	start := time.Now()
+
+	for i := 0; i < 3; i++ {
+		count, _ := s.Add(ctx, "u1", start.Add(time.Duration(i)*20*time.Minute))
+		if count != int64(i+1) {
+			t.Errorf("Add() #%d = %d, want %d", i, count, i+1)
+		}
+	}
+
+	// 第一次违规滑出窗口
+	if count, _ := s.Count(ctx, "u1", start.Add(70*time.Minute)); count != 2 {
+		t.Errorf("Count() = %d, want 2", count)
+	}
+	if count, _ := s.Count(ctx, "u2", start); count != 0 {
+		t.Errorf("Count() for unknown user = %d, want 0", count)
+	}
+
+	s.Reset(ctx, "u1")
+	if count, _ := s.Count(ctx, "u1", start.Add(70*time.Minute)); count != 0 {
+		t.Errorf("Count() after Reset = %d, want 0", count)
+	}
+}
+
+func TestMemoryStoreCapacity(t *testing.T) {
+	s := NewMemoryStore(time.Hour, 2)
+	ctx := context.Background()
+	now := time.Now()
+
+	s.Add(ctx, "old", now)
+	s.Add(ctx, "mid", now.Add(time.Minute))
+	s.Add(ctx, "new", now.Add(2*time.Minute))
+
+	if len(s.users) != 2 {
+		t.Fatalf("users = %d, want 2", len(s.users))
+	}
+	if count, _ := s.Count(ctx, "old", now.Add(2*time.Minute)); count != 0 {
+		t.Errorf("oldest user not evicted, count = %d", count)
+	}
+}
@@ -0,0 +1,49 @@
+package offender
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// 默认配置
+const (
+	defaultWindow   = 24 * time.Hour
+	defaultCapacity = 100000
+)
+
+// ErrDisabled 未启用用户违规统计
+var ErrDisabled = errors.New("offender tracking is not enabled")
+
+// Store 用户违规次数的存储，按滚动窗口统计
+type Store interface {
+	// Add 记录用户的一次违规，返回窗口内的违规次数（包含本次）
+	Add(ctx context.Context, userID string, now time.Time) (int64, error)
+	// Count 返回用户在窗口内的违规次数
+	Count(ctx context.Context, userID string, now time.Time) (int64, error)
+	// Reset 清空用户的违规记录，如申诉成功后
+	Reset(ctx context.Context, userID string) error
+	// Close 释放存储占用的资源
+	Close() error
+}
+
+// NewStore 按配置创建违规统计存储
+func NewStore(config *types.OffenderConfig) (Store, error) {
+	window := config.Window
+	if window <= 0 {
+		window = defaultWindow
+	}
+
+	switch config.Store {
+	case types.OffenderStoreRedis:
+		return NewRedisStore(&config.Redis, window)
+	default:
+		capacity := config.Capacity
+		if capacity <= 0 {
+			capacity = defaultCapacity
+		}
+		return NewMemoryStore(window, capacity), nil
+	}
+}
@@ -0,0 +1,90 @@
+package offender
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/guardian/content-filter/internal/redis"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// defaultKeyPrefix 默认键前缀
+const defaultKeyPrefix = "guardian"
+
+// RedisStore 基于Redis的违规统计，多实例共享
+// 每个用户一个有序集合 {prefix}:offender:{user_id}，成员为违规记录、分数为违规时间（毫秒），键在窗口过后过期
+type RedisStore struct {
+	client goredis.UniversalClient
+	prefix string
+	window time.Duration
+	seq    atomic.Uint64 // 同一毫秒内多次违规时区分成员
+}
+
+// NewRedisStore 连接Redis并创建违规统计
+func NewRedisStore(config *types.RedisConfig, window time.Duration) (*RedisStore, error) {
+	prefix := config.KeyPrefix
+	if prefix == "" {
+		prefix = defaultKeyPrefix
+	}
+
+	client, timeout := redis.NewUniversalClient(config)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+
+	return &RedisStore{client: client, prefix: prefix + ":offender:", window: window}, nil
+}
+
+// Add 记录用户的一次违规
+func (s *RedisStore) Add(ctx context.Context, userID string, now time.Time) (int64, error) {
+	key := s.prefix + userID
+	member := strconv.FormatInt(now.UnixNano(), 10) + "-" + strconv.FormatUint(s.seq.Add(1), 10)
+
+	var count *goredis.IntCmd
+	_, err := s.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.ZRemRangeByScore(ctx, key, "-inf", s.cutoff(now))
+		pipe.ZAdd(ctx, key, goredis.Z{Score: float64(now.UnixMilli()), Member: member})
+		count = pipe.ZCard(ctx, key)
+		pipe.Expire(ctx, key, s.window)
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to record violation: %w", err)
+	}
+	return count.Val(), nil
+}
+
+// Count 返回用户在窗口内的违规次数
+func (s *RedisStore) Count(ctx context.Context, userID string, now time.Time) (int64, error) {
+	count, err := s.client.ZCount(ctx, s.prefix+userID, "("+s.cutoff(now), "+inf").Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count violations: %w", err)
+	}
+	return count, nil
+}
+
+// Reset 清空用户的违规记录
+func (s *RedisStore) Reset(ctx context.Context, userID string) error {
+	if err := s.client.Del(ctx, s.prefix+userID).Err(); err != nil {
+		return fmt.Errorf("failed to reset violations: %w", err)
+	}
+	return nil
+}
+
+// Close 关闭Redis连接
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
+
+// cutoff 窗口起点的分数，不晚于该时间的违规不计入
+func (s *RedisStore) cutoff(now time.Time) string {
+	return strconv.FormatInt(now.Add(-s.window).UnixMilli(), 10)
+}
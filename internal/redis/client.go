@@ -0,0 +1,215 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/guardian/content-filter/internal/logging"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// defaultKeyPrefix 默认键前缀
+const defaultKeyPrefix = "guardian"
+
+// Client Redis客户端，将 DataId/Group 映射为键 {prefix}:{group}:{dataId}
+// 变更通知通过同名频道（或配置的频道）发布，收到通知后重新读取键内容
+type Client struct {
+	client  goredis.UniversalClient
+	config  *types.RedisConfig
+	logger  logging.Logger
+	timeout time.Duration
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewClient 创建新的Redis客户端
+func NewClient(config *types.RedisConfig, logger logging.Logger) (*Client, error) {
+	client, timeout := NewUniversalClient(config)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &Client{
+		client:  client,
+		config:  config,
+		logger:  logger,
+		timeout: timeout,
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+
+	if err := c.HealthCheck(); err != nil {
+		cancel()
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+
+	return c, nil
+}
+
+// NewUniversalClient 按配置创建go-redis客户端（单机、集群或哨兵），同时返回单次请求的超时
+func NewUniversalClient(config *types.RedisConfig) (goredis.UniversalClient, time.Duration) {
+	timeout := time.Duration(config.TimeoutMs) * time.Millisecond
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	client := goredis.NewUniversalClient(&goredis.UniversalOptions{
+		Addrs:        config.Addrs,
+		MasterName:   config.MasterName,
+		Username:     config.Username,
+		Password:     config.Password,
+		DB:           config.DB,
+		DialTimeout:  timeout,
+		ReadTimeout:  timeout,
+		WriteTimeout: timeout,
+	})
+	return client, timeout
+}
+
+// Key 返回 DataId/Group 对应的Redis键
+func (c *Client) Key(dataId, group string) string {
+	prefix := c.config.KeyPrefix
+	if prefix == "" {
+		prefix = defaultKeyPrefix
+	}
+	return prefix + ":" + group + ":" + dataId
+}
+
+// Channel 返回 DataId/Group 对应的变更通知频道
+func (c *Client) Channel(dataId, group string) string {
+	if c.config.Channel != "" {
+		return c.config.Channel
+	}
+	return c.Key(dataId, group)
+}
+
+// GetConfig 获取配置
+func (c *Client) GetConfig(dataId, group string) (string, error) {
+	ctx, cancel := context.WithTimeout(c.ctx, c.timeout)
+	defer cancel()
+
+	key := c.Key(dataId, group)
+	content, err := c.client.Get(ctx, key).Result()
+	if errors.Is(err, goredis.Nil) {
+		return "", fmt.Errorf("%w: config not found: %s", types.ErrEmptyDictionary, key)
+	}
+	if err != nil {
+		return "", fmt.Errorf("%w: failed to get config from redis: %w", types.ErrWordSourceUnavailable, err)
+	}
+
+	return content, nil
+}
+
+// ListenConfig 订阅变更通知频道，收到通知后重新读取配置
+// 共享频道时消息内容为变更的键，仅处理与本监听匹配的通知
+func (c *Client) ListenConfig(dataId, group string, callback func(string)) error {
+	key := c.Key(dataId, group)
+	channel := c.Channel(dataId, group)
+
+	pubsub := c.client.Subscribe(c.ctx, channel)
+	if _, err := pubsub.Receive(c.ctx); err != nil {
+		pubsub.Close()
+		return fmt.Errorf("failed to subscribe redis channel %s: %w", channel, err)
+	}
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-c.ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				if c.config.Channel != "" && msg.Payload != key {
+					continue
+				}
+
+				content, err := c.GetConfig(dataId, group)
+				if err != nil {
+					c.logger.Errorf("Failed to reload config after redis notification: %v", err)
+					continue
+				}
+				c.logger.Infof("Config changed: key=%s, channel=%s", key, channel)
+				callback(content)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// PublishConfig 写入配置并发布变更通知
+func (c *Client) PublishConfig(dataId, group, content string) error {
+	ctx, cancel := context.WithTimeout(c.ctx, c.timeout)
+	defer cancel()
+
+	key := c.Key(dataId, group)
+	if err := c.client.Set(ctx, key, content, 0).Err(); err != nil {
+		return fmt.Errorf("failed to publish config: %w", err)
+	}
+	if err := c.client.Publish(ctx, c.Channel(dataId, group), key).Err(); err != nil {
+		return fmt.Errorf("failed to notify config change: %w", err)
+	}
+
+	c.logger.Infof("Config published successfully: key=%s", key)
+	return nil
+}
+
+// compareAndSetScript 键的值与ARGV[1]一致时写入ARGV[2]，返回是否写入
+var compareAndSetScript = goredis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	redis.call("SET", KEYS[1], ARGV[2])
+	return 1
+end
+return 0
+`)
+
+// PublishConfigIf 键的值仍为expected时写入并发布变更通知，否则返回 types.ErrConfigModified
+func (c *Client) PublishConfigIf(dataId, group, content, expected string) error {
+	ctx, cancel := context.WithTimeout(c.ctx, c.timeout)
+	defer cancel()
+
+	key := c.Key(dataId, group)
+	swapped, err := compareAndSetScript.Run(ctx, c.client, []string{key}, expected, content).Int()
+	if err != nil {
+		return fmt.Errorf("failed to publish config: %w", err)
+	}
+	if swapped == 0 {
+		return fmt.Errorf("%w: key=%s", types.ErrConfigModified, key)
+	}
+	if err := c.client.Publish(ctx, c.Channel(dataId, group), key).Err(); err != nil {
+		return fmt.Errorf("failed to notify config change: %w", err)
+	}
+
+	c.logger.Infof("Config published successfully: key=%s", key)
+	return nil
+}
+
+// HealthCheck 健康检查
+func (c *Client) HealthCheck() error {
+	ctx, cancel := context.WithTimeout(c.ctx, c.timeout)
+	defer cancel()
+
+	if err := c.client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("redis ping failed: %w", err)
+	}
+	return nil
+}
+
+// Close 关闭客户端并停止所有订阅
+func (c *Client) Close() error {
+	c.cancel()
+	c.wg.Wait()
+	return c.client.Close()
+}
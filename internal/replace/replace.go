@@ -0,0 +1,146 @@
+package replace
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/rivo/uniseg"
+)
+
+// Span 待替换的文本区间（字节偏移，左闭右开）
+type Span struct {
+	Start       int    // 起始字节偏移
+	End         int    // 结束字节偏移
+	Replacement string // 替换文本，为空时按字素簇逐个打码
+}
+
+// Mask 按字素簇对区间打码：每个被覆盖的字素簇替换为一个mask，
+// 区间边界落在字素簇内部时向外扩展到完整字素簇，保证emoji序列、组合字符不被截断
+func Mask(text string, spans []Span, mask string) string {
+	if len(spans) == 0 {
+		return text
+	}
+	if mask == "" {
+		mask = "*"
+	}
+
+	return rewrite(text, spans, func(b *strings.Builder, span Span, clusters int) {
+		if span.Replacement != "" {
+			b.WriteString(span.Replacement)
+			return
+		}
+		b.WriteString(strings.Repeat(mask, clusters))
+	})
+}
+
+// Highlight 用open/close包裹命中区间，区间按字素簇对齐
+func Highlight(text string, spans []Span, open, close string) string {
+	if len(spans) == 0 {
+		return text
+	}
+
+	return rewrite(text, spans, func(b *strings.Builder, span Span, _ int) {
+		b.WriteString(open)
+		b.WriteString(text[span.Start:span.End])
+		b.WriteString(close)
+	})
+}
+
+// rewrite 将区间对齐到字素簇并合并重叠区间后逐个改写
+func rewrite(text string, spans []Span, write func(b *strings.Builder, span Span, clusters int)) string {
+	boundaries := clusterBoundaries(text)
+	merged := merge(align(spans, boundaries))
+
+	var b strings.Builder
+	b.Grow(len(text))
+
+	last := 0
+	for _, span := range merged {
+		b.WriteString(text[last:span.Start])
+		write(&b, span, countClusters(boundaries, span))
+		last = span.End
+	}
+	b.WriteString(text[last:])
+
+	return b.String()
+}
+
+// clusterBoundaries 返回所有字素簇边界的字节偏移，包括0和len(text)
+func clusterBoundaries(text string) []int {
+	boundaries := make([]int, 0, len(text)+1)
+	boundaries = append(boundaries, 0)
+
+	state := -1
+	rest := text
+	offset := 0
+	for len(rest) > 0 {
+		var cluster string
+		cluster, rest, _, state = uniseg.FirstGraphemeClusterInString(rest, state)
+		offset += len(cluster)
+		boundaries = append(boundaries, offset)
+	}
+
+	return boundaries
+}
+
+// align 将区间起点向前、终点向后扩展到最近的字素簇边界
+func align(spans []Span, boundaries []int) []Span {
+	aligned := make([]Span, 0, len(spans))
+	last := boundaries[len(boundaries)-1]
+
+	for _, span := range spans {
+		if span.Start < 0 || span.End > last || span.Start >= span.End {
+			continue
+		}
+
+		// 最后一个 <= Start 的边界
+		i := sort.SearchInts(boundaries, span.Start+1) - 1
+		// 第一个 >= End 的边界
+		j := sort.SearchInts(boundaries, span.End)
+
+		if boundaries[i] != span.Start || boundaries[j] != span.End {
+			// 扩展后的区间不再与替换词一一对应，改为打码
+			span.Replacement = ""
+		}
+		span.Start, span.End = boundaries[i], boundaries[j]
+		aligned = append(aligned, span)
+	}
+
+	return aligned
+}
+
+// merge 合并重叠或相邻的区间，合并后的区间统一打码
+func merge(spans []Span) []Span {
+	if len(spans) <= 1 {
+		return spans
+	}
+
+	sort.Slice(spans, func(i, j int) bool {
+		if spans[i].Start != spans[j].Start {
+			return spans[i].Start < spans[j].Start
+		}
+		return spans[i].End > spans[j].End
+	})
+
+	merged := []Span{spans[0]}
+	for _, span := range spans[1:] {
+		current := &merged[len(merged)-1]
+		if span.Start >= current.End {
+			merged = append(merged, span)
+			continue
+		}
+		if span.End > current.End {
+			current.End = span.End
+			current.Replacement = ""
+		}
+	}
+
+	return merged
+}
+
+// countClusters 统计区间内的字素簇数量
+func countClusters(boundaries []int, span Span) int {
+	i := sort.SearchInts(boundaries, span.Start)
+	j := sort.SearchInts(boundaries, span.End)
+	return j - i
+}
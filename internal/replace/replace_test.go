@@ -0,0 +1,33 @@
+package replace
+
+import "testing"
+
+func TestMask(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		spans    []Span
+		expected string
+	}{
+		{"chinese", "包含敏感词的文本", []Span{{Start: 6, End: 15}}, "包含***的文本"},
+		{"replacement", "包含敏感词的文本", []Span{{Start: 6, End: 15, Replacement: "**"}}, "包含**的文本"},
+		{"overlap", "abcdef", []Span{{Start: 1, End: 3}, {Start: 2, End: 5}}, "a****f"},
+		// 👨‍👩‍👧 是由ZWJ连接的单个字素簇，只命中其中一部分时整个簇被打码
+		{"emoji zwj", "hi👨‍👩‍👧!", []Span{{Start: 2, End: 6}}, "hi*!"},
+		// e + 组合重音符
+		{"combining mark", "cafe\u0301s", []Span{{Start: 3, End: 4}}, "caf*s"},
+	}
+
+	for _, test := range tests {
+		if got := Mask(test.text, test.spans, "*"); got != test.expected {
+			t.Errorf("%s: Mask() = %q, expected %q", test.name, got, test.expected)
+		}
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	got := Highlight("hi👍🏽there", []Span{{Start: 2, End: 6}}, "[", "]")
+	if expected := "hi[👍🏽]there"; got != expected {
+		t.Errorf("Highlight() = %q, expected %q", got, expected)
+	}
+}
@@ -0,0 +1,146 @@
+package review
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/guardian/content-filter/internal/logging"
+	"github.com/guardian/content-filter/internal/trace"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// Dispatcher 将处理动作为review的结果在后台加入复核队列，不阻塞检查
+type Dispatcher struct {
+	queue    Queue
+	logger   logging.Logger
+	timeout  time.Duration
+	items    chan *types.ReviewItem
+	done     chan struct{}
+	stopped  sync.WaitGroup
+	once     sync.Once
+	enqueued atomic.Int64
+	dropped  atomic.Int64
+	failed   atomic.Int64
+}
+
+// NewDispatcher 创建并启动后台入队
+func NewDispatcher(config *types.ReviewConfig, queue Queue, logger logging.Logger) *Dispatcher {
+	bufferSize := config.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = defaultBufferSize
+	}
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	d := &Dispatcher{
+		queue:   queue,
+		logger:  logger,
+		timeout: timeout,
+		items:   make(chan *types.ReviewItem, bufferSize),
+		done:    make(chan struct{}),
+	}
+	d.stopped.Add(1)
+	go d.run()
+	return d
+}
+
+// Queue 返回复核队列
+func (d *Dispatcher) Queue() Queue {
+	return d.queue
+}
+
+// Submit 结果的处理动作为review时生成复核条目，缓冲区已满时丢弃
+func (d *Dispatcher) Submit(ctx context.Context, text string, result *types.FilterResult, version string) {
+	if result.Action != types.ActionReview {
+		return
+	}
+
+	select {
+	case <-d.done:
+		return
+	default:
+	}
+
+	tenant, caller := trace.Caller(ctx)
+	item := &types.ReviewItem{
+		ID:         newID(),
+		Time:       time.Now(),
+		TraceID:    trace.TraceID(ctx),
+		Tenant:     tenant,
+		Caller:     caller,
+		Metadata:   trace.Metadata(ctx),
+		Text:       text,
+		ReasonCode: result.ReasonCode,
+		Words:      result.Words,
+		Categories: result.Categories,
+		Matches:    result.Matches,
+		MaxLevel:   result.MaxLevel,
+		RiskScore:  result.RiskScore,
+		Version:    version,
+		Status:     types.ReviewPending,
+	}
+
+	select {
+	case d.items <- item:
+	default:
+		d.dropped.Add(1)
+	}
+}
+
+// run 逐条入队；关闭时入队缓冲区中剩余的条目
+func (d *Dispatcher) run() {
+	defer d.stopped.Done()
+
+	for {
+		select {
+		case item := <-d.items:
+			d.enqueue(item)
+		case <-d.done:
+			for {
+				select {
+				case item := <-d.items:
+					d.enqueue(item)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// enqueue 加入队列并计数
+func (d *Dispatcher) enqueue(item *types.ReviewItem) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.timeout)
+	defer cancel()
+
+	if err := d.queue.Enqueue(ctx, item); err != nil {
+		d.failed.Add(1)
+		d.logger.Errorf("Failed to enqueue review item %s: %v", item.ID, err)
+		return
+	}
+	d.enqueued.Add(1)
+}
+
+// Stats 返回入队统计
+func (d *Dispatcher) Stats() *types.ReviewStats {
+	return &types.ReviewStats{
+		Enqueued: d.enqueued.Load(),
+		Dropped:  d.dropped.Load(),
+		Failed:   d.failed.Load(),
+	}
+}
+
+// Close 入队缓冲区中剩余的条目后关闭队列
+func (d *Dispatcher) Close() error {
+	var err error
+	d.once.Do(func() {
+		close(d.done)
+		d.stopped.Wait()
+		err = d.queue.Close()
+	})
+	return err
+}
@@ -0,0 +1,58 @@
+package review
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/guardian/content-filter/internal/kafkarest"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// KafkaQueue 通过 Kafka REST Proxy 将待复核条目写入主题，复核结论写入结论主题，由外部复核系统消费
+// 只写入，不支持查询待复核条目，也不检查条目是否存在或已有结论
+type KafkaQueue struct {
+	items     *kafkarest.Producer
+	decisions *kafkarest.Producer
+}
+
+// NewKafkaQueue 创建Kafka复核队列
+func NewKafkaQueue(config *types.ReviewConfig) *KafkaQueue {
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	decisionTopic := config.DecisionTopic
+	if decisionTopic == "" {
+		decisionTopic = config.Topic + "-decisions"
+	}
+
+	return &KafkaQueue{
+		items:     kafkarest.NewProducer(config.URL, config.Topic, config.Headers, timeout),
+		decisions: kafkarest.NewProducer(config.URL, decisionTopic, config.Headers, timeout),
+	}
+}
+
+// Enqueue 将条目写入主题
+func (q *KafkaQueue) Enqueue(ctx context.Context, item *types.ReviewItem) error {
+	return q.items.Produce(ctx, item)
+}
+
+// Pending 不支持
+func (q *KafkaQueue) Pending(ctx context.Context, limit int) ([]*types.ReviewItem, error) {
+	return nil, fmt.Errorf("%w: kafka queue does not support listing", ErrUnsupported)
+}
+
+// Get 不支持
+func (q *KafkaQueue) Get(ctx context.Context, id string) (*types.ReviewItem, error) {
+	return nil, fmt.Errorf("%w: kafka queue does not support lookup", ErrUnsupported)
+}
+
+// Resolve 将结论写入结论主题
+func (q *KafkaQueue) Resolve(ctx context.Context, decision *types.ReviewDecision) error {
+	return q.decisions.Produce(ctx, decision)
+}
+
+// Close 无需关闭
+func (q *KafkaQueue) Close() error {
+	return nil
+}
@@ -0,0 +1,107 @@
+package review
+
+import (
+	"context"
+	"sync"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// MemoryQueue 进程内复核队列，重启后丢失，多实例部署时各实例独立
+type MemoryQueue struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[string]*types.ReviewItem
+	pending  []string // 待复核条目ID，按入队顺序
+	resolved []string // 已复核条目ID，按复核顺序，超过容量时淘汰最早的
+}
+
+// NewMemoryQueue 创建进程内复核队列，capacity不大于0时使用默认容量
+func NewMemoryQueue(capacity int) *MemoryQueue {
+	if capacity <= 0 {
+		capacity = defaultCapacity
+	}
+	return &MemoryQueue{
+		capacity: capacity,
+		items:    make(map[string]*types.ReviewItem),
+	}
+}
+
+// Enqueue 加入待复核条目，达到容量时返回 ErrQueueFull
+func (q *MemoryQueue) Enqueue(ctx context.Context, item *types.ReviewItem) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.pending) >= q.capacity {
+		return ErrQueueFull
+	}
+	stored := *item
+	q.items[item.ID] = &stored
+	q.pending = append(q.pending, item.ID)
+	return nil
+}
+
+// Pending 按入队顺序返回最多limit个待复核条目
+func (q *MemoryQueue) Pending(ctx context.Context, limit int) ([]*types.ReviewItem, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if limit <= 0 || limit > len(q.pending) {
+		limit = len(q.pending)
+	}
+	items := make([]*types.ReviewItem, 0, limit)
+	for _, id := range q.pending[:limit] {
+		item := *q.items[id]
+		items = append(items, &item)
+	}
+	return items, nil
+}
+
+// Get 返回复核条目
+func (q *MemoryQueue) Get(ctx context.Context, id string) (*types.ReviewItem, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	stored, ok := q.items[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	item := *stored
+	return &item, nil
+}
+
+// Resolve 记录复核结论
+func (q *MemoryQueue) Resolve(ctx context.Context, decision *types.ReviewDecision) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	stored, ok := q.items[decision.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	if stored.Status != types.ReviewPending {
+		return ErrResolved
+	}
+
+	item := *stored
+	apply(&item, decision)
+	q.items[decision.ID] = &item
+	for i, id := range q.pending {
+		if id == decision.ID {
+			q.pending = append(q.pending[:i], q.pending[i+1:]...)
+			break
+		}
+	}
+
+	q.resolved = append(q.resolved, decision.ID)
+	if len(q.resolved) > q.capacity {
+		delete(q.items, q.resolved[0])
+		q.resolved = q.resolved[1:]
+	}
+	return nil
+}
+
+// Close 无需关闭
+func (q *MemoryQueue) Close() error {
+	return nil
+}
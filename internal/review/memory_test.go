@@ -0,0 +1,69 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+func TestMemoryQueue(t *testing.T) {
+	ctx := context.Background()
+	q := NewMemoryQueue(2)
+
+	for _, id := range []string{"a", "b"} {
+		if err := q.Enqueue(ctx, &types.ReviewItem{ID: id, Status: types.ReviewPending}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := q.Enqueue(ctx, &types.ReviewItem{ID: "c", Status: types.ReviewPending}); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("expected ErrQueueFull, got %v", err)
+	}
+
+	decision := &types.ReviewDecision{ID: "a", Status: types.ReviewRejected, Reviewer: "alice", Time: time.Now()}
+	if err := q.Resolve(ctx, decision); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Resolve(ctx, decision); !errors.Is(err, ErrResolved) {
+		t.Fatalf("expected ErrResolved, got %v", err)
+	}
+	if err := q.Resolve(ctx, &types.ReviewDecision{ID: "x", Status: types.ReviewApproved}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	item, err := q.Get(ctx, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.Status != types.ReviewRejected || item.Reviewer != "alice" || item.ReviewedAt == nil {
+		t.Errorf("unexpected resolved item: %+v", item)
+	}
+
+	pending, err := q.Pending(ctx, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 || pending[0].ID != "b" {
+		t.Errorf("expected only b pending, got %v", pending)
+	}
+}
+
+func TestValidateDecision(t *testing.T) {
+	tests := []struct {
+		decision types.ReviewDecision
+		valid    bool
+	}{
+		{types.ReviewDecision{ID: "a", Status: types.ReviewApproved}, true},
+		{types.ReviewDecision{ID: "a", Status: types.ReviewRejected}, true},
+		{types.ReviewDecision{ID: "a", Status: types.ReviewPending}, false},
+		{types.ReviewDecision{Status: types.ReviewApproved}, false},
+	}
+	for _, tt := range tests {
+		err := ValidateDecision(&tt.decision)
+		if (err == nil) != tt.valid || (err != nil && !errors.Is(err, ErrInvalidDecision)) {
+			t.Errorf("ValidateDecision(%+v) = %v", tt.decision, err)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package review
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/guardian/content-filter/internal/redis"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// defaultKeyPrefix 默认键前缀
+const defaultKeyPrefix = "guardian"
+
+// RedisQueue 基于Redis的复核队列，多实例共享
+// 待复核条目ID保存在列表 {prefix}:{review}:pending，条目以JSON保存在 {prefix}:{review}:item:{id} 并在保留时间后过期；
+// 键使用哈希标签，集群模式下位于同一槽位
+type RedisQueue struct {
+	client    goredis.UniversalClient
+	prefix    string
+	retention time.Duration
+}
+
+// NewRedisQueue 连接Redis并创建复核队列，retention不大于0时使用默认保留时间
+func NewRedisQueue(config *types.RedisConfig, retention time.Duration) (*RedisQueue, error) {
+	if retention <= 0 {
+		retention = defaultRetention
+	}
+	prefix := config.KeyPrefix
+	if prefix == "" {
+		prefix = defaultKeyPrefix
+	}
+
+	client, timeout := redis.NewUniversalClient(config)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+
+	return &RedisQueue{client: client, prefix: prefix + ":{review}", retention: retention}, nil
+}
+
+// pendingKey 待复核条目ID列表的键
+func (q *RedisQueue) pendingKey() string {
+	return q.prefix + ":pending"
+}
+
+// itemKey 条目的键
+func (q *RedisQueue) itemKey(id string) string {
+	return q.prefix + ":item:" + id
+}
+
+// Enqueue 保存条目并加入待复核列表
+func (q *RedisQueue) Enqueue(ctx context.Context, item *types.ReviewItem) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return fmt.Errorf("failed to marshal review item: %w", err)
+	}
+
+	_, err = q.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.Set(ctx, q.itemKey(item.ID), data, q.retention)
+		pipe.RPush(ctx, q.pendingKey(), item.ID)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to enqueue review item: %w", err)
+	}
+	return nil
+}
+
+// Pending 按入队顺序返回最多limit个待复核条目，已过期的条目从列表中移除
+func (q *RedisQueue) Pending(ctx context.Context, limit int) ([]*types.ReviewItem, error) {
+	ids, err := q.client.LRange(ctx, q.pendingKey(), 0, int64(limit)-1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list review items: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = q.itemKey(id)
+	}
+	values, err := q.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review items: %w", err)
+	}
+
+	items := make([]*types.ReviewItem, 0, len(values))
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			q.client.LRem(ctx, q.pendingKey(), 1, ids[i])
+			continue
+		}
+		var item types.ReviewItem
+		if err := json.Unmarshal([]byte(data), &item); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal review item %s: %w", ids[i], err)
+		}
+		items = append(items, &item)
+	}
+	return items, nil
+}
+
+// Get 返回复核条目
+func (q *RedisQueue) Get(ctx context.Context, id string) (*types.ReviewItem, error) {
+	data, err := q.client.Get(ctx, q.itemKey(id)).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review item: %w", err)
+	}
+
+	var item types.ReviewItem
+	if err := json.Unmarshal(data, &item); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal review item %s: %w", id, err)
+	}
+	return &item, nil
+}
+
+// Resolve 记录复核结论并从待复核列表移除，并发复核同一条目时只有一个结论生效
+func (q *RedisQueue) Resolve(ctx context.Context, decision *types.ReviewDecision) error {
+	key := q.itemKey(decision.ID)
+	err := q.client.Watch(ctx, func(tx *goredis.Tx) error {
+		data, err := tx.Get(ctx, key).Bytes()
+		if errors.Is(err, goredis.Nil) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		var item types.ReviewItem
+		if err := json.Unmarshal(data, &item); err != nil {
+			return fmt.Errorf("failed to unmarshal review item %s: %w", decision.ID, err)
+		}
+		if item.Status != types.ReviewPending {
+			return ErrResolved
+		}
+		apply(&item, decision)
+		if data, err = json.Marshal(&item); err != nil {
+			return fmt.Errorf("failed to marshal review item: %w", err)
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+			pipe.Set(ctx, key, data, q.retention)
+			pipe.LRem(ctx, q.pendingKey(), 1, decision.ID)
+			return nil
+		})
+		return err
+	}, key)
+
+	switch {
+	case errors.Is(err, goredis.TxFailedErr):
+		return ErrResolved
+	case err == nil, errors.Is(err, ErrNotFound), errors.Is(err, ErrResolved):
+		return err
+	default:
+		return fmt.Errorf("failed to resolve review item: %w", err)
+	}
+}
+
+// Close 关闭Redis连接
+func (q *RedisQueue) Close() error {
+	return q.client.Close()
+}
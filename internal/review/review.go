@@ -0,0 +1,92 @@
+package review
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+var (
+	// ErrNotFound 复核条目不存在或已过期
+	ErrNotFound = errors.New("review item not found")
+	// ErrResolved 复核条目已有结论
+	ErrResolved = errors.New("review item already resolved")
+	// ErrUnsupported 复核队列未启用，或当前队列不支持该操作（如kafka队列查询待复核条目）
+	ErrUnsupported = errors.New("review operation not supported")
+	// ErrQueueFull 待复核条目达到上限
+	ErrQueueFull = errors.New("review queue is full")
+	// ErrInvalidDecision 复核结论缺少条目ID或状态不是 approved、rejected
+	ErrInvalidDecision = errors.New("invalid review decision")
+)
+
+// 复核队列默认参数
+const (
+	defaultCapacity   = 10000
+	defaultRetention  = 7 * 24 * time.Hour
+	defaultBufferSize = 1000
+	defaultTimeout    = 5 * time.Second
+)
+
+// Queue 人工复核队列
+type Queue interface {
+	// Enqueue 加入待复核条目
+	Enqueue(ctx context.Context, item *types.ReviewItem) error
+	// Pending 按入队顺序返回最多limit个待复核条目
+	Pending(ctx context.Context, limit int) ([]*types.ReviewItem, error)
+	// Get 返回复核条目，不存在时返回 ErrNotFound
+	Get(ctx context.Context, id string) (*types.ReviewItem, error)
+	// Resolve 记录复核结论，条目不存在时返回 ErrNotFound，已有结论时返回 ErrResolved
+	Resolve(ctx context.Context, decision *types.ReviewDecision) error
+	// Close 关闭队列
+	Close() error
+}
+
+// NewQueue 按配置创建复核队列
+func NewQueue(config *types.ReviewConfig) (Queue, error) {
+	switch config.Queue {
+	case "", types.ReviewQueueMemory:
+		return NewMemoryQueue(config.Capacity), nil
+	case types.ReviewQueueRedis:
+		return NewRedisQueue(&config.Redis, config.Retention)
+	case types.ReviewQueueKafka:
+		return NewKafkaQueue(config), nil
+	default:
+		return nil, fmt.Errorf("unsupported review queue: %s", config.Queue)
+	}
+}
+
+// ValidateDecision 校验复核结论，结论只能是通过或驳回
+func ValidateDecision(decision *types.ReviewDecision) error {
+	if decision.ID == "" {
+		return fmt.Errorf("%w: missing id", ErrInvalidDecision)
+	}
+	switch decision.Status {
+	case types.ReviewApproved, types.ReviewRejected:
+		return nil
+	default:
+		return fmt.Errorf("%w: status %q, expected approved or rejected", ErrInvalidDecision, decision.Status)
+	}
+}
+
+// apply 将结论写入条目
+func apply(item *types.ReviewItem, decision *types.ReviewDecision) {
+	reviewedAt := decision.Time
+	item.Status = decision.Status
+	item.Reviewer = decision.Reviewer
+	item.Note = decision.Note
+	item.ReviewedAt = &reviewedAt
+}
+
+// newID 生成条目ID
+func newID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
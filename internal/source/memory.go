@@ -0,0 +1,93 @@
+package source
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// Memory 进程内存配置源，不连接配置中心
+// 用于本地模式和测试：PublishConfig 写入的内容可被 GetConfig 读取，并异步通知监听者
+type Memory struct {
+	mu        sync.RWMutex
+	configs   map[string]string
+	listeners map[string][]func(string)
+}
+
+// NewMemory 创建空的内存配置源
+func NewMemory() *Memory {
+	return &Memory{
+		configs:   make(map[string]string),
+		listeners: make(map[string][]func(string)),
+	}
+}
+
+// memoryKey 配置的键
+func memoryKey(dataId, group string) string {
+	return group + "/" + dataId
+}
+
+// GetConfig 获取配置，不存在时返回错误
+func (m *Memory) GetConfig(dataId, group string) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	content, ok := m.configs[memoryKey(dataId, group)]
+	if !ok {
+		return "", fmt.Errorf("%w: config %s/%s not found", types.ErrEmptyDictionary, group, dataId)
+	}
+	return content, nil
+}
+
+// ListenConfig 监听配置变化
+func (m *Memory) ListenConfig(dataId, group string, callback func(string)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := memoryKey(dataId, group)
+	m.listeners[key] = append(m.listeners[key], callback)
+	return nil
+}
+
+// PublishConfig 发布配置并异步通知监听者，与配置中心的推送行为一致
+func (m *Memory) PublishConfig(dataId, group, content string) error {
+	m.mu.Lock()
+	key := memoryKey(dataId, group)
+	m.configs[key] = content
+	listeners := append([]func(string){}, m.listeners[key]...)
+	m.mu.Unlock()
+
+	for _, listener := range listeners {
+		go listener(content)
+	}
+	return nil
+}
+
+// PublishConfigIf 配置内容仍为expected时发布，否则返回 types.ErrConfigModified
+func (m *Memory) PublishConfigIf(dataId, group, content, expected string) error {
+	m.mu.Lock()
+	key := memoryKey(dataId, group)
+	if m.configs[key] != expected {
+		m.mu.Unlock()
+		return fmt.Errorf("%w: %s/%s", types.ErrConfigModified, group, dataId)
+	}
+	m.configs[key] = content
+	listeners := append([]func(string){}, m.listeners[key]...)
+	m.mu.Unlock()
+
+	for _, listener := range listeners {
+		go listener(content)
+	}
+	return nil
+}
+
+// HealthCheck 健康检查
+func (m *Memory) HealthCheck() error {
+	return nil
+}
+
+// Close 关闭配置源
+func (m *Memory) Close() error {
+	return nil
+}
@@ -0,0 +1,247 @@
+package source
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/guardian/content-filter/internal/types"
+	"github.com/guardian/content-filter/internal/wordlist"
+)
+
+// ErrShardVersionMismatch 分片版本与索引不一致，通常是分片正在发布，稍后重试即可
+var ErrShardVersionMismatch = errors.New("word database shard version mismatch")
+
+// WordDatabaseIndex 分片词库索引，发布在词库的 dataId 下，分片发布在 {dataId}_part_{i} 下
+// 配置中心单个配置有大小限制时，大词库拆分为多个分片发布
+type WordDatabaseIndex struct {
+	Version    string    `json:"version"`             // 词库版本，所有分片的版本必须与之一致
+	UpdateTime time.Time `json:"update_time"`         // 更新时间
+	Shards     int       `json:"shards"`              // 分片数量
+	Checksum   string    `json:"checksum,omitempty"`  // 组装后词库的校验和
+	Signature  string    `json:"signature,omitempty"` // 组装后词库的签名
+}
+
+// ShardDataId 返回第i个分片的dataId
+func ShardDataId(dataId string, i int) string {
+	return fmt.Sprintf("%s_part_%d", dataId, i)
+}
+
+// ParseWordDatabase 解析词库配置内容，内容为分片索引时获取全部分片并组装
+// 非JSON格式的词表不支持分片；文本和CSV词表没有版本号，使用内容摘要作为版本
+// 内容为空时返回 types.ErrEmptyDictionary，无法解析时返回 types.ErrInvalidWordDB
+func ParseWordDatabase(src ConfigSource, dataId, group, format, content string) (*types.WordDatabase, error) {
+	if strings.TrimSpace(content) == "" {
+		return nil, fmt.Errorf("%w: word database %s/%s has no content", types.ErrEmptyDictionary, group, dataId)
+	}
+
+	wordFormat, err := ResolveFormat(dataId, format, content)
+	if err != nil {
+		return nil, err
+	}
+	if wordFormat != wordlist.FormatJSON {
+		return parseWordList(wordFormat, content)
+	}
+
+	var index WordDatabaseIndex
+	if err := json.Unmarshal([]byte(content), &index); err != nil {
+		return nil, fmt.Errorf("%w: failed to unmarshal word database: %w", types.ErrInvalidWordDB, err)
+	}
+	if index.Shards <= 0 {
+		var wordDB types.WordDatabase
+		if err := json.Unmarshal([]byte(content), &wordDB); err != nil {
+			return nil, fmt.Errorf("%w: failed to unmarshal word database: %w", types.ErrInvalidWordDB, err)
+		}
+		return &wordDB, nil
+	}
+
+	wordDB := &types.WordDatabase{
+		Version:      index.Version,
+		UpdateTime:   index.UpdateTime,
+		Checksum:     index.Checksum,
+		Signature:    index.Signature,
+		Whitelist:    []string{},
+		Blacklist:    []types.SensitiveWord{},
+		Categories:   map[string][]types.SensitiveWord{},
+		Replacements: map[string]string{},
+	}
+	for i := 0; i < index.Shards; i++ {
+		shardId := ShardDataId(dataId, i)
+		content, err := src.GetConfig(shardId, group)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get word database shard %s: %w", shardId, err)
+		}
+
+		var shard types.WordDatabase
+		if err := json.Unmarshal([]byte(content), &shard); err != nil {
+			return nil, fmt.Errorf("%w: failed to unmarshal word database shard %s: %w", types.ErrInvalidWordDB, shardId, err)
+		}
+		if shard.Version != index.Version {
+			return nil, fmt.Errorf("%w: shard %s has version %q, index has %q", ErrShardVersionMismatch, shardId, shard.Version, index.Version)
+		}
+
+		wordDB.Whitelist = append(wordDB.Whitelist, shard.Whitelist...)
+		wordDB.Blacklist = append(wordDB.Blacklist, shard.Blacklist...)
+		for category, words := range shard.Categories {
+			wordDB.Categories[category] = append(wordDB.Categories[category], words...)
+		}
+		for word, replacement := range shard.Replacements {
+			wordDB.Replacements[word] = replacement
+		}
+	}
+
+	return wordDB, nil
+}
+
+// ResolveFormat 确定词库格式，未声明时按dataId的扩展名和内容识别
+func ResolveFormat(dataId, format, content string) (wordlist.Format, error) {
+	if format != "" && format != types.WordFormatAuto {
+		return wordlist.ParseFormat(format)
+	}
+	return wordlist.DetectFormat(dataId, []byte(content)), nil
+}
+
+// parseWordList 解析非JSON格式的词表
+func parseWordList(format wordlist.Format, content string) (*types.WordDatabase, error) {
+	options := wordlist.DefaultImportOptions()
+	options.Version = types.ContentVersion([]byte(content))
+
+	wordDB, err := wordlist.Parse(strings.NewReader(content), format, options)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", types.ErrInvalidWordDB, err)
+	}
+	if format != wordlist.FormatYAML {
+		// 内容不变时词库也不变，避免每次重载都被视为新词库
+		wordDB.UpdateTime = time.Time{}
+	} else if wordDB.Version == "" {
+		wordDB.Version = options.Version
+	}
+	return wordDB, nil
+}
+
+// PublishWordDatabaseShards 向配置源发布词库，序列化后超过shardSize字节时拆分为分片发布
+// 先发布全部分片再发布索引，读取方在索引更新后才会看到新版本；shardSize不大于0时不拆分
+func PublishWordDatabaseShards(src ConfigSource, dataId, group string, wordDB *types.WordDatabase, shardSize int) error {
+	return publishWordDatabaseShards(src, dataId, group, wordDB, shardSize, func(content string) error {
+		return src.PublishConfig(dataId, group, content)
+	})
+}
+
+// PublishWordDatabaseShardsIf 与 PublishWordDatabaseShards 相同，但词库（分片发布时为索引）只在
+// dataId 的配置内容仍为expected时写入，否则返回 types.ErrConfigModified
+// 分片不做条件写入，并发发布可能覆盖对方的分片，调用方应回读并校验发布结果
+func PublishWordDatabaseShardsIf(src ConditionalPublisher, dataId, group string, wordDB *types.WordDatabase, shardSize int, expected string) error {
+	return publishWordDatabaseShards(src, dataId, group, wordDB, shardSize, func(content string) error {
+		return src.PublishConfigIf(dataId, group, content, expected)
+	})
+}
+
+// publishWordDatabaseShards 发布分片后调用publish写入词库或索引
+func publishWordDatabaseShards(src ConfigSource, dataId, group string, wordDB *types.WordDatabase, shardSize int, publish func(content string) error) error {
+	content, err := json.MarshalIndent(wordDB, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal word database: %w", err)
+	}
+	if shardSize <= 0 || len(content) <= shardSize {
+		return publish(string(content))
+	}
+
+	shards, err := splitWordDatabase(wordDB, shardSize)
+	if err != nil {
+		return err
+	}
+	for i, shard := range shards {
+		content, err := json.Marshal(shard)
+		if err != nil {
+			return fmt.Errorf("failed to marshal word database shard: %w", err)
+		}
+		if err := src.PublishConfig(ShardDataId(dataId, i), group, string(content)); err != nil {
+			return fmt.Errorf("failed to publish word database shard %d: %w", i, err)
+		}
+	}
+
+	index, err := json.MarshalIndent(&WordDatabaseIndex{
+		Version:    wordDB.Version,
+		UpdateTime: wordDB.UpdateTime,
+		Shards:     len(shards),
+		Checksum:   wordDB.Checksum,
+		Signature:  wordDB.Signature,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal word database index: %w", err)
+	}
+	return publish(string(index))
+}
+
+// splitWordDatabase 按序列化大小拆分词库，白名单和替换词放在第一个分片
+// 黑名单和各分类的敏感词按原顺序放入分片的对应位置，组装后与原词库一致，校验和不变
+func splitWordDatabase(wordDB *types.WordDatabase, shardSize int) ([]*types.WordDatabase, error) {
+	newShard := func() *types.WordDatabase {
+		return &types.WordDatabase{Version: wordDB.Version, UpdateTime: wordDB.UpdateTime}
+	}
+
+	first := newShard()
+	first.Whitelist = wordDB.Whitelist
+	first.Replacements = wordDB.Replacements
+	header, err := json.Marshal(first)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal word database shard: %w", err)
+	}
+	empty, err := json.Marshal(newShard())
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal word database shard: %w", err)
+	}
+
+	// size 为当前分片序列化后的大致字节数，每个敏感词额外计入分隔符和分类名
+	shards := []*types.WordDatabase{first}
+	size := len(header)
+	count := 0 // 当前分片的敏感词数
+	add := func(category string, word types.SensitiveWord) error {
+		entry, err := json.Marshal(word)
+		if err != nil {
+			return fmt.Errorf("failed to marshal sensitive word %q: %w", word.Word, err)
+		}
+
+		shard := shards[len(shards)-1]
+		if size+len(entry)+len(category)+8 > shardSize && count > 0 {
+			shard = newShard()
+			shards = append(shards, shard)
+			size = len(empty)
+			count = 0
+		}
+		if category == "" {
+			shard.Blacklist = append(shard.Blacklist, word)
+		} else {
+			if shard.Categories == nil {
+				shard.Categories = make(map[string][]types.SensitiveWord)
+			}
+			shard.Categories[category] = append(shard.Categories[category], word)
+		}
+		size += len(entry) + len(category) + 8
+		count++
+		return nil
+	}
+
+	for _, word := range wordDB.Blacklist {
+		if err := add("", word); err != nil {
+			return nil, err
+		}
+	}
+	categories := make([]string, 0, len(wordDB.Categories))
+	for category := range wordDB.Categories {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	for _, category := range categories {
+		for _, word := range wordDB.Categories[category] {
+			if err := add(category, word); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return shards, nil
+}
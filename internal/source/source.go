@@ -0,0 +1,91 @@
+package source
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/guardian/content-filter/internal/apollo"
+	"github.com/guardian/content-filter/internal/etcd"
+	"github.com/guardian/content-filter/internal/logging"
+	"github.com/guardian/content-filter/internal/nacos"
+	"github.com/guardian/content-filter/internal/redis"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// ConfigSource 词库配置源，按 DataId/Group 读取、监听和发布配置内容
+// Nacos、etcd、Apollo、Redis 等配置中心客户端均实现该接口
+type ConfigSource interface {
+	// GetConfig 获取配置
+	GetConfig(dataId, group string) (string, error)
+	// ListenConfig 监听配置变化
+	ListenConfig(dataId, group string, callback func(string)) error
+	// PublishConfig 发布配置
+	PublishConfig(dataId, group, content string) error
+	// HealthCheck 健康检查
+	HealthCheck() error
+	// Close 关闭客户端
+	Close() error
+}
+
+// ConditionalPublisher 支持条件发布（compare-and-swap）的配置源
+// etcd、Redis 和内存配置源实现该接口，写回词库时用于避免覆盖其他实例的并发修改
+type ConditionalPublisher interface {
+	ConfigSource
+	// PublishConfigIf 配置内容仍为expected时发布，否则不写入并返回 types.ErrConfigModified
+	PublishConfigIf(dataId, group, content, expected string) error
+}
+
+// New 按配置创建词库配置源
+func New(config *types.Config, logger logging.Logger) (ConfigSource, error) {
+	switch config.Source {
+	case "", types.SourceNacos:
+		client, err := nacos.NewClient(&config.NacosConfig, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create nacos client: %w", err)
+		}
+		return client, nil
+	case types.SourceEtcd:
+		client, err := etcd.NewClient(&config.EtcdConfig, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create etcd client: %w", err)
+		}
+		return client, nil
+	case types.SourceApollo:
+		client, err := apollo.NewClient(&config.ApolloConfig, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create apollo client: %w", err)
+		}
+		return client, nil
+	case types.SourceRedis:
+		client, err := redis.NewClient(&config.RedisConfig, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create redis client: %w", err)
+		}
+		return client, nil
+	case types.SourceMemory:
+		return NewMemory(), nil
+	default:
+		return nil, fmt.Errorf("unsupported word source: %s", config.Source)
+	}
+}
+
+// GetWordDatabase 从配置源获取词库，分片发布的词库自动组装
+// format为词库格式（见 types.WordFormat*），为空或auto时按内容识别
+func GetWordDatabase(src ConfigSource, dataId, group, format string) (*types.WordDatabase, error) {
+	content, err := src.GetConfig(dataId, group)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseWordDatabase(src, dataId, group, format, content)
+}
+
+// PublishWordDatabase 向配置源发布词库
+func PublishWordDatabase(src ConfigSource, dataId, group string, wordDB *types.WordDatabase) error {
+	content, err := json.MarshalIndent(wordDB, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal word database: %w", err)
+	}
+
+	return src.PublishConfig(dataId, group, string(content))
+}
@@ -0,0 +1,106 @@
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/guardian/content-filter/internal/logging"
+)
+
+// FieldTraceID 日志中追踪ID的字段名
+const FieldTraceID = "trace_id"
+
+// ctxKey 上下文键
+type ctxKey struct{}
+
+// callerKey 调用方信息的上下文键
+type callerKey struct{}
+
+// metadataKey 调用方附加信息的上下文键
+type metadataKey struct{}
+
+// caller 调用方信息
+type caller struct {
+	tenant string
+	id     string
+}
+
+// WithTraceID 将追踪ID写入上下文
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if traceID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, ctxKey{}, traceID)
+}
+
+// TraceID 从上下文读取追踪ID，不存在时返回空字符串
+func TraceID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	traceID, _ := ctx.Value(ctxKey{}).(string)
+	return traceID
+}
+
+// WithCaller 将租户和调用方ID写入上下文，用于审计记录
+func WithCaller(ctx context.Context, tenant, callerID string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if tenant == "" && callerID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, callerKey{}, caller{tenant: tenant, id: callerID})
+}
+
+// Caller 从上下文读取租户和调用方ID，不存在时返回空字符串
+func Caller(ctx context.Context) (tenant, callerID string) {
+	if ctx == nil {
+		return "", ""
+	}
+	c, _ := ctx.Value(callerKey{}).(caller)
+	return c.tenant, c.id
+}
+
+// WithMetadata 将调用方附加的信息（如内容ID、用户ID）写入上下文，用于复核条目
+func WithMetadata(ctx context.Context, metadata map[string]string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if len(metadata) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, metadataKey{}, metadata)
+}
+
+// Metadata 从上下文读取调用方附加的信息，不存在时返回nil
+func Metadata(ctx context.Context) map[string]string {
+	if ctx == nil {
+		return nil
+	}
+	metadata, _ := ctx.Value(metadataKey{}).(map[string]string)
+	return metadata
+}
+
+// NewTraceID 生成新的追踪ID
+func NewTraceID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// Entry 返回附带追踪ID的日志，上下文中没有追踪ID时返回原日志
+func Entry(ctx context.Context, logger logging.Logger) logging.Logger {
+	if traceID := TraceID(ctx); traceID != "" {
+		return logging.WithField(logger, FieldTraceID, traceID)
+	}
+	return logger
+}
@@ -0,0 +1,53 @@
+package types
+
+// BatchItem 批量检查中带ID的一条文本
+type BatchItem struct {
+	ID   string `json:"id"`   // 调用方的标识，如数据库主键，原样返回
+	Text string `json:"text"` // 待检查文本
+}
+
+// BatchItemResult 带ID的单条检查结果
+type BatchItemResult struct {
+	ID     string        `json:"id"`               // 请求中的ID
+	Result *FilterResult `json:"result,omitempty"` // 检查结果，该条无法检查时为空
+	Error  string        `json:"error,omitempty"`  // 该条无法检查的原因，如流式检查中无法解析的请求行
+}
+
+// BatchSummary 批量检查结果的汇总
+type BatchSummary struct {
+	Total      int            `json:"total"`              // 文本条数
+	Passed     int            `json:"passed"`             // 通过的条数
+	Blocked    int            `json:"blocked"`            // 未通过的条数
+	Degraded   int            `json:"degraded,omitempty"` // 降级结果的条数
+	Categories map[string]int `json:"categories"`         // 分类 -> 命中该分类的条数
+}
+
+// BatchResponse 带ID的批量检查结果，顺序与请求一致
+type BatchResponse struct {
+	Results []BatchItemResult `json:"results"` // 各条结果
+	Summary BatchSummary      `json:"summary"` // 汇总
+}
+
+// NewBatchResponse 按items的ID组装批量检查结果并汇总，results与items一一对应
+func NewBatchResponse(items []BatchItem, results []*FilterResult) *BatchResponse {
+	response := &BatchResponse{
+		Results: make([]BatchItemResult, len(items)),
+		Summary: BatchSummary{Total: len(items), Categories: make(map[string]int)},
+	}
+	for i, item := range items {
+		result := results[i]
+		response.Results[i] = BatchItemResult{ID: item.ID, Result: result}
+		if result.Passed {
+			response.Summary.Passed++
+		} else {
+			response.Summary.Blocked++
+		}
+		if result.Degraded {
+			response.Summary.Degraded++
+		}
+		for _, category := range result.Categories {
+			response.Summary.Categories[category]++
+		}
+	}
+	return response
+}
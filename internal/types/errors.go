@@ -0,0 +1,48 @@
+package types
+
+import "errors"
+
+// 可用 errors.Is 判断的错误类型，各包返回的错误会包装这些值
+var (
+	// ErrWordSourceUnavailable 词库配置源不可用，如配置中心连接失败或超时
+	ErrWordSourceUnavailable = errors.New("word source unavailable")
+	// ErrEmptyDictionary 词库为空：配置源中没有该词库、内容为空或没有任何敏感词
+	ErrEmptyDictionary = errors.New("empty dictionary")
+	// ErrInvalidWordDB 词库内容无法解析或未通过校验
+	ErrInvalidWordDB = errors.New("invalid word database")
+	// ErrTextTooLong 文本超过最大长度
+	ErrTextTooLong = errors.New("text too long")
+	// ErrConfigModified 条件发布时配置内容已被修改，与读取时不一致
+	ErrConfigModified = errors.New("config was modified concurrently")
+)
+
+// Err 返回结果对应的错误类型：超长被拒绝时为 ErrTextTooLong，词库为空的降级结果为 ErrEmptyDictionary，其余为nil
+func (r *FilterResult) Err() error {
+	switch {
+	case r.ReasonCode == ReasonTextTooLong:
+		return ErrTextTooLong
+	case r.Degraded && r.DegradedReason == DegradedEmptyDictionary:
+		return ErrEmptyDictionary
+	default:
+		return nil
+	}
+}
+
+// FilterError 过滤没有正常完成，返回的是降级或兜底结果
+type FilterError struct {
+	Reason string // 降级原因，见 Degraded* 常量
+	Err    error  // 导致降级的错误，如词库过期的原因，可能为nil
+}
+
+// Error 实现error接口
+func (e *FilterError) Error() string {
+	if e.Err == nil {
+		return "filter degraded: " + e.Reason
+	}
+	return "filter degraded: " + e.Reason + ": " + e.Err.Error()
+}
+
+// Unwrap 返回导致降级的错误，便于使用 errors.Is 判断
+func (e *FilterError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,46 @@
+package types
+
+import "time"
+
+// Scheduled 是否设置了生效或失效时间
+func (w *SensitiveWord) Scheduled() bool {
+	return w.ActiveFrom != nil || w.ActiveUntil != nil
+}
+
+// ActiveAt 敏感词在t时刻是否生效，生效区间为 [ActiveFrom, ActiveUntil)
+func (w *SensitiveWord) ActiveAt(t time.Time) bool {
+	if w.ActiveFrom != nil && t.Before(*w.ActiveFrom) {
+		return false
+	}
+	if w.ActiveUntil != nil && !t.Before(*w.ActiveUntil) {
+		return false
+	}
+	return true
+}
+
+// LastScheduleChange 返回不晚于t的最近一个敏感词生效或失效时间，没有时返回零值
+// 同一词库在两次变化之间生效的敏感词相同，可与版本一起标识生效的词语
+func LastScheduleChange(words []SensitiveWord, t time.Time) time.Time {
+	var last time.Time
+	for i := range words {
+		for _, boundary := range []*time.Time{words[i].ActiveFrom, words[i].ActiveUntil} {
+			if boundary != nil && !boundary.After(t) && boundary.After(last) {
+				last = *boundary
+			}
+		}
+	}
+	return last
+}
+
+// NextScheduleChange 返回t之后最近的一个敏感词生效或失效时间，没有时返回零值
+func NextScheduleChange(words []SensitiveWord, t time.Time) time.Time {
+	var next time.Time
+	for i := range words {
+		for _, boundary := range []*time.Time{words[i].ActiveFrom, words[i].ActiveUntil} {
+			if boundary != nil && boundary.After(t) && (next.IsZero() || boundary.Before(next)) {
+				next = *boundary
+			}
+		}
+	}
+	return next
+}
@@ -0,0 +1,122 @@
+package types
+
+import "time"
+
+// Stats 运行统计，/stats 接口按此结构序列化
+// 检查次数、处理动作、分类和耗时为进程启动以来的累计值，不随词库重载清零
+type Stats struct {
+	Version        string                 `json:"version"`                    // 正在服务的词库版本
+	LastUpdate     time.Time              `json:"last_update"`                // 词库自身的更新时间
+	LoadedAt       time.Time              `json:"loaded_at"`                  // 最近一次成功重载（当前词库生效）的时间
+	ReloadError    string                 `json:"reload_error,omitempty"`     // 最近一次重载的错误，重载成功后清空
+	ReloadFailures int                    `json:"reload_failures,omitempty"`  // 连续重载失败次数，重载成功后清零
+	NodeCount      int                    `json:"node_count"`                 // 自动机节点数
+	WordCount      int                    `json:"word_count"`                 // 敏感词数量
+	WhitelistSize  int                    `json:"whitelist_size"`             // 白名单条目数
+	Checks         int64                  `json:"checks"`                     // 检查总次数
+	Blocked        int64                  `json:"blocked"`                    // 未通过的次数
+	BlockRate      float64                `json:"block_rate"`                 // 未通过的比例
+	Degraded       int64                  `json:"degraded"`                   // 返回降级结果的次数
+	Coalesced      int64                  `json:"coalesced,omitempty"`        // 缓存未命中时与并发的相同检查合并、复用其结果的次数
+	Actions        map[Action]int64       `json:"actions"`                    // 处理动作 -> 次数
+	Categories     map[string]int64       `json:"categories"`                 // 分类 -> 命中该分类的检查次数
+	Latency        LatencyStats           `json:"latency"`                    // 检查耗时
+	LastReloadDiff *ReloadDiffSummary     `json:"last_reload_diff,omitempty"` // 最近一次重载的变化，没有重载记录时为空
+	Lint           *LintSummary           `json:"lint,omitempty"`             // 最近一次词库检查，未检查过时为空
+	Canary         *CanaryStatus          `json:"canary,omitempty"`           // 灰度中的词库，没有时为空
+	MonitorHits    map[string]int64       `json:"monitor_hits,omitempty"`     // 只观察的敏感词 -> 累计命中次数
+	WordHits       []WordHit              `json:"word_hits,omitempty"`        // 当前词库生效以来命中最多的敏感词
+	Audit          *AuditStats            `json:"audit,omitempty"`            // 审计日志写入统计，未启用时为空
+	Sampling       *AuditStats            `json:"sampling,omitempty"`         // 抽样复核写入统计，未启用时为空
+	Review         *ReviewStats           `json:"review,omitempty"`           // 人工复核入队统计，未启用时为空
+	Anomaly        *AnomalyStatus         `json:"anomaly,omitempty"`          // 拦截率突变检测，未启用时为空
+	Escalation     *EscalationStats       `json:"escalation,omitempty"`       // 外部审核接口调用统计，未启用时为空
+	Webhooks       *WebhookStats          `json:"webhooks,omitempty"`         // 违规回调推送统计，所有回调地址合计，未配置时为空
+	CategoryFlags  *CategoryFlags         `json:"category_flags,omitempty"`   // 分类开关
+	BuildProgress  BuildStats             `json:"build_progress"`             // 最近一次自动机构建的进度
+	CacheSummary   *CacheStats            `json:"cache,omitempty"`            // 结果缓存的命中和容量统计，未启用缓存时为空
+	Cache          map[string]interface{} `json:"cache_stats,omitempty"`      // 缓存实现自身的统计，字段因实现而异，未启用缓存时为空
+}
+
+// CacheStats 结果缓存统计，命中次数由过滤器统计，对任何缓存实现都有效
+// 条目数、容量、淘汰次数和内存估算来自内置缓存，自定义缓存未提供时为0
+type CacheStats struct {
+	Hits        int64   `json:"hits"`         // 命中次数
+	Misses      int64   `json:"misses"`       // 未命中次数
+	HitRate     float64 `json:"hit_rate"`     // 命中的比例
+	Skipped     int64   `json:"skipped"`      // 文本超过 cache_max_text_length、未读写缓存的检查次数
+	Evictions   int64   `json:"evictions"`    // 因容量淘汰的条目数，不含过期
+	Entries     int     `json:"entries"`      // 当前条目数
+	Capacity    int     `json:"capacity"`     // 条目数上限，0表示不限制
+	MemoryBytes int64   `json:"memory_bytes"` // 条目占用内存的估算值
+}
+
+// LatencyStats 检查耗时（毫秒），分位数按对数分桶估算，误差不超过所在桶的宽度
+type LatencyStats struct {
+	AvgMs float64 `json:"avg_ms"` // 平均耗时
+	P50Ms float64 `json:"p50_ms"` // 50分位
+	P90Ms float64 `json:"p90_ms"` // 90分位
+	P99Ms float64 `json:"p99_ms"` // 99分位
+	MaxMs float64 `json:"max_ms"` // 最大耗时
+}
+
+// ReloadDiffSummary 词库重载差异的数量汇总，完整差异见 ReloadDiff
+type ReloadDiffSummary struct {
+	FromVersion      string    `json:"from_version"`      // 重载前的版本
+	ToVersion        string    `json:"to_version"`        // 重载后的版本
+	Time             time.Time `json:"time"`              // 重载时间
+	Added            int       `json:"added"`             // 新增的敏感词数
+	Removed          int       `json:"removed"`           // 移除的敏感词数
+	LevelChanges     int       `json:"level_changes"`     // 敏感级别变化的敏感词数
+	WhitelistAdded   int       `json:"whitelist_added"`   // 新增的白名单条目数
+	WhitelistRemoved int       `json:"whitelist_removed"` // 移除的白名单条目数
+}
+
+// LintSummary 词库检查结果的汇总，完整问题列表见 LintReport
+type LintSummary struct {
+	Version  string         `json:"version"`  // 检查的词库版本
+	Time     time.Time      `json:"time"`     // 检查时间
+	Errors   int            `json:"errors"`   // 错误数
+	Warnings int            `json:"warnings"` // 警告数
+	Rejected bool           `json:"rejected"` // 是否因检查未通过拒绝了该词库
+	Rules    map[string]int `json:"rules"`    // 规则 -> 问题数
+}
+
+// BuildStats 自动机构建进度
+type BuildStats struct {
+	Phase          string `json:"phase"`           // 当前阶段
+	Done           int    `json:"done"`            // 已处理敏感词数
+	Total          int    `json:"total"`           // 敏感词总数
+	Nodes          int    `json:"nodes"`           // 已创建节点数
+	EstimatedBytes int64  `json:"estimated_bytes"` // 估算内存占用
+}
+
+// AnomalyStatus 拦截率突变检测的最近一次判定
+type AnomalyStatus struct {
+	Anomalous      bool       `json:"anomalous"`               // 当前是否异常
+	WindowChecks   int64      `json:"window_checks"`           // 当前窗口的检查次数
+	WindowRate     float64    `json:"window_rate"`             // 当前窗口的拦截率
+	BaselineChecks int64      `json:"baseline_checks"`         // 基线窗口的检查次数
+	BaselineRate   float64    `json:"baseline_rate"`           // 基线窗口的拦截率
+	EvaluatedAt    time.Time  `json:"evaluated_at"`            // 判定时间
+	Alerts         int64      `json:"alerts"`                  // 累计发出的告警次数
+	LastAlertAt    *time.Time `json:"last_alert_at,omitempty"` // 最近一次告警的时间
+}
+
+// EscalationStats 外部审核接口调用统计
+type EscalationStats struct {
+	Requests    int64 `json:"requests"`     // 调用次数，不含缓存命中
+	CacheHits   int64 `json:"cache_hits"`   // 结论缓存命中次数
+	Failures    int64 `json:"failures"`     // 调用失败次数（超时、非2xx、响应无法解析）
+	Skipped     int64 `json:"skipped"`      // 熔断期间跳过的次数
+	Flagged     int64 `json:"flagged"`      // 接口判定违规的次数
+	CircuitOpen bool  `json:"circuit_open"` // 是否处于熔断中
+}
+
+// WebhookStats 违规回调推送统计
+type WebhookStats struct {
+	Sent    int64 `json:"sent"`    // 推送成功的事件数
+	Retries int64 `json:"retries"` // 重试次数
+	Dropped int64 `json:"dropped"` // 等待推送的缓冲区满被丢弃的事件数
+	Failed  int64 `json:"failed"`  // 重试后仍失败的事件数
+}
@@ -1,74 +1,1080 @@
 package types
 
-import "time"
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"time"
+)
 
 // FilterResult 过滤结果
 type FilterResult struct {
-	Passed     bool              `json:"passed"`     // 是否通过
-	Categories []string          `json:"categories"` // 匹配的敏感词分类
-	Words      []string          `json:"words"`      // 匹配的敏感词
-	Details    map[string]string `json:"details"`    // 详细信息
+	Passed             bool              `json:"passed"`                        // 是否通过
+	Categories         []string          `json:"categories"`                    // 匹配的敏感词分类
+	Words              []string          `json:"words"`                         // 匹配的敏感词
+	Details            map[string]string `json:"details"`                       // 附加信息，如放行原因；开启legacy_details时包含 敏感词 -> "level:3,categories:abuse"
+	Degraded           bool              `json:"degraded,omitempty"`            // 是否为降级结果
+	DegradedReason     string            `json:"degraded_reason,omitempty"`     // 降级原因
+	NeedsReview        bool              `json:"needs_review,omitempty"`        // 命中了被强制人审的分类
+	Message            string            `json:"message,omitempty"`             // 面向用户的提示语，按请求语言生成
+	FilteredText       string            `json:"filtered_text,omitempty"`       // 替换模式下打码后的文本
+	ScanStrategy       string            `json:"scan_strategy,omitempty"`       // 超长文本使用的扫描策略
+	ScanCoverage       float64           `json:"scan_coverage,omitempty"`       // 超长文本实际扫描的比例
+	Truncated          bool              `json:"truncated,omitempty"`           // 文本超过最大长度，只检查了前max_text_length字节
+	InvisibleCount     int               `json:"invisible_count,omitempty"`     // 匹配前去除的零宽、双向控制等不可见字符数量
+	InvisiblePositions []Position        `json:"invisible_positions,omitempty"` // 不可见字符在原文中的位置
+	RiskScore          float64           `json:"risk_score"`                    // 风险分，命中的敏感词权重之和（同一敏感词只计一次）
+	Action             Action            `json:"action"`                        // 处理动作: pass|mask|review|reject
+	ReasonCode         string            `json:"reason_code,omitempty"`         // 机器可读的判定原因，内置原因见 Reason* 常量，策略规则可自定义
+	Reason             string            `json:"reason,omitempty"`              // 面向人的判定原因，按请求语言从提示语目录的 "reason.<原因码>" 查找，未配置时为空
+	Matches            []MatchDetail     `json:"matches,omitempty"`             // 命中详情，每个敏感词一条
+	MaxLevel           int               `json:"max_level"`                     // 命中敏感词的最高级别，未命中为0
+	TotalMatches       int               `json:"total_matches"`                 // 命中总次数
+	CategoryCounts     map[string]int    `json:"category_counts,omitempty"`     // 分类 -> 命中次数
+	Monitored          []MatchDetail     `json:"monitored,omitempty"`           // 只观察的敏感词和分类的命中，不影响Passed和上面的命中统计
+	Offender           *OffenderStatus   `json:"offender,omitempty"`            // 检查选项带 UserID 且启用违规统计时，该用户在统计窗口内的违规次数
 }
 
+// Clone 深拷贝结果，修改副本的名单、命中详情和附加信息不影响原结果；nil和空切片保持不变
+func (r *FilterResult) Clone() *FilterResult {
+	clone := *r
+	clone.Categories = slices.Clone(r.Categories)
+	clone.Words = slices.Clone(r.Words)
+	clone.Details = maps.Clone(r.Details)
+	clone.InvisiblePositions = slices.Clone(r.InvisiblePositions)
+	clone.Matches = cloneMatches(r.Matches)
+	clone.CategoryCounts = maps.Clone(r.CategoryCounts)
+	clone.Monitored = cloneMatches(r.Monitored)
+	if r.Offender != nil {
+		offender := *r.Offender
+		clone.Offender = &offender
+	}
+	return &clone
+}
+
+// cloneMatches 深拷贝命中详情
+func cloneMatches(matches []MatchDetail) []MatchDetail {
+	clone := slices.Clone(matches)
+	for i := range clone {
+		clone[i].Categories = slices.Clone(matches[i].Categories)
+		clone[i].Positions = slices.Clone(matches[i].Positions)
+	}
+	return clone
+}
+
+// OffenderStatus 用户在统计窗口内的违规次数，调用方可据此逐级处罚（警告、禁言、封号）
+type OffenderStatus struct {
+	UserID     string `json:"user_id"`    // 用户ID
+	Violations int64  `json:"violations"` // 统计窗口内未通过的检查次数，包含本次
+}
+
+// 内置判定原因码
+const (
+	ReasonClean          = "clean"                // 未命中敏感词
+	ReasonWhitelist      = "whitelist"            // 全部命中均落在白名单短语内
+	ReasonMatched        = "matched"              // 命中敏感词，按默认规则拒绝
+	ReasonCategoryReview = "category_review"      // 命中被强制人审的分类
+	ReasonRiskPass       = "risk_below_threshold" // 风险分低于审核阈值
+	ReasonRiskReview     = "risk_review"          // 风险分达到审核阈值
+	ReasonRiskReject     = "risk_reject"          // 风险分达到拒绝阈值
+	ReasonPolicy         = "policy"               // 命中未设置原因码的策略规则或策略默认动作
+	ReasonFailClosed     = "fail_closed"          // 异常时按fail-closed策略转人工审核
+	ReasonTextTooLong    = "text_too_long"        // 文本超过最大长度被拒绝
+	ReasonExternalReject = "external_reject"      // 风险分处于临界区间，外部审核接口判定违规
+	ReasonExternalPass   = "external_pass"        // 风险分处于临界区间，外部审核接口判定正常
+)
+
+// MatchDetail 敏感词命中详情
+type MatchDetail struct {
+	Word       string     `json:"word"`       // 敏感词（标准化后）
+	Categories []string   `json:"categories"` // 生效的分类
+	Level      int        `json:"level"`      // 敏感级别
+	Positions  []Position `json:"positions"`  // 每次命中在原文中的位置
+	Count      int        `json:"count"`      // 命中次数
+}
+
+// FieldResult JSON文档中单个字段的检查结果
+type FieldResult struct {
+	Path   string        `json:"path"`   // 字段的具体路径，如 $.comments[0].text
+	Result *FilterResult `json:"result"` // 检查结果
+}
+
+// StreamMatch 流式检查中的一次命中
+type StreamMatch struct {
+	Word       string   `json:"word"`       // 敏感词（标准化后）
+	Categories []string `json:"categories"` // 生效的分类
+	Level      int      `json:"level"`      // 敏感级别
+	Start      int64    `json:"start"`      // 在整个输入中的起始字节偏移
+	End        int64    `json:"end"`        // 在整个输入中的结束字节偏移（不含）
+}
+
+// CanaryStatus 词库灰度状态
+type CanaryStatus struct {
+	Version            string    `json:"version"`              // 灰度中的词库版本
+	StableVersion      string    `json:"stable_version"`       // 正在服务的词库版本
+	StartedAt          time.Time `json:"started_at"`           // 灰度开始时间
+	Percent            float64   `json:"percent"`              // 分流比例(%)
+	Shadow             bool      `json:"shadow"`               // 是否为影子模式
+	StableRequests     int64     `json:"stable_requests"`      // 正在服务的词库统计的请求数
+	StableBlockRate    float64   `json:"stable_block_rate"`    // 正在服务的词库的拦截率
+	CandidateRequests  int64     `json:"candidate_requests"`   // 灰度词库统计的请求数
+	CandidateBlockRate float64   `json:"candidate_block_rate"` // 灰度词库的拦截率
+	BlockRateDelta     float64   `json:"block_rate_delta"`     // 拦截率变化，正数表示灰度词库拦截更多
+}
+
+// ReloadDiff 一次词库重载相对上一版本的变化
+type ReloadDiff struct {
+	FromVersion      string        `json:"from_version"`      // 重载前的版本
+	ToVersion        string        `json:"to_version"`        // 重载后的版本
+	Time             time.Time     `json:"time"`              // 重载时间
+	Added            []string      `json:"added"`             // 新增的敏感词
+	Removed          []string      `json:"removed"`           // 移除的敏感词
+	LevelChanges     []LevelChange `json:"level_changes"`     // 敏感级别变化的敏感词
+	WhitelistAdded   []string      `json:"whitelist_added"`   // 新增的白名单条目
+	WhitelistRemoved []string      `json:"whitelist_removed"` // 移除的白名单条目
+}
+
+// Empty 词库内容是否没有变化
+func (d *ReloadDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.LevelChanges) == 0 &&
+		len(d.WhitelistAdded) == 0 && len(d.WhitelistRemoved) == 0
+}
+
+// LevelChange 敏感词的级别变化
+type LevelChange struct {
+	Word string `json:"word"` // 敏感词
+	From int    `json:"from"` // 原级别
+	To   int    `json:"to"`   // 新级别
+}
+
+// 词库检查问题的严重程度
+const (
+	LintError   = "error"   // 错误，strictness为error或strict时拒绝词库
+	LintWarning = "warning" // 警告，strictness为strict时拒绝词库
+)
+
+// 词库检查规则
+const (
+	LintEmptyWord    = "empty_word"    // 敏感词为空
+	LintInvalidLevel = "invalid_level" // 敏感级别不在1-5之间
+	LintDuplicate    = "duplicate"     // 同一名单中重复出现的敏感词
+	LintWhitelisted  = "whitelisted"   // 同时在黑名单和白名单中
+	LintShortWord    = "short_word"    // 过短的敏感词，容易误判
+)
+
+// 词库检查严格程度
+const (
+	LintOff    = "off"    // 不检查
+	LintWarn   = "warn"   // 只记录问题（默认）
+	LintReject = "error"  // 有错误时拒绝词库
+	LintStrict = "strict" // 有任何问题时拒绝词库
+)
+
+// LintIssue 词库检查发现的一个问题
+type LintIssue struct {
+	Rule     string `json:"rule"`               // 规则
+	Severity string `json:"severity"`           // 严重程度
+	Word     string `json:"word"`               // 相关的敏感词或白名单条目
+	Category string `json:"category,omitempty"` // 所在分类，在黑名单中时为空
+	Message  string `json:"message"`            // 问题描述
+}
+
+// LintReport 最近一次词库检查的结果
+type LintReport struct {
+	Version  string      `json:"version"`  // 检查的词库版本
+	Time     time.Time   `json:"time"`     // 检查时间
+	Errors   int         `json:"errors"`   // 错误数
+	Warnings int         `json:"warnings"` // 警告数
+	Rejected bool        `json:"rejected"` // 是否因检查未通过拒绝了该词库
+	Issues   []LintIssue `json:"issues"`   // 发现的问题
+}
+
+// DryRunResult 候选词库在样本文本上相对当前词库的预览结果
+type DryRunResult struct {
+	LiveVersion      string         `json:"live_version"`      // 当前词库版本
+	CandidateVersion string         `json:"candidate_version"` // 候选词库版本
+	Diff             *ReloadDiff    `json:"diff"`              // 候选词库相对当前词库的变化
+	Lint             []LintIssue    `json:"lint"`              // 候选词库的检查问题
+	Texts            int            `json:"texts"`             // 样本数
+	Changed          int            `json:"changed"`           // 结果有变化（是否通过、处理动作或命中的敏感词）的样本数
+	NewlyBlocked     int            `json:"newly_blocked"`     // 当前词库通过、候选词库不通过的样本数
+	NewlyPassed      int            `json:"newly_passed"`      // 当前词库不通过、候选词库通过的样本数
+	Changes          []DryRunChange `json:"changes"`           // 结果有变化的样本
+}
+
+// DryRunChange 一条样本在当前词库和候选词库下的结果差异
+type DryRunChange struct {
+	Index           int      `json:"index"`            // 样本序号
+	Text            string   `json:"text"`             // 样本文本
+	LivePassed      bool     `json:"live_passed"`      // 当前词库是否通过
+	CandidatePassed bool     `json:"candidate_passed"` // 候选词库是否通过
+	LiveAction      Action   `json:"live_action"`      // 当前词库的处理动作
+	CandidateAction Action   `json:"candidate_action"` // 候选词库的处理动作
+	AddedWords      []string `json:"added_words"`      // 只在候选词库下命中的敏感词
+	RemovedWords    []string `json:"removed_words"`    // 候选词库下不再命中的敏感词
+}
+
+// AuditRecord 一条审核决定的审计记录
+type AuditRecord struct {
+	Time       time.Time `json:"time"`                  // 判定时间
+	TraceID    string    `json:"trace_id,omitempty"`    // 追踪/请求ID
+	Tenant     string    `json:"tenant,omitempty"`      // 租户
+	Caller     string    `json:"caller,omitempty"`      // 调用方ID
+	TextHash   string    `json:"text_hash"`             // 原文的SHA-256摘要（十六进制）
+	Text       string    `json:"text,omitempty"`        // 原文或截断后的原文，按 audit.text 配置记录
+	TextLength int       `json:"text_length"`           // 原文字节数
+	Action     Action    `json:"action"`                // 处理动作
+	ReasonCode string    `json:"reason_code,omitempty"` // 判定原因
+	Words      []string  `json:"words"`                 // 命中的敏感词
+	Categories []string  `json:"categories"`            // 命中的分类
+	MaxLevel   int       `json:"max_level"`             // 命中敏感词的最高级别
+	RiskScore  float64   `json:"risk_score"`            // 风险分
+	Degraded   bool      `json:"degraded,omitempty"`    // 是否为降级结果
+	Version    string    `json:"version"`               // 判定时的词库版本
+	Sample     string    `json:"sample,omitempty"`      // 抽样复核记录的抽样类别: blocked|passed，审计日志为空
+}
+
+// WordHit 敏感词的命中次数
+type WordHit struct {
+	Word  string `json:"word"`  // 敏感词（标准化后）
+	Count int64  `json:"count"` // 命中次数
+}
+
+// WordHitReport 当前词库生效以来的敏感词命中统计，用于清理从不命中的词条和发现过于宽泛的词条
+type WordHitReport struct {
+	Version     string    `json:"version"`      // 词库版本
+	Since       time.Time `json:"since"`        // 开始计数的时间，即当前词库的生效时间
+	Top         []WordHit `json:"top"`          // 命中最多的敏感词，按命中次数降序
+	Unused      []string  `json:"unused"`       // 生效以来从未命中的敏感词，按词语排序
+	UnusedCount int       `json:"unused_count"` // 从未命中的敏感词总数
+}
+
+// Alert 运行告警，通过日志和告警回调发出
+type Alert struct {
+	Type    string                 `json:"type"`              // 告警类型，见 Alert* 常量
+	Level   string                 `json:"level"`             // 级别: warning|critical|resolved
+	Time    time.Time              `json:"time"`              // 告警时间
+	Message string                 `json:"message"`           // 面向人的告警内容
+	Version string                 `json:"version,omitempty"` // 告警时正在服务的词库版本
+	Details map[string]interface{} `json:"details,omitempty"` // 告警相关的数据，按类型不同
+}
+
+// 告警类型
+const (
+	AlertBlockRateAnomaly = "block_rate_anomaly" // 拦截率相对基线突变
+	AlertReloadFailed     = "reload_failed"      // 词库连续重载失败
+	AlertDictionaryStale  = "dictionary_stale"   // 词库超过阈值时长未成功刷新
+)
+
+// 告警级别
+const (
+	AlertWarning  = "warning"  // 需要关注
+	AlertCritical = "critical" // 需要立即处理
+	AlertResolved = "resolved" // 之前的告警已恢复
+)
+
+// ReviewStatus 复核状态
+type ReviewStatus string
+
+const (
+	ReviewPending  ReviewStatus = "pending"  // 待复核
+	ReviewApproved ReviewStatus = "approved" // 复核通过，内容可以放行
+	ReviewRejected ReviewStatus = "rejected" // 复核驳回，内容应被拦截
+)
+
+// ReviewItem 处理动作为review的结果生成的人工复核条目
+type ReviewItem struct {
+	ID         string            `json:"id"`                    // 条目ID
+	Time       time.Time         `json:"time"`                  // 入队时间
+	TraceID    string            `json:"trace_id,omitempty"`    // 追踪/请求ID
+	Tenant     string            `json:"tenant,omitempty"`      // 租户
+	Caller     string            `json:"caller,omitempty"`      // 调用方ID
+	Metadata   map[string]string `json:"metadata,omitempty"`    // 调用方附加的信息，如内容ID、用户ID
+	Text       string            `json:"text"`                  // 原文
+	ReasonCode string            `json:"reason_code,omitempty"` // 判定原因
+	Words      []string          `json:"words"`                 // 命中的敏感词
+	Categories []string          `json:"categories"`            // 命中的分类
+	Matches    []MatchDetail     `json:"matches,omitempty"`     // 命中详情
+	MaxLevel   int               `json:"max_level"`             // 命中敏感词的最高级别
+	RiskScore  float64           `json:"risk_score"`            // 风险分
+	Version    string            `json:"version"`               // 判定时的词库版本
+	Status     ReviewStatus      `json:"status"`                // 复核状态
+	Reviewer   string            `json:"reviewer,omitempty"`    // 复核人
+	Note       string            `json:"note,omitempty"`        // 复核备注
+	ReviewedAt *time.Time        `json:"reviewed_at,omitempty"` // 复核时间
+}
+
+// ReviewDecision 复核结论
+type ReviewDecision struct {
+	ID       string       `json:"id"`             // 条目ID
+	Status   ReviewStatus `json:"status"`         // approved|rejected
+	Reviewer string       `json:"reviewer"`       // 复核人
+	Note     string       `json:"note,omitempty"` // 复核备注
+	Time     time.Time    `json:"time"`           // 复核时间
+}
+
+// ReviewStats 复核队列的入队统计
+type ReviewStats struct {
+	Enqueued int64 `json:"enqueued"` // 已入队的条目数
+	Dropped  int64 `json:"dropped"`  // 等待入队的缓冲区满被丢弃的条目数
+	Failed   int64 `json:"failed"`   // 入队失败的条目数
+}
+
+// AuditStats 审计日志的写入统计
+type AuditStats struct {
+	Written int64 `json:"written"` // 已写入的记录数
+	Dropped int64 `json:"dropped"` // 缓冲区满被丢弃的记录数
+	Failed  int64 `json:"failed"`  // 写入失败的记录数
+	Skipped int64 `json:"skipped"` // 按抽样比例未抽中的记录数
+}
+
+// Position 命中在原文中的字节区间 [Start, End)
+type Position struct {
+	Start int `json:"start"` // 起始字节偏移
+	End   int `json:"end"`   // 结束字节偏移（不含）
+}
+
+// Action 过滤结果的处理动作
+type Action string
+
+const (
+	ActionPass   Action = "pass"   // 放行
+	ActionMask   Action = "mask"   // 打码后放行，FilteredText为打码后的文本
+	ActionReview Action = "review" // 转人工审核
+	ActionReject Action = "reject" // 拒绝
+)
+
+// 降级原因
+const (
+	DegradedEmptyDictionary = "empty_dictionary" // 词库为空
+	DegradedStaleDictionary = "stale_dictionary" // 词库超过最大允许时长未成功刷新
+	DegradedTimeout         = "timeout"          // 过滤超时后的兜底结果
+	DegradedFilterError     = "filter_error"     // 过滤过程出错后的兜底结果
+	DegradedRemoteFallback  = "remote_fallback"  // 远程服务不可用，pkg/client 返回的缓存或本地实例的结果
+)
+
+// NormalizeConfig 文本标准化配置
+type NormalizeConfig struct {
+	ChineseConversion string            `json:"chinese_conversion" yaml:"chinese_conversion"` // 简繁转换: t2s 繁转简 | s2t 简转繁，为空不转换
+	Substitutions     map[string]string `json:"substitutions" yaml:"substitutions"`           // 匹配前的字符替换，键和值均为单个字符，如 "1": "i"、"@": "a"，用于识别 leetspeak 变体
+	MaxRepeat         int               `json:"max_repeat" yaml:"max_repeat"`                 // 连续重复字符最多保留的个数，如为1时 "傻傻傻逼" 按 "傻逼" 匹配；0表示不折叠
+	KeepInvisible     bool              `json:"keep_invisible" yaml:"keep_invisible"`         // 保留零宽、软连字符、双向控制等不可见字符，默认匹配前去除
+}
+
+// 简繁转换方向
+const (
+	ConvertT2S = "t2s" // 繁体转简体
+	ConvertS2T = "s2t" // 简体转繁体
+)
+
+// 自动机内存布局
+const (
+	LayoutMap  = "map"  // 每个节点一个map，构建后仍可增量修改
+	LayoutFlat = "flat" // 紧凑的连续切片布局，内存占用小，只读
+)
+
+// 异常处理策略
+const (
+	FailOpen   = "open"   // 异常时放行
+	FailClosed = "closed" // 异常时拒绝并转人工审核
+)
+
 // SensitiveWord 敏感词结构
 type SensitiveWord struct {
-	Word       string   `json:"word"`       // 敏感词
-	Categories []string `json:"categories"` // 分类
-	Level      int      `json:"level"`      // 敏感级别 1-5
+	Word        string     `json:"word"`                   // 敏感词
+	Categories  []string   `json:"categories"`             // 分类
+	Level       int        `json:"level"`                  // 敏感级别 1-5
+	Pinyin      bool       `json:"pinyin,omitempty"`       // 是否启用拼音匹配，如 "minganci" 命中 "敏感词"，开销较大且易误判
+	WholeWord   bool       `json:"whole_word,omitempty"`   // 是否只匹配完整单词，如 "ass" 不命中 "classic"，只对字母文字生效
+	Fuzzy       bool       `json:"fuzzy,omitempty"`        // 是否启用编辑距离匹配，替换、缺失或多出一个字符也算命中，至少3个字符，开销较大且易误判，只应对高敏感级别的词语启用
+	Weight      float64    `json:"weight,omitempty"`       // 风险权重，命中后累加到风险分，为0时使用敏感级别
+	Exclusions  []string   `json:"exclusions,omitempty"`   // 排除语境，命中落在任一短语内时不算命中，短语需包含敏感词本身，如 "出售" 的 "禁止出售"
+	Monitor     bool       `json:"monitor,omitempty"`      // 只观察：命中记入统计和日志、结果的Monitored，但不影响Passed，用于在线上流量中试运行新规则
+	ActiveFrom  *time.Time `json:"active_from,omitempty"`  // 生效时间，之前不参与匹配，为空时立即生效
+	ActiveUntil *time.Time `json:"active_until,omitempty"` // 失效时间，之后不参与匹配，为空时长期有效；用于只在特定时段生效的词语
 }
 
+// 词库配置源类型
+const (
+	SourceNacos  = "nacos"  // Nacos配置中心（默认）
+	SourceEtcd   = "etcd"   // etcd
+	SourceApollo = "apollo" // Apollo配置中心
+	SourceRedis  = "redis"  // Redis，通过 pub/sub 推送变更
+	SourceMemory = "memory" // 进程内存，不连接配置中心，用于本地模式和测试
+)
+
 // Config 配置结构
 type Config struct {
-	NacosConfig NacosConfig `json:"nacos_config"`
-	FilterConfig FilterConfig `json:"filter_config"`
+	Source       string         `json:"source" yaml:"source"` // 词库配置源: nacos|etcd|apollo|redis|memory，默认nacos
+	NacosConfig  NacosConfig    `json:"nacos_config" yaml:"nacos_config"`
+	EtcdConfig   EtcdConfig     `json:"etcd_config" yaml:"etcd_config"`
+	ApolloConfig ApolloConfig   `json:"apollo_config" yaml:"apollo_config"`
+	RedisConfig  RedisConfig    `json:"redis_config" yaml:"redis_config"`
+	FilterConfig FilterConfig   `json:"filter_config" yaml:"filter_config"`
+	Admin        AdminConfig    `json:"admin" yaml:"admin"`       // 管理接口配置
+	Consumer     ConsumerConfig `json:"consumer" yaml:"consumer"` // Kafka消费模式配置，通过 guardian consume 启动
+}
+
+// ConsumerConfig Kafka消费模式配置，通过 Kafka REST Proxy（v2 接口）消费主题，检查消息中的字段后写入输出主题
+// 输出写入成功后才提交位移，进程重启后未提交的消息会重新处理
+type ConsumerConfig struct {
+	URL            string            `json:"url" yaml:"url"`                         // Kafka REST Proxy地址
+	Headers        map[string]string `json:"headers" yaml:"headers"`                 // 请求附加的请求头，如鉴权
+	Group          string            `json:"group" yaml:"group"`                     // 消费组，默认guardian
+	Topic          string            `json:"topic" yaml:"topic"`                     // 输入主题，消息为JSON
+	Field          string            `json:"field" yaml:"field"`                     // 要检查的字段，JSONPath风格，如 "$.comment.text"，默认 "$.text"
+	OutputTopic    string            `json:"output_topic" yaml:"output_topic"`       // 检查结果写入的主题
+	ViolationsOnly bool              `json:"violations_only" yaml:"violations_only"` // 只输出未通过的消息
+	Scene          string            `json:"scene" yaml:"scene"`                     // 使用的场景检查选项，为空时使用默认选项
+	BatchSize      int               `json:"batch_size" yaml:"batch_size"`           // 每批最多处理的消息数，每批写入一次输出并提交一次位移，默认100
+	OffsetReset    string            `json:"offset_reset" yaml:"offset_reset"`       // 消费组没有已提交位移时的起始位置: latest（默认）|earliest
+	PollTimeout    time.Duration     `json:"poll_timeout" yaml:"poll_timeout"`       // 拉取消息时等待新消息的最长时间，默认1s
+	Timeout        time.Duration     `json:"timeout" yaml:"timeout"`                 // REST Proxy请求超时（不含等待新消息的时间），默认10s
+}
+
+// 消费组没有已提交位移时的起始位置
+const (
+	OffsetResetLatest   = "latest"   // 只消费启动后的新消息
+	OffsetResetEarliest = "earliest" // 从最早的消息开始消费
+)
+
+// AdminConfig 管理接口配置
+type AdminConfig struct {
+	Token      string `json:"token" yaml:"token"`             // 访问 /admin 接口的令牌，通过 "Authorization: Bearer <token>" 传递；为空时不开放管理接口
+	SigningKey string `json:"signing_key" yaml:"signing_key"` // 词库签名私钥（Ed25519，PEM或base64），设置后发布的词库带签名，filter_config配置了public_key时必填
 }
 
 // NacosConfig Nacos配置
 type NacosConfig struct {
-	ServerConfigs []ServerConfig `json:"server_configs"`
-	ClientConfig  ClientConfig   `json:"client_config"`
+	ServerConfigs []ServerConfig `json:"server_configs" yaml:"server_configs"`
+	ClientConfig  ClientConfig   `json:"client_config" yaml:"client_config"`
 }
 
 // ServerConfig Nacos服务器配置
 type ServerConfig struct {
-	IpAddr string `json:"ip_addr"`
-	Port   uint64 `json:"port"`
+	IpAddr string `json:"ip_addr" yaml:"ip_addr"`
+	Port   uint64 `json:"port" yaml:"port"`
 }
 
 // ClientConfig Nacos客户端配置
 type ClientConfig struct {
-	NamespaceId         string `json:"namespace_id"`
-	TimeoutMs           uint64 `json:"timeout_ms"`
-	NotLoadCacheAtStart bool   `json:"not_load_cache_at_start"`
-	LogDir              string `json:"log_dir"`
-	CacheDir            string `json:"cache_dir"`
-	LogLevel            string `json:"log_level"`
+	NamespaceId         string `json:"namespace_id" yaml:"namespace_id"`
+	TimeoutMs           uint64 `json:"timeout_ms" yaml:"timeout_ms"`
+	NotLoadCacheAtStart bool   `json:"not_load_cache_at_start" yaml:"not_load_cache_at_start"`
+	LogDir              string `json:"log_dir" yaml:"log_dir"`
+	CacheDir            string `json:"cache_dir" yaml:"cache_dir"`
+	LogLevel            string `json:"log_level" yaml:"log_level"`
+}
+
+// EtcdConfig etcd配置，DataId/Group 映射为键 {key_prefix}/{group}/{data_id}
+type EtcdConfig struct {
+	Endpoints []string `json:"endpoints" yaml:"endpoints"`   // 集群地址
+	Username  string   `json:"username" yaml:"username"`     // 用户名
+	Password  string   `json:"password" yaml:"password"`     // 密码
+	TimeoutMs uint64   `json:"timeout_ms" yaml:"timeout_ms"` // 请求超时
+	KeyPrefix string   `json:"key_prefix" yaml:"key_prefix"` // 键前缀，默认/guardian
+}
+
+// ApolloConfig Apollo配置，Group 映射为命名空间，DataId 映射为命名空间下的键
+type ApolloConfig struct {
+	AppId      string   `json:"app_id" yaml:"app_id"`           // 应用ID
+	Cluster    string   `json:"cluster" yaml:"cluster"`         // 集群，默认default
+	MetaAddr   string   `json:"meta_addr" yaml:"meta_addr"`     // Meta Server / Config Service 地址
+	Secret     string   `json:"secret" yaml:"secret"`           // 访问密钥
+	Namespaces []string `json:"namespaces" yaml:"namespaces"`   // 启动时加载的命名空间，默认application
+	BackupPath string   `json:"backup_path" yaml:"backup_path"` // 本地备份目录，为空则不备份
+	TimeoutMs  uint64   `json:"timeout_ms" yaml:"timeout_ms"`   // 开放平台请求超时
+	PortalAddr string   `json:"portal_addr" yaml:"portal_addr"` // Portal地址，发布词库时使用
+	Env        string   `json:"env" yaml:"env"`                 // 发布环境，默认DEV
+	Token      string   `json:"token" yaml:"token"`             // 开放平台令牌
+	Operator   string   `json:"operator" yaml:"operator"`       // 发布操作人，默认apollo
+}
+
+// RedisConfig Redis配置，DataId/Group 映射为键 {key_prefix}:{group}:{data_id}
+type RedisConfig struct {
+	Addrs      []string `json:"addrs" yaml:"addrs"`             // 地址，多个地址时使用集群模式
+	MasterName string   `json:"master_name" yaml:"master_name"` // 哨兵模式主节点名称
+	Username   string   `json:"username" yaml:"username"`       // 用户名
+	Password   string   `json:"password" yaml:"password"`       // 密码
+	DB         int      `json:"db" yaml:"db"`                   // 数据库编号
+	TimeoutMs  uint64   `json:"timeout_ms" yaml:"timeout_ms"`   // 请求超时
+	KeyPrefix  string   `json:"key_prefix" yaml:"key_prefix"`   // 键前缀，默认guardian
+	Channel    string   `json:"channel" yaml:"channel"`         // 变更通知频道，为空则使用键名作为频道
 }
 
 // FilterConfig 过滤器配置
 type FilterConfig struct {
-	DataId        string        `json:"data_id"`        // 配置ID
-	Group         string        `json:"group"`          // 配置组
-	ReloadPeriod  time.Duration `json:"reload_period"`  // 重载周期
-	EnableCache   bool          `json:"enable_cache"`   // 是否启用缓存
-	CacheSize     int           `json:"cache_size"`     // 缓存大小
-	EnableWhitelist bool        `json:"enable_whitelist"` // 是否启用白名单
+	DataId               string                       `json:"data_id" yaml:"data_id"`                               // 配置ID
+	Group                string                       `json:"group" yaml:"group"`                                   // 配置组
+	ReloadPeriod         time.Duration                `json:"reload_period" yaml:"reload_period"`                   // 重载周期
+	EnableCache          bool                         `json:"enable_cache" yaml:"enable_cache"`                     // 是否启用缓存
+	CacheSize            int                          `json:"cache_size" yaml:"cache_size"`                         // 缓存大小
+	CacheTTL             time.Duration                `json:"cache_ttl" yaml:"cache_ttl"`                           // 缓存结果的有效期，默认10m
+	CacheMaxTextLength   int                          `json:"cache_max_text_length" yaml:"cache_max_text_length"`   // 缓存的文本最大字节数，默认65536，更长的文本不读写缓存，避免少量超长文本挤掉大量短文本；负数表示不限制
+	CacheShards          int                          `json:"cache_shards" yaml:"cache_shards"`                     // 进程内缓存的分段数，各分段独立加锁以减少高并发下的锁争用，默认16，1表示不分段
+	CacheStore           string                       `json:"cache_store" yaml:"cache_store"`                       // 缓存存储: memory（默认）|redis，redis时进程内LRU之外再使用Redis，多实例共享、重启后仍然有效
+	CacheRedis           RedisConfig                  `json:"cache_redis" yaml:"cache_redis"`                       // cache_store为redis时的连接配置，键为 {key_prefix}:cache:{hash}
+	EnableWhitelist      bool                         `json:"enable_whitelist" yaml:"enable_whitelist"`             // 是否启用白名单
+	ArtifactPath         string                       `json:"artifact_path" yaml:"artifact_path"`                   // 预编译词库产物路径，设置后从产物加载词库
+	InvalidationDataId   string                       `json:"invalidation_data_id" yaml:"invalidation_data_id"`     // 集群缓存失效广播使用的dataId，为空则不启用
+	MaxStaleness         time.Duration                `json:"max_staleness" yaml:"max_staleness"`                   // 词库最大允许未刷新时长，超过后结果标记为降级，0表示不限制
+	BuildMemoryBudgetMB  int                          `json:"build_memory_budget_mb" yaml:"build_memory_budget_mb"` // 自动机构建内存预算(MB)，按 automaton_layout 的布局估算，超出时放弃本次更新并保留旧词库，0表示不限制
+	FlagsDataId          string                       `json:"flags_data_id" yaml:"flags_data_id"`                   // 分类开关配置的dataId，为空则不启用
+	PolicyDataId         string                       `json:"policy_data_id" yaml:"policy_data_id"`                 // 处理策略配置的dataId，为空则按风险分阈值和分类开关判定
+	DefaultLocale        string                       `json:"default_locale" yaml:"default_locale"`                 // 提示语默认语言，默认zh-CN
+	Messages             map[string]map[string]string `json:"messages" yaml:"messages"`                             // 自定义提示语：语言 -> 分类 -> 文案，覆盖内置文案
+	LongText             LongTextConfig               `json:"long_text" yaml:"long_text"`                           // 超长文本扫描配置
+	MaxTextLength        int                          `json:"max_text_length" yaml:"max_text_length"`               // 文本最大字节数，0表示不限制
+	MaxTextLengthAction  string                       `json:"max_text_length_action" yaml:"max_text_length_action"` // 文本超过最大长度时的处理: truncate|reject，默认truncate
+	BatchWorkers         int                          `json:"batch_workers" yaml:"batch_workers"`                   // 批量检查的并发数，默认为CPU核数，1表示逐条检查
+	WordSources          []WordSource                 `json:"word_sources" yaml:"word_sources"`                     // 启动时按顺序尝试的词库来源，为空则只使用配置源
+	SnapshotPath         string                       `json:"snapshot_path" yaml:"snapshot_path"`                   // 词库快照文件路径，每次成功加载后写入，配置源不可用时用于恢复
+	FailurePolicy        string                       `json:"failure_policy" yaml:"failure_policy"`                 // 词库为空、词库源不可用或过滤出错时的处理策略: open|closed，默认open
+	AutomatonLayout      string                       `json:"automaton_layout" yaml:"automaton_layout"`             // 自动机内存布局: map|flat，默认map；大词库建议flat，直接构建为连续数组，不经过map布局
+	AutomatonCachePath   string                       `json:"automaton_cache_path" yaml:"automaton_cache_path"`     // 编译后自动机的磁盘缓存路径，词库版本未变时启动直接加载，为空则不缓存
+	MonitorCategories    []string                     `json:"monitor_categories" yaml:"monitor_categories"`         // 只观察的分类，命中记入统计和日志但不影响结果，等同于分类开关monitor
+	WholeWordCategories  []string                     `json:"whole_word_categories" yaml:"whole_word_categories"`   // 只匹配完整单词的分类，分类下所有敏感词等同于设置了whole_word
+	RiskThresholds       RiskThresholds               `json:"risk_thresholds" yaml:"risk_thresholds"`               // 风险分阈值，未设置时任何命中均拒绝
+	LegacyDetails        bool                         `json:"legacy_details" yaml:"legacy_details"`                 // 兼容旧版本，在Details中按 "level:3,categories:abuse" 格式输出命中详情，新代码请使用Matches
+	Scenes               map[string]SceneConfig       `json:"scenes" yaml:"scenes"`                                 // 场景名 -> 检查选项，如 nickname、comment、private_chat、live_barrage
+	Normalize            NormalizeConfig              `json:"normalize" yaml:"normalize"`                           // 文本标准化配置，同时作用于待检查文本和词库
+	Contact              ContactConfig                `json:"contact" yaml:"contact"`                               // 联系方式（网址、邮箱、手机号）检测配置
+	PersistWhitelist     bool                         `json:"persist_whitelist" yaml:"persist_whitelist"`           // 运行时白名单修改是否写回词库源（递增版本后发布），使修改在重载、重启后保留并同步到其他实例
+	PersistWords         bool                         `json:"persist_words" yaml:"persist_words"`                   // 运行时敏感词增删是否写回词库源，语义同persist_whitelist
+	RebuildDebounce      time.Duration                `json:"rebuild_debounce" yaml:"rebuild_debounce"`             // 运行时增删敏感词后等待的时间，期间的修改合并为一次自动机重建，默认1s
+	WordFormat           string                       `json:"word_format" yaml:"word_format"`                       // 词库格式: auto（默认，按内容识别）|json|yaml|text|csv，写回词库时总是发布JSON
+	MergeDataIds         []WordDataSet                `json:"merge_data_ids" yaml:"merge_data_ids"`                 // 与data_id合并的其他词库，按顺序合并并各自监听变更，如公司通用词库+产品词库
+	MergeStrategy        string                       `json:"merge_strategy" yaml:"merge_strategy"`                 // 多个词库中同一敏感词的冲突处理: override|keep_first|max_level，默认override
+	AllowVersionRollback bool                         `json:"allow_version_rollback" yaml:"allow_version_rollback"` // 是否允许配置源的词库版本回退，默认拒绝比已加载版本旧的词库，回滚词库时临时开启
+	Canary               CanaryConfig                 `json:"canary" yaml:"canary"`                                 // 新词库灰度配置，未启用时新词库立即全量生效
+	DiffHistory          int                          `json:"diff_history" yaml:"diff_history"`                     // 保留最近多少次词库重载的差异，默认10
+	Lint                 LintConfig                   `json:"lint" yaml:"lint"`                                     // 词库检查配置，新词库生效前检查空词、非法级别、重复等问题
+	Audit                AuditConfig                  `json:"audit" yaml:"audit"`                                   // 审计日志配置，记录未通过的审核决定
+	Sampling             SamplingConfig               `json:"sampling" yaml:"sampling"`                             // 抽样复核配置，按比例抽取结果供人工复核
+	Review               ReviewConfig                 `json:"review" yaml:"review"`                                 // 人工复核队列配置，处理动作为review的结果入队
+	Anomaly              AnomalyConfig                `json:"anomaly" yaml:"anomaly"`                               // 拦截率突变检测配置
+	ReloadAlert          ReloadAlertConfig            `json:"reload_alert" yaml:"reload_alert"`                     // 词库重载告警配置，连续重载失败或词库长时间未刷新时告警
+	AlertNotifiers       []AlertNotifierConfig        `json:"alert_notifiers" yaml:"alert_notifiers"`               // 告警通道（钉钉、飞书、企业微信机器人等），所有告警都会发送到这些通道
+	Escalation           EscalationConfig             `json:"escalation" yaml:"escalation"`                         // 外部审核接口配置，风险分处于临界区间时调用并采用其结论
+	Classifier           ClassifierConfig             `json:"classifier" yaml:"classifier"`                         // 文本分类模型配置，违规概率计入风险分
+	Offender             OffenderConfig               `json:"offender" yaml:"offender"`                             // 用户违规统计配置，按检查选项中的 UserID 累计滚动窗口内的违规次数
+	ViolationWebhooks    []ViolationWebhookConfig     `json:"violation_webhooks" yaml:"violation_webhooks"`         // 违规回调，检查未通过且达到级别或命中分类时推送违规事件，如工单、用户运营系统
+	PublicKey            string                       `json:"public_key" yaml:"public_key"`                         // 词库签名公钥（Ed25519，PEM或base64），设置后拒绝签名无效的词库更新
+	ShardSize            int                          `json:"shard_size" yaml:"shard_size"`                         // 写回词库时单个配置的最大字节数，超过时拆分为 {data_id}_part_{i} 分片和索引发布，0表示不拆分
+}
+
+// WordDataSet 词库在配置源中的位置
+type WordDataSet struct {
+	DataId string `json:"data_id" yaml:"data_id"` // 配置ID
+	Group  string `json:"group" yaml:"group"`     // 配置组，为空时使用filter_config.group
+	Format string `json:"format" yaml:"format"`   // 词库格式，同 filter_config.word_format
+}
+
+// 词库格式
+const (
+	WordFormatAuto = "auto" // 按内容识别（默认）
+	WordFormatJSON = "json" // WordDatabase JSON，支持分片索引
+	WordFormatYAML = "yaml" // 与JSON结构相同的YAML
+	WordFormatText = "text" // 每行一个词，支持 [分类] 或 ## 分类 作为分类标题
+	WordFormatCSV  = "csv"  // word,category,level，表头可选
+)
+
+// 多词库合并的冲突处理策略
+const (
+	MergeOverride  = "override"   // 后面的词库覆盖前面的定义
+	MergeKeepFirst = "keep_first" // 保留最先出现的定义
+	MergeMaxLevel  = "max_level"  // 保留级别最高的定义，分类取并集
+)
+
+// CanaryConfig 新词库灰度配置
+// 配置源推送的新版本先只用于部分请求（或影子检查），观察期结束后拦截率上升不超过阈值时全量生效，否则放弃
+type CanaryConfig struct {
+	Enabled           bool          `json:"enabled" yaml:"enabled"`                           // 是否启用
+	Percent           float64       `json:"percent" yaml:"percent"`                           // 分流到新词库的请求比例(%)，按文本哈希分组，同一文本总在同一组
+	Shadow            bool          `json:"shadow" yaml:"shadow"`                             // 影子模式：分流的请求仍使用当前词库的结果，新词库只用于对比
+	Duration          time.Duration `json:"duration" yaml:"duration"`                         // 观察时长，默认10m
+	MinRequests       int           `json:"min_requests" yaml:"min_requests"`                 // 每组最少请求数，不足时延长观察，默认1000
+	MaxBlockRateDelta float64       `json:"max_block_rate_delta" yaml:"max_block_rate_delta"` // 允许的拦截率上升幅度，如0.05表示上升5个百分点，默认0.05
+}
+
+// LintConfig 词库检查配置
+type LintConfig struct {
+	Strictness    string   `json:"strictness" yaml:"strictness"`           // 严格程度：off、warn（默认，只记录）、error（有错误时拒绝）、strict（有任何问题时拒绝）
+	MinWordLength int      `json:"min_word_length" yaml:"min_word_length"` // 短于该字符数的敏感词报告short_word警告，默认2
+	Ignore        []string `json:"ignore" yaml:"ignore"`                   // 忽略的规则，如 ["short_word"]
+}
+
+// SinkConfig 审计记录的输出配置，审计日志和抽样复核共用
+// 记录在后台按批写入，不阻塞检查；缓冲区满时丢弃并计入统计
+type SinkConfig struct {
+	Sink          string            `json:"sink" yaml:"sink"`                     // 输出: file|http|kafka
+	Path          string            `json:"path" yaml:"path"`                     // sink为file时的文件路径，按JSON Lines追加写入
+	URL           string            `json:"url" yaml:"url"`                       // sink为http时的接收地址（POST记录数组）；sink为kafka时为Kafka REST Proxy地址
+	Topic         string            `json:"topic" yaml:"topic"`                   // sink为kafka时写入的主题
+	Headers       map[string]string `json:"headers" yaml:"headers"`               // http和kafka请求附加的请求头，如鉴权
+	BufferSize    int               `json:"buffer_size" yaml:"buffer_size"`       // 等待写入的记录数上限，默认10000
+	BatchSize     int               `json:"batch_size" yaml:"batch_size"`         // 每批写入的最大记录数，默认100
+	FlushInterval time.Duration     `json:"flush_interval" yaml:"flush_interval"` // 不足一批时的最长等待时间，默认1s
+	Timeout       time.Duration     `json:"timeout" yaml:"timeout"`               // http和kafka请求超时，默认5s
+}
+
+// AuditConfig 审计日志配置，记录未通过的审核决定用于合规留证
+type AuditConfig struct {
+	SinkConfig `yaml:",inline"` // 输出配置
+
+	Enabled       bool     `json:"enabled" yaml:"enabled"`                 // 是否启用
+	Actions       []Action `json:"actions" yaml:"actions"`                 // 记录的处理动作，默认 mask、review、reject
+	SampleRate    float64  `json:"sample_rate" yaml:"sample_rate"`         // 记录比例(0, 1]，默认1即全部记录
+	Text          string   `json:"text" yaml:"text"`                       // 原文的记录方式: hash（默认，只记录摘要）|truncate|full
+	MaxTextLength int      `json:"max_text_length" yaml:"max_text_length"` // text为truncate时保留的字符数，默认200
+}
+
+// SamplingConfig 抽样复核配置，按比例抽取未通过和通过的结果（含完整原文）写入复核队列，用于持续评估审核质量
+type SamplingConfig struct {
+	SinkConfig `yaml:",inline"` // 输出配置
+
+	Enabled     bool    `json:"enabled" yaml:"enabled"`           // 是否启用
+	BlockedRate float64 `json:"blocked_rate" yaml:"blocked_rate"` // 未通过结果的抽样比例[0, 1]，如0.01
+	PassedRate  float64 `json:"passed_rate" yaml:"passed_rate"`   // 通过结果的抽样比例[0, 1]，如0.001
+}
+
+// 审计日志输出
+const (
+	AuditSinkFile  = "file"  // 本地文件，JSON Lines
+	AuditSinkHTTP  = "http"  // HTTP接口
+	AuditSinkKafka = "kafka" // Kafka REST Proxy
+)
+
+// 抽样复核记录的抽样类别
+const (
+	SampleBlocked = "blocked" // 未通过的结果
+	SamplePassed  = "passed"  // 通过的结果
+)
+
+// ReviewConfig 人工复核队列配置，处理动作为review的结果在后台入队，复核人员通过管理接口通过或驳回
+type ReviewConfig struct {
+	Enabled       bool              `json:"enabled" yaml:"enabled"`               // 是否启用
+	Queue         string            `json:"queue" yaml:"queue"`                   // 队列: memory（默认，进程内，重启后丢失）|redis|kafka
+	Capacity      int               `json:"capacity" yaml:"capacity"`             // memory队列的待复核条目上限，默认10000，已复核的条目最多同样保留该数量
+	Redis         RedisConfig       `json:"redis" yaml:"redis"`                   // queue为redis时的连接配置，键为 {key_prefix}:{review}:*
+	Retention     time.Duration     `json:"retention" yaml:"retention"`           // redis队列中条目的保留时间，默认168h
+	URL           string            `json:"url" yaml:"url"`                       // queue为kafka时的Kafka REST Proxy地址
+	Topic         string            `json:"topic" yaml:"topic"`                   // queue为kafka时待复核条目写入的主题
+	DecisionTopic string            `json:"decision_topic" yaml:"decision_topic"` // queue为kafka时复核结论写入的主题，默认 {topic}-decisions
+	Headers       map[string]string `json:"headers" yaml:"headers"`               // kafka请求附加的请求头，如鉴权
+	BufferSize    int               `json:"buffer_size" yaml:"buffer_size"`       // 等待入队的条目数上限，默认1000，满时丢弃
+	Timeout       time.Duration     `json:"timeout" yaml:"timeout"`               // 入队请求超时，默认5s
+}
+
+// 检查结果缓存存储
+const (
+	CacheStoreMemory = "memory" // 进程内LRU
+	CacheStoreRedis  = "redis"  // 进程内LRU + Redis，多实例共享
+)
+
+// 违规统计存储
+const (
+	OffenderStoreMemory = "memory" // 进程内，多实例各自统计
+	OffenderStoreRedis  = "redis"  // Redis有序集合，多实例共享
+)
+
+// OffenderConfig 用户违规统计配置，未通过的检查计为一次违规，降级结果不计入
+type OffenderConfig struct {
+	Enabled  bool          `json:"enabled" yaml:"enabled"`   // 是否启用
+	Store    string        `json:"store" yaml:"store"`       // 存储: memory（默认）|redis
+	Window   time.Duration `json:"window" yaml:"window"`     // 滚动统计窗口，默认24h
+	Capacity int           `json:"capacity" yaml:"capacity"` // memory存储的用户数上限，默认100000，超过时淘汰最久没有违规的用户
+	Redis    RedisConfig   `json:"redis" yaml:"redis"`       // store为redis时的连接配置，键为 {key_prefix}:offender:{user_id}
+}
+
+// AnomalyConfig 拦截率突变检测配置，比较最近窗口与之前基线窗口的拦截率，突增或突降时告警
+// 词库发布后拦截率从2%跳到40%几乎总是错误的词库，反之可能是词库被清空
+type AnomalyConfig struct {
+	Enabled    bool              `json:"enabled" yaml:"enabled"`         // 是否启用
+	Window     time.Duration     `json:"window" yaml:"window"`           // 当前窗口长度，默认5m
+	Baseline   time.Duration     `json:"baseline" yaml:"baseline"`       // 基线窗口长度（当前窗口之前），默认1h
+	MinChecks  int64             `json:"min_checks" yaml:"min_checks"`   // 当前窗口和基线窗口各自的最少检查次数，不足时不判定，默认100
+	Ratio      float64           `json:"ratio" yaml:"ratio"`             // 当前拦截率达到基线的该倍数（或降到基线的该分之一）时告警，默认3
+	MinDelta   float64           `json:"min_delta" yaml:"min_delta"`     // 拦截率变化的最小绝对值，避免低拦截率下的小波动告警，默认0.1
+	Cooldown   time.Duration     `json:"cooldown" yaml:"cooldown"`       // 持续异常时重复告警的间隔，默认30m
+	WebhookURL string            `json:"webhook_url" yaml:"webhook_url"` // 告警回调地址，POST告警JSON，为空时只记录日志和统计
+	Headers    map[string]string `json:"headers" yaml:"headers"`         // 告警回调附加的请求头，如鉴权
+}
+
+// ReloadAlertConfig 词库重载告警配置，连续重载失败或词库长时间未刷新时告警，避免长期使用旧词库而无人察觉
+// 告警发送到 webhook_url，同时调用 OnReloadError 注册的回调，问题消除后各发送一次恢复告警
+type ReloadAlertConfig struct {
+	FailureThreshold int               `json:"failure_threshold" yaml:"failure_threshold"` // 连续重载失败多少次后告警，默认3
+	StaleAfter       time.Duration     `json:"stale_after" yaml:"stale_after"`             // 词库超过该时长未成功刷新时告警，默认同 max_staleness，0表示不检查
+	WebhookURL       string            `json:"webhook_url" yaml:"webhook_url"`             // 告警回调地址，POST告警JSON，为空时只记录日志和调用注册的回调
+	Headers          map[string]string `json:"headers" yaml:"headers"`                     // 告警回调附加的请求头，如鉴权
+}
+
+// 告警通道类型
+const (
+	AlertNotifierWebhook  = "webhook"  // POST告警JSON到指定地址
+	AlertNotifierDingTalk = "dingtalk" // 钉钉群机器人
+	AlertNotifierFeishu   = "feishu"   // 飞书群机器人
+	AlertNotifierWeCom    = "wecom"    // 企业微信群机器人
+)
+
+// AlertNotifierConfig 告警通道配置
+type AlertNotifierConfig struct {
+	Type    string            `json:"type" yaml:"type"`       // 通道类型：webhook（默认）| dingtalk | feishu | wecom
+	URL     string            `json:"url" yaml:"url"`         // 回调地址或机器人的webhook地址
+	Secret  string            `json:"secret" yaml:"secret"`   // 钉钉、飞书机器人的加签密钥，为空时不签名
+	Headers map[string]string `json:"headers" yaml:"headers"` // webhook附加的请求头，如鉴权
+}
+
+// ViolationWebhookConfig 违规回调配置，min_level 和 categories 满足其一即推送，都未设置时推送所有未通过的结果
+type ViolationWebhookConfig struct {
+	URL        string            `json:"url" yaml:"url"`                 // 回调地址，POST违规事件JSON
+	Headers    map[string]string `json:"headers" yaml:"headers"`         // 附加的请求头，如鉴权
+	Secret     string            `json:"secret" yaml:"secret"`           // 签名密钥，设置后请求带 X-Guardian-Timestamp 和 X-Guardian-Signature
+	MinLevel   int               `json:"min_level" yaml:"min_level"`     // 命中敏感词的最高级别不低于该值时推送，0表示不按级别
+	Categories []string          `json:"categories" yaml:"categories"`   // 命中其中任一分类时推送
+	MaxRetries int               `json:"max_retries" yaml:"max_retries"` // 请求失败（网络错误、429、5xx）后的最大重试次数，默认3，负数表示不重试
+	Timeout    time.Duration     `json:"timeout" yaml:"timeout"`         // 单次请求超时，默认5s
+	BufferSize int               `json:"buffer_size" yaml:"buffer_size"` // 等待推送的事件数上限，默认1000，满时丢弃
+}
+
+// ViolationEvent 违规回调推送的事件
+type ViolationEvent struct {
+	ID         string            `json:"id"`                    // 事件ID，重试时不变，可用于去重
+	Time       time.Time         `json:"time"`                  // 检查时间
+	TraceID    string            `json:"trace_id,omitempty"`    // 追踪/请求ID
+	Tenant     string            `json:"tenant,omitempty"`      // 租户
+	Caller     string            `json:"caller,omitempty"`      // 调用方ID
+	Metadata   map[string]string `json:"metadata,omitempty"`    // 调用方附加的信息，如内容ID
+	UserID     string            `json:"user_id,omitempty"`     // 检查选项中的用户ID
+	Violations int64             `json:"violations,omitempty"`  // 启用违规统计时该用户在统计窗口内的违规次数
+	Text       string            `json:"text"`                  // 原文
+	Action     Action            `json:"action"`                // 处理动作
+	ReasonCode string            `json:"reason_code,omitempty"` // 判定原因
+	Words      []string          `json:"words"`                 // 命中的敏感词
+	Categories []string          `json:"categories"`            // 命中的分类
+	Matches    []MatchDetail     `json:"matches,omitempty"`     // 命中详情
+	MaxLevel   int               `json:"max_level"`             // 命中敏感词的最高级别
+	RiskScore  float64           `json:"risk_score"`            // 风险分
+	Version    string            `json:"version"`               // 判定时的词库版本
+}
+
+// 复核队列
+const (
+	ReviewQueueMemory = "memory" // 进程内队列
+	ReviewQueueRedis  = "redis"  // Redis列表
+	ReviewQueueKafka  = "kafka"  // Kafka REST Proxy，只写入，不支持查询待复核条目
+)
+
+// 审计日志中原文的记录方式
+const (
+	AuditTextHash     = "hash"     // 只记录摘要
+	AuditTextTruncate = "truncate" // 记录截断后的原文
+	AuditTextFull     = "full"     // 记录完整原文
+)
+
+// 词库来源类型
+const (
+	WordSourceRemote   = "remote"   // 词库配置源（Nacos、etcd等）
+	WordSourceFile     = "file"     // 本地词库文件
+	WordSourceSnapshot = "snapshot" // 本地词库快照（snapshot_path）
+	WordSourceEmbedded = "embedded" // 内置默认词库
+)
+
+// WordSource 启动词库来源
+type WordSource struct {
+	Type string `json:"type" yaml:"type"` // 来源类型: remote|file|snapshot|embedded
+	Path string `json:"path" yaml:"path"` // 本地词库文件路径，type为file时必填
+}
+
+// 超长文本扫描策略
+const (
+	ScanFull     = "full"      // 全文扫描
+	ScanHeadTail = "head_tail" // 只扫描首尾窗口
+	ScanRandom   = "random"    // 按覆盖率随机抽取窗口
+)
+
+// 超过最大长度的文本处理方式
+const (
+	TextTruncate = "truncate" // 截断到最大长度后检查，结果标记Truncated
+	TextReject   = "reject"   // 直接拒绝
+)
+
+// 内置联系方式检测器
+const (
+	DetectorURL   = "url"   // 网址，分类为url
+	DetectorEmail = "email" // 邮箱，分类为contact
+	DetectorPhone = "phone" // 中国大陆手机号，校验号段，分类为contact
+)
+
+// 联系方式检测结果的分类
+const (
+	CategoryURL     = "url"     // 网址
+	CategoryContact = "contact" // 邮箱、手机号等联系方式
+)
+
+// ContactConfig 联系方式检测配置，检测结果与敏感词一样出现在过滤结果中
+type ContactConfig struct {
+	Enabled   bool             `json:"enabled" yaml:"enabled"`     // 是否启用
+	Detectors []string         `json:"detectors" yaml:"detectors"` // 启用的内置检测器: url|email|phone，为空启用全部
+	Level     int              `json:"level" yaml:"level"`         // 检测结果的敏感级别，默认3
+	Patterns  []ContactPattern `json:"patterns" yaml:"patterns"`   // 自定义正则规则，如QQ号、微信号
+}
+
+// ContactPattern 自定义联系方式正则规则
+type ContactPattern struct {
+	Name     string `json:"name" yaml:"name"`         // 规则名称
+	Category string `json:"category" yaml:"category"` // 命中分类，默认contact
+	Pattern  string `json:"pattern" yaml:"pattern"`   // 正则表达式，匹配标准化（小写、半角）后的文本
+	Level    int    `json:"level" yaml:"level"`       // 敏感级别，默认使用ContactConfig.Level
+}
+
+// EscalationConfig 外部审核接口配置，本地结果的风险分处于临界区间时调用第三方审核接口，违规则拒绝，正常则放行
+// 接口接收 POST {"text": "..."}，返回 {"flagged": true, "score": 0.93, "categories": ["abuse"]}；调用失败或熔断时保留本地结果
+type EscalationConfig struct {
+	Enabled          bool              `json:"enabled" yaml:"enabled"`                     // 是否启用
+	URL              string            `json:"url" yaml:"url"`                             // 审核接口地址
+	Headers          map[string]string `json:"headers" yaml:"headers"`                     // 附加的请求头，如鉴权
+	Timeout          time.Duration     `json:"timeout" yaml:"timeout"`                     // 单次请求超时，默认2s
+	MinScore         float64           `json:"min_score" yaml:"min_score"`                 // 临界区间下限（含），默认 risk_thresholds.review
+	MaxScore         float64           `json:"max_score" yaml:"max_score"`                 // 临界区间上限（不含），默认 risk_thresholds.reject，0表示不设上限
+	FailureThreshold int               `json:"failure_threshold" yaml:"failure_threshold"` // 连续失败多少次后熔断，默认5
+	Cooldown         time.Duration     `json:"cooldown" yaml:"cooldown"`                   // 熔断持续时间，之后放行一次试探请求，默认30s
+	CacheSize        int               `json:"cache_size" yaml:"cache_size"`               // 结论缓存条目数，默认10000，负数表示不缓存
+	CacheTTL         time.Duration     `json:"cache_ttl" yaml:"cache_ttl"`                 // 结论缓存时长，默认10m
+}
+
+// ScoreRange 返回临界区间 [min, max)，未设置的上下限取风险分阈值，max为0表示不设上限
+func (c *EscalationConfig) ScoreRange(thresholds RiskThresholds) (float64, float64) {
+	minScore, maxScore := c.MinScore, c.MaxScore
+	if minScore == 0 {
+		minScore = thresholds.Review
+	}
+	if maxScore == 0 {
+		maxScore = thresholds.Reject
+	}
+	return minScore, maxScore
+}
+
+// ClassifierConfig 文本分类模型配置，模型给出的违规概率乘以权重计入风险分，不含词库敏感词的变体和隐晦表达也能被识别
+// 内置远程推理接口：POST {"text": "..."}，返回 {"probability": 0.87}；本地模型（如ONNX）通过 SetClassifier 接入
+type ClassifierConfig struct {
+	Enabled   bool              `json:"enabled" yaml:"enabled"`     // 是否启用远程推理接口
+	URL       string            `json:"url" yaml:"url"`             // 推理接口地址
+	Headers   map[string]string `json:"headers" yaml:"headers"`     // 附加的请求头，如鉴权
+	Timeout   time.Duration     `json:"timeout" yaml:"timeout"`     // 单次请求超时，默认1s
+	Weight    float64           `json:"weight" yaml:"weight"`       // 概率计入风险分的权重，风险分增加 概率×权重，默认10
+	Threshold float64           `json:"threshold" yaml:"threshold"` // 概率达到该值才计入风险分，默认0.5
+	Category  string            `json:"category" yaml:"category"`   // 概率计入风险分时加入结果的分类，默认toxicity
+}
+
+// ClassifierOptions 单次检查或场景的分类模型选项，未设置的值使用 classifier 配置
+type ClassifierOptions struct {
+	Disabled  bool    `json:"disabled,omitempty" yaml:"disabled"`   // 不调用分类模型
+	Weight    float64 `json:"weight,omitempty" yaml:"weight"`       // 概率计入风险分的权重
+	Threshold float64 `json:"threshold,omitempty" yaml:"threshold"` // 概率达到该值才计入风险分
+}
+
+// CategoryToxicity 分类模型判定违规时加入结果的默认分类
+const CategoryToxicity = "toxicity"
+
+// RiskThresholds 风险分阈值，风险分低于Review时通过，达到Review时转人工审核，达到Reject时拒绝
+type RiskThresholds struct {
+	Review float64 `json:"review" yaml:"review"` // 转人工审核的最低风险分，0表示不设审核区间
+	Reject float64 `json:"reject" yaml:"reject"` // 拒绝的最低风险分，0表示不按风险分拒绝
+}
+
+// LongTextConfig 超长文本扫描配置
+type LongTextConfig struct {
+	Threshold  int     `json:"threshold" yaml:"threshold"`     // 超过该字节数的文本按策略扫描，0表示始终全文扫描
+	Strategy   string  `json:"strategy" yaml:"strategy"`       // 扫描策略: full|head_tail|random
+	WindowSize int     `json:"window_size" yaml:"window_size"` // 窗口大小（字节），默认4096
+	Coverage   float64 `json:"coverage" yaml:"coverage"`       // random策略的覆盖率(0,1]，默认0.3
+	ChunkSize  int     `json:"chunk_size" yaml:"chunk_size"`   // 全文扫描时按该字节数分块，块间重叠保证跨块命中不丢失，默认1MB
 }
 
 // WordDatabase 词库结构
 type WordDatabase struct {
-	Version      string                    `json:"version"`       // 版本号
-	UpdateTime   time.Time                 `json:"update_time"`   // 更新时间
-	Whitelist    []string                  `json:"whitelist"`     // 白名单
-	Blacklist    []SensitiveWord           `json:"blacklist"`     // 黑名单
-	Categories   map[string][]SensitiveWord `json:"categories"`   // 分类敏感词
-	Replacements map[string]string         `json:"replacements"`  // 替换词
+	Version      string                     `json:"version"`             // 版本号
+	UpdateTime   time.Time                  `json:"update_time"`         // 更新时间
+	Whitelist    []string                   `json:"whitelist"`           // 白名单短语，只豁免落在短语内的命中
+	Blacklist    []SensitiveWord            `json:"blacklist"`           // 黑名单
+	Categories   map[string][]SensitiveWord `json:"categories"`          // 分类敏感词
+	Replacements map[string]string          `json:"replacements"`        // 替换词
+	Checksum     string                     `json:"checksum,omitempty"`  // 内容的SHA-256校验和，设置后加载时校验
+	Signature    string                     `json:"signature,omitempty"` // 校验和的Ed25519签名（base64），配置public_key后必须有效
+}
+
+// Words 返回黑名单和各分类下的全部敏感词
+func (db *WordDatabase) Words() []SensitiveWord {
+	count := len(db.Blacklist)
+	for _, words := range db.Categories {
+		count += len(words)
+	}
+
+	words := make([]SensitiveWord, 0, count)
+	words = append(words, db.Blacklist...)
+	for _, categoryWords := range db.Categories {
+		words = append(words, categoryWords...)
+	}
+	return words
+}
+
+// Clone 复制词库，修改副本的名单和分类不影响原词库
+func (db *WordDatabase) Clone() *WordDatabase {
+	clone := *db
+	clone.Whitelist = append([]string(nil), db.Whitelist...)
+	clone.Blacklist = append([]SensitiveWord(nil), db.Blacklist...)
+	if db.Categories != nil {
+		clone.Categories = make(map[string][]SensitiveWord, len(db.Categories))
+		for category, words := range db.Categories {
+			clone.Categories[category] = append([]SensitiveWord(nil), words...)
+		}
+	}
+	if db.Replacements != nil {
+		clone.Replacements = make(map[string]string, len(db.Replacements))
+		for word, replacement := range db.Replacements {
+			clone.Replacements[word] = replacement
+		}
+	}
+	return &clone
+}
+
+// CategoryFlag 分类开关状态
+type CategoryFlag string
+
+const (
+	CategoryEnabled  CategoryFlag = "enabled"  // 正常生效
+	CategoryDisabled CategoryFlag = "disabled" // 关闭，命中后不影响结果
+	CategoryReview   CategoryFlag = "review"   // 强制人审
+	CategoryMonitor  CategoryFlag = "monitor"  // 只观察，命中记入统计和日志但不影响结果
+)
+
+// CategoryFlags 分类开关配置，通过配置中心下发
+type CategoryFlags struct {
+	Version    string                  `json:"version"`    // 版本号
+	Categories map[string]CategoryFlag `json:"categories"` // 分类 -> 开关状态
+}
+
+// PolicyRule 策略规则，设置的条件全部满足时生效
+type PolicyRule struct {
+	Categories []string `json:"categories,omitempty"`  // 命中其中任一分类，为空不限
+	MinLevel   int      `json:"min_level,omitempty"`   // 命中敏感词的最高级别不低于该值，0不限
+	MinScore   float64  `json:"min_score,omitempty"`   // 风险分不低于该值，0不限
+	Action     Action   `json:"action"`                // 处理动作
+	ReasonCode string   `json:"reason_code,omitempty"` // 判定原因码，为空时为 policy
+}
+
+// Matches 检查命中的分类、最高敏感级别和风险分是否满足规则
+func (r *PolicyRule) Matches(categories []string, maxLevel int, score float64) bool {
+	if r.MinLevel > 0 && maxLevel < r.MinLevel {
+		return false
+	}
+	if r.MinScore > 0 && score < r.MinScore {
+		return false
+	}
+	if len(r.Categories) == 0 {
+		return true
+	}
+
+	for _, category := range categories {
+		for _, ruleCategory := range r.Categories {
+			if category == ruleCategory {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Policy 处理策略，通过配置中心下发，只作用于有命中的结果，按顺序使用第一条满足的规则
+type Policy struct {
+	Version string       `json:"version"`           // 版本号
+	Rules   []PolicyRule `json:"rules"`             // 规则
+	Default Action       `json:"default,omitempty"` // 没有规则满足时的动作，为空时按风险分阈值和分类开关判定
+}
+
+// SceneConfig 场景检查选项，各业务按场景名复用同一套选项
+type SceneConfig struct {
+	Categories      []string          `json:"categories" yaml:"categories"`             // 要检查的分类，为空检查全部
+	MinLevel        int               `json:"min_level" yaml:"min_level"`               // 最小敏感级别，默认1
+	ReplaceMode     bool              `json:"replace_mode" yaml:"replace_mode"`         // 是否替换模式
+	ReplaceChar     string            `json:"replace_char" yaml:"replace_char"`         // 替换模式下的打码字符，默认为*
+	EnableWhitelist bool              `json:"enable_whitelist" yaml:"enable_whitelist"` // 是否启用白名单
+	Locale          string            `json:"locale" yaml:"locale"`                     // 提示语语言，为空使用默认语言
+	Format          string            `json:"format" yaml:"format"`                     // 输入格式: text|html|markdown，默认text
+	Classifier      ClassifierOptions `json:"classifier" yaml:"classifier"`             // 文本分类模型选项，如私聊场景提高权重、昵称场景不调用
+}
+
+// Options 转换为过滤选项
+func (s *SceneConfig) Options() *FilterOptions {
+	options := &FilterOptions{
+		EnableWhitelist: s.EnableWhitelist,
+		Categories:      s.Categories,
+		MinLevel:        s.MinLevel,
+		ReplaceMode:     s.ReplaceMode,
+		Locale:          s.Locale,
+		ReplaceChar:     s.ReplaceChar,
+		Format:          s.Format,
+		Classifier:      s.Classifier,
+	}
+	return options.WithDefaults()
 }
 
 // FilterOptions 过滤选项
 type FilterOptions struct {
-	EnableWhitelist bool     `json:"enable_whitelist"` // 是否启用白名单
-	Categories      []string `json:"categories"`       // 要检查的分类
-	MinLevel        int      `json:"min_level"`        // 最小敏感级别
-	ReplaceMode     bool     `json:"replace_mode"`     // 是否替换模式
+	EnableWhitelist bool              `json:"enable_whitelist"` // 是否启用白名单
+	Categories      []string          `json:"categories"`       // 要检查的分类
+	MinLevel        int               `json:"min_level"`        // 最小敏感级别
+	ReplaceMode     bool              `json:"replace_mode"`     // 是否替换模式
+	Locale          string            `json:"locale"`           // 提示语语言，为空使用默认语言
+	ReplaceChar     string            `json:"replace_char"`     // 替换模式下的打码字符，默认为*
+	AllowTerms      []string          `json:"allow_terms"`      // 本次请求的放行词，如正在讨论的商品名，落在放行词内的命中被忽略，不影响全局白名单
+	Format          string            `json:"format"`           // 输入格式: text|html|markdown，默认text；html和markdown会先去除标记再匹配
+	Classifier      ClassifierOptions `json:"classifier"`       // 文本分类模型选项，未设置的值使用 classifier 配置
+	UserID          string            `json:"user_id"`          // 发布内容的用户，启用违规统计时累计该用户的违规次数并写入结果
+}
+
+// DefaultFilterOptions 返回默认过滤选项：启用白名单，检查全部分类和级别
+func DefaultFilterOptions() *FilterOptions {
+	return &FilterOptions{
+		EnableWhitelist: true,
+		Categories:      []string{},
+		MinLevel:        1,
+	}
+}
+
+// UnmarshalJSON 在默认选项上解码，请求只设置部分选项（如 {"min_level":2}）时未设置的 enable_whitelist 保持开启
+func (o *FilterOptions) UnmarshalJSON(data []byte) error {
+	type plain FilterOptions
+	options := plain(*DefaultFilterOptions())
+	if err := json.Unmarshal(data, &options); err != nil {
+		return err
+	}
+	*o = FilterOptions(options)
+	return nil
 }
+
+// WithDefaults 返回补全默认值后的副本，不修改原选项；nil返回 DefaultFilterOptions
+// MinLevel不大于0时为1，Categories为nil时为空列表（检查全部分类）
+func (o *FilterOptions) WithDefaults() *FilterOptions {
+	if o == nil {
+		return DefaultFilterOptions()
+	}
+
+	options := *o
+	if options.MinLevel <= 0 {
+		options.MinLevel = 1
+	}
+	if options.Categories == nil {
+		options.Categories = []string{}
+	}
+	return &options
+}
+
+// 输入格式
+const (
+	FormatText     = "text"     // 纯文本
+	FormatHTML     = "html"     // HTML，去除标签、注释、脚本和样式，解码字符实体
+	FormatMarkdown = "markdown" // Markdown，去除强调标记、行首标记和链接地址
+)
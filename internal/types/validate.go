@@ -0,0 +1,541 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Validate 校验配置，返回所有问题的汇总错误
+func (c *Config) Validate() error {
+	var problems []string
+
+	switch c.Source {
+	case "", SourceNacos:
+		if len(c.NacosConfig.ServerConfigs) == 0 {
+			problems = append(problems, "nacos_config.server_configs must not be empty")
+		}
+		for i, server := range c.NacosConfig.ServerConfigs {
+			if server.IpAddr == "" {
+				problems = append(problems, fmt.Sprintf("nacos_config.server_configs[%d].ip_addr must not be empty", i))
+			}
+			if server.Port == 0 || server.Port > 65535 {
+				problems = append(problems, fmt.Sprintf("nacos_config.server_configs[%d].port %d is out of range", i, server.Port))
+			}
+		}
+	case SourceEtcd:
+		if len(c.EtcdConfig.Endpoints) == 0 {
+			problems = append(problems, "etcd_config.endpoints must not be empty")
+		}
+	case SourceApollo:
+		if c.ApolloConfig.AppId == "" {
+			problems = append(problems, "apollo_config.app_id must not be empty")
+		}
+		if c.ApolloConfig.MetaAddr == "" {
+			problems = append(problems, "apollo_config.meta_addr must not be empty")
+		}
+	case SourceRedis:
+		if len(c.RedisConfig.Addrs) == 0 {
+			problems = append(problems, "redis_config.addrs must not be empty")
+		}
+	case SourceMemory:
+	default:
+		problems = append(problems, fmt.Sprintf("source %q is not supported", c.Source))
+	}
+
+	if c.Admin.Token != "" && c.FilterConfig.PublicKey != "" && c.Admin.SigningKey == "" {
+		problems = append(problems, "admin.signing_key must be set when filter_config.public_key is set")
+	}
+
+	if format := c.FilterConfig.WordFormat; c.Admin.Token != "" && format != "" && format != WordFormatAuto && format != WordFormatJSON {
+		problems = append(problems, "admin API publishes JSON and requires filter_config.word_format auto or json")
+	}
+
+	problems = append(problems, c.Consumer.validate()...)
+
+	if err := c.FilterConfig.Validate(); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	if len(problems) > 0 {
+		return errors.New("invalid config: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Validate 校验过滤器配置
+func (c *FilterConfig) Validate() error {
+	var problems []string
+
+	if c.DataId == "" && c.ArtifactPath == "" {
+		problems = append(problems, "filter_config.data_id must not be empty")
+	}
+	if c.Group == "" {
+		problems = append(problems, "filter_config.group must not be empty")
+	}
+	if c.ReloadPeriod < 0 {
+		problems = append(problems, "filter_config.reload_period must not be negative")
+	}
+	if c.EnableCache && c.CacheSize <= 0 {
+		problems = append(problems, "filter_config.cache_size must be positive when cache is enabled")
+	}
+	if c.CacheShards < 0 || c.CacheTTL < 0 {
+		problems = append(problems, "filter_config.cache_shards and cache_ttl must not be negative")
+	}
+	if c.EnableCache {
+		switch c.CacheStore {
+		case "", CacheStoreMemory:
+		case CacheStoreRedis:
+			if len(c.CacheRedis.Addrs) == 0 {
+				problems = append(problems, "filter_config.cache_redis.addrs is required for redis cache store")
+			}
+		default:
+			problems = append(problems, fmt.Sprintf("filter_config.cache_store %q is not supported", c.CacheStore))
+		}
+	}
+	if c.BatchWorkers < 0 {
+		problems = append(problems, "filter_config.batch_workers must not be negative")
+	}
+	if c.RebuildDebounce < 0 {
+		problems = append(problems, "filter_config.rebuild_debounce must not be negative")
+	}
+	if !validWordFormat(c.WordFormat) {
+		problems = append(problems, fmt.Sprintf("filter_config.word_format %q is not supported", c.WordFormat))
+	}
+	if (c.PersistWhitelist || c.PersistWords) && c.WordFormat != "" && c.WordFormat != WordFormatAuto && c.WordFormat != WordFormatJSON {
+		problems = append(problems, "filter_config.persist_whitelist and persist_words publish JSON and require word_format auto or json")
+	}
+	for i, set := range c.MergeDataIds {
+		if set.DataId == "" {
+			problems = append(problems, fmt.Sprintf("filter_config.merge_data_ids[%d].data_id must not be empty", i))
+		}
+		if !validWordFormat(set.Format) {
+			problems = append(problems, fmt.Sprintf("filter_config.merge_data_ids[%d].format %q is not supported", i, set.Format))
+		}
+	}
+	if c.PublicKey != "" && c.PersistWhitelist {
+		problems = append(problems, "filter_config.persist_whitelist cannot be used with public_key, runtime changes cannot be signed")
+	}
+	if c.PublicKey != "" && c.PersistWords {
+		problems = append(problems, "filter_config.persist_words cannot be used with public_key, runtime changes cannot be signed")
+	}
+	if c.Canary.Enabled && (c.Canary.Percent <= 0 || c.Canary.Percent > 100) {
+		problems = append(problems, "filter_config.canary.percent must be within (0, 100]")
+	}
+	if c.Canary.Duration < 0 || c.Canary.MinRequests < 0 {
+		problems = append(problems, "filter_config.canary.duration and min_requests must not be negative")
+	}
+	if c.Canary.MaxBlockRateDelta < 0 || c.Canary.MaxBlockRateDelta > 1 {
+		problems = append(problems, "filter_config.canary.max_block_rate_delta must be within [0, 1]")
+	}
+	if c.DiffHistory < 0 {
+		problems = append(problems, "filter_config.diff_history must not be negative")
+	}
+	switch c.Lint.Strictness {
+	case "", LintOff, LintWarn, LintReject, LintStrict:
+	default:
+		problems = append(problems, fmt.Sprintf("filter_config.lint.strictness %q is not supported", c.Lint.Strictness))
+	}
+	problems = append(problems, c.Audit.validate()...)
+	problems = append(problems, c.Sampling.validate()...)
+	problems = append(problems, c.Review.validate()...)
+	problems = append(problems, c.Anomaly.validate()...)
+	if c.ReloadAlert.FailureThreshold < 0 || c.ReloadAlert.StaleAfter < 0 {
+		problems = append(problems, "filter_config.reload_alert.failure_threshold and stale_after must not be negative")
+	}
+	problems = append(problems, c.validateEscalation()...)
+	problems = append(problems, c.Offender.validate()...)
+	if c.Classifier.Enabled && c.Classifier.URL == "" {
+		problems = append(problems, "filter_config.classifier.url is required")
+	}
+	if c.Classifier.Weight < 0 || c.Classifier.Threshold < 0 || c.Classifier.Threshold > 1 {
+		problems = append(problems, "filter_config.classifier.weight must not be negative and threshold must be within [0, 1]")
+	}
+	for i := range c.AlertNotifiers {
+		problems = append(problems, c.AlertNotifiers[i].validate(i)...)
+	}
+	for i := range c.ViolationWebhooks {
+		problems = append(problems, c.ViolationWebhooks[i].validate(i)...)
+	}
+	if c.Lint.MinWordLength < 0 {
+		problems = append(problems, "filter_config.lint.min_word_length must not be negative")
+	}
+	for _, rule := range c.Lint.Ignore {
+		switch rule {
+		case LintEmptyWord, LintInvalidLevel, LintDuplicate, LintWhitelisted, LintShortWord:
+		default:
+			problems = append(problems, fmt.Sprintf("filter_config.lint.ignore %q is not a known rule", rule))
+		}
+	}
+	if c.ShardSize < 0 {
+		problems = append(problems, "filter_config.shard_size must not be negative")
+	}
+	switch c.MergeStrategy {
+	case "", MergeOverride, MergeKeepFirst, MergeMaxLevel:
+	default:
+		problems = append(problems, fmt.Sprintf("filter_config.merge_strategy %q is not supported", c.MergeStrategy))
+	}
+	if c.BuildMemoryBudgetMB < 0 {
+		problems = append(problems, "filter_config.build_memory_budget_mb must not be negative")
+	}
+
+	switch c.LongText.Strategy {
+	case "", ScanFull, ScanHeadTail, ScanRandom:
+	default:
+		problems = append(problems, fmt.Sprintf("filter_config.long_text.strategy %q is not supported", c.LongText.Strategy))
+	}
+	if c.LongText.Coverage < 0 || c.LongText.Coverage > 1 {
+		problems = append(problems, "filter_config.long_text.coverage must be within [0, 1]")
+	}
+	if c.LongText.ChunkSize < 0 {
+		problems = append(problems, "filter_config.long_text.chunk_size must not be negative")
+	}
+
+	if c.MaxTextLength < 0 {
+		problems = append(problems, "filter_config.max_text_length must not be negative")
+	}
+	switch c.MaxTextLengthAction {
+	case "", TextTruncate, TextReject:
+	default:
+		problems = append(problems, fmt.Sprintf("filter_config.max_text_length_action %q is not supported", c.MaxTextLengthAction))
+	}
+
+	if c.RiskThresholds.Review < 0 || c.RiskThresholds.Reject < 0 {
+		problems = append(problems, "filter_config.risk_thresholds must not be negative")
+	}
+	if c.RiskThresholds.Review > 0 && c.RiskThresholds.Reject > 0 && c.RiskThresholds.Review > c.RiskThresholds.Reject {
+		problems = append(problems, "filter_config.risk_thresholds.review must not exceed reject")
+	}
+
+	for _, detector := range c.Contact.Detectors {
+		switch detector {
+		case DetectorURL, DetectorEmail, DetectorPhone:
+		default:
+			problems = append(problems, fmt.Sprintf("filter_config.contact.detectors %q is not supported", detector))
+		}
+	}
+	if c.Contact.Level < 0 {
+		problems = append(problems, "filter_config.contact.level must not be negative")
+	}
+	for i, pattern := range c.Contact.Patterns {
+		if pattern.Name == "" {
+			problems = append(problems, fmt.Sprintf("filter_config.contact.patterns[%d].name must not be empty", i))
+		}
+		if _, err := regexp.Compile(pattern.Pattern); pattern.Pattern == "" || err != nil {
+			problems = append(problems, fmt.Sprintf("filter_config.contact.patterns[%d].pattern is not a valid regular expression", i))
+		}
+	}
+
+	for name, scene := range c.Scenes {
+		if name == "" {
+			problems = append(problems, "filter_config.scenes must not contain an empty scene name")
+		}
+		if scene.MinLevel < 0 {
+			problems = append(problems, fmt.Sprintf("filter_config.scenes.%s.min_level must not be negative", name))
+		}
+		if scene.Classifier.Weight < 0 || scene.Classifier.Threshold < 0 || scene.Classifier.Threshold > 1 {
+			problems = append(problems, fmt.Sprintf("filter_config.scenes.%s.classifier.weight must not be negative and threshold must be within [0, 1]", name))
+		}
+		switch scene.Format {
+		case "", FormatText, FormatHTML, FormatMarkdown:
+		default:
+			problems = append(problems, fmt.Sprintf("filter_config.scenes.%s.format %q is not supported", name, scene.Format))
+		}
+	}
+
+	switch c.Normalize.ChineseConversion {
+	case "", ConvertT2S, ConvertS2T:
+	default:
+		problems = append(problems, fmt.Sprintf("filter_config.normalize.chinese_conversion %q is not supported", c.Normalize.ChineseConversion))
+	}
+	for from, to := range c.Normalize.Substitutions {
+		if utf8.RuneCountInString(from) != 1 || utf8.RuneCountInString(to) != 1 {
+			problems = append(problems, fmt.Sprintf("filter_config.normalize.substitutions %q -> %q must map a single character to a single character", from, to))
+		}
+	}
+	if c.Normalize.MaxRepeat < 0 {
+		problems = append(problems, "filter_config.normalize.max_repeat must not be negative")
+	}
+
+	switch c.AutomatonLayout {
+	case "", LayoutMap, LayoutFlat:
+	default:
+		problems = append(problems, fmt.Sprintf("filter_config.automaton_layout %q is not supported", c.AutomatonLayout))
+	}
+
+	switch c.FailurePolicy {
+	case "", FailOpen, FailClosed:
+	default:
+		problems = append(problems, fmt.Sprintf("filter_config.failure_policy %q is not supported", c.FailurePolicy))
+	}
+
+	for i, source := range c.WordSources {
+		switch source.Type {
+		case WordSourceRemote, WordSourceEmbedded:
+		case WordSourceFile:
+			if source.Path == "" {
+				problems = append(problems, fmt.Sprintf("filter_config.word_sources[%d].path must not be empty", i))
+			}
+		case WordSourceSnapshot:
+			if c.SnapshotPath == "" {
+				problems = append(problems, fmt.Sprintf("filter_config.word_sources[%d] requires filter_config.snapshot_path", i))
+			}
+		default:
+			problems = append(problems, fmt.Sprintf("filter_config.word_sources[%d].type %q is not supported", i, source.Type))
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Validate 校验处理策略中的动作
+func (p *Policy) Validate() error {
+	var problems []string
+
+	for i, rule := range p.Rules {
+		if !rule.Action.valid() {
+			problems = append(problems, fmt.Sprintf("rules[%d].action %q is not supported", i, rule.Action))
+		}
+	}
+	if p.Default != "" && !p.Default.valid() {
+		problems = append(problems, fmt.Sprintf("default %q is not supported", p.Default))
+	}
+
+	if len(problems) > 0 {
+		return errors.New("invalid policy: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// validWordFormat 是否为支持的词库格式
+func validWordFormat(format string) bool {
+	switch format {
+	case "", WordFormatAuto, WordFormatJSON, WordFormatYAML, WordFormatText, WordFormatCSV:
+		return true
+	default:
+		return false
+	}
+}
+
+// valid 是否为支持的处理动作
+func (a Action) valid() bool {
+	switch a {
+	case ActionPass, ActionMask, ActionReview, ActionReject:
+		return true
+	default:
+		return false
+	}
+}
+
+// validate 校验审计日志配置，未启用时不校验
+func (c *AuditConfig) validate() []string {
+	if !c.Enabled {
+		return nil
+	}
+
+	problems := c.SinkConfig.validate("filter_config.audit")
+	for _, action := range c.Actions {
+		if !action.valid() {
+			problems = append(problems, fmt.Sprintf("filter_config.audit.actions %q is not a known action", action))
+		}
+	}
+	if c.SampleRate < 0 || c.SampleRate > 1 {
+		problems = append(problems, "filter_config.audit.sample_rate must be within [0, 1]")
+	}
+	switch c.Text {
+	case "", AuditTextHash, AuditTextTruncate, AuditTextFull:
+	default:
+		problems = append(problems, fmt.Sprintf("filter_config.audit.text %q is not supported", c.Text))
+	}
+	if c.MaxTextLength < 0 {
+		problems = append(problems, "filter_config.audit.max_text_length must not be negative")
+	}
+	return problems
+}
+
+// validate 校验抽样复核配置，未启用时不校验
+func (c *SamplingConfig) validate() []string {
+	if !c.Enabled {
+		return nil
+	}
+
+	problems := c.SinkConfig.validate("filter_config.sampling")
+	if c.BlockedRate < 0 || c.BlockedRate > 1 || c.PassedRate < 0 || c.PassedRate > 1 {
+		problems = append(problems, "filter_config.sampling.blocked_rate and passed_rate must be within [0, 1]")
+	}
+	if c.BlockedRate == 0 && c.PassedRate == 0 {
+		problems = append(problems, "filter_config.sampling requires blocked_rate or passed_rate")
+	}
+	return problems
+}
+
+// validate 校验审计记录输出配置，prefix为所属配置项的路径
+func (c *SinkConfig) validate(prefix string) []string {
+	var problems []string
+	switch c.Sink {
+	case AuditSinkFile:
+		if c.Path == "" {
+			problems = append(problems, prefix+".path is required for file sink")
+		}
+	case AuditSinkHTTP:
+		if c.URL == "" {
+			problems = append(problems, prefix+".url is required for http sink")
+		}
+	case AuditSinkKafka:
+		if c.URL == "" || c.Topic == "" {
+			problems = append(problems, prefix+".url and topic are required for kafka sink")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("%s.sink %q is not supported", prefix, c.Sink))
+	}
+	if c.BufferSize < 0 || c.BatchSize < 0 || c.FlushInterval < 0 || c.Timeout < 0 {
+		problems = append(problems, prefix+".buffer_size, batch_size, flush_interval and timeout must not be negative")
+	}
+	return problems
+}
+
+// validate 校验人工复核队列配置，未启用时不校验
+func (c *ReviewConfig) validate() []string {
+	if !c.Enabled {
+		return nil
+	}
+
+	var problems []string
+	switch c.Queue {
+	case "", ReviewQueueMemory:
+	case ReviewQueueRedis:
+		if len(c.Redis.Addrs) == 0 {
+			problems = append(problems, "filter_config.review.redis.addrs is required for redis queue")
+		}
+	case ReviewQueueKafka:
+		if c.URL == "" || c.Topic == "" {
+			problems = append(problems, "filter_config.review.url and topic are required for kafka queue")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("filter_config.review.queue %q is not supported", c.Queue))
+	}
+	if c.Capacity < 0 || c.Retention < 0 || c.BufferSize < 0 || c.Timeout < 0 {
+		problems = append(problems, "filter_config.review.capacity, retention, buffer_size and timeout must not be negative")
+	}
+	return problems
+}
+
+// validate 校验Kafka消费模式配置，未配置输入主题时不校验
+func (c *ConsumerConfig) validate() []string {
+	if c.Topic == "" {
+		return nil
+	}
+
+	var problems []string
+	if c.URL == "" || c.OutputTopic == "" {
+		problems = append(problems, "consumer.url and output_topic are required when consumer.topic is set")
+	}
+	switch c.OffsetReset {
+	case "", OffsetResetLatest, OffsetResetEarliest:
+	default:
+		problems = append(problems, fmt.Sprintf("consumer.offset_reset %q is not supported", c.OffsetReset))
+	}
+	if c.BatchSize < 0 || c.PollTimeout < 0 || c.Timeout < 0 {
+		problems = append(problems, "consumer.batch_size, poll_timeout and timeout must not be negative")
+	}
+	return problems
+}
+
+// validate 校验用户违规统计配置，未启用时不校验
+func (c *OffenderConfig) validate() []string {
+	if !c.Enabled {
+		return nil
+	}
+
+	var problems []string
+	switch c.Store {
+	case "", OffenderStoreMemory:
+	case OffenderStoreRedis:
+		if len(c.Redis.Addrs) == 0 {
+			problems = append(problems, "filter_config.offender.redis.addrs is required for redis store")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("filter_config.offender.store %q is not supported", c.Store))
+	}
+	if c.Window < 0 || c.Capacity < 0 {
+		problems = append(problems, "filter_config.offender.window and capacity must not be negative")
+	}
+	return problems
+}
+
+// validate 校验拦截率突变检测配置，未启用时不校验
+func (c *AnomalyConfig) validate() []string {
+	if !c.Enabled {
+		return nil
+	}
+
+	var problems []string
+	if c.Window < 0 || c.Baseline < 0 || c.MinChecks < 0 || c.Cooldown < 0 {
+		problems = append(problems, "filter_config.anomaly.window, baseline, min_checks and cooldown must not be negative")
+	}
+	if c.Window > 0 && c.Baseline > 0 && c.Baseline < c.Window {
+		problems = append(problems, "filter_config.anomaly.baseline must not be shorter than window")
+	}
+	if c.Ratio != 0 && c.Ratio <= 1 {
+		problems = append(problems, "filter_config.anomaly.ratio must be greater than 1")
+	}
+	if c.MinDelta < 0 || c.MinDelta > 1 {
+		problems = append(problems, "filter_config.anomaly.min_delta must be within [0, 1]")
+	}
+	return problems
+}
+
+// validate 校验告警通道配置
+func (c *AlertNotifierConfig) validate(index int) []string {
+	var problems []string
+	switch c.Type {
+	case "", AlertNotifierWebhook, AlertNotifierDingTalk, AlertNotifierFeishu, AlertNotifierWeCom:
+	default:
+		problems = append(problems, fmt.Sprintf("filter_config.alert_notifiers[%d].type %q is not one of webhook, dingtalk, feishu, wecom", index, c.Type))
+	}
+	if c.URL == "" {
+		problems = append(problems, fmt.Sprintf("filter_config.alert_notifiers[%d].url is required", index))
+	}
+	return problems
+}
+
+// validate 校验违规回调配置，index为在 violation_webhooks 中的下标
+func (c *ViolationWebhookConfig) validate(index int) []string {
+	var problems []string
+	if c.URL == "" {
+		problems = append(problems, fmt.Sprintf("filter_config.violation_webhooks[%d].url is required", index))
+	}
+	if c.MinLevel < 0 || c.Timeout < 0 || c.BufferSize < 0 {
+		problems = append(problems, fmt.Sprintf("filter_config.violation_webhooks[%d].min_level, timeout and buffer_size must not be negative", index))
+	}
+	return problems
+}
+
+// validateEscalation 校验外部审核接口配置，未启用时不校验；临界区间默认取风险分阈值，需要一并校验
+func (c *FilterConfig) validateEscalation() []string {
+	e := &c.Escalation
+	if !e.Enabled {
+		return nil
+	}
+
+	var problems []string
+	if e.URL == "" {
+		problems = append(problems, "filter_config.escalation.url is required")
+	}
+	if e.Timeout < 0 || e.Cooldown < 0 || e.CacheTTL < 0 || e.FailureThreshold < 0 {
+		problems = append(problems, "filter_config.escalation.timeout, cooldown, cache_ttl and failure_threshold must not be negative")
+	}
+	minScore, maxScore := e.ScoreRange(c.RiskThresholds)
+	if minScore <= 0 {
+		problems = append(problems, "filter_config.escalation.min_score or filter_config.risk_thresholds.review must be positive")
+	}
+	if maxScore > 0 && maxScore <= minScore {
+		problems = append(problems, "filter_config.escalation.max_score must be greater than min_score")
+	}
+	return problems
+}
@@ -0,0 +1,113 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// contentVersionPrefix 内容摘要版本的前缀
+const contentVersionPrefix = "sha256:"
+
+// ContentVersion 返回内容摘要版本，用于文本、CSV等自身没有版本号的词表，内容不变时版本不变
+func ContentVersion(content []byte) string {
+	sum := sha256.Sum256(content)
+	return contentVersionPrefix + hex.EncodeToString(sum[:8])
+}
+
+// IsContentVersion 是否为内容摘要版本，摘要版本之间没有先后顺序
+func IsContentVersion(version string) bool {
+	return strings.HasPrefix(version, contentVersionPrefix)
+}
+
+// NextVersion 递增词库版本：末尾的数字加一，如 "1.0.9" -> "1.0.10"；末尾不是数字时追加 ".1"，为空或为内容摘要版本时返回 "1"
+func NextVersion(version string) string {
+	if IsContentVersion(version) {
+		return "1"
+	}
+
+	end := len(version)
+	start := end
+	for start > 0 && '0' <= version[start-1] && version[start-1] <= '9' {
+		start--
+	}
+	if start == end {
+		if version == "" {
+			return "1"
+		}
+		return version + ".1"
+	}
+
+	n, err := strconv.ParseUint(version[start:end], 10, 64)
+	if err != nil {
+		return version + ".1"
+	}
+	return version[:start] + strconv.FormatUint(n+1, 10)
+}
+
+// CompareVersions 比较词库版本，a较旧时返回-1，相同时返回0，较新时返回1
+// 按语义化版本比较：忽略前缀 "v"，数字段按数值比较、其余按字典序；
+// "-" 之后为预发布版本，低于对应的正式版本，如 "1.0.0-rc.1" < "1.0.0" < "1.0.10"
+func CompareVersions(a, b string) int {
+	a = strings.TrimPrefix(a, "v")
+	b = strings.TrimPrefix(b, "v")
+	coreA, preA, hasPreA := strings.Cut(a, "-")
+	coreB, preB, hasPreB := strings.Cut(b, "-")
+
+	if c := compareNatural(coreA, coreB); c != 0 {
+		return c
+	}
+	switch {
+	case hasPreA && !hasPreB:
+		return -1
+	case !hasPreA && hasPreB:
+		return 1
+	}
+	return compareNatural(preA, preB)
+}
+
+// compareNatural 逐段比较，连续数字按数值比较，其余字符按字节比较
+func compareNatural(a, b string) int {
+	for a != "" && b != "" {
+		digitsA, digitsB := leadingDigits(a), leadingDigits(b)
+		if digitsA > 0 && digitsB > 0 {
+			numA := strings.TrimLeft(a[:digitsA], "0")
+			numB := strings.TrimLeft(b[:digitsB], "0")
+			if len(numA) != len(numB) {
+				return compareInt(len(numA), len(numB))
+			}
+			if c := strings.Compare(numA, numB); c != 0 {
+				return c
+			}
+			a, b = a[digitsA:], b[digitsB:]
+			continue
+		}
+
+		if a[0] != b[0] {
+			return compareInt(int(a[0]), int(b[0]))
+		}
+		a, b = a[1:], b[1:]
+	}
+	return compareInt(len(a), len(b))
+}
+
+// leadingDigits 返回开头连续数字的长度
+func leadingDigits(s string) int {
+	n := 0
+	for n < len(s) && '0' <= s[n] && s[n] <= '9' {
+		n++
+	}
+	return n
+}
+
+// compareInt 比较两个整数
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,326 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/guardian/content-filter/internal/logging"
+	"github.com/guardian/content-filter/internal/trace"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+const (
+	// defaultMaxRetries 请求失败后的默认重试次数
+	defaultMaxRetries = 3
+	// defaultTimeout 单次请求的默认超时
+	defaultTimeout = 5 * time.Second
+	// defaultBufferSize 等待推送的事件数上限的默认值
+	defaultBufferSize = 1000
+	// retryBackoff 第一次重试前的等待时间，之后每次翻倍
+	retryBackoff = time.Second
+
+	// HeaderEventID 事件ID请求头
+	HeaderEventID = "X-Guardian-Event-Id"
+	// HeaderTimestamp 签名时间戳（Unix秒）请求头
+	HeaderTimestamp = "X-Guardian-Timestamp"
+	// HeaderSignature 签名请求头，值为 "sha256=" + hex(HMAC-SHA256(secret, timestamp + "." + body))
+	HeaderSignature = "X-Guardian-Signature"
+)
+
+// Dispatcher 将违规事件在后台推送到配置的回调地址，每个地址独立缓冲和重试，不阻塞检查
+type Dispatcher struct {
+	senders []*sender
+	logger  logging.Logger
+	retries atomic.Int64 // 所有地址合计，写入统计时使用
+}
+
+// New 创建并启动违规回调推送，configs为空时返回nil
+func New(configs []types.ViolationWebhookConfig, logger logging.Logger) *Dispatcher {
+	if len(configs) == 0 {
+		return nil
+	}
+
+	d := &Dispatcher{logger: logger}
+	for i := range configs {
+		d.senders = append(d.senders, newSender(&configs[i], d))
+	}
+	return d
+}
+
+// Submit 结果未通过时按每个回调的条件推送违规事件，缓冲区已满时丢弃
+func (d *Dispatcher) Submit(ctx context.Context, text string, result *types.FilterResult, options *types.FilterOptions, version string) {
+	if result == nil || result.Passed {
+		return
+	}
+
+	var event *types.ViolationEvent
+	for _, s := range d.senders {
+		if !s.matches(result) {
+			continue
+		}
+		if event == nil {
+			event = newEvent(ctx, text, result, options, version)
+		}
+		s.submit(event)
+	}
+}
+
+// Stats 返回所有回调地址合计的推送统计
+func (d *Dispatcher) Stats() *types.WebhookStats {
+	stats := &types.WebhookStats{Retries: d.retries.Load()}
+	for _, s := range d.senders {
+		stats.Sent += s.sent.Load()
+		stats.Dropped += s.dropped.Load()
+		stats.Failed += s.failed.Load()
+	}
+	return stats
+}
+
+// Close 推送缓冲区中剩余的事件后停止，关闭时不再重试失败的事件
+func (d *Dispatcher) Close() {
+	for _, s := range d.senders {
+		s.close()
+	}
+}
+
+// newEvent 根据检查结果生成违规事件
+func newEvent(ctx context.Context, text string, result *types.FilterResult, options *types.FilterOptions, version string) *types.ViolationEvent {
+	tenant, caller := trace.Caller(ctx)
+	event := &types.ViolationEvent{
+		ID:         newID(),
+		Time:       time.Now(),
+		TraceID:    trace.TraceID(ctx),
+		Tenant:     tenant,
+		Caller:     caller,
+		Metadata:   trace.Metadata(ctx),
+		Text:       text,
+		Action:     result.Action,
+		ReasonCode: result.ReasonCode,
+		Words:      result.Words,
+		Categories: result.Categories,
+		Matches:    result.Matches,
+		MaxLevel:   result.MaxLevel,
+		RiskScore:  result.RiskScore,
+		Version:    version,
+	}
+	if options != nil {
+		event.UserID = options.UserID
+	}
+	if result.Offender != nil {
+		event.Violations = result.Offender.Violations
+	}
+	return event
+}
+
+// sender 推送到单个回调地址
+type sender struct {
+	dispatcher *Dispatcher
+	url        string
+	headers    map[string]string
+	secret     string
+	minLevel   int
+	categories map[string]bool
+	maxRetries int
+	client     *http.Client
+	events     chan *types.ViolationEvent
+	done       chan struct{}
+	stopped    sync.WaitGroup
+	once       sync.Once
+	sent       atomic.Int64
+	dropped    atomic.Int64
+	failed     atomic.Int64
+}
+
+// newSender 创建并启动单个回调地址的推送
+func newSender(config *types.ViolationWebhookConfig, d *Dispatcher) *sender {
+	maxRetries := config.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = defaultMaxRetries
+	}
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	bufferSize := config.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = defaultBufferSize
+	}
+
+	s := &sender{
+		dispatcher: d,
+		url:        config.URL,
+		headers:    config.Headers,
+		secret:     config.Secret,
+		minLevel:   config.MinLevel,
+		maxRetries: maxRetries,
+		client:     &http.Client{Timeout: timeout},
+		events:     make(chan *types.ViolationEvent, bufferSize),
+		done:       make(chan struct{}),
+	}
+	if len(config.Categories) > 0 {
+		s.categories = make(map[string]bool, len(config.Categories))
+		for _, category := range config.Categories {
+			s.categories[category] = true
+		}
+	}
+	s.stopped.Add(1)
+	go s.run()
+	return s
+}
+
+// matches 级别达到 min_level 或命中任一配置的分类；两者都未配置时匹配所有未通过的结果
+func (s *sender) matches(result *types.FilterResult) bool {
+	if s.minLevel <= 0 && s.categories == nil {
+		return true
+	}
+	if s.minLevel > 0 && result.MaxLevel >= s.minLevel {
+		return true
+	}
+	for _, category := range result.Categories {
+		if s.categories[category] {
+			return true
+		}
+	}
+	return false
+}
+
+// submit 加入缓冲区，已满或已关闭时丢弃
+func (s *sender) submit(event *types.ViolationEvent) {
+	select {
+	case <-s.done:
+		return
+	default:
+	}
+
+	select {
+	case s.events <- event:
+	default:
+		s.dropped.Add(1)
+	}
+}
+
+// run 逐条推送；关闭时推送缓冲区中剩余的事件
+func (s *sender) run() {
+	defer s.stopped.Done()
+
+	for {
+		select {
+		case event := <-s.events:
+			s.deliver(event)
+		case <-s.done:
+			for {
+				select {
+				case event := <-s.events:
+					s.deliver(event)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// deliver 推送一个事件，可重试的失败按指数退避重试，关闭时不再等待重试
+func (s *sender) deliver(event *types.ViolationEvent) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		s.failed.Add(1)
+		s.dispatcher.logger.Errorf("Failed to marshal violation event %s: %v", event.ID, err)
+		return
+	}
+
+	backoff := retryBackoff
+	for attempt := 0; ; attempt++ {
+		retryable, err := s.post(event.ID, body)
+		if err == nil {
+			s.sent.Add(1)
+			return
+		}
+		if !retryable || attempt >= s.maxRetries {
+			s.failed.Add(1)
+			s.dispatcher.logger.Errorf("Failed to send violation event %s to %s after %d attempts: %v", event.ID, s.url, attempt+1, err)
+			return
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-s.done:
+			s.failed.Add(1)
+			s.dispatcher.logger.Errorf("Failed to send violation event %s to %s, shutting down: %v", event.ID, s.url, err)
+			return
+		}
+		backoff *= 2
+		s.dispatcher.retries.Add(1)
+	}
+}
+
+// post 发送一次请求，返回失败是否可以重试（网络错误、429、5xx）
+func (s *sender) post(id string, body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range s.headers {
+		req.Header.Set(key, value)
+	}
+	req.Header.Set(HeaderEventID, id)
+	if s.secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(HeaderTimestamp, timestamp)
+		req.Header.Set(HeaderSignature, Sign(s.secret, timestamp, body))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("failed to send violation event: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return retryable, fmt.Errorf("violation webhook returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	io.Copy(io.Discard, resp.Body)
+	return false, nil
+}
+
+// close 停止接收事件，推送缓冲区中剩余的事件后返回
+func (s *sender) close() {
+	s.once.Do(func() {
+		close(s.done)
+		s.stopped.Wait()
+	})
+}
+
+// Sign 计算请求签名，接收方用相同方法计算并与 X-Guardian-Signature 比较，同时校验时间戳防止重放
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// newID 生成随机事件ID
+func newID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
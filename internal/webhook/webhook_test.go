@@ -0,0 +1,99 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/guardian/content-filter/internal/logging"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+func testLogger() logging.Logger {
+	return logging.Discard()
+}
+
+func TestSenderMatches(t *testing.T) {
+	tests := []struct {
+		name   string
+		config types.ViolationWebhookConfig
+		result types.FilterResult
+		want   bool
+	}{
+		{"no conditions", types.ViolationWebhookConfig{}, types.FilterResult{MaxLevel: 1}, true},
+		{"level reached", types.ViolationWebhookConfig{MinLevel: 4}, types.FilterResult{MaxLevel: 4}, true},
+		{"level below", types.ViolationWebhookConfig{MinLevel: 4}, types.FilterResult{MaxLevel: 3}, false},
+		{"category", types.ViolationWebhookConfig{MinLevel: 4, Categories: []string{"politics"}}, types.FilterResult{MaxLevel: 2, Categories: []string{"ads", "politics"}}, true},
+		{"other category", types.ViolationWebhookConfig{Categories: []string{"politics"}}, types.FilterResult{MaxLevel: 5, Categories: []string{"ads"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newSender(&tt.config, &Dispatcher{logger: testLogger()})
+			defer s.close()
+			if got := s.matches(&tt.result); got != tt.want {
+				t.Errorf("matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDispatcherRetryAndSign(t *testing.T) {
+	var attempts atomic.Int32
+	var event types.ViolationEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if got, want := r.Header.Get(HeaderSignature), Sign("s3cret", r.Header.Get(HeaderTimestamp), body); got != want {
+			t.Errorf("signature = %q, want %q", got, want)
+		}
+		json.Unmarshal(body, &event)
+	}))
+	defer server.Close()
+
+	d := New([]types.ViolationWebhookConfig{{URL: server.URL, Secret: "s3cret", MinLevel: 3}}, testLogger())
+	options := &types.FilterOptions{UserID: "u-1"}
+	d.Submit(context.Background(), "ok", &types.FilterResult{Passed: true}, options, "v1")
+	d.Submit(context.Background(), "low", &types.FilterResult{MaxLevel: 2}, options, "v1")
+	d.Submit(context.Background(), "bad", &types.FilterResult{MaxLevel: 4, Action: types.ActionReject}, options, "v1")
+
+	// 关闭时不再重试，等待重试完成后再关闭
+	deadline := time.Now().Add(5 * time.Second)
+	for d.Stats().Sent == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	d.Close()
+
+	if event.Text != "bad" || event.UserID != "u-1" || event.Version != "v1" {
+		t.Errorf("event = %+v, want the level 4 violation", event)
+	}
+	stats := d.Stats()
+	if stats.Sent != 1 || stats.Retries != 1 || stats.Failed != 0 {
+		t.Errorf("Stats() = %+v, want 1 sent after 1 retry", stats)
+	}
+}
+
+func TestDispatcherClientError(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		http.Error(w, "bad request", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	d := New([]types.ViolationWebhookConfig{{URL: server.URL}}, testLogger())
+	d.Submit(context.Background(), "bad", &types.FilterResult{MaxLevel: 4}, nil, "v1")
+	d.Close()
+
+	if attempts.Load() != 1 || d.Stats().Failed != 1 {
+		t.Errorf("attempts = %d, stats = %+v, want one attempt without retry", attempts.Load(), d.Stats())
+	}
+}
@@ -0,0 +1,83 @@
+package wordlist
+
+import (
+	"sort"
+	"time"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// Diff 比较两个版本的词库，返回新增、移除、级别变化的敏感词和白名单变化，结果按词语排序
+// 同一敏感词出现在黑名单和多个分类中时按最高级别比较；old为nil时视为空词库
+func Diff(old, new *types.WordDatabase) *types.ReloadDiff {
+	if old == nil {
+		old = &types.WordDatabase{}
+	}
+
+	diff := &types.ReloadDiff{
+		FromVersion:      old.Version,
+		ToVersion:        new.Version,
+		Time:             time.Now(),
+		Added:            []string{},
+		Removed:          []string{},
+		LevelChanges:     []types.LevelChange{},
+		WhitelistAdded:   []string{},
+		WhitelistRemoved: []string{},
+	}
+
+	oldLevels, newLevels := wordLevels(old), wordLevels(new)
+	for word, level := range newLevels {
+		oldLevel, ok := oldLevels[word]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, word)
+		case oldLevel != level:
+			diff.LevelChanges = append(diff.LevelChanges, types.LevelChange{Word: word, From: oldLevel, To: level})
+		}
+	}
+	for word := range oldLevels {
+		if _, ok := newLevels[word]; !ok {
+			diff.Removed = append(diff.Removed, word)
+		}
+	}
+
+	oldWhitelist, newWhitelist := stringSet(old.Whitelist), stringSet(new.Whitelist)
+	for entry := range newWhitelist {
+		if !oldWhitelist[entry] {
+			diff.WhitelistAdded = append(diff.WhitelistAdded, entry)
+		}
+	}
+	for entry := range oldWhitelist {
+		if !newWhitelist[entry] {
+			diff.WhitelistRemoved = append(diff.WhitelistRemoved, entry)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Slice(diff.LevelChanges, func(i, j int) bool { return diff.LevelChanges[i].Word < diff.LevelChanges[j].Word })
+	sort.Strings(diff.WhitelistAdded)
+	sort.Strings(diff.WhitelistRemoved)
+	return diff
+}
+
+// wordLevels 返回敏感词到最高级别的映射
+func wordLevels(wordDB *types.WordDatabase) map[string]int {
+	words := wordDB.Words()
+	levels := make(map[string]int, len(words))
+	for _, word := range words {
+		if level, ok := levels[word.Word]; !ok || word.Level > level {
+			levels[word.Word] = word.Level
+		}
+	}
+	return levels
+}
+
+// stringSet 将字符串列表转换为集合
+func stringSet(items []string) map[string]bool {
+	set := make(map[string]bool, len(items))
+	for _, item := range items {
+		set[item] = true
+	}
+	return set
+}
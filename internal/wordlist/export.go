@@ -0,0 +1,33 @@
+package wordlist
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// WriteCSV 将词库中的敏感词导出为CSV（word,category,level），多个分类以 / 分隔，结果按词语排序
+// 同一敏感词出现在黑名单和多个分类中时只导出一次，分类取并集、级别取最高，可由 Parse 以 FormatCSV 导入
+func WriteCSV(w io.Writer, db *types.WordDatabase) error {
+	merged := buildWordDatabase(db.Words(), &ImportOptions{Version: db.Version})
+	words := merged.Blacklist
+	sort.Slice(words, func(i, j int) bool { return words[i].Word < words[j].Word })
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"word", "category", "level"}); err != nil {
+		return fmt.Errorf("failed to write csv word list: %w", err)
+	}
+	for _, word := range words {
+		record := []string{word.Word, strings.Join(word.Categories, "/"), strconv.Itoa(word.Level)}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write csv word list: %w", err)
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
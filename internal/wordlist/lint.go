@@ -0,0 +1,91 @@
+package wordlist
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// DefaultLintMinWordLength 默认的最短敏感词字符数
+const DefaultLintMinWordLength = 2
+
+// LintWithConfig 按检查配置检查词库：min_word_length为0时使用默认值，跳过ignore中的规则
+func LintWithConfig(db *types.WordDatabase, config *types.LintConfig) []types.LintIssue {
+	minLength := config.MinWordLength
+	if minLength == 0 {
+		minLength = DefaultLintMinWordLength
+	}
+
+	issues := []types.LintIssue{}
+	for _, issue := range Lint(db, minLength) {
+		if !ignored(config.Ignore, issue.Rule) {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// ignored 规则是否在忽略列表中
+func ignored(rules []string, rule string) bool {
+	for _, r := range rules {
+		if r == rule {
+			return true
+		}
+	}
+	return false
+}
+
+// Lint 检查词库中的空词、非法级别、同一名单中的重复词、同时在黑白名单中的词和过短的词
+// minLength为0时不检查过短的词；结果按黑名单、分类名的顺序排列
+func Lint(db *types.WordDatabase, minLength int) []types.LintIssue {
+	whitelist := make(map[string]bool, len(db.Whitelist))
+	for _, word := range db.Whitelist {
+		whitelist[strings.TrimSpace(word)] = true
+	}
+
+	issues := lintList(nil, "", db.Blacklist, whitelist, minLength)
+
+	categories := make([]string, 0, len(db.Categories))
+	for category := range db.Categories {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	for _, category := range categories {
+		issues = lintList(issues, category, db.Categories[category], whitelist, minLength)
+	}
+	return issues
+}
+
+// lintList 检查一个名单（黑名单或一个分类）中的敏感词
+func lintList(issues []types.LintIssue, category string, words []types.SensitiveWord, whitelist map[string]bool, minLength int) []types.LintIssue {
+	seen := make(map[string]bool, len(words))
+	for _, word := range words {
+		text := strings.TrimSpace(word.Word)
+		if text == "" {
+			issues = append(issues, types.LintIssue{Rule: types.LintEmptyWord, Severity: types.LintError, Word: word.Word, Category: category,
+				Message: "word is empty"})
+			continue
+		}
+		if word.Level < 1 || word.Level > 5 {
+			issues = append(issues, types.LintIssue{Rule: types.LintInvalidLevel, Severity: types.LintError, Word: text, Category: category,
+				Message: fmt.Sprintf("level %d is out of range [1, 5]", word.Level)})
+		}
+		if seen[text] {
+			issues = append(issues, types.LintIssue{Rule: types.LintDuplicate, Severity: types.LintWarning, Word: text, Category: category,
+				Message: "word is defined more than once, the last definition wins"})
+		}
+		seen[text] = true
+		if whitelist[text] {
+			issues = append(issues, types.LintIssue{Rule: types.LintWhitelisted, Severity: types.LintWarning, Word: text, Category: category,
+				Message: "word is also whitelisted and will never match"})
+		}
+		if minLength > 0 && utf8.RuneCountInString(text) < minLength {
+			issues = append(issues, types.LintIssue{Rule: types.LintShortWord, Severity: types.LintWarning, Word: text, Category: category,
+				Message: fmt.Sprintf("word is shorter than %d characters and likely to cause false positives", minLength)})
+		}
+	}
+	return issues
+}
@@ -0,0 +1,77 @@
+package wordlist
+
+import (
+	"strings"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// Merge 按顺序合并多个词库
+// 白名单取并集；同一敏感词和替换词按策略处理冲突；合并结果的敏感词全部放入黑名单，
+// 版本号由各词库版本以 "+" 连接，任一词库变化时合并版本随之变化
+func Merge(dbs []*types.WordDatabase, strategy string) *types.WordDatabase {
+	if len(dbs) == 1 {
+		return dbs[0]
+	}
+
+	merged := &types.WordDatabase{
+		Whitelist:    []string{},
+		Blacklist:    []types.SensitiveWord{},
+		Categories:   map[string][]types.SensitiveWord{},
+		Replacements: map[string]string{},
+	}
+	versions := make([]string, 0, len(dbs))
+	index := make(map[string]int)
+	whitelisted := make(map[string]bool)
+
+	for _, db := range dbs {
+		versions = append(versions, db.Version)
+		if db.UpdateTime.After(merged.UpdateTime) {
+			merged.UpdateTime = db.UpdateTime
+		}
+
+		for _, entry := range db.Whitelist {
+			if !whitelisted[entry] {
+				whitelisted[entry] = true
+				merged.Whitelist = append(merged.Whitelist, entry)
+			}
+		}
+
+		for _, word := range db.Words() {
+			i, ok := index[word.Word]
+			if !ok {
+				index[word.Word] = len(merged.Blacklist)
+				merged.Blacklist = append(merged.Blacklist, word)
+				continue
+			}
+			merged.Blacklist[i] = resolveConflict(merged.Blacklist[i], word, strategy)
+		}
+
+		for word, replacement := range db.Replacements {
+			if _, ok := merged.Replacements[word]; ok && strategy == types.MergeKeepFirst {
+				continue
+			}
+			merged.Replacements[word] = replacement
+		}
+	}
+
+	merged.Version = strings.Join(versions, "+")
+	return merged
+}
+
+// resolveConflict 处理同一敏感词在两个词库中的定义，existing 来自先合并的词库
+func resolveConflict(existing, word types.SensitiveWord, strategy string) types.SensitiveWord {
+	switch strategy {
+	case types.MergeKeepFirst:
+		return existing
+	case types.MergeMaxLevel:
+		categories := mergeCategories(append([]string(nil), existing.Categories...), word.Categories)
+		if word.Level > existing.Level {
+			existing = word
+		}
+		existing.Categories = categories
+		return existing
+	default:
+		return word
+	}
+}
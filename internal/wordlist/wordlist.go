@@ -0,0 +1,425 @@
+package wordlist
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// Format 词表格式
+type Format string
+
+const (
+	FormatJSON  Format = "json"  // Guardian原生WordDatabase JSON
+	FormatYAML  Format = "yaml"  // 与WordDatabase JSON结构相同的YAML
+	FormatText  Format = "text"  // 每行一个词，支持 [分类] 或 ## 分类 作为分类标题
+	FormatHanlp Format = "hanlp" // HanLP词典格式：词语 词性 频次 ...
+	FormatTieba Format = "tieba" // 贴吧导出格式：词语|分类|级别 或 词语\t分类\t级别
+	FormatCSV   Format = "csv"   // CSV格式：word,category,level（表头可选，支持逗号/制表符/分号分隔）
+)
+
+// ImportOptions 导入选项
+type ImportOptions struct {
+	DefaultCategory string // 未指定分类时使用的分类
+	DefaultLevel    int    // 未指定级别时使用的级别
+	Version         string // 生成词库的版本号
+}
+
+// DefaultImportOptions 默认导入选项
+func DefaultImportOptions() *ImportOptions {
+	return &ImportOptions{
+		DefaultCategory: "default",
+		DefaultLevel:    3,
+	}
+}
+
+// ParseFormat 解析格式名称
+func ParseFormat(name string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimSpace(name))) {
+	case FormatJSON:
+		return FormatJSON, nil
+	case FormatYAML, "yml":
+		return FormatYAML, nil
+	case FormatText, "txt":
+		return FormatText, nil
+	case FormatHanlp:
+		return FormatHanlp, nil
+	case FormatTieba:
+		return FormatTieba, nil
+	case FormatCSV, "tsv":
+		return FormatCSV, nil
+	default:
+		return "", fmt.Errorf("unsupported word list format: %s", name)
+	}
+}
+
+// DetectFormat 根据文件名和内容推断格式
+func DetectFormat(filename string, data []byte) Format {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".json":
+		return FormatJSON
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".csv", ".tsv":
+		return FormatCSV
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		return FormatJSON
+	}
+	if bytes.HasPrefix(trimmed, []byte("---")) {
+		return FormatYAML
+	}
+
+	// 取第一行有效内容判断
+	for _, line := range strings.Split(string(trimmed), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "##") {
+			continue
+		}
+		switch {
+		case isYAMLKey(line):
+			return FormatYAML
+		case strings.Contains(line, "|"):
+			return FormatTieba
+		case strings.Contains(line, ",") || strings.Contains(line, "\t"):
+			return FormatCSV
+		case len(strings.Fields(line)) >= 3:
+			return FormatHanlp
+		}
+		return FormatText
+	}
+
+	return FormatText
+}
+
+// Parse 将第三方格式的词表转换为WordDatabase
+func Parse(r io.Reader, format Format, options *ImportOptions) (*types.WordDatabase, error) {
+	if options == nil {
+		options = DefaultImportOptions()
+	}
+
+	var (
+		words []types.SensitiveWord
+		err   error
+	)
+
+	switch format {
+	case FormatJSON:
+		var wordDB types.WordDatabase
+		if err := json.NewDecoder(r).Decode(&wordDB); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal word database: %w", err)
+		}
+		return &wordDB, nil
+	case FormatYAML:
+		return parseYAML(r)
+	case FormatText:
+		words, err = parseText(r, options)
+	case FormatHanlp:
+		words, err = parseHanlp(r, options)
+	case FormatTieba:
+		words, err = parseTieba(r, options)
+	case FormatCSV:
+		words, err = parseCSV(r, options)
+	default:
+		return nil, fmt.Errorf("unsupported word list format: %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return buildWordDatabase(words, options), nil
+}
+
+// parseYAML 解析YAML词库，字段名与JSON相同
+func parseYAML(r io.Reader) (*types.WordDatabase, error) {
+	var document interface{}
+	if err := yaml.NewDecoder(r).Decode(&document); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to unmarshal yaml word database: %w", err)
+	}
+
+	// 经JSON转换，复用WordDatabase的json标签
+	data, err := json.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert yaml word database: %w", err)
+	}
+	var wordDB types.WordDatabase
+	if err := json.Unmarshal(data, &wordDB); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal yaml word database: %w", err)
+	}
+	return &wordDB, nil
+}
+
+// isYAMLKey 是否为YAML词库的顶层字段行，如 "version: 1.0.0"、"blacklist:"
+func isYAMLKey(line string) bool {
+	key, _, ok := strings.Cut(line, ":")
+	if !ok {
+		return false
+	}
+	switch key {
+	case "version", "update_time", "whitelist", "blacklist", "categories", "replacements":
+		return true
+	default:
+		return false
+	}
+}
+
+// parseText 解析纯文本词表
+func parseText(r io.Reader, options *ImportOptions) ([]types.SensitiveWord, error) {
+	words := make([]types.SensitiveWord, 0)
+	category := options.DefaultCategory
+
+	err := scanLines(r, func(line string) error {
+		// 分类标题
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			category = strings.TrimSpace(line[1 : len(line)-1])
+			return nil
+		}
+		if strings.HasPrefix(line, "##") {
+			category = strings.TrimSpace(strings.TrimLeft(line, "#"))
+			return nil
+		}
+		if strings.HasPrefix(line, "#") {
+			return nil
+		}
+
+		words = append(words, newWord(line, category, options.DefaultLevel))
+		return nil
+	})
+
+	return words, err
+}
+
+// parseHanlp 解析HanLP词典格式，仅取首列作为词语，词性作为分类
+func parseHanlp(r io.Reader, options *ImportOptions) ([]types.SensitiveWord, error) {
+	words := make([]types.SensitiveWord, 0)
+
+	err := scanLines(r, func(line string) error {
+		if strings.HasPrefix(line, "#") {
+			return nil
+		}
+
+		fields := strings.Fields(line)
+		category := options.DefaultCategory
+		if len(fields) >= 2 && !isNumber(fields[1]) {
+			category = fields[1]
+		}
+
+		words = append(words, newWord(fields[0], category, options.DefaultLevel))
+		return nil
+	})
+
+	return words, err
+}
+
+// parseTieba 解析贴吧导出格式
+func parseTieba(r io.Reader, options *ImportOptions) ([]types.SensitiveWord, error) {
+	words := make([]types.SensitiveWord, 0)
+
+	err := scanLines(r, func(line string) error {
+		if strings.HasPrefix(line, "#") {
+			return nil
+		}
+
+		sep := "|"
+		if !strings.Contains(line, sep) {
+			sep = "\t"
+		}
+		fields := strings.Split(line, sep)
+
+		word, err := fieldsToWord(fields, options)
+		if err != nil {
+			return err
+		}
+		words = append(words, word)
+		return nil
+	})
+
+	return words, err
+}
+
+// parseCSV 解析CSV词表
+func parseCSV(r io.Reader, options *ImportOptions) ([]types.SensitiveWord, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read word list: %w", err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = detectDelimiter(data)
+	reader.FieldsPerRecord = -1
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse csv word list: %w", err)
+	}
+
+	words := make([]types.SensitiveWord, 0, len(records))
+	for i, record := range records {
+		if len(record) == 0 || strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		// 跳过表头
+		if i == 0 && strings.EqualFold(strings.TrimSpace(record[0]), "word") {
+			continue
+		}
+
+		word, err := fieldsToWord(record, options)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		words = append(words, word)
+	}
+
+	return words, nil
+}
+
+// fieldsToWord 将 词语,分类,级别 字段转换为敏感词
+func fieldsToWord(fields []string, options *ImportOptions) (types.SensitiveWord, error) {
+	category := options.DefaultCategory
+	level := options.DefaultLevel
+
+	if len(fields) >= 2 && strings.TrimSpace(fields[1]) != "" {
+		category = strings.TrimSpace(fields[1])
+	}
+	if len(fields) >= 3 && strings.TrimSpace(fields[2]) != "" {
+		parsed, err := strconv.Atoi(strings.TrimSpace(fields[2]))
+		if err != nil {
+			return types.SensitiveWord{}, fmt.Errorf("invalid level %q for word %q", fields[2], fields[0])
+		}
+		level = parsed
+	}
+
+	return newWord(fields[0], category, level), nil
+}
+
+// newWord 创建敏感词，分类可用 / 或 ; 分隔多个
+func newWord(word, category string, level int) types.SensitiveWord {
+	categories := make([]string, 0, 1)
+	for _, c := range strings.FieldsFunc(category, func(r rune) bool { return r == '/' || r == ';' }) {
+		if c = strings.TrimSpace(c); c != "" {
+			categories = append(categories, c)
+		}
+	}
+
+	return types.SensitiveWord{
+		Word:       strings.TrimSpace(word),
+		Categories: categories,
+		Level:      level,
+	}
+}
+
+// buildWordDatabase 构建词库，相同词语只保留最高级别并合并分类
+func buildWordDatabase(words []types.SensitiveWord, options *ImportOptions) *types.WordDatabase {
+	index := make(map[string]int)
+	blacklist := make([]types.SensitiveWord, 0, len(words))
+
+	for _, word := range words {
+		if word.Word == "" {
+			continue
+		}
+		if i, ok := index[word.Word]; ok {
+			existing := &blacklist[i]
+			if word.Level > existing.Level {
+				existing.Level = word.Level
+			}
+			existing.Categories = mergeCategories(existing.Categories, word.Categories)
+			continue
+		}
+		index[word.Word] = len(blacklist)
+		blacklist = append(blacklist, word)
+	}
+
+	version := options.Version
+	if version == "" {
+		version = time.Now().Format("20060102150405")
+	}
+
+	return &types.WordDatabase{
+		Version:      version,
+		UpdateTime:   time.Now(),
+		Whitelist:    []string{},
+		Blacklist:    blacklist,
+		Categories:   map[string][]types.SensitiveWord{},
+		Replacements: map[string]string{},
+	}
+}
+
+// mergeCategories 合并分类并去重
+func mergeCategories(a, b []string) []string {
+	seen := make(map[string]bool, len(a))
+	for _, c := range a {
+		seen[c] = true
+	}
+	for _, c := range b {
+		if !seen[c] {
+			seen[c] = true
+			a = append(a, c)
+		}
+	}
+	return a
+}
+
+// scanLines 逐行扫描，跳过空行
+func scanLines(r io.Reader, fn func(line string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if lineNo == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		if line == "" {
+			continue
+		}
+		if err := fn(line); err != nil {
+			return fmt.Errorf("line %d: %w", lineNo, err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read word list: %w", err)
+	}
+	return nil
+}
+
+// detectDelimiter 推断CSV分隔符
+func detectDelimiter(data []byte) rune {
+	firstLine := data
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		firstLine = data[:i]
+	}
+
+	switch {
+	case bytes.ContainsRune(firstLine, '\t'):
+		return '\t'
+	case bytes.ContainsRune(firstLine, ';') && !bytes.ContainsRune(firstLine, ','):
+		return ';'
+	default:
+		return ','
+	}
+}
+
+// isNumber 判断字符串是否为数字
+func isNumber(s string) bool {
+	_, err := strconv.Atoi(s)
+	return err == nil
+}
@@ -0,0 +1,202 @@
+package wordlist
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+func TestParseFormats(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   Format
+		input    string
+		expected map[string]int // 词语 -> 级别
+	}{
+		{"text", FormatText, "[abuse]\n辱骂词\n# 注释\n## politics\n敏感词\n", map[string]int{"辱骂词": 3, "敏感词": 3}},
+		{"hanlp", FormatHanlp, "敏感词 nz 1024\n辱骂词 abuse 10\n", map[string]int{"敏感词": 3, "辱骂词": 3}},
+		{"tieba", FormatTieba, "敏感词|politics|4\n辱骂词\tabuse\t5\n", map[string]int{"敏感词": 4, "辱骂词": 5}},
+		{"csv", FormatCSV, "word,category,level\n敏感词,politics,4\n敏感词,abuse,2\n", map[string]int{"敏感词": 4}},
+		{"yaml", FormatYAML, "version: 1.0.0\nblacklist:\n  - word: 敏感词\n    level: 4\n    categories: [politics]\n", map[string]int{"敏感词": 4}},
+	}
+
+	for _, test := range tests {
+		wordDB, err := Parse(strings.NewReader(test.input), test.format, nil)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if len(wordDB.Blacklist) != len(test.expected) {
+			t.Errorf("%s: got %d words, expected %d", test.name, len(wordDB.Blacklist), len(test.expected))
+		}
+		for _, word := range wordDB.Blacklist {
+			if level, ok := test.expected[word.Word]; !ok || level != word.Level {
+				t.Errorf("%s: unexpected word %s level %d", test.name, word.Word, word.Level)
+			}
+		}
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		filename string
+		data     string
+		expected Format
+	}{
+		{"words.json", "{}", FormatJSON},
+		{"words.csv", "a,b", FormatCSV},
+		{"words.txt", "[abuse]\n辱骂词", FormatText},
+		{"words.txt", "敏感词|politics", FormatTieba},
+		{"words.dic", "敏感词 nz 1024", FormatHanlp},
+		{"words.yml", "blacklist: []", FormatYAML},
+		{"words", "# 词库\nversion: 1.0.0\nblacklist:\n  - word: 敏感词", FormatYAML},
+	}
+
+	for _, test := range tests {
+		if got := DetectFormat(test.filename, []byte(test.data)); got != test.expected {
+			t.Errorf("DetectFormat(%s) = %s, expected %s", test.filename, got, test.expected)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	base := &types.WordDatabase{
+		Version:      "1",
+		Whitelist:    []string{"白名单"},
+		Blacklist:    []types.SensitiveWord{{Word: "敏感词", Categories: []string{"politics"}, Level: 4}},
+		Replacements: map[string]string{"敏感词": "***"},
+	}
+	product := &types.WordDatabase{
+		Version:      "7",
+		Whitelist:    []string{"白名单", "产品名"},
+		Categories:   map[string][]types.SensitiveWord{"abuse": {{Word: "敏感词", Categories: []string{"abuse"}, Level: 2}, {Word: "辱骂词", Level: 3}}},
+		Replacements: map[string]string{"敏感词": "[已屏蔽]"},
+	}
+
+	tests := []struct {
+		strategy    string
+		level       int
+		categories  string
+		replacement string
+	}{
+		{types.MergeOverride, 2, "abuse", "[已屏蔽]"},
+		{types.MergeKeepFirst, 4, "politics", "***"},
+		{types.MergeMaxLevel, 4, "politics,abuse", "[已屏蔽]"},
+	}
+
+	for _, test := range tests {
+		merged := Merge([]*types.WordDatabase{base, product}, test.strategy)
+		if merged.Version != "1+7" {
+			t.Errorf("%s: got version %q, expected 1+7", test.strategy, merged.Version)
+		}
+		if len(merged.Whitelist) != 2 || len(merged.Blacklist) != 2 {
+			t.Fatalf("%s: got %d whitelist entries and %d words, expected 2 and 2", test.strategy, len(merged.Whitelist), len(merged.Blacklist))
+		}
+		word := merged.Blacklist[0]
+		if word.Level != test.level || strings.Join(word.Categories, ",") != test.categories {
+			t.Errorf("%s: got level %d categories %v", test.strategy, word.Level, word.Categories)
+		}
+		if merged.Replacements["敏感词"] != test.replacement {
+			t.Errorf("%s: got replacement %q, expected %q", test.strategy, merged.Replacements["敏感词"], test.replacement)
+		}
+	}
+
+	if base.Blacklist[0].Categories[0] != "politics" || len(base.Blacklist[0].Categories) != 1 {
+		t.Errorf("merge modified the input word database: %v", base.Blacklist[0].Categories)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	old := &types.WordDatabase{
+		Version:    "1",
+		Whitelist:  []string{"白名单", "旧条目"},
+		Blacklist:  []types.SensitiveWord{{Word: "保留词", Level: 3}, {Word: "删除词", Level: 2}},
+		Categories: map[string][]types.SensitiveWord{"abuse": {{Word: "调级词", Level: 2}}},
+	}
+	new := &types.WordDatabase{
+		Version:   "2",
+		Whitelist: []string{"白名单", "新条目"},
+		Blacklist: []types.SensitiveWord{{Word: "保留词", Level: 3}, {Word: "调级词", Level: 4}, {Word: "新增词", Level: 1}},
+	}
+
+	diff := Diff(old, new)
+	if diff.FromVersion != "1" || diff.ToVersion != "2" {
+		t.Errorf("got versions %s -> %s", diff.FromVersion, diff.ToVersion)
+	}
+	if strings.Join(diff.Added, ",") != "新增词" || strings.Join(diff.Removed, ",") != "删除词" {
+		t.Errorf("got added %v removed %v", diff.Added, diff.Removed)
+	}
+	if len(diff.LevelChanges) != 1 || diff.LevelChanges[0] != (types.LevelChange{Word: "调级词", From: 2, To: 4}) {
+		t.Errorf("got level changes %v", diff.LevelChanges)
+	}
+	if strings.Join(diff.WhitelistAdded, ",") != "新条目" || strings.Join(diff.WhitelistRemoved, ",") != "旧条目" {
+		t.Errorf("got whitelist added %v removed %v", diff.WhitelistAdded, diff.WhitelistRemoved)
+	}
+	if !Diff(new, new).Empty() {
+		t.Errorf("expected empty diff for identical word databases")
+	}
+}
+
+func TestLint(t *testing.T) {
+	db := &types.WordDatabase{
+		Whitelist: []string{"白名单词"},
+		Blacklist: []types.SensitiveWord{
+			{Word: "敏感词", Level: 3},
+			{Word: "敏感词", Level: 4},
+			{Word: " ", Level: 3},
+			{Word: "白名单词", Level: 2},
+			{Word: "赌", Level: 3},
+		},
+		Categories: map[string][]types.SensitiveWord{
+			"abuse": {{Word: "辱骂词", Level: 7}, {Word: "敏感词", Level: 3}},
+		},
+	}
+
+	issues := Lint(db, 2)
+	expected := []string{
+		"duplicate:敏感词:",
+		"empty_word: :",
+		"whitelisted:白名单词:",
+		"short_word:赌:",
+		"invalid_level:辱骂词:abuse",
+	}
+	if len(issues) != len(expected) {
+		t.Fatalf("got %d issues, expected %d: %+v", len(issues), len(expected), issues)
+	}
+	for i, issue := range issues {
+		if got := issue.Rule + ":" + issue.Word + ":" + issue.Category; got != expected[i] {
+			t.Errorf("issue %d = %s, expected %s", i, got, expected[i])
+		}
+	}
+	if issues[1].Severity != types.LintError || issues[0].Severity != types.LintWarning {
+		t.Errorf("unexpected severities: %+v", issues)
+	}
+
+	if issues := Lint(db, 0); len(issues) != 4 {
+		t.Errorf("min length 0 should skip short_word, got %d issues", len(issues))
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	db := &types.WordDatabase{
+		Blacklist:  []types.SensitiveWord{{Word: "敏感词", Categories: []string{"politics"}, Level: 3}, {Word: "广告, 词", Level: 2}},
+		Categories: map[string][]types.SensitiveWord{"abuse": {{Word: "敏感词", Categories: []string{"abuse"}, Level: 4}}},
+	}
+
+	var buf strings.Builder
+	if err := WriteCSV(&buf, db); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "word,category,level\n\"广告, 词\",,2\n敏感词,politics/abuse,4\n"
+	if buf.String() != expected {
+		t.Fatalf("got %q, expected %q", buf.String(), expected)
+	}
+
+	parsed, err := Parse(strings.NewReader(buf.String()), FormatCSV, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(parsed.Blacklist) != 2 || parsed.Blacklist[1].Level != 4 || strings.Join(parsed.Blacklist[1].Categories, ",") != "politics,abuse" {
+		t.Errorf("round trip got %+v", parsed.Blacklist)
+	}
+}
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"container/list"
+	"encoding/json"
+	"sync"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// resultCache 最近的远程检查结果，LRU淘汰，远程服务不可用时使用；nil表示不缓存
+type resultCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List               // 最近使用的在前
+	entries  map[string]*list.Element // 键 -> order中的元素
+}
+
+// cacheEntry 缓存的结果
+type cacheEntry struct {
+	key    string
+	result *types.FilterResult
+}
+
+// newResultCache 创建最多保存capacity个结果的缓存
+func newResultCache(capacity int) *resultCache {
+	return &resultCache{capacity: capacity, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+// cacheKey 文本和检查选项组成的缓存键
+func cacheKey(text string, options *types.FilterOptions) string {
+	data, _ := json.Marshal(options)
+	return string(data) + "\x00" + text
+}
+
+// add 加入结果，超过容量时淘汰最久未使用的结果
+func (c *resultCache) add(key string, result *types.FilterResult) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*cacheEntry).result = result
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, result: result})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// get 返回缓存的结果，未命中时返回nil
+func (c *resultCache) get(key string) *types.FilterResult {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*cacheEntry).result
+}
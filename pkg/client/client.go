@@ -0,0 +1,334 @@
+// Package client 是 Guardian HTTP 服务（cmd/guardian）的Go客户端，封装检查、批量检查、统计和白名单接口
+//
+//	c, err := client.New(&client.Config{BaseURL: "http://guardian:8080"})
+//	result, err := c.Check(ctx, "待检查文本", nil)
+//
+// 网络错误、408、429、502、503、504 按指数退避重试；配置 CacheSize 或 Fallback 后，
+// 重试仍失败时返回最近的远程结果或本地实例的结果，结果的 degraded_reason 为 remote_fallback
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/guardian/content-filter/internal/trace"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+const (
+	// defaultTimeout 单次请求的默认超时
+	defaultTimeout = 5 * time.Second
+	// defaultMaxRetries 默认重试次数
+	defaultMaxRetries = 2
+	// defaultRetryBackoff 第一次重试前的默认等待时间，之后每次翻倍
+	defaultRetryBackoff = 100 * time.Millisecond
+	// defaultMaxIdleConns 每个服务地址保持的默认空闲连接数
+	defaultMaxIdleConns = 100
+)
+
+// Checker 远程服务不可用时使用的本地检查，*guardian.Guardian 实现了该接口
+type Checker interface {
+	CheckContext(ctx context.Context, text string, options *types.FilterOptions) (*types.FilterResult, error)
+}
+
+// Config 客户端配置
+type Config struct {
+	BaseURL      string            // 服务地址，如 http://guardian:8080
+	Headers      map[string]string // 每个请求附加的请求头，如鉴权
+	Tenant       string            // 租户，通过 X-Tenant-ID 传递，写入审计记录
+	CallerID     string            // 调用方ID，通过 X-Caller-ID 传递，写入审计记录
+	Timeout      time.Duration     // 单次请求超时，默认5s
+	MaxRetries   int               // 可重试的失败后的最大重试次数，默认2，负数表示不重试
+	RetryBackoff time.Duration     // 第一次重试前的等待时间，之后每次翻倍，默认100ms
+	MaxIdleConns int               // 每个服务地址保持的空闲连接数，默认100
+	HTTPClient   *http.Client      // 自定义HTTP客户端，设置后忽略 Timeout 和 MaxIdleConns
+	CacheSize    int               // 缓存最近多少个远程检查结果，远程服务不可用时返回缓存的结果，0表示不缓存
+	Fallback     Checker           // 远程服务不可用且缓存未命中时使用的本地检查，如用本地词库创建的 *guardian.Guardian
+}
+
+// ErrUnavailable 重试后仍因网络错误、超时、限流或网关错误失败，可用 errors.Is 判断
+var ErrUnavailable = errors.New("guardian service unavailable")
+
+// Error 服务返回的非2xx响应
+type Error struct {
+	StatusCode int    // HTTP状态码
+	Message    string // 响应内容
+}
+
+// Error 返回错误描述
+func (e *Error) Error() string {
+	return fmt.Sprintf("guardian returned %d: %s", e.StatusCode, e.Message)
+}
+
+// Client Guardian HTTP 服务的客户端，可并发使用
+type Client struct {
+	baseURL      string
+	headers      map[string]string
+	tenant       string
+	callerID     string
+	maxRetries   int
+	retryBackoff time.Duration
+	http         *http.Client
+	cache        *resultCache // 未配置 CacheSize 时为nil
+	fallback     Checker
+}
+
+// New 创建客户端
+func New(config *Config) (*Client, error) {
+	if config.BaseURL == "" {
+		return nil, errors.New("client: base url is required")
+	}
+
+	c := &Client{
+		baseURL:      strings.TrimRight(config.BaseURL, "/"),
+		headers:      config.Headers,
+		tenant:       config.Tenant,
+		callerID:     config.CallerID,
+		maxRetries:   config.MaxRetries,
+		retryBackoff: config.RetryBackoff,
+		http:         config.HTTPClient,
+		fallback:     config.Fallback,
+	}
+	if c.maxRetries == 0 {
+		c.maxRetries = defaultMaxRetries
+	}
+	if c.retryBackoff <= 0 {
+		c.retryBackoff = defaultRetryBackoff
+	}
+	if c.http == nil {
+		timeout := config.Timeout
+		if timeout <= 0 {
+			timeout = defaultTimeout
+		}
+		maxIdleConns := config.MaxIdleConns
+		if maxIdleConns <= 0 {
+			maxIdleConns = defaultMaxIdleConns
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.MaxIdleConns = maxIdleConns
+		transport.MaxIdleConnsPerHost = maxIdleConns
+		c.http = &http.Client{Timeout: timeout, Transport: transport}
+	}
+	if config.CacheSize > 0 {
+		c.cache = newResultCache(config.CacheSize)
+	}
+	return c, nil
+}
+
+// Check 检查文本，options为nil时使用服务端的默认选项；上下文中的追踪ID和 guardian.WithMetadata 附加的信息随请求传递
+func (c *Client) Check(ctx context.Context, text string, options *types.FilterOptions) (*types.FilterResult, error) {
+	req := struct {
+		Text     string               `json:"text"`
+		Options  *types.FilterOptions `json:"options,omitempty"`
+		Metadata map[string]string    `json:"metadata,omitempty"`
+	}{Text: text, Options: options, Metadata: trace.Metadata(ctx)}
+
+	var result types.FilterResult
+	err := c.do(ctx, http.MethodPost, "/check", req, &result)
+	if err == nil {
+		c.cache.add(cacheKey(text, options), &result)
+		return &result, nil
+	}
+	if errors.Is(err, ErrUnavailable) {
+		if fallback := c.fallbackResult(ctx, text, options); fallback != nil {
+			return fallback, nil
+		}
+	}
+	return nil, err
+}
+
+// BatchCheck 批量检查，结果与texts一一对应；远程服务不可用时逐条使用缓存或本地检查，有任一条得不到结果时返回错误
+func (c *Client) BatchCheck(ctx context.Context, texts []string, options *types.FilterOptions) ([]*types.FilterResult, error) {
+	req := struct {
+		Texts   []string             `json:"texts"`
+		Options *types.FilterOptions `json:"options,omitempty"`
+	}{Texts: texts, Options: options}
+
+	var results []*types.FilterResult
+	err := c.do(ctx, http.MethodPost, "/check/batch", req, &results)
+	if err == nil {
+		if len(results) != len(texts) {
+			return nil, fmt.Errorf("client: batch check returned %d results for %d texts", len(results), len(texts))
+		}
+		for i, result := range results {
+			c.cache.add(cacheKey(texts[i], options), result)
+		}
+		return results, nil
+	}
+	if !errors.Is(err, ErrUnavailable) || (c.cache == nil && c.fallback == nil) {
+		return nil, err
+	}
+
+	results = make([]*types.FilterResult, len(texts))
+	for i, text := range texts {
+		if results[i] = c.fallbackResult(ctx, text, options); results[i] == nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// BatchCheckItems 批量检查带ID的文本，返回附带ID的结果和汇总；远程服务不可用时的处理同 BatchCheck
+func (c *Client) BatchCheckItems(ctx context.Context, items []types.BatchItem, options *types.FilterOptions) (*types.BatchResponse, error) {
+	req := struct {
+		Items   []types.BatchItem    `json:"items"`
+		Options *types.FilterOptions `json:"options,omitempty"`
+	}{Items: items, Options: options}
+
+	var response types.BatchResponse
+	err := c.do(ctx, http.MethodPost, "/check/batch", req, &response)
+	if err == nil {
+		if len(response.Results) != len(items) {
+			return nil, fmt.Errorf("client: batch check returned %d results for %d items", len(response.Results), len(items))
+		}
+		for i, item := range response.Results {
+			c.cache.add(cacheKey(items[i].Text, options), item.Result)
+		}
+		return &response, nil
+	}
+	if !errors.Is(err, ErrUnavailable) || (c.cache == nil && c.fallback == nil) {
+		return nil, err
+	}
+
+	results := make([]*types.FilterResult, len(items))
+	for i, item := range items {
+		if results[i] = c.fallbackResult(ctx, item.Text, options); results[i] == nil {
+			return nil, err
+		}
+	}
+	return types.NewBatchResponse(items, results), nil
+}
+
+// Stats 返回服务的统计信息
+func (c *Client) Stats(ctx context.Context) (*types.Stats, error) {
+	var stats types.Stats
+	if err := c.do(ctx, http.MethodGet, "/stats", nil, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// AddToWhitelist 添加白名单，服务端要求管理令牌，通过 Config.Headers 设置 Authorization: Bearer <token>
+func (c *Client) AddToWhitelist(ctx context.Context, word string) error {
+	return c.do(ctx, http.MethodPost, "/whitelist", map[string]string{"word": word}, nil)
+}
+
+// RemoveFromWhitelist 移除白名单，同样要求管理令牌
+func (c *Client) RemoveFromWhitelist(ctx context.Context, word string) error {
+	return c.do(ctx, http.MethodDelete, "/whitelist", map[string]string{"word": word}, nil)
+}
+
+// Close 关闭空闲连接
+func (c *Client) Close() {
+	c.http.CloseIdleConnections()
+}
+
+// fallbackResult 远程服务不可用时依次使用缓存和本地检查，返回标记为降级的结果副本，都没有时返回nil
+func (c *Client) fallbackResult(ctx context.Context, text string, options *types.FilterOptions) *types.FilterResult {
+	result := c.cache.get(cacheKey(text, options))
+	if result == nil && c.fallback != nil && ctx.Err() == nil {
+		local, err := c.fallback.CheckContext(ctx, text, options)
+		if err == nil {
+			result = local
+		}
+	}
+	if result == nil {
+		return nil
+	}
+
+	degraded := *result
+	degraded.Degraded = true
+	degraded.DegradedReason = types.DegradedRemoteFallback
+	return &degraded
+}
+
+// do 发送请求并解析JSON响应，out为nil时丢弃响应；可重试的失败按指数退避重试
+func (c *Client) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return fmt.Errorf("client: failed to marshal request: %w", err)
+		}
+	}
+
+	backoff := c.retryBackoff
+	for attempt := 0; ; attempt++ {
+		retryable, err := c.send(ctx, method, path, body, out)
+		if err == nil || !retryable {
+			return err
+		}
+		if attempt >= c.maxRetries || ctx.Err() != nil {
+			return fmt.Errorf("%w: %w", ErrUnavailable, err)
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", ErrUnavailable, err)
+		}
+		backoff *= 2
+	}
+}
+
+// send 发送一次请求，返回失败是否可以重试
+func (c *Client) send(ctx context.Context, method, path string, body []byte, out interface{}) (bool, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return false, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range c.headers {
+		req.Header.Set(key, value)
+	}
+	if traceID := trace.TraceID(ctx); traceID != "" {
+		req.Header.Set("X-Request-ID", traceID)
+	}
+	if c.tenant != "" {
+		req.Header.Set("X-Tenant-ID", c.tenant)
+	}
+	if c.callerID != "" {
+		req.Header.Set("X-Caller-ID", c.callerID)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("client: failed to request %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return retryableStatus(resp.StatusCode), &Error{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(msg))}
+	}
+	if out == nil {
+		io.Copy(io.Discard, resp.Body)
+		return false, nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return false, fmt.Errorf("client: failed to decode %s response: %w", path, err)
+	}
+	return false, nil
+}
+
+// retryableStatus 请求超时、限流和网关错误可以重试
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,188 @@
+package guardian_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/guardian/content-filter/pkg/guardian"
+	"github.com/guardian/content-filter/pkg/guardiantest"
+)
+
+// 扩展接口只使用 pkg/guardian 导出的类型即可实现，不需要导入 internal 包
+
+// mapCache 以map保存结果的缓存，记录最近一次读写收到的上下文
+type mapCache struct {
+	mu      sync.Mutex
+	entries map[string]*guardian.FilterResult
+	ctx     context.Context
+}
+
+var (
+	_ guardian.Cache              = (*mapCache)(nil)
+	_ guardian.CacheUsageReporter = (*mapCache)(nil)
+)
+
+func (c *mapCache) Get(ctx context.Context, key string) (*guardian.FilterResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ctx = ctx
+	result, ok := c.entries[key]
+	return result, ok
+}
+
+func (c *mapCache) Set(ctx context.Context, key string, value *guardian.FilterResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ctx = ctx
+	c.entries[key] = value
+}
+
+func (c *mapCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*guardian.FilterResult)
+}
+
+func (c *mapCache) Stats() map[string]interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return map[string]interface{}{"size": len(c.entries)}
+}
+
+func (c *mapCache) Close() {}
+
+func (c *mapCache) Usage() guardian.CacheUsage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return guardian.CacheUsage{Entries: len(c.entries)}
+}
+
+func TestExternalCache(t *testing.T) {
+	g := guardiantest.New(t, guardiantest.WordDatabase("违禁词"))
+	c := &mapCache{entries: make(map[string]*guardian.FilterResult)}
+	g.SetCache(c)
+
+	for i := 0; i < 2; i++ {
+		if result := g.Check("这里有违禁词"); result.Passed || result.Action != guardian.ActionReject {
+			t.Fatalf("Check() = %+v, want rejected", result)
+		}
+	}
+	if usage := c.Usage(); usage.Entries != 1 {
+		t.Errorf("cache entries = %d, want 1", usage.Entries)
+	}
+
+	// 缓存收到检查调用方的上下文
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "caller")
+	if _, err := g.CheckContext(ctx, "这里有违禁词", nil); err != nil {
+		t.Fatalf("CheckContext() error = %v", err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ctx == nil || c.ctx.Value(ctxKey{}) != "caller" {
+		t.Error("cache Get() did not receive the caller's context")
+	}
+}
+
+// memoryQueue 以map保存条目的复核队列
+type memoryQueue struct {
+	mu    sync.Mutex
+	items map[string]*guardian.ReviewItem
+	order []string
+}
+
+var _ guardian.ReviewQueue = (*memoryQueue)(nil)
+
+func (q *memoryQueue) Enqueue(ctx context.Context, item *guardian.ReviewItem) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.items[item.ID] = item
+	q.order = append(q.order, item.ID)
+	return nil
+}
+
+func (q *memoryQueue) Pending(ctx context.Context, limit int) ([]*guardian.ReviewItem, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var pending []*guardian.ReviewItem
+	for _, id := range q.order {
+		if item := q.items[id]; item.Status == guardian.ReviewPending && (limit <= 0 || len(pending) < limit) {
+			pending = append(pending, item)
+		}
+	}
+	return pending, nil
+}
+
+func (q *memoryQueue) Get(ctx context.Context, id string) (*guardian.ReviewItem, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	item, ok := q.items[id]
+	if !ok {
+		return nil, guardian.ErrReviewNotFound
+	}
+	return item, nil
+}
+
+func (q *memoryQueue) Resolve(ctx context.Context, decision *guardian.ReviewDecision) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	item, ok := q.items[decision.ID]
+	if !ok {
+		return guardian.ErrReviewNotFound
+	}
+	if item.Status != guardian.ReviewPending {
+		return guardian.ErrReviewResolved
+	}
+	item.Status = decision.Status
+	return nil
+}
+
+func (q *memoryQueue) Close() error { return nil }
+
+// recordingSink 保存写入的审计记录
+type recordingSink struct {
+	mu      sync.Mutex
+	records []*guardian.AuditRecord
+}
+
+var _ guardian.AuditSink = (*recordingSink)(nil)
+
+func (s *recordingSink) Write(ctx context.Context, records []*guardian.AuditRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, records...)
+	return nil
+}
+
+func (s *recordingSink) Close() error { return nil }
+
+func TestExternalMatcher(t *testing.T) {
+	g := guardiantest.New(t, guardiantest.WordDatabase("违禁词"))
+	g.RegisterMatcher(guardian.MatcherFunc(func(text string) []guardian.MatchDetail {
+		return []guardian.MatchDetail{{Word: "外部", Categories: []string{"external"}, Level: 3, Positions: []guardian.Position{{Start: 0, End: len(text)}}}}
+	}))
+
+	if result := g.Check("正常文本"); result.Passed {
+		t.Errorf("Check() = %+v, want blocked by the external matcher", result)
+	}
+}
+
+func TestExternalReviewQueue(t *testing.T) {
+	g := guardiantest.New(t, guardiantest.WordDatabase("违禁词"))
+	queue := &memoryQueue{items: make(map[string]*guardian.ReviewItem)}
+	g.SetReviewQueue(queue)
+	ctx := context.Background()
+
+	if err := queue.Enqueue(ctx, &guardian.ReviewItem{ID: "1", Text: "待复核", Status: guardian.ReviewPending}); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.ResolveReview(ctx, &guardian.ReviewDecision{ID: "1", Status: guardian.ReviewApproved, Reviewer: "alice"}); err != nil {
+		t.Fatalf("ResolveReview() error = %v", err)
+	}
+	err := g.ResolveReview(ctx, &guardian.ReviewDecision{ID: "1", Status: guardian.ReviewRejected, Reviewer: "alice"})
+	if !errors.Is(err, guardian.ErrReviewResolved) {
+		t.Errorf("ResolveReview() error = %v, want ErrReviewResolved", err)
+	}
+}
@@ -1,56 +1,149 @@
 package guardian
 
 import (
+	"context"
+	"crypto/ed25519"
+	"crypto/hmac"
 	"fmt"
-	"time"
-
-	"github.com/sirupsen/logrus"
+	"io"
+	"log/slog"
 
+	"github.com/guardian/content-filter/internal/audit"
+	"github.com/guardian/content-filter/internal/cache"
+	"github.com/guardian/content-filter/internal/classifier"
 	"github.com/guardian/content-filter/internal/filter"
-	"github.com/guardian/content-filter/internal/nacos"
+	"github.com/guardian/content-filter/internal/logging"
+	"github.com/guardian/content-filter/internal/matcher"
+	"github.com/guardian/content-filter/internal/offender"
+	"github.com/guardian/content-filter/internal/review"
+	"github.com/guardian/content-filter/internal/source"
+	"github.com/guardian/content-filter/internal/trace"
 	"github.com/guardian/content-filter/internal/types"
+	"github.com/guardian/content-filter/internal/webhook"
+)
+
+var (
+	// ErrWordNotFound 要移除的敏感词不存在
+	ErrWordNotFound = filter.ErrWordNotFound
+	// ErrWordExists 要新增的敏感词已存在
+	ErrWordExists = filter.ErrWordExists
+	// ErrConcurrentUpdate 写回词库时词库被其他实例并发修改，重试后仍未成功
+	ErrConcurrentUpdate = filter.ErrConcurrentUpdate
+	// ErrPersistFormat 词库源中的内容不是JSON格式，拒绝写回以免覆盖原有格式
+	ErrPersistFormat = filter.ErrPersistFormat
+	// ErrStaleVersion 配置源返回的词库版本比已加载的旧，本次重载被拒绝
+	ErrStaleVersion = filter.ErrStaleVersion
+	// ErrNoCanary 当前没有灰度中的词库
+	ErrNoCanary = filter.ErrNoCanary
+	// ErrLintFailed 词库检查未通过，按 lint.strictness 拒绝了该词库，同时满足 errors.Is(err, ErrInvalidWordDB)
+	ErrLintFailed = filter.ErrLintFailed
+	// ErrWordSourceUnavailable 词库配置源不可用，如配置中心连接失败或超时
+	ErrWordSourceUnavailable = types.ErrWordSourceUnavailable
+	// ErrEmptyDictionary 词库为空：配置源中没有该词库、内容为空或没有任何敏感词
+	ErrEmptyDictionary = types.ErrEmptyDictionary
+	// ErrInvalidWordDB 词库内容无法解析或未通过校验和、签名、词库检查
+	ErrInvalidWordDB = types.ErrInvalidWordDB
+	// ErrTextTooLong 文本超过 max_text_length 且 max_text_length_action 为 reject，见 FilterResult.Err
+	ErrTextTooLong = types.ErrTextTooLong
+	// ErrReviewNotFound 复核条目不存在或已过期
+	ErrReviewNotFound = review.ErrNotFound
+	// ErrReviewResolved 复核条目已有结论
+	ErrReviewResolved = review.ErrResolved
+	// ErrReviewUnsupported 复核队列未启用，或当前队列不支持该操作（如kafka队列查询待复核条目）
+	ErrReviewUnsupported = review.ErrUnsupported
+	// ErrInvalidReviewDecision 复核结论缺少条目ID或状态不是 approved、rejected
+	ErrInvalidReviewDecision = review.ErrInvalidDecision
+	// ErrOffenderDisabled 未启用用户违规统计
+	ErrOffenderDisabled = offender.ErrDisabled
 )
 
+// AuditSink 审计记录的输出，可实现该接口写入自有存储，通过 SetAuditSink、SetSamplingSink 设置
+type AuditSink = audit.Sink
+
+// Cache 检查结果缓存，可实现该接口接入 memcached、groupcache 等自有存储，通过 SetCache 设置
+// Get、Set 传入检查调用方的上下文，远程存储的读写应遵守其取消和截止时间
+// 需要并发安全；写入和读出时 Guardian 都会复制结果，实现可以直接保存和返回指针；Clear 在词库、策略等影响结果的配置变更后调用
+type Cache = cache.ContextCache
+
+// CacheUsage 缓存的容量使用情况
+type CacheUsage = cache.Usage
+
+// CacheUsageReporter 自定义缓存可选实现，提供统计中的条目数、容量、淘汰次数和内存估算
+type CacheUsageReporter = cache.UsageReporter
+
+// ReviewQueue 人工复核队列，可实现该接口接入自有复核系统，通过 SetReviewQueue 设置
+type ReviewQueue = review.Queue
+
+// Matcher 自定义匹配器（机器学习分类、外部接口、自定义规则等），通过 RegisterMatcher 注册
+// 在自动机之后运行，命中使用自己的分类和级别，与敏感词命中合并到结果中
+type Matcher = matcher.Matcher
+
+// MatcherFunc 将函数适配为 Matcher
+type MatcherFunc = matcher.Func
+
+// Classifier 文本分类模型，返回文本违规的概率，通过 SetClassifier 接入本地模型（如ONNX Runtime）
+type Classifier = classifier.Classifier
+
+// ClassifierFunc 将函数适配为 Classifier
+type ClassifierFunc = classifier.Func
+
+// FilterError 过滤没有正常完成，CheckStrict 等返回的是降级或兜底结果，Reason 为降级原因
+type FilterError = types.FilterError
+
+// WordDatabaseEdit 对词库源中最新词库的一次修改，通过 PublishWordDatabase 发布
+type WordDatabaseEdit = filter.WordDatabaseEdit
+
+// ConfigSource 词库配置源，按 DataId/Group 读取、监听和发布配置内容
+// 内置 Nacos、etcd、Apollo、Redis 和内存实现，也可自行实现后传给 NewGuardianWithSource
+type ConfigSource = source.ConfigSource
+
+// Logger 日志接口，Guardian 内部只依赖该接口，不要求应用使用特定的日志库
+// logrus、zap 使用 logruslog、zaplog 子包适配，字段作为结构化字段输出
+type Logger = logging.Logger
+
+// SlogLogger 适配标准库 *slog.Logger
+func SlogLogger(logger *slog.Logger) Logger {
+	return logging.Slog(logger)
+}
+
+// DiscardLogger 返回丢弃全部输出的日志
+func DiscardLogger() Logger {
+	return logging.Discard()
+}
+
 // Guardian 黄反校验SDK主入口
 type Guardian struct {
 	filter *filter.ContentFilter
-	logger *logrus.Logger
+	logger Logger
+	scenes map[string]types.SceneConfig // 场景检查选项
 }
 
-// NewGuardian 创建新的Guardian实例
+// NewGuardian 创建新的Guardian实例，使用默认日志（slog，info级别）
 func NewGuardian(config *types.Config) (*Guardian, error) {
-	// 初始化日志
-	logger := logrus.New()
-	logger.SetLevel(logrus.InfoLevel)
+	return NewGuardianWithLogger(config, nil)
+}
 
-	// 创建Nacos客户端
-	nacosClient, err := nacos.NewClient(&config.NacosConfig, logger)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create nacos client: %w", err)
-	}
+// NewGuardianWithLogger 使用自定义日志创建Guardian实例，logger为nil时使用默认日志
+// *slog.Logger 使用 SlogLogger 适配，logrus 和 zap 使用 logruslog.New、zaplog.New 适配
+func NewGuardianWithLogger(config *types.Config, logger Logger) (*Guardian, error) {
+	logger = logging.From(logger)
 
-	// 创建内容过滤器
-	contentFilter, err := filter.NewContentFilter(nacosClient, &config.FilterConfig, logger)
+	// 创建词库配置源
+	src, err := source.New(config, logger)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create content filter: %w", err)
+		return nil, err
 	}
 
-	return &Guardian{
-		filter: contentFilter,
-		logger: logger,
-	}, nil
+	return NewGuardianWithSource(config, src, logger)
 }
 
-// NewGuardianWithLogger 使用自定义日志创建Guardian实例
-func NewGuardianWithLogger(config *types.Config, logger *logrus.Logger) (*Guardian, error) {
-	// 创建Nacos客户端
-	nacosClient, err := nacos.NewClient(&config.NacosConfig, logger)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create nacos client: %w", err)
-	}
+// NewGuardianWithSource 使用指定的词库配置源创建Guardian实例，忽略config中的配置中心设置
+// 用于自定义配置源或在测试中使用 guardiantest 提供的假配置源；logger为nil时使用默认日志
+func NewGuardianWithSource(config *types.Config, src ConfigSource, logger Logger) (*Guardian, error) {
+	logger = logging.From(logger)
 
 	// 创建内容过滤器
-	contentFilter, err := filter.NewContentFilter(nacosClient, &config.FilterConfig, logger)
+	contentFilter, err := filter.NewContentFilter(src, &config.FilterConfig, logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create content filter: %w", err)
 	}
@@ -58,42 +151,122 @@ func NewGuardianWithLogger(config *types.Config, logger *logrus.Logger) (*Guardi
 	return &Guardian{
 		filter: contentFilter,
 		logger: logger,
+		scenes: config.FilterConfig.Scenes,
 	}, nil
 }
 
-// Check 检查文本内容
+// Check 使用默认选项检查文本内容
 func (g *Guardian) Check(text string) *types.FilterResult {
-	return g.CheckWithOptions(text, &types.FilterOptions{
-		EnableWhitelist: true,
-		Categories:      []string{},
-		MinLevel:        1,
-		ReplaceMode:     false,
-	})
+	return g.CheckWithOptions(text, nil)
+}
+
+// DefaultOptions 返回默认检查选项（启用白名单，检查全部分类和级别），可修改后传给 CheckWithOptions
+func DefaultOptions() *types.FilterOptions {
+	return types.DefaultFilterOptions()
 }
 
-// CheckWithOptions 带选项检查文本内容
+// CheckWithOptions 带选项检查文本内容，options为nil时使用默认选项，未设置的字段使用默认值
 func (g *Guardian) CheckWithOptions(text string, options *types.FilterOptions) *types.FilterResult {
 	return g.filter.Filter(text, options)
 }
 
+// CheckContext 带上下文检查文本内容
+// 上下文取消或超时时返回上下文错误；上下文中的追踪ID会贯穿过滤日志和广播事件；options为nil时使用默认选项
+func (g *Guardian) CheckContext(ctx context.Context, text string, options *types.FilterOptions) (*types.FilterResult, error) {
+	return g.filter.FilterContext(ctx, text, options)
+}
+
+// CheckE 检查文本内容，过滤没有正常完成时同时返回错误，见 CheckStrict
+func (g *Guardian) CheckE(text string) (*types.FilterResult, error) {
+	return g.CheckStrict(context.Background(), text, nil)
+}
+
+// CheckWithOptionsE 带选项检查文本内容，过滤没有正常完成时同时返回错误，见 CheckStrict
+func (g *Guardian) CheckWithOptionsE(text string, options *types.FilterOptions) (*types.FilterResult, error) {
+	return g.CheckStrict(context.Background(), text, options)
+}
+
+// CheckStrict 带上下文检查文本内容，用于区分"内容正常"和"过滤没有正常完成"
+// 结果为降级或兜底结果（词库为空、词库过期、过滤出错）时同时返回 *FilterError，可用 errors.Is 判断原因，如 ErrEmptyDictionary；
+// 文本超长被拒绝时同时返回 ErrTextTooLong；以上情况结果仍按异常处理策略给出，调用方可自行决定是否采用
+// 上下文取消或超时时只返回上下文错误；options为nil时使用默认选项
+func (g *Guardian) CheckStrict(ctx context.Context, text string, options *types.FilterOptions) (*types.FilterResult, error) {
+	return g.filter.FilterStrict(ctx, text, options)
+}
+
+// CheckReader 分块扫描任意大小的输入，跨越块边界的命中也能识别，每发现一次命中调用一次onMatch
+// 命中位置为在整个输入中的字节偏移；onMatch返回错误或上下文取消时停止扫描并返回该错误；options为nil时使用默认选项
+func (g *Guardian) CheckReader(ctx context.Context, r io.Reader, options *types.FilterOptions, onMatch func(types.StreamMatch) error) error {
+	return g.filter.FilterReader(ctx, r, options, onMatch)
+}
+
+// CheckJSON 检查JSON文档中选择器命中的字符串字段，返回每个字段的结果
+// 选择器为JSONPath风格，如 "$.title"、"$.comments[*].text"、"$.author['display name']"；options为nil时使用默认选项
+func (g *Guardian) CheckJSON(ctx context.Context, data []byte, selectors []string, options *types.FilterOptions) ([]types.FieldResult, error) {
+	return g.filter.FilterJSON(ctx, data, selectors, options)
+}
+
+// CheckScene 使用配置中的场景选项检查文本内容，场景不存在时返回错误
+func (g *Guardian) CheckScene(scene, text string) (*types.FilterResult, error) {
+	return g.CheckSceneContext(context.Background(), scene, text)
+}
+
+// CheckSceneContext 带上下文使用场景选项检查文本内容
+func (g *Guardian) CheckSceneContext(ctx context.Context, scene, text string) (*types.FilterResult, error) {
+	options, err := g.SceneOptions(scene)
+	if err != nil {
+		return nil, err
+	}
+	return g.filter.FilterContext(ctx, text, options)
+}
+
+// SceneOptions 返回场景对应的检查选项
+func (g *Guardian) SceneOptions(scene string) (*types.FilterOptions, error) {
+	config, ok := g.scenes[scene]
+	if !ok {
+		return nil, fmt.Errorf("unknown scene: %s", scene)
+	}
+	return config.Options(), nil
+}
+
+// WithTraceID 将追踪/请求ID写入上下文
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return trace.WithTraceID(ctx, traceID)
+}
+
+// WithCaller 将租户和调用方ID写入上下文，写入审计记录
+func WithCaller(ctx context.Context, tenant, callerID string) context.Context {
+	return trace.WithCaller(ctx, tenant, callerID)
+}
+
+// WithMetadata 将调用方附加的信息（如内容ID、用户ID）写入上下文，随复核条目保存，便于复核后回查内容
+func WithMetadata(ctx context.Context, metadata map[string]string) context.Context {
+	return trace.WithMetadata(ctx, metadata)
+}
+
+// TraceIDFromContext 从上下文读取追踪/请求ID
+func TraceIDFromContext(ctx context.Context) string {
+	return trace.TraceID(ctx)
+}
+
+// VerifyViolationSignature 供违规回调的接收方校验 X-Guardian-Signature，timestamp为 X-Guardian-Timestamp，body为原始请求体
+// 调用方还应拒绝时间戳过旧的请求以防重放
+func VerifyViolationSignature(secret, timestamp string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(webhook.Sign(secret, timestamp, body)), []byte(signature))
+}
+
 // CheckCategory 检查特定分类的敏感词
 func (g *Guardian) CheckCategory(text string, categories []string) *types.FilterResult {
-	return g.CheckWithOptions(text, &types.FilterOptions{
-		EnableWhitelist: true,
-		Categories:      categories,
-		MinLevel:        1,
-		ReplaceMode:     false,
-	})
+	options := DefaultOptions()
+	options.Categories = categories
+	return g.CheckWithOptions(text, options)
 }
 
 // CheckLevel 检查特定级别的敏感词
 func (g *Guardian) CheckLevel(text string, minLevel int) *types.FilterResult {
-	return g.CheckWithOptions(text, &types.FilterOptions{
-		EnableWhitelist: true,
-		Categories:      []string{},
-		MinLevel:        minLevel,
-		ReplaceMode:     false,
-	})
+	options := DefaultOptions()
+	options.MinLevel = minLevel
+	return g.CheckWithOptions(text, options)
 }
 
 // IsSafe 检查文本是否安全
@@ -115,7 +288,7 @@ func (g *Guardian) GetMatchedCategories(text string) []string {
 }
 
 // GetStats 获取统计信息
-func (g *Guardian) GetStats() map[string]interface{} {
+func (g *Guardian) GetStats() *types.Stats {
 	return g.filter.GetStats()
 }
 
@@ -129,45 +302,222 @@ func (g *Guardian) Close() error {
 	return g.filter.Close()
 }
 
-// BatchCheck 批量检查
+// BatchCheck 批量检查，结果与输入顺序一致
 func (g *Guardian) BatchCheck(texts []string) []*types.FilterResult {
-	results := make([]*types.FilterResult, len(texts))
-	for i, text := range texts {
-		results[i] = g.Check(text)
-	}
-	return results
+	return g.BatchCheckWithOptions(texts, nil)
 }
 
-// BatchCheckWithOptions 带选项批量检查
+// BatchCheckWithOptions 带选项批量检查，结果与输入顺序一致
 func (g *Guardian) BatchCheckWithOptions(texts []string, options *types.FilterOptions) []*types.FilterResult {
-	results := make([]*types.FilterResult, len(texts))
-	for i, text := range texts {
-		results[i] = g.CheckWithOptions(text, options)
-	}
+	// 不可取消的上下文不会返回错误
+	results, _ := g.filter.FilterBatch(context.Background(), texts, options)
 	return results
 }
 
+// BatchCheckContext 带上下文批量检查，按 batch_workers 并发检查，结果与输入顺序一致
+// 上下文取消或超时时不再开始新的检查，返回上下文错误；options为nil时使用默认选项
+func (g *Guardian) BatchCheckContext(ctx context.Context, texts []string, options *types.FilterOptions) ([]*types.FilterResult, error) {
+	return g.filter.FilterBatch(ctx, texts, options)
+}
+
+// BatchCheckItems 批量检查带ID的文本，返回的每条结果附带请求中的ID，并汇总通过、未通过和各分类的条数
+// 调用方可按ID对应到数据库记录，无需依赖顺序；其余行为同 BatchCheckContext
+func (g *Guardian) BatchCheckItems(ctx context.Context, items []types.BatchItem, options *types.FilterOptions) (*types.BatchResponse, error) {
+	texts := make([]string, len(items))
+	for i, item := range items {
+		texts[i] = item.Text
+	}
+	results, err := g.filter.FilterBatch(ctx, texts, options)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewBatchResponse(items, results), nil
+}
+
 // UpdateWordDatabase 更新词库
 func (g *Guardian) UpdateWordDatabase(wordDB *types.WordDatabase) error {
 	return g.filter.UpdateWordDatabase(wordDB)
 }
 
-// AddToWhitelist 添加到白名单
-func (g *Guardian) AddToWhitelist(word string) {
-	g.filter.AddToWhitelist(word)
+// BroadcastReload 重新加载词库并通知集群内其他实例同步重载
+func (g *Guardian) BroadcastReload(ctx context.Context, reason string) error {
+	return g.filter.BroadcastReload(ctx, reason)
+}
+
+// ReloadDiffs 返回最近的词库重载差异（新增、移除、级别变化的敏感词和白名单变化），最新的在前
+func (g *Guardian) ReloadDiffs() []*types.ReloadDiff {
+	return g.filter.ReloadDiffs()
+}
+
+// DryRun 使用候选词库检查样本文本，返回相对当前词库的变化和结果不同的样本，发布到配置中心前预览影响
+// 不影响正在服务的词库、缓存和统计；options为nil时使用默认选项
+func (g *Guardian) DryRun(ctx context.Context, candidate *types.WordDatabase, texts []string, options *types.FilterOptions) (*types.DryRunResult, error) {
+	return g.filter.DryRun(ctx, candidate, texts, options)
+}
+
+// SetAuditSink 使用自定义输出记录审计日志，替换 filter_config.audit 中的输出，筛选和抽样仍按该配置
+// 未启用 audit 时同样生效；原输出写完已缓冲的记录后关闭，sink为nil时停止记录
+func (g *Guardian) SetAuditSink(sink AuditSink) {
+	g.filter.SetAuditSink(sink)
+}
+
+// SetSamplingSink 使用自定义输出（如复核队列）接收抽样复核的结果，替换 filter_config.sampling 中的输出
+// 抽样比例仍按该配置，未配置比例时不会抽中任何结果；原输出写完已缓冲的记录后关闭，sink为nil时停止抽样
+func (g *Guardian) SetSamplingSink(sink AuditSink) {
+	g.filter.SetSamplingSink(sink)
+}
+
+// SetCache 使用自定义的检查结果缓存，替换 enable_cache 创建的内置缓存，未启用 enable_cache 时同样生效
+// 原缓存被关闭，c为nil时停用缓存；统计信息中的 cache 为 c.Stats() 的返回值
+func (g *Guardian) SetCache(c Cache) {
+	g.filter.SetCache(c)
+}
+
+// SetReviewQueue 使用自定义复核队列，替换 filter_config.review 中的队列，未启用 review 时同样生效
+// 原队列入队已缓冲的条目后关闭，queue为nil时停止入队
+func (g *Guardian) SetReviewQueue(queue ReviewQueue) {
+	g.filter.SetReviewQueue(queue)
+}
+
+// PendingReviews 按入队顺序返回最多limit个待复核条目，limit不大于0时返回全部
+func (g *Guardian) PendingReviews(ctx context.Context, limit int) ([]*types.ReviewItem, error) {
+	return g.filter.PendingReviews(ctx, limit)
+}
+
+// ReviewItem 返回复核条目，可用于查询复核结论；不存在时返回 ErrReviewNotFound
+func (g *Guardian) ReviewItem(ctx context.Context, id string) (*types.ReviewItem, error) {
+	return g.filter.ReviewItem(ctx, id)
+}
+
+// ResolveReview 记录复核结论（approved或rejected），已有结论时返回 ErrReviewResolved
+func (g *Guardian) ResolveReview(ctx context.Context, decision *types.ReviewDecision) error {
+	return g.filter.ResolveReview(ctx, decision)
+}
+
+// OffenderStatus 返回用户在 filter_config.offender.window 窗口内的违规次数，未启用时返回 ErrOffenderDisabled
+func (g *Guardian) OffenderStatus(ctx context.Context, userID string) (*types.OffenderStatus, error) {
+	return g.filter.OffenderStatus(ctx, userID)
+}
+
+// ResetOffender 清空用户的违规记录，如申诉通过后
+func (g *Guardian) ResetOffender(ctx context.Context, userID string) error {
+	return g.filter.ResetOffender(ctx, userID)
+}
+
+// OnReloadError 注册词库重载告警的回调，连续重载失败达到 filter_config.reload_alert.failure_threshold 次
+// 或词库超过 stale_after 未成功刷新时调用，问题消除后以 resolved 级别再次调用；回调同步执行，不应长时间阻塞
+func (g *Guardian) OnReloadError(handler func(alert *types.Alert)) {
+	g.filter.OnReloadError(handler)
+}
+
+// RegisterMatcher 注册自定义匹配器，对标准化后的文本运行，命中同样受分类开关、白名单和检查选项的影响
+// 匹配器在每次检查中同步调用，需要并发安全；注册后清空结果缓存
+func (g *Guardian) RegisterMatcher(m Matcher) {
+	g.filter.RegisterMatcher(m)
+}
+
+// SetClassifier 使用自定义分类模型，替换 filter_config.classifier 中的远程推理接口，未启用 classifier 时同样生效
+// 违规概率按 classifier 配置和场景选项的权重、阈值计入风险分；c为nil时停止调用分类模型
+func (g *Guardian) SetClassifier(c Classifier) {
+	g.filter.SetClassifier(c)
+}
+
+// OnMatch 注册命中敏感词时的回调，可用于记录用户违规、发送通知等，无需包装每个调用点
+// 回调在检查返回前同步调用，不应长时间阻塞；result 即返回给调用方的结果，不应修改
+func (g *Guardian) OnMatch(hook func(ctx context.Context, text string, result *types.FilterResult)) {
+	g.filter.OnMatch(hook)
+}
+
+// OnReload 注册词库替换后的回调，参数为替换前后的统计信息，可用于清理应用自己的缓存、发送通知等
+// 重载、灰度全量、定时生效和运行时修改敏感词都会触发，回调在替换词库的协程中同步调用
+func (g *Guardian) OnReload(hook func(old, new *types.Stats)) {
+	g.filter.OnReload(hook)
+}
+
+// WordHits 返回当前词库生效以来命中最多和从未命中的敏感词，词库重载后重新计数
+// limit限制两个列表的长度，不大于0时返回全部
+func (g *Guardian) WordHits(limit int) *types.WordHitReport {
+	return g.filter.WordHits(limit)
+}
+
+// LintReport 返回最近一次词库检查（空词、非法级别、重复等）的结果，未检查过时返回nil
+func (g *Guardian) LintReport() *types.LintReport {
+	return g.filter.LintReport()
+}
+
+// CanaryStatus 返回新词库的灰度状态（两组的请求数和拦截率），没有灰度中的词库时返回nil
+func (g *Guardian) CanaryStatus() *types.CanaryStatus {
+	return g.filter.CanaryStatus()
+}
+
+// PromoteCanary 立即全量生效灰度中的词库，没有时返回 ErrNoCanary
+func (g *Guardian) PromoteCanary() error {
+	return g.filter.PromoteCanary()
+}
+
+// AbortCanary 放弃灰度中的词库，没有时返回 ErrNoCanary
+func (g *Guardian) AbortCanary() error {
+	return g.filter.AbortCanary()
+}
+
+// AddToWhitelist 添加到白名单，开启 persist_whitelist 时同时写回词库源
+func (g *Guardian) AddToWhitelist(word string) error {
+	return g.filter.AddToWhitelist(word)
+}
+
+// RemoveFromWhitelist 从白名单移除，开启 persist_whitelist 时同时写回词库源
+func (g *Guardian) RemoveFromWhitelist(word string) error {
+	return g.filter.RemoveFromWhitelist(word)
+}
+
+// AddSensitiveWord 添加敏感词，已存在的同名敏感词被替换；修改在防抖时间后合并重建，开启 persist_words 时同时写回词库源
+func (g *Guardian) AddSensitiveWord(word types.SensitiveWord) error {
+	return g.filter.AddSensitiveWord(word)
+}
+
+// RemoveSensitiveWord 从黑名单和所有分类中移除敏感词，不存在时返回的错误满足 errors.Is(err, guardian.ErrWordNotFound)
+func (g *Guardian) RemoveSensitiveWord(word string) error {
+	return g.filter.RemoveSensitiveWord(word)
+}
+
+// ListWords 返回当前生效的全部敏感词
+func (g *Guardian) ListWords() []types.SensitiveWord {
+	return g.filter.ListWords()
+}
+
+// PublishWordDatabase 读取词库源中的最新词库，应用修改、递增版本并签名后发布，返回发布的词库
+// 与 persist_words 的写回流程相同：支持条件发布的配置源只在内容未被并发修改时写入，重试后仍冲突返回 ErrConcurrentUpdate；
+// 词库源不是JSON格式时返回 ErrPersistFormat。key 为nil时只写入校验和
+func (g *Guardian) PublishWordDatabase(edit WordDatabaseEdit, key ed25519.PrivateKey) (*types.WordDatabase, error) {
+	return g.filter.PublishWordDatabase(edit, key)
+}
+
+// InsertWord 新增敏感词的修改，同名敏感词（按标准化后的形式比较）已存在时返回 ErrWordExists
+func (g *Guardian) InsertWord(word types.SensitiveWord) WordDatabaseEdit {
+	return g.filter.InsertWord(word)
+}
+
+// ReplaceWord 替换敏感词的修改，不存在时返回 ErrWordNotFound
+func (g *Guardian) ReplaceWord(word types.SensitiveWord) WordDatabaseEdit {
+	return g.filter.ReplaceWord(word)
+}
+
+// DeleteWord 从黑名单和所有分类中删除敏感词的修改，不存在时返回 ErrWordNotFound
+func (g *Guardian) DeleteWord(word string) WordDatabaseEdit {
+	return g.filter.DeleteWord(word)
 }
 
-// RemoveFromWhitelist 从白名单移除
-func (g *Guardian) RemoveFromWhitelist(word string) {
-	g.filter.RemoveFromWhitelist(word)
+// MergeWords 合并敏感词的修改，已存在的同名敏感词被替换，其余加入黑名单
+func (g *Guardian) MergeWords(words []types.SensitiveWord) WordDatabaseEdit {
+	return g.filter.MergeWords(words)
 }
 
 // SetLogger 设置日志器
-func (g *Guardian) SetLogger(logger *logrus.Logger) {
+func (g *Guardian) SetLogger(logger Logger) {
 	g.logger = logger
 }
 
 // GetLogger 获取日志器
-func (g *Guardian) GetLogger() *logrus.Logger {
+func (g *Guardian) GetLogger() Logger {
 	return g.logger
 }
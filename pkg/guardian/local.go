@@ -0,0 +1,90 @@
+package guardian
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/guardian/content-filter/internal/filter"
+	"github.com/guardian/content-filter/internal/source"
+	"github.com/guardian/content-filter/internal/types"
+	"github.com/guardian/content-filter/internal/wordlist"
+)
+
+// 本地模式使用的词库配置ID和配置组
+const (
+	localDataId = "sensitive_words"
+	localGroup  = "LOCAL"
+)
+
+// LocalConfig 返回本地模式的默认配置：词库保存在进程内存中，不连接配置中心
+// 可在此基础上修改过滤器选项后传给 NewGuardianFromConfig
+func LocalConfig() *types.Config {
+	return &types.Config{
+		Source: types.SourceMemory,
+		FilterConfig: types.FilterConfig{
+			DataId:          localDataId,
+			Group:           localGroup,
+			EnableCache:     true,
+			CacheSize:       10000,
+			EnableWhitelist: true,
+		},
+	}
+}
+
+// NewGuardianFromWordDatabase 使用给定词库创建Guardian实例，不需要任何配置中心
+// 之后可通过 UpdateWordDatabase 替换词库
+func NewGuardianFromWordDatabase(wordDB *types.WordDatabase) (*Guardian, error) {
+	return NewGuardianFromConfig(LocalConfig(), wordDB)
+}
+
+// NewGuardianFromFile 从本地词库文件创建Guardian实例，格式（json|yaml|text|csv等）按文件名和内容识别
+func NewGuardianFromFile(path string) (*Guardian, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read word file: %w", err)
+	}
+
+	wordDB, err := wordlist.Parse(bytes.NewReader(data), wordlist.DetectFormat(path, data), nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to parse word file %s: %w", types.ErrInvalidWordDB, path, err)
+	}
+	return NewGuardianFromWordDatabase(wordDB)
+}
+
+// NewGuardianWithDefaults 使用内置的入门词库创建Guardian实例，适合快速试用
+func NewGuardianWithDefaults() (*Guardian, error) {
+	wordDB, err := DefaultWordDatabase()
+	if err != nil {
+		return nil, err
+	}
+	return NewGuardianFromWordDatabase(wordDB)
+}
+
+// DefaultWordDatabase 返回内置入门词库的副本，可在其基础上增删后传给 NewGuardianFromWordDatabase
+func DefaultWordDatabase() (*types.WordDatabase, error) {
+	return filter.EmbeddedWordDatabase()
+}
+
+// NewGuardianFromConfig 使用本地模式配置和给定词库创建Guardian实例
+// config.Source 必须为 memory，词库写入 filter_config 的 data_id/group
+func NewGuardianFromConfig(config *types.Config, wordDB *types.WordDatabase) (*Guardian, error) {
+	if config.Source != types.SourceMemory {
+		return nil, fmt.Errorf("local mode requires source %q, got %q", types.SourceMemory, config.Source)
+	}
+	if wordDB == nil {
+		return nil, fmt.Errorf("%w: word database is nil", types.ErrEmptyDictionary)
+	}
+
+	content, err := json.Marshal(wordDB)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal word database: %w", err)
+	}
+	src := source.NewMemory()
+	if err := src.PublishConfig(config.FilterConfig.DataId, config.FilterConfig.Group, string(content)); err != nil {
+		return nil, err
+	}
+
+	return NewGuardianWithSource(config, src, nil)
+}
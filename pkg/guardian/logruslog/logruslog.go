@@ -0,0 +1,47 @@
+// Package logruslog 将 logrus 日志适配为 guardian.Logger，追踪ID等字段作为 logrus 的结构化字段输出
+//
+//	g, err := guardian.NewGuardianWithLogger(config, logruslog.New(logrus.StandardLogger()))
+//
+// 适配器放在单独的包中，不使用 logrus 的应用不会引入该依赖
+package logruslog
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"github.com/guardian/content-filter/internal/logging"
+)
+
+// New 适配 logrus 的 *Logger 或 *Entry
+func New(logger logrus.FieldLogger) logging.Logger {
+	return &adapter{logger: logger}
+}
+
+// adapter logrus适配器
+type adapter struct {
+	logger logrus.FieldLogger
+}
+
+// WithField 附加字段
+func (l *adapter) WithField(key string, value interface{}) logging.Logger {
+	return &adapter{logger: l.logger.WithField(key, value)}
+}
+
+// Debugf 输出调试日志
+func (l *adapter) Debugf(format string, args ...interface{}) {
+	l.logger.Debugf(format, args...)
+}
+
+// Infof 输出信息日志
+func (l *adapter) Infof(format string, args ...interface{}) {
+	l.logger.Infof(format, args...)
+}
+
+// Warnf 输出警告日志
+func (l *adapter) Warnf(format string, args ...interface{}) {
+	l.logger.Warnf(format, args...)
+}
+
+// Errorf 输出错误日志
+func (l *adapter) Errorf(format string, args ...interface{}) {
+	l.logger.Errorf(format, args...)
+}
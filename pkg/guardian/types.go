@@ -0,0 +1,47 @@
+package guardian
+
+import "github.com/guardian/content-filter/internal/types"
+
+// 扩展接口（Cache、Matcher 等）方法签名中使用的类型，外部包通过这些别名实现接口
+
+// FilterResult 检查结果
+type FilterResult = types.FilterResult
+
+// MatchDetail 敏感词命中详情
+type MatchDetail = types.MatchDetail
+
+// Position 命中在原文中的字节区间 [Start, End)
+type Position = types.Position
+
+// OffenderStatus 用户在统计窗口内的违规次数
+type OffenderStatus = types.OffenderStatus
+
+// Action 检查结果的处理动作
+type Action = types.Action
+
+// 处理动作
+const (
+	ActionPass   = types.ActionPass   // 放行
+	ActionMask   = types.ActionMask   // 打码后放行
+	ActionReview = types.ActionReview // 转人工审核
+	ActionReject = types.ActionReject // 拒绝
+)
+
+// ReviewItem 人工复核条目
+type ReviewItem = types.ReviewItem
+
+// ReviewDecision 复核结论
+type ReviewDecision = types.ReviewDecision
+
+// ReviewStatus 复核状态
+type ReviewStatus = types.ReviewStatus
+
+// 复核状态
+const (
+	ReviewPending  = types.ReviewPending  // 待复核
+	ReviewApproved = types.ReviewApproved // 复核通过
+	ReviewRejected = types.ReviewRejected // 复核驳回
+)
+
+// AuditRecord 一条审核决定的审计记录
+type AuditRecord = types.AuditRecord
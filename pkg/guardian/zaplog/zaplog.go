@@ -0,0 +1,52 @@
+// Package zaplog 将 zap 日志适配为 guardian.Logger，追踪ID等字段作为 zap 的结构化字段输出
+//
+//	g, err := guardian.NewGuardianWithLogger(config, zaplog.New(zapLogger))
+//
+// 适配器放在单独的包中，不使用 zap 的应用不会引入该依赖
+package zaplog
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/guardian/content-filter/internal/logging"
+)
+
+// New 适配 *zap.Logger，日志的调用位置为适配器的调用方
+func New(logger *zap.Logger) logging.Logger {
+	return &adapter{sugar: logger.WithOptions(zap.AddCallerSkip(1)).Sugar()}
+}
+
+// Sugared 适配 *zap.SugaredLogger
+func Sugared(logger *zap.SugaredLogger) logging.Logger {
+	return New(logger.Desugar())
+}
+
+// adapter zap适配器
+type adapter struct {
+	sugar *zap.SugaredLogger
+}
+
+// WithField 附加字段
+func (l *adapter) WithField(key string, value interface{}) logging.Logger {
+	return &adapter{sugar: l.sugar.With(key, value)}
+}
+
+// Debugf 输出调试日志
+func (l *adapter) Debugf(format string, args ...interface{}) {
+	l.sugar.Debugf(format, args...)
+}
+
+// Infof 输出信息日志
+func (l *adapter) Infof(format string, args ...interface{}) {
+	l.sugar.Infof(format, args...)
+}
+
+// Warnf 输出警告日志
+func (l *adapter) Warnf(format string, args ...interface{}) {
+	l.sugar.Warnf(format, args...)
+}
+
+// Errorf 输出错误日志
+func (l *adapter) Errorf(format string, args ...interface{}) {
+	l.sugar.Errorf(format, args...)
+}
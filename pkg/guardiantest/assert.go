@@ -0,0 +1,102 @@
+package guardiantest
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// AssertPassed 断言结果通过且没有命中
+func AssertPassed(t testing.TB, result *types.FilterResult) {
+	t.Helper()
+	if result == nil {
+		t.Fatalf("expected passed result, got nil")
+	}
+	if !result.Passed || len(result.Words) > 0 {
+		t.Errorf("expected passed result, got passed=%v words=%v", result.Passed, result.Words)
+	}
+}
+
+// AssertBlocked 断言结果未通过，且命中的敏感词恰好为words（不计顺序）；words为空时只断言未通过
+func AssertBlocked(t testing.TB, result *types.FilterResult, words ...string) {
+	t.Helper()
+	if result == nil {
+		t.Fatalf("expected blocked result, got nil")
+	}
+	if result.Passed {
+		t.Errorf("expected blocked result, got passed")
+	}
+	if len(words) > 0 && !sameSet(result.Words, words) {
+		t.Errorf("expected matched words %v, got %v", words, result.Words)
+	}
+}
+
+// AssertMatched 断言结果命中了words中的每个敏感词，不要求未通过（如只观察或放行的分类）
+func AssertMatched(t testing.TB, result *types.FilterResult, words ...string) {
+	t.Helper()
+	if result == nil {
+		t.Fatalf("expected matched result, got nil")
+	}
+	for _, word := range words {
+		if !contains(result.Words, word) {
+			t.Errorf("expected %q to be matched, got %v", word, result.Words)
+		}
+	}
+}
+
+// AssertCategories 断言命中的分类恰好为categories（不计顺序）
+func AssertCategories(t testing.TB, result *types.FilterResult, categories ...string) {
+	t.Helper()
+	if result == nil {
+		t.Fatalf("expected result, got nil")
+	}
+	if !sameSet(result.Categories, categories) {
+		t.Errorf("expected categories %v, got %v", categories, result.Categories)
+	}
+}
+
+// AssertAction 断言处理动作
+func AssertAction(t testing.TB, result *types.FilterResult, action types.Action) {
+	t.Helper()
+	if result == nil {
+		t.Fatalf("expected result, got nil")
+	}
+	if result.Action != action {
+		t.Errorf("expected action %s, got %s", action, result.Action)
+	}
+}
+
+// AssertFilteredText 断言替换模式下打码后的文本
+func AssertFilteredText(t testing.TB, result *types.FilterResult, expected string) {
+	t.Helper()
+	if result == nil {
+		t.Fatalf("expected result, got nil")
+	}
+	if result.FilteredText != expected {
+		t.Errorf("expected filtered text %q, got %q", expected, result.FilteredText)
+	}
+}
+
+// sameSet 两个字符串列表是否包含相同的元素
+func sameSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = append([]string(nil), a...)
+	b = append([]string(nil), b...)
+	sort.Strings(a)
+	sort.Strings(b)
+	return strings.Join(a, "\x00") == strings.Join(b, "\x00")
+}
+
+// contains 列表中是否包含s
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
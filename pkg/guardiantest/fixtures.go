@@ -0,0 +1,53 @@
+package guardiantest
+
+import (
+	"time"
+
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// 预置词库中的词语，用于编写断言
+const (
+	AbuseWord    = "测试辱骂词"   // 分类abuse，级别4
+	PoliticsWord = "测试政治敏感词" // 分类politics，级别5
+	AdsWord      = "测试广告词"   // 分类ads，级别2
+	WhitelistHit = "测试广告词说明" // 白名单短语，包含 AdsWord
+)
+
+// SampleWordDatabase 返回预置词库：abuse、politics、ads 三个分类各一个词，以及一条白名单短语
+// 每次调用返回新的副本，可随意修改
+func SampleWordDatabase() *types.WordDatabase {
+	return &types.WordDatabase{
+		Version:    "test-1",
+		UpdateTime: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Whitelist:  []string{WhitelistHit},
+		Blacklist: []types.SensitiveWord{
+			{Word: AbuseWord, Categories: []string{"abuse"}, Level: 4},
+			{Word: PoliticsWord, Categories: []string{"politics"}, Level: 5},
+			{Word: AdsWord, Categories: []string{"ads"}, Level: 2},
+		},
+		Categories:   map[string][]types.SensitiveWord{},
+		Replacements: map[string]string{},
+	}
+}
+
+// EmptyWordDatabase 返回不含任何敏感词的词库
+func EmptyWordDatabase() *types.WordDatabase {
+	return &types.WordDatabase{
+		Version:      "test-empty",
+		Whitelist:    []string{},
+		Blacklist:    []types.SensitiveWord{},
+		Categories:   map[string][]types.SensitiveWord{},
+		Replacements: map[string]string{},
+	}
+}
+
+// WordDatabase 用给定词语快速构造词库，分类为default、级别为3
+func WordDatabase(words ...string) *types.WordDatabase {
+	wordDB := EmptyWordDatabase()
+	wordDB.Version = "test-words"
+	for _, word := range words {
+		wordDB.Blacklist = append(wordDB.Blacklist, types.SensitiveWord{Word: word, Categories: []string{"default"}, Level: 3})
+	}
+	return wordDB
+}
@@ -0,0 +1,61 @@
+// Package guardiantest 提供测试工具：内存词库源、假Nacos客户端、预置词库和 FilterResult 断言，
+// 使业务代码不依赖真实配置中心即可对审核逻辑做单元测试
+//
+//	func TestComment(t *testing.T) {
+//		g := guardiantest.New(t, guardiantest.WordDatabase("违禁词"))
+//		guardiantest.AssertBlocked(t, g.Check("含有违禁词的评论"), "违禁词")
+//	}
+package guardiantest
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/guardian/content-filter/internal/types"
+	"github.com/guardian/content-filter/pkg/guardian"
+)
+
+// 测试配置使用的词库配置ID和配置组
+const (
+	DataId = "sensitive_words"
+	Group  = "TEST_GROUP"
+)
+
+// Config 返回测试用配置：关闭缓存避免用例之间互相影响，词库读取 DataId/Group
+func Config() *types.Config {
+	return &types.Config{
+		Source: types.SourceMemory,
+		FilterConfig: types.FilterConfig{
+			DataId:          DataId,
+			Group:           Group,
+			EnableWhitelist: true,
+		},
+	}
+}
+
+// New 使用给定词库创建Guardian实例，测试结束时自动关闭；创建失败时终止测试
+func New(t testing.TB, wordDB *types.WordDatabase) *guardian.Guardian {
+	t.Helper()
+
+	src := NewWordSource()
+	if err := src.SetWordDatabase(DataId, Group, wordDB); err != nil {
+		t.Fatalf("guardiantest: %v", err)
+	}
+	return NewWithSource(t, Config(), src)
+}
+
+// NewWithSource 使用给定配置和配置源创建Guardian实例，测试结束时自动关闭；创建失败时终止测试
+// 日志只输出警告及以上级别
+func NewWithSource(t testing.TB, config *types.Config, src guardian.ConfigSource) *guardian.Guardian {
+	t.Helper()
+
+	logger := guardian.SlogLogger(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn})))
+
+	g, err := guardian.NewGuardianWithSource(config, src, logger)
+	if err != nil {
+		t.Fatalf("guardiantest: failed to create guardian: %v", err)
+	}
+	t.Cleanup(func() { g.Close() })
+	return g
+}
@@ -0,0 +1,152 @@
+package guardiantest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/guardian/content-filter/internal/source"
+	"github.com/guardian/content-filter/internal/types"
+)
+
+// ErrUnavailable FakeNacos 模拟配置中心不可用时返回的错误
+var ErrUnavailable = errors.New("guardiantest: config center unavailable")
+
+// WordSource 内存词库源，实现 guardian.ConfigSource
+// SetWordDatabase 写入的词库会异步推送给监听者，与配置中心的行为一致
+type WordSource struct {
+	*source.Memory
+}
+
+// NewWordSource 创建空的内存词库源
+func NewWordSource() *WordSource {
+	return &WordSource{Memory: source.NewMemory()}
+}
+
+// SetWordDatabase 发布词库，正在运行的Guardian实例会收到变更通知并重载
+func (s *WordSource) SetWordDatabase(dataId, group string, wordDB *types.WordDatabase) error {
+	content, err := json.Marshal(wordDB)
+	if err != nil {
+		return fmt.Errorf("failed to marshal word database: %w", err)
+	}
+	return s.PublishConfig(dataId, group, string(content))
+}
+
+// FakeNacos 假Nacos客户端，实现 guardian.ConfigSource
+// 在内存词库源的基础上可模拟配置中心故障，并记录每次调用，用于测试降级、重试和写回逻辑
+type FakeNacos struct {
+	*WordSource
+
+	mu         sync.Mutex
+	getErr     error
+	publishErr error
+	gets       int
+	published  []Published
+}
+
+// Published 一次发布的配置
+type Published struct {
+	DataId  string
+	Group   string
+	Content string
+}
+
+// NewFakeNacos 创建假Nacos客户端
+func NewFakeNacos() *FakeNacos {
+	return &FakeNacos{WordSource: NewWordSource()}
+}
+
+// SetUnavailable 设置配置中心是否不可用，不可用时读取、发布和健康检查都返回 ErrUnavailable
+// 与真实客户端一致，读取错误同时满足 errors.Is(err, guardian.ErrWordSourceUnavailable)
+func (n *FakeNacos) SetUnavailable(unavailable bool) {
+	var err error
+	if unavailable {
+		err = ErrUnavailable
+	}
+	n.FailGet(err)
+	n.FailPublish(err)
+}
+
+// FailGet 设置读取配置时返回的错误，为nil时恢复正常
+func (n *FakeNacos) FailGet(err error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.getErr = err
+}
+
+// FailPublish 设置发布配置时返回的错误，为nil时恢复正常
+func (n *FakeNacos) FailPublish(err error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.publishErr = err
+}
+
+// GetConfig 获取配置
+func (n *FakeNacos) GetConfig(dataId, group string) (string, error) {
+	n.mu.Lock()
+	n.gets++
+	err := n.getErr
+	n.mu.Unlock()
+
+	if err != nil {
+		return "", fmt.Errorf("%w: failed to get config from nacos: %w", types.ErrWordSourceUnavailable, err)
+	}
+	return n.WordSource.GetConfig(dataId, group)
+}
+
+// PublishConfig 发布配置并记录
+func (n *FakeNacos) PublishConfig(dataId, group, content string) error {
+	n.mu.Lock()
+	err := n.publishErr
+	if err == nil {
+		n.published = append(n.published, Published{DataId: dataId, Group: group, Content: content})
+	}
+	n.mu.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("failed to publish config: %w", err)
+	}
+	return n.WordSource.PublishConfig(dataId, group, content)
+}
+
+// PublishConfigIf 配置内容仍为expected时发布并记录，写回词库时使用；与 PublishConfig 一样受 FailPublish 影响
+func (n *FakeNacos) PublishConfigIf(dataId, group, content, expected string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.publishErr != nil {
+		return fmt.Errorf("failed to publish config: %w", n.publishErr)
+	}
+	if err := n.WordSource.PublishConfigIf(dataId, group, content, expected); err != nil {
+		return err
+	}
+	n.published = append(n.published, Published{DataId: dataId, Group: group, Content: content})
+	return nil
+}
+
+// SetWordDatabase 发布词库，不受 FailPublish 影响，也不计入 Published
+func (n *FakeNacos) SetWordDatabase(dataId, group string, wordDB *types.WordDatabase) error {
+	return n.WordSource.SetWordDatabase(dataId, group, wordDB)
+}
+
+// HealthCheck 健康检查，不可用时返回 ErrUnavailable
+func (n *FakeNacos) HealthCheck() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.getErr
+}
+
+// Gets 返回 GetConfig 的调用次数
+func (n *FakeNacos) Gets() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.gets
+}
+
+// Published 返回通过 PublishConfig 成功发布的配置，按发布顺序
+func (n *FakeNacos) Published() []Published {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]Published(nil), n.published...)
+}
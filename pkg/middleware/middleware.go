@@ -0,0 +1,257 @@
+// Package middleware 为 net/http 路由提供内容检查中间件，检查请求中选定的字段，按拒绝策略拦截违规请求
+//
+// 中间件的签名为 func(http.Handler) http.Handler，可直接用于 net/http 和 chi，echo 通过 echo.WrapMiddleware 使用：
+//
+//	mw := middleware.New(g, &middleware.Config{JSONFields: []string{"$.title", "$.comments[*].text"}})
+//	http.Handle("/posts", mw(postsHandler))      // net/http
+//	r.Use(mw)                                    // chi
+//	e.Use(echo.WrapMiddleware(mw))               // echo
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/guardian/content-filter/internal/types"
+	"github.com/guardian/content-filter/pkg/guardian"
+)
+
+const (
+	// defaultMaxBodyBytes 读取请求体的默认上限
+	defaultMaxBodyBytes = 1 << 20
+	// maxFormMemory 解析 multipart 表单时保存在内存中的上限，超过的部分写入临时文件
+	maxFormMemory = 8 << 20
+)
+
+// Policy 拒绝策略
+type Policy string
+
+const (
+	PolicyBlockFailed   Policy = "block_failed"   // 任一字段未通过检查时拒绝（默认）
+	PolicyBlockRejected Policy = "block_rejected" // 只在处理动作为reject时拒绝，mask、review放行，由处理器通过 Results 处理
+	PolicyMonitor       Policy = "monitor"        // 不拒绝，只将结果写入请求上下文
+)
+
+// RejectFunc 写入拒绝响应，rejected为按拒绝策略需要拒绝的字段
+type RejectFunc func(w http.ResponseWriter, r *http.Request, rejected []types.FieldResult)
+
+// Config 中间件配置，JSONFields、FormFields、QueryParams 都为空时不检查任何内容
+type Config struct {
+	JSONFields   []string             // JSON请求体中检查的字段，JSONPath风格，如 "$.title"、"$.comments[*].text"
+	FormFields   []string             // 表单（urlencoded、multipart）中检查的字段名
+	QueryParams  []string             // 检查的查询参数名
+	Scene        string               // 使用的场景检查选项，设置后忽略 Options
+	Options      *types.FilterOptions // 检查选项，为nil时使用默认选项
+	Policy       Policy               // 拒绝策略，默认 block_failed
+	MaxBodyBytes int64                // 读取请求体的上限，默认1MB，超过时返回413
+	OnReject     RejectFunc           // 自定义拒绝响应，默认返回422和违规字段
+}
+
+// resultsKey 检查结果在请求上下文中的键
+type resultsKey struct{}
+
+// Results 返回中间件写入请求上下文的所有字段的检查结果，表单字段的路径为 "form.{name}"，查询参数为 "query.{name}"
+func Results(ctx context.Context) []types.FieldResult {
+	results, _ := ctx.Value(resultsKey{}).([]types.FieldResult)
+	return results
+}
+
+// New 创建检查中间件，检查请求中配置的字段，按拒绝策略拦截或放行；放行的请求体可被后续处理器正常读取
+// 请求体不是合法JSON时返回400，请求体的 Content-Type 没有配置要检查的字段（如缺少 Content-Type、
+// 只配置了 JSONFields 时提交表单）时返回415，检查被取消（如客户端断开）时返回503
+func New(g *guardian.Guardian, config *Config) func(http.Handler) http.Handler {
+	c := *config
+	if c.Policy == "" {
+		c.Policy = PolicyBlockFailed
+	}
+	if c.MaxBodyBytes <= 0 {
+		c.MaxBodyBytes = defaultMaxBodyBytes
+	}
+	if c.OnReject == nil {
+		c.OnReject = writeRejected
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			results, status, err := c.check(g, w, r)
+			if err != nil {
+				http.Error(w, err.Error(), status)
+				return
+			}
+
+			if rejected := c.rejected(results); len(rejected) > 0 {
+				c.OnReject(w, r, rejected)
+				return
+			}
+			if len(results) > 0 {
+				r = r.WithContext(context.WithValue(r.Context(), resultsKey{}, results))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// check 检查请求中配置的字段，出错时返回应答的状态码
+func (c *Config) check(g *guardian.Guardian, w http.ResponseWriter, r *http.Request) ([]types.FieldResult, int, error) {
+	ctx := r.Context()
+	options := c.Options
+	if c.Scene != "" {
+		var err error
+		if options, err = g.SceneOptions(c.Scene); err != nil {
+			return nil, http.StatusInternalServerError, err
+		}
+	}
+
+	var results []types.FieldResult
+	if len(c.QueryParams) > 0 {
+		query := r.URL.Query()
+		fieldResults, err := checkValues(ctx, g, "query.", c.QueryParams, query, options)
+		if err != nil {
+			return nil, http.StatusServiceUnavailable, err
+		}
+		results = append(results, fieldResults...)
+	}
+
+	if r.Body == nil || r.Body == http.NoBody || (len(c.JSONFields) == 0 && len(c.FormFields) == 0) {
+		return results, 0, nil
+	}
+	// 请求体的类型没有配置要检查的字段时拒绝，不能放行：处理器可能不看 Content-Type 直接解析请求体
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	isJSON := mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+	isForm := mediaType == "application/x-www-form-urlencoded" || mediaType == "multipart/form-data"
+	if (!isJSON || len(c.JSONFields) == 0) && (!isForm || len(c.FormFields) == 0) {
+		return nil, http.StatusUnsupportedMediaType, c.unsupportedMediaType(mediaType)
+	}
+
+	// 缓冲请求体，检查后还原供后续处理器读取
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, c.MaxBodyBytes))
+	r.Body.Close()
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return nil, http.StatusRequestEntityTooLarge, err
+		}
+		return nil, http.StatusBadRequest, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(data))
+
+	if isJSON {
+		fieldResults, err := g.CheckJSON(ctx, data, c.JSONFields, options)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, http.StatusServiceUnavailable, err
+			}
+			return nil, http.StatusBadRequest, err
+		}
+		return append(results, fieldResults...), 0, nil
+	}
+
+	form, err := parseForm(r, data)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	fieldResults, err := checkValues(ctx, g, "form.", c.FormFields, form, options)
+	if err != nil {
+		return nil, http.StatusServiceUnavailable, err
+	}
+	return append(results, fieldResults...), 0, nil
+}
+
+// unsupportedMediaType 请求体类型无法检查时的错误，说明接受的类型
+func (c *Config) unsupportedMediaType(mediaType string) error {
+	var accepted []string
+	if len(c.JSONFields) > 0 {
+		accepted = append(accepted, "application/json")
+	}
+	if len(c.FormFields) > 0 {
+		accepted = append(accepted, "application/x-www-form-urlencoded", "multipart/form-data")
+	}
+	if mediaType == "" {
+		mediaType = "missing"
+	}
+	return fmt.Errorf("unsupported content type %s, expected %s", mediaType, strings.Join(accepted, " or "))
+}
+
+// parseForm 在请求的副本上解析表单，不消耗原请求体
+func parseForm(r *http.Request, data []byte) (map[string][]string, error) {
+	clone := r.Clone(r.Context())
+	clone.Body = io.NopCloser(bytes.NewReader(data))
+	if err := clone.ParseMultipartForm(maxFormMemory); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		return nil, err
+	}
+	if clone.MultipartForm != nil {
+		defer clone.MultipartForm.RemoveAll()
+		return clone.MultipartForm.Value, nil
+	}
+	return clone.PostForm, nil
+}
+
+// checkValues 检查指定名称的所有取值，路径为 prefix + 名称，同名多值时依次检查
+func checkValues(ctx context.Context, g *guardian.Guardian, prefix string, names []string, values map[string][]string, options *types.FilterOptions) ([]types.FieldResult, error) {
+	var results []types.FieldResult
+	for _, name := range names {
+		for _, value := range values[name] {
+			result, err := g.CheckContext(ctx, value, options)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, types.FieldResult{Path: prefix + name, Result: result})
+		}
+	}
+	return results, nil
+}
+
+// rejected 按拒绝策略返回需要拒绝的字段
+func (c *Config) rejected(results []types.FieldResult) []types.FieldResult {
+	var rejected []types.FieldResult
+	for _, field := range results {
+		switch c.Policy {
+		case PolicyMonitor:
+			return nil
+		case PolicyBlockRejected:
+			if field.Result.Action == types.ActionReject {
+				rejected = append(rejected, field)
+			}
+		default:
+			if !field.Result.Passed {
+				rejected = append(rejected, field)
+			}
+		}
+	}
+	return rejected
+}
+
+// violation 默认拒绝响应中的违规字段，不返回命中的敏感词，避免被用于试探词库
+type violation struct {
+	Field      string   `json:"field"`
+	ReasonCode string   `json:"reason_code,omitempty"`
+	Message    string   `json:"message,omitempty"`
+	Categories []string `json:"categories,omitempty"`
+}
+
+// writeRejected 默认拒绝响应：422和违规字段列表
+func writeRejected(w http.ResponseWriter, r *http.Request, rejected []types.FieldResult) {
+	body := struct {
+		Error      string      `json:"error"`
+		Violations []violation `json:"violations"`
+	}{Error: "content rejected"}
+	for _, field := range rejected {
+		body.Violations = append(body.Violations, violation{
+			Field:      field.Path,
+			ReasonCode: field.Result.ReasonCode,
+			Message:    field.Result.Message,
+			Categories: field.Result.Categories,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	json.NewEncoder(w).Encode(body)
+}
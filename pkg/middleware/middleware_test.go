@@ -0,0 +1,133 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/guardian/content-filter/internal/types"
+	"github.com/guardian/content-filter/pkg/guardian"
+	"github.com/guardian/content-filter/pkg/guardiantest"
+	"github.com/guardian/content-filter/pkg/middleware"
+)
+
+// newGuardian 创建测试实例：abuse分类的词直接拒绝，ads分类的词按策略转人工审核
+func newGuardian(t *testing.T) *guardian.Guardian {
+	t.Helper()
+
+	wordDB := guardiantest.EmptyWordDatabase()
+	wordDB.Version = "1.0.0"
+	wordDB.Blacklist = []types.SensitiveWord{
+		{Word: "违禁词", Categories: []string{"abuse"}, Level: 3},
+		{Word: "加微信", Categories: []string{"ads"}, Level: 2},
+	}
+	policy, err := json.Marshal(&types.Policy{
+		Version: "1",
+		Rules:   []types.PolicyRule{{Categories: []string{"ads"}, Action: types.ActionReview}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := guardiantest.NewWordSource()
+	if err := src.SetWordDatabase(guardiantest.DataId, guardiantest.Group, wordDB); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.PublishConfig("policy", guardiantest.Group, string(policy)); err != nil {
+		t.Fatal(err)
+	}
+	config := guardiantest.Config()
+	config.FilterConfig.PolicyDataId = "policy"
+	return guardiantest.NewWithSource(t, config, src)
+}
+
+// serve 经中间件发送请求，返回状态码和处理器是否被调用
+func serve(t *testing.T, mw func(http.Handler) http.Handler, contentType, body string) (int, bool) {
+	t.Helper()
+
+	called := false
+	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/posts", strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec.Code, called
+}
+
+func TestPolicies(t *testing.T) {
+	g := newGuardian(t)
+
+	tests := []struct {
+		name   string
+		policy middleware.Policy
+		text   string
+		want   int
+	}{
+		{"block_failed passes clean text", middleware.PolicyBlockFailed, "正常评论", http.StatusOK},
+		{"block_failed blocks review", middleware.PolicyBlockFailed, "快来加微信", http.StatusUnprocessableEntity},
+		{"block_failed blocks reject", middleware.PolicyBlockFailed, "这里有违禁词", http.StatusUnprocessableEntity},
+		{"block_rejected passes review", middleware.PolicyBlockRejected, "快来加微信", http.StatusOK},
+		{"block_rejected blocks reject", middleware.PolicyBlockRejected, "这里有违禁词", http.StatusUnprocessableEntity},
+		{"monitor passes reject", middleware.PolicyMonitor, "这里有违禁词", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mw := middleware.New(g, &middleware.Config{JSONFields: []string{"$.text"}, Policy: tt.policy})
+			body, err := json.Marshal(map[string]string{"text": tt.text})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			code, called := serve(t, mw, "application/json", string(body))
+			if code != tt.want {
+				t.Errorf("status = %d, want %d", code, tt.want)
+			}
+			if called != (tt.want == http.StatusOK) {
+				t.Errorf("handler called = %v, want %v", called, tt.want == http.StatusOK)
+			}
+		})
+	}
+}
+
+func TestContentType(t *testing.T) {
+	g := newGuardian(t)
+	jsonOnly := middleware.New(g, &middleware.Config{JSONFields: []string{"$.text"}})
+	jsonAndForm := middleware.New(g, &middleware.Config{JSONFields: []string{"$.text"}, FormFields: []string{"text"}})
+
+	tests := []struct {
+		name        string
+		mw          func(http.Handler) http.Handler
+		contentType string
+		body        string
+		want        int
+	}{
+		{"json", jsonOnly, "application/json", `{"text":"这里有违禁词"}`, http.StatusUnprocessableEntity},
+		{"json with charset", jsonOnly, "application/json; charset=utf-8", `{"text":"这里有违禁词"}`, http.StatusUnprocessableEntity},
+		{"json suffix", jsonOnly, "application/merge-patch+json", `{"text":"这里有违禁词"}`, http.StatusUnprocessableEntity},
+		{"invalid json", jsonOnly, "application/json", `{"text":`, http.StatusBadRequest},
+		{"missing content type", jsonOnly, "", `{"text":"这里有违禁词"}`, http.StatusUnsupportedMediaType},
+		{"text/plain", jsonOnly, "text/plain", `{"text":"这里有违禁词"}`, http.StatusUnsupportedMediaType},
+		{"xml", jsonOnly, "application/xml", `{"text":"这里有违禁词"}`, http.StatusUnsupportedMediaType},
+		{"form without form fields", jsonOnly, "application/x-www-form-urlencoded", "text=这里有违禁词", http.StatusUnsupportedMediaType},
+		{"form", jsonAndForm, "application/x-www-form-urlencoded", "text=这里有违禁词", http.StatusUnprocessableEntity},
+		{"clean form", jsonAndForm, "application/x-www-form-urlencoded", "text=正常评论", http.StatusOK},
+		{"missing content type with form fields", jsonAndForm, "", "text=这里有违禁词", http.StatusUnsupportedMediaType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, called := serve(t, tt.mw, tt.contentType, tt.body)
+			if code != tt.want {
+				t.Errorf("status = %d, want %d", code, tt.want)
+			}
+			if called != (tt.want == http.StatusOK) {
+				t.Errorf("handler called = %v, want %v", called, tt.want == http.StatusOK)
+			}
+		})
+	}
+}